@@ -5,14 +5,26 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	_ "github.com/tuan1kdt/soa-ba-test/docs"
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/exchangerate"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/export"
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/geohelper"
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/handler/http"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/importer"
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/logger"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/report"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/disk"
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres"
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres/repository"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/redis"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/worker"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
 	"github.com/tuan1kdt/soa-ba-test/internal/core/service"
 )
 
@@ -25,7 +37,7 @@ import (
 // @contact.email				leanhtuan1998hl@gmail.com
 //
 // @host						localhost:8080
-// @BasePath					/v1
+// @BasePath					/
 // @schemes					http https
 //
 // @securityDefinitions.apikey	BearerAuth
@@ -45,8 +57,12 @@ func main() {
 
 	slog.Info("Starting the application", "app", config.App.Name, "env", config.App.Env)
 
+	// Stop the server and the workers on SIGINT or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	var workers sync.WaitGroup
+
 	// Init database
-	ctx := context.Background()
 	db, err := postgres.New(ctx, config.DB)
 	if err != nil {
 		slog.Error("Error initializing database connection", "error", err)
@@ -56,27 +72,115 @@ func main() {
 
 	slog.Info("Successfully connected to the database", "db", config.DB.Connection)
 
+	// Audit
+	auditRepo := repository.NewAuditRepository(db)
+
 	// Category
 	categoryRepo := repository.NewCategoryRepository(db)
-	categoryService := service.NewCategoryService(categoryRepo, nil)
+	categoryService := service.NewCategoryService(categoryRepo, auditRepo, db, nil)
 	categoryHandler := http.NewCategoryHandler(categoryService)
 
+	// Exchange rate
+	rounding, err := domain.ParseRoundingRules(config.Currency.RoundingMode, config.Currency.DecimalPlaces)
+	if err != nil {
+		slog.Error("Error parsing currency rounding rules", "error", err)
+		os.Exit(1)
+	}
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	exchangeRateService := service.NewExchangeRateService(exchangeRateRepo, db, rounding)
+	exchangeRateHandler := http.NewExchangeRateHandler(exchangeRateService)
+
+	if config.Currency.RatesFile != "" {
+		rates, err := exchangerate.LoadFile(config.Currency.RatesFile)
+		if err != nil {
+			slog.Error("Error loading exchange rates file", "error", err)
+			os.Exit(1)
+		}
+		err = exchangeRateService.ImportExchangeRates(ctx, rates)
+		if err != nil {
+			slog.Error("Error importing exchange rates", "error", err)
+			os.Exit(1)
+		}
+		slog.Info("Imported exchange rates", "file", config.Currency.RatesFile, "count", len(rates))
+	}
+
 	// Product
 	productRepo := repository.NewProductRepository(db)
-	geoClient := geohelper.New(config.GEO)
-	productService := service.NewProductService(productRepo, categoryRepo, nil, geoClient)
-	productHandler := http.NewProductHandler(productService)
+	priceRepo := repository.NewPriceRepository(db)
+	stockRepo := repository.NewStockRepository(db)
+	var cache port.CacheRepository
+	if config.Redis.Addr != "" {
+		cache, err = redis.New(ctx, config.Redis)
+		if err != nil {
+			slog.Warn("Error connecting to redis, geo locations and products are not cached", "error", err)
+		} else {
+			defer cache.Close()
+		}
+	}
+	geoClient, err := geohelper.NewChainFromConfig(config.GEO, cache)
+	if err != nil {
+		slog.Error("Error initializing geo providers", "error", err)
+		os.Exit(1)
+	}
+	defer geoClient.Close()
 
 	// Statistic
 	statisticService := service.NewStatisticService(productRepo)
 	statisticHandler := http.NewStatisticHandler(statisticService)
 
+	reportTemplate, err := report.LoadTemplate(config.Report.TemplateFile, export.ReportColumns())
+	if err != nil {
+		slog.Error("Error loading report template", "error", err)
+		os.Exit(1)
+	}
+	productService := service.NewProductService(productRepo, categoryRepo, priceRepo, stockRepo, auditRepo, db, cache, geoClient)
+	exporter := export.New(productService, exchangeRateService, statisticService, reportTemplate)
+	productHandler := http.NewProductHandler(productService, exchangeRateService, exporter, importer.New())
+
+	// Export
+	exportStorage, err := disk.NewExportStorage(config.Export)
+	if err != nil {
+		slog.Error("Error initializing export storage", "error", err)
+		os.Exit(1)
+	}
+	exportRepo := repository.NewExportRepository(db)
+	exportService := service.NewExportService(exportRepo, exportStorage, exporter, config.Export.Retention)
+	exportHandler := http.NewExportHandler(exportService)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		worker.NewExportWorker(config.Export, exportService).Run(ctx)
+	}()
+
+	// Price
+	priceService := service.NewPriceService(priceRepo, productRepo, auditRepo, db, cache)
+	priceHandler := http.NewPriceHandler(priceService)
+	workers.Add(1)
+	go func() {
+		defer workers.Done()
+		worker.NewPriceWorker(config.Worker, priceService).Run(ctx)
+	}()
+
+	// Stock
+	stockService := service.NewStockService(stockRepo, productRepo, auditRepo, db, cache)
+	stockHandler := http.NewStockHandler(stockService)
+
+	// Warehouse
+	warehouseRepo := repository.NewWarehouseRepository(db)
+	warehouseService := service.NewWarehouseService(warehouseRepo, auditRepo, db)
+	warehouseHandler := http.NewWarehouseHandler(warehouseService)
+
 	// Init router
 	router, err := http.NewRouter(
 		config.HTTP,
 		*categoryHandler,
 		*productHandler,
+		*priceHandler,
+		*stockHandler,
+		*warehouseHandler,
+		*exchangeRateHandler,
 		*statisticHandler,
+		*exportHandler,
 	)
 	if err != nil {
 		slog.Error("Error initializing router", "error", err)
@@ -86,9 +190,12 @@ func main() {
 	// Start server
 	listenAddr := fmt.Sprintf("%s:%s", config.HTTP.URL, config.HTTP.Port)
 	slog.Info("Starting the HTTP server", "listen_address", listenAddr)
-	err = router.Serve(listenAddr)
+	err = router.Serve(ctx, listenAddr)
 	if err != nil {
 		slog.Error("Error starting the HTTP server", "error", err)
 		os.Exit(1)
 	}
+
+	workers.Wait()
+	slog.Info("Stopped the application")
 }
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/geohelper"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/importer"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/logger"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres/repository"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/redis"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/service"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/util"
+)
+
+// importActor is the actor recorded in the audit trail for the imported products
+const importActor = "import-cli"
+
+// import imports products from a CSV or JSON Lines file and prints the import report as JSON.
+// It exits with an error when the file could not be imported or an atomic import rejected some rows
+func main() {
+	file := flag.String("file", "", "CSV or JSON Lines file to import, - for the standard input")
+	format := flag.String("format", "", "file format, csv or jsonl, read from the file extension by default")
+	dryRun := flag.Bool("dry-run", false, "validate the rows without writing them")
+	upsert := flag.Bool("upsert", false, "update the products whose reference exists")
+	mode := flag.String("mode", string(domain.ImportAtomic), "atomic imports nothing when a row is rejected, partial imports the valid rows")
+	flag.Parse()
+
+	if *file == "" {
+		slog.Error("Missing file to import")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		switch strings.ToLower(filepath.Ext(*file)) {
+		case ".csv":
+			*format = string(domain.ImportCSV)
+		case ".jsonl", ".ndjson":
+			*format = string(domain.ImportJSONL)
+		default:
+			slog.Error("Unknown file format, set the format flag", "file", *file)
+			os.Exit(2)
+		}
+	}
+
+	if *mode != string(domain.ImportAtomic) && *mode != string(domain.ImportPartial) {
+		slog.Error("Unknown import mode", "mode", *mode)
+		os.Exit(2)
+	}
+
+	// Load environment variables
+	config, err := config.New()
+	if err != nil {
+		slog.Error("Error loading environment variables", "error", err)
+		os.Exit(1)
+	}
+
+	// Set logger
+	logger.Set(config.App)
+
+	input := os.Stdin
+	if *file != "-" {
+		input, err = os.Open(*file)
+		if err != nil {
+			slog.Error("Error opening file", "error", err)
+			os.Exit(1)
+		}
+		defer input.Close()
+	}
+
+	rows, err := importer.New().ParseProducts(input, domain.ImportFormat(*format))
+	if err != nil {
+		slog.Error("Error reading file", "error", err)
+		os.Exit(1)
+	}
+
+	// Init database
+	ctx := util.WithActor(context.Background(), importActor)
+	db, err := postgres.New(ctx, config.DB)
+	if err != nil {
+		slog.Error("Error initializing database connection", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	// The cache of the products read by id, which the updated products are forgotten from
+	var cache port.CacheRepository
+	if config.Redis.Addr != "" {
+		cache, err = redis.New(ctx, config.Redis)
+		if err != nil {
+			slog.Warn("Error connecting to redis, the updated products stay cached until they expire", "error", err)
+		} else {
+			defer cache.Close()
+		}
+	}
+
+	geoClient, err := geohelper.NewChainFromConfig(config.GEO, cache)
+	if err != nil {
+		slog.Error("Error initializing geo providers", "error", err)
+		os.Exit(1)
+	}
+	defer geoClient.Close()
+
+	productService := service.NewProductService(
+		repository.NewProductRepository(db),
+		repository.NewCategoryRepository(db),
+		repository.NewPriceRepository(db),
+		repository.NewStockRepository(db),
+		repository.NewAuditRepository(db),
+		db,
+		cache,
+		geoClient,
+	)
+
+	options := domain.ImportOptions{
+		DryRun: *dryRun,
+		Upsert: *upsert,
+		Mode:   domain.ImportMode(*mode),
+	}
+
+	report, err := productService.ImportProducts(ctx, rows, options)
+	if err != nil {
+		slog.Error("Error importing products", "error", err)
+		os.Exit(1)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		slog.Error("Error printing report", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Imported products", "rows", report.Rows, "created", report.Created, "updated", report.Updated,
+		"unchanged", report.Unchanged, "failed", report.Failed, "dry_run", report.DryRun, "committed", report.Committed)
+
+	if report.Failed > 0 && options.Mode == domain.ImportAtomic {
+		os.Exit(1)
+	}
+}
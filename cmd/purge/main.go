@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/logger"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres/repository"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/service"
+)
+
+// purge permanently removes the products and categories
+// that were soft deleted longer ago than the retention window
+func main() {
+	retention := flag.Duration("retention", 30*24*time.Hour, "how long soft deleted rows are kept before they are purged")
+	flag.Parse()
+
+	// Load environment variables
+	config, err := config.New()
+	if err != nil {
+		slog.Error("Error loading environment variables", "error", err)
+		os.Exit(1)
+	}
+
+	// Set logger
+	logger.Set(config.App)
+
+	// Init database
+	ctx := context.Background()
+	db, err := postgres.New(ctx, config.DB)
+	if err != nil {
+		slog.Error("Error initializing database connection", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	auditRepo := repository.NewAuditRepository(db)
+
+	categoryRepo := repository.NewCategoryRepository(db)
+	categoryService := service.NewCategoryService(categoryRepo, auditRepo, db, nil)
+
+	productRepo := repository.NewProductRepository(db)
+	productService := service.NewProductService(productRepo, categoryRepo, repository.NewPriceRepository(db), repository.NewStockRepository(db), auditRepo, db, nil, nil)
+
+	// Products go first so that their categories are no longer referenced
+	products, err := productService.PurgeProducts(ctx, *retention)
+	if err != nil {
+		slog.Error("Error purging products", "error", err)
+		os.Exit(1)
+	}
+
+	categories, err := categoryService.PurgeCategories(ctx, *retention)
+	if err != nil {
+		slog.Error("Error purging categories", "error", err)
+		os.Exit(1)
+	}
+
+	slog.Info("Purged soft deleted rows", "retention", retention.String(), "products", products, "categories", categories)
+}
@@ -19,14 +19,14 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/categories": {
+        "/v1/categories": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "List categories with pagination",
+                "description": "List categories with pagination, newest first unless sorted otherwise.\nWith a cursor, the pages follow each other through the next_cursor of the previous page instead of skip",
                 "consumes": [
                     "application/json"
                 ],
@@ -38,6 +38,24 @@ const docTemplate = `{
                 ],
                 "summary": "List categories",
                 "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include soft deleted categories",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields among id, name and created_at, each descending when prefixed with -",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page with a cursor instead of skip, empty for the first page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Skip",
@@ -146,7 +164,7 @@ const docTemplate = `{
                 }
             }
         },
-        "/categories/{id}": {
+        "/v1/categories/{id}": {
             "get": {
                 "security": [
                     {
@@ -206,7 +224,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a category by id",
+                "description": "Soft delete a category by id, a category used by a product cannot be deleted",
                 "consumes": [
                     "application/json"
                 ],
@@ -346,82 +364,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/products": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "List products with pagination",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Products"
-                ],
-                "summary": "List products",
-                "parameters": [
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Category IDs",
-                        "name": "category_ids",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Query",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Skip",
-                        "name": "skip",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Products retrieved",
-                        "schema": {
-                            "$ref": "#/definitions/http.meta"
-                        }
-                    },
-                    "400": {
-                        "description": "Validation error",
-                        "schema": {
-                            "$ref": "#/definitions/http.errorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal server error",
-                        "schema": {
-                            "$ref": "#/definitions/http.errorResponse"
-                        }
-                    }
-                }
-            },
+        "/v1/categories/{id}/restore": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "create a new product with name, image, price, and stock",
+                "description": "Restore a soft deleted category by id",
                 "consumes": [
                     "application/json"
                 ],
@@ -429,25 +379,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Categories"
                 ],
-                "summary": "Create a new product",
+                "summary": "Restore a category",
                 "parameters": [
                     {
-                        "description": "Create product request",
-                        "name": "createProductRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/http.createProductRequest"
-                        }
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product created",
+                        "description": "Category restored",
                         "schema": {
-                            "$ref": "#/definitions/http.productResponse"
+                            "$ref": "#/definitions/http.categoryResponse"
                         }
                     },
                     "400": {
@@ -489,65 +437,24 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/export": {
+        "/v1/exchange-rates": {
             "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Export a list of products as a PDF file",
+                "description": "List the exchange rates against the base currency used to convert prices",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/pdf"
+                    "application/json"
                 ],
                 "tags": [
-                    "Products"
-                ],
-                "summary": "Export products",
-                "parameters": [
-                    {
-                        "type": "array",
-                        "items": {
-                            "type": "string"
-                        },
-                        "collectionFormat": "csv",
-                        "description": "Category IDs",
-                        "name": "category_ids",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Query",
-                        "name": "q",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Skip",
-                        "name": "skip",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    }
+                    "Exchange rates"
                 ],
+                "summary": "List exchange rates",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "file"
-                        }
-                    },
-                    "400": {
-                        "description": "Validation error",
+                        "description": "Exchange rates retrieved",
                         "schema": {
-                            "$ref": "#/definitions/http.errorResponse"
+                            "$ref": "#/definitions/http.exchangeRateResponse"
                         }
                     },
                     "500": {
@@ -559,14 +466,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}": {
-            "get": {
+        "/v1/exchange-rates/{currency}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get a product by id with its category",
+                "description": "Create or replace how many units of a currency one unit of the base currency buys",
                 "consumes": [
                     "application/json"
                 ],
@@ -574,23 +481,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Exchange rates"
                 ],
-                "summary": "Get a product",
+                "summary": "Set an exchange rate",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
-                        "name": "id",
+                        "description": "Currency",
+                        "name": "currency",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Set exchange rate request",
+                        "name": "setExchangeRateRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.setExchangeRateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product retrieved",
+                        "description": "Exchange rate set",
                         "schema": {
-                            "$ref": "#/definitions/http.productResponse"
+                            "$ref": "#/definitions/http.exchangeRateResponse"
                         }
                     },
                     "400": {
@@ -599,8 +515,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.errorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Data not found error",
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
                         "schema": {
                             "$ref": "#/definitions/http.errorResponse"
                         }
@@ -619,7 +541,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a product by id",
+                "description": "Delete the exchange rate of a currency, prices can no longer be converted to it",
                 "consumes": [
                     "application/json"
                 ],
@@ -627,21 +549,21 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Exchange rates"
                 ],
-                "summary": "Delete a product",
+                "summary": "Delete an exchange rate",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Currency",
+                        "name": "currency",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product deleted",
+                        "description": "Exchange rate deleted",
                         "schema": {
                             "$ref": "#/definitions/http.response"
                         }
@@ -677,14 +599,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/exports": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "update a product's name, image, price, or stock by id",
+                "description": "Queue an export of the products matching the filters, generated in the background as a PDF report, a CSV file or an Excel workbook.\nThe options are those of the product export, a PDF report holds all the matching products unless a limit is given.\nThe job is polled until completed, then its file is downloaded until it expires",
                 "consumes": [
                     "application/json"
                 ],
@@ -692,32 +616,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Exports"
                 ],
-                "summary": "Update a product",
+                "summary": "Create an export job",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Update product request",
-                        "name": "updateProductRequest",
+                        "description": "Create export request",
+                        "name": "createExportRequest",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/http.updateProductRequest"
+                            "$ref": "#/definitions/http.createExportRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product updated",
+                        "description": "Export job created",
                         "schema": {
-                            "$ref": "#/definitions/http.productResponse"
+                            "$ref": "#/definitions/http.exportResponse"
                         }
                     },
                     "400": {
@@ -726,26 +643,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.errorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized error",
-                        "schema": {
-                            "$ref": "#/definitions/http.errorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden error",
-                        "schema": {
-                            "$ref": "#/definitions/http.errorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Data not found error",
-                        "schema": {
-                            "$ref": "#/definitions/http.errorResponse"
-                        }
-                    },
-                    "409": {
-                        "description": "Data conflict error",
+                    "422": {
+                        "description": "Unknown client location error",
                         "schema": {
                             "$ref": "#/definitions/http.errorResponse"
                         }
@@ -759,14 +658,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}/distance": {
+        "/v1/exports/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "get a product by id with its category",
+                "description": "Get the state and the progress of an export job, rows being the number of products exported so far",
                 "consumes": [
                     "application/json"
                 ],
@@ -774,13 +673,13 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Products"
+                    "Exports"
                 ],
-                "summary": "Get a product",
+                "summary": "Get an export job",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Product ID",
+                        "description": "Export job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -788,9 +687,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Product retrieved",
+                        "description": "Export job retrieved",
                         "schema": {
-                            "$ref": "#/definitions/http.productResponse"
+                            "$ref": "#/definitions/http.exportResponse"
                         }
                     },
                     "400": {
@@ -814,14 +713,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/statistics/products-per-category": {
-            "get": {
+        "/v1/exports/{id}/cancel": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get Statistic of category product",
+                "description": "Cancel a pending or running export job, a running job stops within a few seconds and its file is removed",
                 "consumes": [
                     "application/json"
                 ],
@@ -829,14 +728,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Statistics"
+                    "Exports"
+                ],
+                "summary": "Cancel an export job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Statistic of category product",
                 "responses": {
                     "200": {
-                        "description": "Statistic created",
+                        "description": "Export job canceled",
                         "schema": {
-                            "$ref": "#/definitions/http.StatisticCategoryProductResponse"
+                            "$ref": "#/definitions/http.exportResponse"
                         }
                     },
                     "400": {
@@ -845,18 +753,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/http.errorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized error",
-                        "schema": {
-                            "$ref": "#/definitions/http.errorResponse"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden error",
-                        "schema": {
-                            "$ref": "#/definitions/http.errorResponse"
-                        }
-                    },
                     "404": {
                         "description": "Data not found error",
                         "schema": {
@@ -864,7 +760,7 @@ const docTemplate = `{
                         }
                     },
                     "409": {
-                        "description": "Data conflict error",
+                        "description": "Export job already finished error",
                         "schema": {
                             "$ref": "#/definitions/http.errorResponse"
                         }
@@ -878,29 +774,232 @@ const docTemplate = `{
                 }
             }
         },
-        "/statistics/products-per-supplier": {
+        "/v1/exports/{id}/download": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get Statistic of supplier product",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Download the file of a completed export job until it expires, range requests are supported",
                 "produces": [
-                    "application/json"
+                    "application/pdf",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Statistics"
+                    "Exports"
+                ],
+                "summary": "Download the file of an export job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Export job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Statistic of supplier product",
                 "responses": {
                     "200": {
-                        "description": "Statistic created",
+                        "description": "Export file",
                         "schema": {
-                            "$ref": "#/definitions/http.StatisticSupplierProductResponse"
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Export job not completed yet error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Export job failed, canceled or expired error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List products with pagination.\nNear a location, each product carries its distance_km to the closest of its stock city and the warehouses holding it, radius_km keeps the products within that distance and sort=distance lists the closest first\nThe products are listed newest first unless sorted otherwise. With a cursor, the pages follow each other through the next_cursor of the previous page instead of skip\nWith ids, the products with those comma separated ids are returned as by POST /v1/products:batchGet and the other filters are ignored",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "List products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated IDs of the products to get, up to 100",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Category IDs",
+                        "name": "category_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "IDs of warehouses holding stock of the products",
+                        "name": "warehouse_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft deleted products",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Origin as latitude,longitude, or ip for the location of the client",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Keep the products within that distance of the origin",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields among id, reference, name, added_date, status, price, quantity, stock_city and distance, each descending when prefixed with -. Newest first by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to convert the prices to",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page with a cursor instead of skip, empty for the first page then the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skip",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Products retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.meta"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown client location error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "create a new product with name, image, price, and stock",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Create a new product",
+                "parameters": [
+                    {
+                        "description": "Create product request",
+                        "name": "createProductRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product created",
+                        "schema": {
+                            "$ref": "#/definitions/http.productResponse"
                         }
                     },
                     "400": {
@@ -941,180 +1040,3108 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "http.StatisticCategoryProductResponse": {
-            "type": "object",
-            "properties": {
+        },
+        "/v1/products/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Export a page of products as a PDF report, or all the products matching the filters as a CSV file streamed as it is read\nor as an Excel workbook. The workbook has a summary sheet with the share of all the products held by each category and supplier,\nthen one sheet of products per category, or a single one, each with a frozen header row with filters. Prices, quantities and distances are numbers, dates are dates in UTC.\nThe PDF report is laid out by the configured template, it starts with the filters and the generation time, and may group the products by category with subtotals of the quantity and stock value.\nThe columns of a CSV or Excel export are chosen with columns, among id, reference, name, added_date, status, category_id, category, price, effective_price, currency, converted_price, converted_currency, stock_city, supplier_id, quantity, stock_value, distance_km and deleted_at",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf",
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Export products",
+                "parameters": [
+                    {
+                        "enum": [
+                            "pdf",
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "default": "pdf",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "category",
+                            "single"
+                        ],
+                        "type": "string",
+                        "default": "category",
+                        "description": "Excel sheets of products",
+                        "name": "sheets",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "category",
+                            "none"
+                        ],
+                        "type": "string",
+                        "description": "Grouping of the PDF report, the template one by default",
+                        "name": "group",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Category IDs",
+                        "name": "category_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "IDs of warehouses holding stock of the products",
+                        "name": "warehouse_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft deleted products",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Origin as latitude,longitude, or ip for the location of the client",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Keep the products within that distance of the origin",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields among id, reference, name, added_date, status, price, quantity, stock_city and distance, each descending when prefixed with -. Newest first by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to convert the prices to",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "CSV or Excel columns, comma separated or repeated",
+                        "name": "columns",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": ",",
+                        "description": "CSV delimiter, a single character, tab or semicolon",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Start the CSV file with a UTF-8 byte order mark",
+                        "name": "bom",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skip, PDF only",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit, PDF only",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown client location error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Import products from a CSV file or a JSON Lines file, sent as the request body or as the file field of a multipart form.\nThe columns, or the keys of the JSON objects, are reference, name, status, category_id, price, currency, stock_city, supplier_id and quantity, only reference and name are required.\nThe format is read from the format parameter, the content type (text/csv or application/x-ndjson) or the file extension.\nEvery row is validated as a created product, its category and supplier must exist and its reference must be new unless upsert is set.\nAn atomic import writes nothing when a row is rejected, a partial one writes the valid rows, a dry run only validates them.\nThe report lists the rejected rows by line with their errors",
+                "consumes": [
+                    "text/csv",
+                    "application/x-ndjson",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Import products",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "jsonl"
+                        ],
+                        "type": "string",
+                        "description": "File format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate the rows without writing them",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Update the products whose reference exists, empty values are kept",
+                        "name": "upsert",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "atomic",
+                            "partial"
+                        ],
+                        "type": "string",
+                        "default": "atomic",
+                        "description": "Import nothing or the valid rows when a row is rejected",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Imported file, when sent as a multipart form",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Import report",
+                        "schema": {
+                            "$ref": "#/definitions/http.importReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Data conflict error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/labels": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Print a page of the products matching the filters as a PDF sheet of shelf labels, at most 1000 labels, the first 1000 when no limit is given.\nEach label shows the name, reference and price of a product with a Code128 barcode or a QR code of its reference or ID.\nThe layouts are avery-l7159 (3x8 labels on A4), avery-l7160 (3x7 on A4), avery-l7163 (2x7 on A4) and avery-5160 (3x10 on Letter).\nStart prints from a position of the first sheet, to reuse a partly used sheet. IDs are long for a barcode, a QR code scans better",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Print product labels",
+                "parameters": [
+                    {
+                        "enum": [
+                            "avery-l7159",
+                            "avery-l7160",
+                            "avery-l7163",
+                            "avery-5160"
+                        ],
+                        "type": "string",
+                        "default": "avery-l7159",
+                        "description": "Label sheet",
+                        "name": "layout",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "code128",
+                            "qr"
+                        ],
+                        "type": "string",
+                        "default": "code128",
+                        "description": "Code printed on the labels",
+                        "name": "symbology",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "reference",
+                            "id"
+                        ],
+                        "type": "string",
+                        "default": "reference",
+                        "description": "Product field the code holds",
+                        "name": "encode",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Position of the first label on the first sheet",
+                        "name": "start",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Category IDs",
+                        "name": "category_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "IDs of warehouses holding stock of the products",
+                        "name": "warehouse_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft deleted products",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Origin as latitude,longitude, or ip for the location of the client",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Keep the products within that distance of the origin",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields among id, reference, name, added_date, status, price, quantity, stock_city and distance, each descending when prefixed with -. Newest first by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to convert the prices to",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skip",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "default": 1000,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown client location error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get a product by id with its category, with its price converted when a currency is given",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to convert the price to",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft deleted products",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.productResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Soft delete a product by id, it can be restored until it is purged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product deleted",
+                        "schema": {
+                            "$ref": "#/definitions/http.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "update a product's name, image, price, or stock by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Update a product",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update product request",
+                        "name": "updateProductRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateProductRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product updated",
+                        "schema": {
+                            "$ref": "#/definitions/http.productResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Data conflict error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/{id}/availability": {
+            "get": {
+                "description": "List the warehouses holding stock of a product with their quantity and their distance from the client, nearest first.\nThe client is located with lat and lon, or from its ip when they are not given. Warehouses without coordinates come last",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get the availability of a product near a location",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the client",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the client",
+                        "name": "lon",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product availability retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.productAvailabilityResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown client location error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/{id}/distance": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "get a product by id with its category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.productResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown stock city or client location error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the changes made to a product, newest first, with the actor, request id and the changed fields.\nThe actor is the authenticated user or anonymous, the claimed_actor is the unverified name sent by the client in the X-Actor header",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get the history of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skip",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product history retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.auditEntryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/{id}/prices": {
+            "get": {
+                "description": "List the price timeline of a product, latest start first, including past, running, scheduled and canceled prices",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "List the prices of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skip",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Prices retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.priceChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Schedule a new price for a product. Without an end the price replaces the base price once it starts, with an end it is a promotion overriding the base price while it lasts. A missing start means now",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Schedule a price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Schedule price request",
+                        "name": "schedulePriceRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.schedulePriceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price scheduled",
+                        "schema": {
+                            "$ref": "#/definitions/http.priceChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/{id}/prices/{price_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancel a scheduled price or end a running promotion. A price that already replaced the base price can not be canceled",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Prices"
+                ],
+                "summary": "Cancel a price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price ID",
+                        "name": "price_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Price canceled",
+                        "schema": {
+                            "$ref": "#/definitions/http.priceChangeResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Price already applied error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restore a soft deleted product by id, its category being restored first when it was deleted too",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Restore a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product restored",
+                        "schema": {
+                            "$ref": "#/definitions/http.productResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Data conflict error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Category deleted error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products/{id}/stock-movements": {
+            "get": {
+                "description": "List the stock ledger of a product, newest first, with the stock left after each movement",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "List the stock movements of a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Movement types",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Warehouse ID",
+                        "name": "warehouse_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Moved at or after (RFC 3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Moved before (RFC 3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skip",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stock movements retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.stockMovementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products:batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Apply up to 100 creations, updates and deletions of products in a single transaction, each validated as the single request it stands for.\nAn update only sets the fields that are not empty. A product can only be updated or deleted by one operation of a batch.\nAn atomic batch applies all the operations or none of them, the operations it did not apply fail with status 424.\nA best effort batch applies the operations that succeed, the operations still pending after a few failures being written fail with status 424.\nThe result of each operation holds its status code and its product or errors",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Create, update and delete products in a batch",
+                "parameters": [
+                    {
+                        "description": "Batch products request",
+                        "name": "batchProductsRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.batchProductsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Batch applied, in whole or in part",
+                        "schema": {
+                            "$ref": "#/definitions/http.batchProductsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/products:batchGet": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get up to 100 products by id with their category, in the order of the ids, each id once.\nThe ids matching no product, deleted ones included, are listed in missing_ids. GET /v1/products?ids=a,b,c does the same for short lists",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get products by id",
+                "parameters": [
+                    {
+                        "description": "Batch get products request",
+                        "name": "batchGetProductsRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.batchGetProductsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Products retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.batchGetProductsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/statistics/products-per-category": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get Statistic of category product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get Statistic of category product",
+                "responses": {
+                    "200": {
+                        "description": "Statistic created",
+                        "schema": {
+                            "$ref": "#/definitions/http.StatisticCategoryProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Data conflict error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/statistics/products-per-supplier": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get Statistic of supplier product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get Statistic of supplier product",
+                "responses": {
+                    "200": {
+                        "description": "Statistic created",
+                        "schema": {
+                            "$ref": "#/definitions/http.StatisticSupplierProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Data conflict error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock-movements": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Append movements to the stock ledger of products and update their quantity and status, either all of them or none.\nThe quantity of a receipt, a sale or a return is the positive number of units moved, the quantity of an adjustment or a transfer is signed and they require a reason.\nA movement with a warehouse changes the stock of that warehouse, a movement without one changes the stock not held by any warehouse",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Post stock movements",
+                "parameters": [
+                    {
+                        "description": "Post stock movements request",
+                        "name": "postStockMovementsRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.postStockMovementsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stock movements posted",
+                        "schema": {
+                            "$ref": "#/definitions/http.stockMovementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock-movements/transfers": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move units of a product from one warehouse to another. The transfer is recorded as a pair of transfer movements sharing a transfer id, the total stock of the product is unchanged",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Stock"
+                ],
+                "summary": "Transfer stock between warehouses",
+                "parameters": [
+                    {
+                        "description": "Transfer stock request",
+                        "name": "transferStockRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.transferStockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stock transferred",
+                        "schema": {
+                            "$ref": "#/definitions/http.stockMovementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown warehouse error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/warehouses": {
+            "get": {
+                "description": "List warehouses by name with pagination",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "List warehouses",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Skip",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Warehouses displayed",
+                        "schema": {
+                            "$ref": "#/definitions/http.warehouseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new warehouse with a name, a city and its coordinates",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Create a new warehouse",
+                "parameters": [
+                    {
+                        "description": "Create warehouse request",
+                        "name": "createWarehouseRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.createWarehouseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Warehouse created",
+                        "schema": {
+                            "$ref": "#/definitions/http.warehouseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Data conflict error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/warehouses/{id}": {
+            "get": {
+                "description": "get a warehouse by id",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Get a warehouse",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Warehouse ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Warehouse retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.warehouseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a warehouse by id, a warehouse still holding stock cannot be deleted",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Delete a warehouse",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Warehouse ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Warehouse deleted",
+                        "schema": {
+                            "$ref": "#/definitions/http.response"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Data conflict error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the name, city or coordinates of a warehouse by id, the fields left empty are kept",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Warehouses"
+                ],
+                "summary": "Update a warehouse",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Warehouse ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update warehouse request",
+                        "name": "updateWarehouseRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/http.updateWarehouseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Warehouse updated",
+                        "schema": {
+                            "$ref": "#/definitions/http.warehouseResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Data conflict error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/products": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List products with pagination as ListProducts does, with snake_case keys.\nFields selects the fields represented, the id is always represented. A list only reads the columns the fields need.\nInclude embeds related objects: category, supplier, and warehouses for the stock of the product per warehouse.\nThe products are listed newest first unless sorted otherwise. With a cursor, the pages follow each other through the next_cursor of the previous page instead of skip\nWith ids, the products with those comma separated ids are returned in their order with the ids matching no product, and the other filters are ignored.\nProducts got by ids are read whole whatever the fields, as they are cached whole",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "List products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields: id, reference, name, added_date, status, category_id, price, effective_price, stock_city, supplier_id, quantity, distance_km, deleted_at. All of them by default",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated relations: category, supplier, warehouses",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated IDs of the products to get, up to 100",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "Category IDs",
+                        "name": "category_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "csv",
+                        "description": "IDs of warehouses holding stock of the products",
+                        "name": "warehouse_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Query",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft deleted products",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Origin as latitude,longitude, or ip for the location of the client",
+                        "name": "near",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Keep the products within that distance of the origin",
+                        "name": "radius_km",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields among id, reference, name, added_date, status, price, quantity, stock_city and distance, each descending when prefixed with -. Newest first by default",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to convert the effective price to, when it is selected",
+                        "name": "currency",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Page with a cursor instead of skip, empty for the first page then the next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Skip",
+                        "name": "skip",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Products retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.meta"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unknown client location error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/products/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a product by id with snake_case keys. Fields selects the fields represented, the id is always represented.\nThe product is read whole whatever the fields, as it is cached whole.\nInclude embeds related objects: category, supplier, and warehouses for the stock of the product per warehouse",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated fields, all of them by default",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated relations: category, supplier, warehouses",
+                        "name": "include",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to convert the effective price to, when it is selected",
+                        "name": "currency",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product retrieved",
+                        "schema": {
+                            "$ref": "#/definitions/http.productV2Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Validation error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Data not found error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/http.errorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "http.StatisticCategoryProductResponse": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "number"
+                }
+            }
+        },
+        "http.StatisticSupplierProductResponse": {
+            "type": "object",
+            "properties": {
+                "percentage": {
+                    "type": "number"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "supplier_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.auditEntryResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "actor": {
+                    "type": "string",
+                    "example": "anonymous"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.fieldChangeResponse"
+                    }
+                },
+                "claimed_actor": {
+                    "description": "ClaimedActor is the actor named by the client in the X-Actor header, which is not verified",
+                    "type": "string",
+                    "example": "jane"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "0b5e6e3c-8f4d-4a59-9f0c-2f1d6c7b8a90"
+                }
+            }
+        },
+        "http.batchGetProductsRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "ids": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "6b7a4b0e-3f0a-4c8e-9a51-0e4b8b2f6d2a"
+                    ]
+                }
+            }
+        },
+        "http.batchGetProductsResponse": {
+            "type": "object",
+            "properties": {
+                "missing_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "6b7a4b0e-3f0a-4c8e-9a51-0e4b8b2f6d2a"
+                    ]
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.productResponse"
+                    }
+                }
+            }
+        },
+        "http.batchProductOperationRequest": {
+            "type": "object",
+            "required": [
+                "method"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "string",
+                    "example": "2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "0b5e6e3c-8f4d-4a59-9f0c-2f1d6c7b8a90"
+                },
+                "method": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update",
+                        "delete"
+                    ],
+                    "example": "update"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Steel water bottle"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "12.50"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 200
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "SKU-0001"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "Available",
+                        "On Order",
+                        "Out of Stock"
+                    ],
+                    "example": "Available"
+                },
+                "stock_city": {
+                    "type": "string",
+                    "example": "Hanoi"
+                },
+                "supplier_id": {
+                    "type": "string",
+                    "example": "6f1f7b0e-3c2a-4d5e-8f9a-0b1c2d3e4f5a"
+                }
+            }
+        },
+        "http.batchProductResultResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "data not found"
+                    ]
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "product": {
+                    "$ref": "#/definitions/http.productResponse"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 200
+                }
+            }
+        },
+        "http.batchProductsRequest": {
+            "type": "object",
+            "required": [
+                "operations"
+            ],
+            "properties": {
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "atomic",
+                        "best_effort"
+                    ],
+                    "example": "atomic"
+                },
+                "operations": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/http.batchProductOperationRequest"
+                    }
+                }
+            }
+        },
+        "http.batchProductsResponse": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "atomic"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.batchProductResultResponse"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "http.categoryResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                },
+                "deleted_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Foods"
+                }
+            }
+        },
+        "http.createCategoryRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "Foods"
+                }
+            }
+        },
+        "http.createExportRequest": {
+            "type": "object",
+            "required": [
+                "format"
+            ],
+            "properties": {
+                "bom": {
+                    "type": "boolean"
+                },
+                "category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "reference",
+                        "name",
+                        "price"
+                    ]
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "delimiter": {
+                    "type": "string",
+                    "example": ";"
+                },
+                "format": {
+                    "type": "string",
+                    "enum": [
+                        "pdf",
+                        "csv",
+                        "xlsx"
+                    ],
+                    "example": "csv"
+                },
+                "group": {
+                    "type": "string",
+                    "enum": [
+                        "category",
+                        "none"
+                    ]
+                },
+                "include_deleted": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "near": {
+                    "type": "string",
+                    "example": "21.0285,105.8542"
+                },
+                "q": {
+                    "type": "string"
+                },
+                "radius_km": {
+                    "type": "number"
+                },
+                "sheets": {
+                    "type": "string",
+                    "enum": [
+                        "category",
+                        "single"
+                    ]
+                },
+                "skip": {
+                    "type": "integer"
+                },
+                "sort": {
+                    "type": "string",
+                    "example": "-added_date,name"
+                },
+                "warehouse_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "http.createProductRequest": {
+            "type": "object",
+            "properties": {
+                "categoryID": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "12.50"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stockCity": {
+                    "type": "string"
+                },
+                "supplierID": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.createWarehouseRequest": {
+            "type": "object",
+            "required": [
+                "city",
+                "name"
+            ],
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Hanoi"
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": 21.0285
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": 105.8542
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Hanoi North"
+                }
+            }
+        },
+        "http.errorResponse": {
+            "type": "object",
+            "properties": {
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Error message 1",
+                        " Error message 2"
+                    ]
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "http.exchangeRateResponse": {
+            "type": "object",
+            "properties": {
+                "base": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "rate": {
+                    "type": "string",
+                    "example": "0.92"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                }
+            }
+        },
+        "http.exportResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                },
+                "download_url": {
+                    "type": "string",
+                    "example": "/v1/exports/2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10/download"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "1970-01-02T00:00:00Z"
+                },
+                "finished_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                },
+                "format": {
+                    "type": "string",
+                    "example": "csv"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 1200
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 524288
+                },
+                "started_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                },
+                "state": {
+                    "type": "string",
+                    "example": "running"
+                }
+            }
+        },
+        "http.fieldChangeResponse": {
+            "type": "object",
+            "properties": {
+                "after": {},
+                "before": {},
+                "field": {
+                    "type": "string",
+                    "example": "price"
+                }
+            }
+        },
+        "http.importReportResponse": {
+            "type": "object",
+            "properties": {
+                "committed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "created": {
+                    "type": "integer",
+                    "example": 100
+                },
+                "dry_run": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.importRowErrorResponse"
+                    }
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "rows": {
+                    "type": "integer",
+                    "example": 120
+                },
+                "unchanged": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "updated": {
+                    "type": "integer",
+                    "example": 15
+                }
+            }
+        },
+        "http.importRowErrorResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "price: must not be negative"
+                    ]
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "SKU-0001"
+                }
+            }
+        },
+        "http.meta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "next_cursor": {
+                    "description": "NextCursor is the cursor of the next page of a list paged with a cursor, empty on the last page",
+                    "type": "string",
+                    "example": "eyJzb3J0IjoiIiwidmFsdWVzIjpbXX0"
+                },
+                "skip": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 100
+                }
+            }
+        },
+        "http.moneyResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "12.50"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                }
+            }
+        },
+        "http.postStockMovementsRequest": {
+            "type": "object",
+            "required": [
+                "movements"
+            ],
+            "properties": {
+                "movements": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/http.stockMovementRequest"
+                    }
+                }
+            }
+        },
+        "http.priceChangeResponse": {
+            "type": "object",
+            "properties": {
+                "applied_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                },
+                "ends_at": {
+                    "type": "string",
+                    "example": "2030-01-31T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"
+                },
+                "price": {
+                    "$ref": "#/definitions/http.moneyResponse"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "0b5e6e3c-8f4d-4a59-9f0c-2f1d6c7b8a90"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "scheduled"
+                }
+            }
+        },
+        "http.productAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "latitude": {
+                    "type": "number",
+                    "example": 21.0285
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 105.8542
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"
+                },
+                "total": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "warehouses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.warehouseAvailabilityResponse"
+                    }
+                }
+            }
+        },
+        "http.productResponse": {
+            "type": "object",
+            "properties": {
+                "addedDate": {
+                    "type": "string"
+                },
+                "category": {
+                    "$ref": "#/definitions/http.categoryResponse"
+                },
+                "categoryID": {
+                    "type": "string"
+                },
+                "converted_price": {
+                    "description": "ConvertedPrice is the effective price converted to the requested currency",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.moneyResponse"
+                        }
+                    ]
+                },
+                "currency": {
+                    "description": "Currency is the currency of both the price and the effective price",
+                    "type": "string",
+                    "example": "USD"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "distance_km": {
+                    "description": "DistanceKM is the distance from the origin of a listing near a location",
+                    "type": "number"
+                },
+                "effectivePrice": {
+                    "description": "EffectivePrice is the price the product currently sells at, including a running promotion",
+                    "type": "number",
+                    "example": 12.5
+                },
+                "id": {
+                    "type": "string",
+                    "example": "1"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number",
+                    "example": 12.5
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "stock": {
+                    "description": "Stock is the availability of the product, in total and per warehouse",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/http.stockResponse"
+                        }
+                    ]
+                },
+                "stockCity": {
+                    "type": "string"
+                },
+                "supplierID": {
+                    "type": "string"
+                }
+            }
+        },
+        "http.productV2Response": {
+            "type": "object",
+            "properties": {
+                "added_date": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                },
+                "category": {
+                    "$ref": "#/definitions/http.categoryResponse"
+                },
                 "category_id": {
                     "type": "string"
                 },
-                "category_name": {
+                "converted_price": {
+                    "$ref": "#/definitions/http.moneyResponse"
+                },
+                "deleted_at": {
                     "type": "string"
                 },
-                "percentage": {
+                "distance_km": {
                     "type": "number"
+                },
+                "effective_price": {
+                    "$ref": "#/definitions/http.moneyResponse"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "6b7a4b0e-3f0a-4c8e-9a51-0e4b8b2f6d2a"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Chiko Chicken"
+                },
+                "price": {
+                    "$ref": "#/definitions/http.moneyResponse"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "reference": {
+                    "type": "string",
+                    "example": "PRD-0001"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "Available"
+                },
+                "stock_city": {
+                    "type": "string",
+                    "example": "Hanoi"
+                },
+                "supplier": {
+                    "$ref": "#/definitions/http.supplierResponse"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "warehouses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.warehouseStockResponse"
+                    }
                 }
             }
         },
-        "http.StatisticSupplierProductResponse": {
+        "http.response": {
             "type": "object",
             "properties": {
-                "percentage": {
-                    "type": "number"
-                },
-                "supplier_id": {
-                    "type": "string"
+                "data": {},
+                "message": {
+                    "type": "string",
+                    "example": "Success"
                 },
-                "supplier_name": {
-                    "type": "string"
+                "success": {
+                    "type": "boolean",
+                    "example": true
                 }
             }
         },
-        "http.categoryResponse": {
+        "http.schedulePriceRequest": {
             "type": "object",
             "properties": {
-                "id": {
+                "ends_at": {
                     "type": "string",
-                    "example": "1"
+                    "example": "2030-01-31T00:00:00Z"
                 },
-                "name": {
+                "price": {
                     "type": "string",
-                    "example": "Foods"
+                    "example": "1500.00"
+                },
+                "starts_at": {
+                    "type": "string",
+                    "example": "2030-01-01T00:00:00Z"
                 }
             }
         },
-        "http.createCategoryRequest": {
+        "http.setExchangeRateRequest": {
+            "type": "object",
+            "properties": {
+                "rate": {
+                    "type": "string",
+                    "example": "0.92"
+                }
+            }
+        },
+        "http.stockMovementRequest": {
             "type": "object",
             "required": [
-                "name"
+                "product_id",
+                "quantity",
+                "type"
             ],
             "properties": {
-                "name": {
+                "product_id": {
                     "type": "string",
-                    "example": "Foods"
+                    "example": "2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Purchase order 1042"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "receipt",
+                        "sale",
+                        "return",
+                        "adjustment",
+                        "transfer"
+                    ],
+                    "example": "receipt"
+                },
+                "warehouse_id": {
+                    "type": "string",
+                    "example": "8c2f5d1e-3b7a-4f9c-a1d2-6e4b0c9f7a35"
                 }
             }
         },
-        "http.createProductRequest": {
+        "http.stockMovementResponse": {
             "type": "object",
             "properties": {
-                "categoryID": {
-                    "type": "string"
+                "actor": {
+                    "type": "string",
+                    "example": "anonymous"
                 },
-                "id": {
-                    "type": "string"
+                "balance": {
+                    "type": "integer",
+                    "example": 8
                 },
-                "name": {
-                    "type": "string"
+                "created_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
                 },
-                "price": {
-                    "type": "number"
+                "id": {
+                    "type": "string",
+                    "example": "2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"
+                },
+                "product_id": {
+                    "type": "string",
+                    "example": "8c2f5d1e-3b7a-4f9c-a1d2-6e4b0c9f7a35"
                 },
                 "quantity": {
-                    "type": "integer"
+                    "type": "integer",
+                    "example": -2
                 },
-                "reference": {
-                    "type": "string"
+                "reason": {
+                    "type": "string",
+                    "example": "Order 1042"
                 },
-                "status": {
-                    "type": "string"
+                "request_id": {
+                    "type": "string",
+                    "example": "0b5e6e3c-8f4d-4a59-9f0c-2f1d6c7b8a90"
                 },
-                "stockCity": {
-                    "type": "string"
+                "transfer_id": {
+                    "type": "string",
+                    "example": "6f1e2d3c-4b5a-4c8d-9e3f-2a1c8c3e7e4b"
                 },
-                "supplierID": {
-                    "type": "string"
-                }
-            }
-        },
-        "http.errorResponse": {
-            "type": "object",
-            "properties": {
-                "messages": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    },
-                    "example": [
-                        "Error message 1",
-                        " Error message 2"
-                    ]
+                "type": {
+                    "type": "string",
+                    "example": "sale"
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": false
+                "warehouse_id": {
+                    "type": "string",
+                    "example": "4d7b1a2c-9e3f-4c8d-b6a5-1f0e2d3c4b5a"
                 }
             }
         },
-        "http.meta": {
+        "http.stockResponse": {
             "type": "object",
             "properties": {
-                "limit": {
+                "total": {
                     "type": "integer",
-                    "example": 10
+                    "example": 12
                 },
-                "skip": {
+                "unassigned": {
                     "type": "integer",
                     "example": 0
                 },
-                "total": {
-                    "type": "integer",
-                    "example": 100
+                "warehouses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/http.warehouseStockResponse"
+                    }
                 }
             }
         },
-        "http.productResponse": {
+        "http.supplierResponse": {
             "type": "object",
             "properties": {
-                "addedDate": {
-                    "type": "string"
-                },
-                "category": {
-                    "$ref": "#/definitions/http.categoryResponse"
-                },
-                "categoryID": {
-                    "type": "string"
-                },
                 "id": {
                     "type": "string",
-                    "example": "1"
+                    "example": "4f1c2b7e-9d3a-4e6b-8a5c-1f2e3d4c5b6a"
                 },
                 "name": {
-                    "type": "string"
-                },
-                "price": {
-                    "type": "number"
-                },
-                "quantity": {
-                    "type": "integer"
-                },
-                "reference": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
-                },
-                "stockCity": {
-                    "type": "string"
-                },
-                "supplierID": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "Acme"
                 }
             }
         },
-        "http.response": {
+        "http.transferStockRequest": {
             "type": "object",
+            "required": [
+                "from_warehouse_id",
+                "product_id",
+                "quantity",
+                "to_warehouse_id"
+            ],
             "properties": {
-                "data": {},
-                "message": {
+                "from_warehouse_id": {
                     "type": "string",
-                    "example": "Success"
+                    "example": "8c2f5d1e-3b7a-4f9c-a1d2-6e4b0c9f7a35"
                 },
-                "success": {
-                    "type": "boolean",
-                    "example": true
+                "product_id": {
+                    "type": "string",
+                    "example": "2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "minimum": 1,
+                    "example": 5
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "Rebalancing for the sale"
+                },
+                "to_warehouse_id": {
+                    "type": "string",
+                    "example": "4d7b1a2c-9e3f-4c8d-b6a5-1f0e2d3c4b5a"
                 }
             }
         },
@@ -1149,20 +4176,17 @@ const docTemplate = `{
                     "type": "string"
                 },
                 "price": {
-                    "type": "number",
-                    "minimum": 0,
-                    "example": 2000
+                    "type": "string",
+                    "example": "2000.00"
                 },
                 "status": {
                     "type": "string",
                     "enum": [
                         "Available",
-                        "On",
-                        "Order",
-                        "Out",
-                        "of",
-                        "Stock"
-                    ]
+                        "On Order",
+                        "Out of Stock"
+                    ],
+                    "example": "Available"
                 },
                 "stock": {
                     "type": "integer",
@@ -1170,6 +4194,114 @@ const docTemplate = `{
                     "example": 200
                 }
             }
+        },
+        "http.updateWarehouseRequest": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Hanoi"
+                },
+                "latitude": {
+                    "type": "number",
+                    "maximum": 90,
+                    "minimum": -90,
+                    "example": 21.0285
+                },
+                "longitude": {
+                    "type": "number",
+                    "maximum": 180,
+                    "minimum": -180,
+                    "example": 105.8542
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Hanoi North"
+                }
+            }
+        },
+        "http.warehouseAvailabilityResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Hanoi"
+                },
+                "distance_km": {
+                    "type": "number",
+                    "example": 1.5
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 21.0285
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 105.8542
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Hanoi North"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "warehouse_id": {
+                    "type": "string",
+                    "example": "8c2f5d1e-3b7a-4f9c-a1d2-6e4b0c9f7a35"
+                }
+            }
+        },
+        "http.warehouseResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Hanoi"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "1970-01-01T00:00:00Z"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"
+                },
+                "latitude": {
+                    "type": "number",
+                    "example": 21.0285
+                },
+                "longitude": {
+                    "type": "number",
+                    "example": 105.8542
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Hanoi North"
+                }
+            }
+        },
+        "http.warehouseStockResponse": {
+            "type": "object",
+            "properties": {
+                "city": {
+                    "type": "string",
+                    "example": "Hanoi"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Hanoi North"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "warehouse_id": {
+                    "type": "string",
+                    "example": "8c2f5d1e-3b7a-4f9c-a1d2-6e4b0c9f7a35"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -1186,7 +4318,7 @@ const docTemplate = `{
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
 	Host:             "localhost:8080",
-	BasePath:         "/v1",
+	BasePath:         "/",
 	Schemes:          []string{"http", "https"},
 	Title:            "Go SOA Test (Source of Asia) API",
 	Description:      "This is a simple RESTful Product Backend Service API written in Go using Gin web framework, MySQL database",
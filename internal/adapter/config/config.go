@@ -1,7 +1,9 @@
 package config
 
 import (
+	"fmt"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -10,12 +12,16 @@ import (
 // Container contains environment variables for the application, database, cache, token, and http server
 type (
 	Container struct {
-		App   *App
-		Token *Token
-		Redis *Redis
-		DB    *DB
-		GEO   *GEO
-		HTTP  *HTTP
+		App      *App
+		Token    *Token
+		Redis    *Redis
+		DB       *DB
+		GEO      *GEO
+		HTTP     *HTTP
+		Worker   *Worker
+		Currency *Currency
+		Report   *Report
+		Export   *Export
 	}
 	// App contains all the environment variables for the application
 	App struct {
@@ -42,9 +48,25 @@ type (
 		MaxIdleConns int
 		MaxOpenConns int
 		MaxLifetime  time.Duration
+		MaxIdleTime  time.Duration
+		SSLMode      string
+		SSLRootCert  string
+		SSLCert      string
+		SSLKey       string
+		// ReplicaURL is the optional DSN of a read replica used for list, search, statistic and export queries
+		ReplicaURL string
 	}
 	GEO struct {
-		APIKey string
+		Providers       string
+		APIKey          string
+		GazetteerFile   string
+		DefaultCountry  string
+		MMDBFile        string
+		Timeout         time.Duration
+		Retries         int
+		BreakerFailures int
+		BreakerCooldown time.Duration
+		CacheTTL        time.Duration
 	}
 	// HTTP contains all the environment variables for the http server
 	HTTP struct {
@@ -54,6 +76,36 @@ type (
 		Port           string
 		AllowedOrigins string
 	}
+	// Worker contains all the environment variables for the background workers
+	Worker struct {
+		// PriceInterval is how often scheduled prices that started replace the base price of their product
+		PriceInterval time.Duration
+	}
+	// Currency contains all the environment variables for the price conversion
+	Currency struct {
+		// RatesFile is an optional CSV or JSON file of exchange rates imported at startup
+		RatesFile string
+		// RoundingMode is one of half_up, half_even, up or down
+		RoundingMode string
+		// DecimalPlaces overrides the decimal places of some currencies, such as "JPY:0,VND:0"
+		DecimalPlaces string
+	}
+	// Report contains all the environment variables for the PDF reports
+	Report struct {
+		// TemplateFile is an optional JSON file laying out the product report, over the default layout
+		TemplateFile string
+	}
+	// Export contains all the environment variables for the export jobs
+	Export struct {
+		// Dir is the directory the files of the export jobs are written to
+		Dir string
+		// Workers is the number of export jobs run at once
+		Workers int
+		// Retention is how long the file of a finished export job is kept
+		Retention time.Duration
+		// PollInterval is how often an idle worker looks for a pending export job
+		PollInterval time.Duration
+	}
 )
 
 // New creates a new container instance
@@ -79,17 +131,80 @@ func New() (*Container, error) {
 		Password: os.Getenv("REDIS_PASSWORD"),
 	}
 
+	maxIdleConns, err := getEnvInt("DB_MAX_IDLE_CONNS")
+	if err != nil {
+		return nil, err
+	}
+
+	maxOpenConns, err := getEnvInt("DB_MAX_OPEN_CONNS")
+	if err != nil {
+		return nil, err
+	}
+
+	maxLifetime, err := getEnvDuration("DB_MAX_LIFETIME")
+	if err != nil {
+		return nil, err
+	}
+
+	maxIdleTime, err := getEnvDuration("DB_MAX_IDLE_TIME")
+	if err != nil {
+		return nil, err
+	}
+
 	db := &DB{
-		Connection: os.Getenv("DB_CONNECTION"),
-		Host:       os.Getenv("DB_HOST"),
-		Port:       os.Getenv("DB_PORT"),
-		User:       os.Getenv("DB_USER"),
-		Password:   os.Getenv("DB_PASSWORD"),
-		Name:       os.Getenv("DB_NAME"),
+		Connection:   os.Getenv("DB_CONNECTION"),
+		Host:         os.Getenv("DB_HOST"),
+		Port:         os.Getenv("DB_PORT"),
+		User:         os.Getenv("DB_USER"),
+		Password:     os.Getenv("DB_PASSWORD"),
+		Name:         os.Getenv("DB_NAME"),
+		MaxIdleConns: maxIdleConns,
+		MaxOpenConns: maxOpenConns,
+		MaxLifetime:  maxLifetime,
+		MaxIdleTime:  maxIdleTime,
+		SSLMode:      os.Getenv("DB_SSL_MODE"),
+		SSLRootCert:  os.Getenv("DB_SSL_ROOT_CERT"),
+		SSLCert:      os.Getenv("DB_SSL_CERT"),
+		SSLKey:       os.Getenv("DB_SSL_KEY"),
+		ReplicaURL:   os.Getenv("DB_REPLICA_URL"),
+	}
+
+	geoTimeout, err := getEnvDuration("GEO_TIMEOUT")
+	if err != nil {
+		return nil, err
+	}
+
+	geoRetries, err := getEnvInt("GEO_RETRIES")
+	if err != nil {
+		return nil, err
+	}
+
+	geoBreakerFailures, err := getEnvInt("GEO_BREAKER_FAILURES")
+	if err != nil {
+		return nil, err
+	}
+
+	geoBreakerCooldown, err := getEnvDuration("GEO_BREAKER_COOLDOWN")
+	if err != nil {
+		return nil, err
+	}
+
+	geoCacheTTL, err := getEnvDuration("GEO_CACHE_TTL")
+	if err != nil {
+		return nil, err
 	}
 
 	geo := &GEO{
-		APIKey: os.Getenv("GEO_API_KEY"),
+		Providers:       os.Getenv("GEO_PROVIDERS"),
+		APIKey:          os.Getenv("GEO_API_KEY"),
+		GazetteerFile:   os.Getenv("GEO_GAZETTEER_FILE"),
+		DefaultCountry:  os.Getenv("GEO_DEFAULT_COUNTRY"),
+		MMDBFile:        os.Getenv("GEO_MMDB_FILE"),
+		Timeout:         geoTimeout,
+		Retries:         geoRetries,
+		BreakerFailures: geoBreakerFailures,
+		BreakerCooldown: geoBreakerCooldown,
+		CacheTTL:        geoCacheTTL,
 	}
 
 	http := &HTTP{
@@ -100,6 +215,47 @@ func New() (*Container, error) {
 		AllowedOrigins: os.Getenv("HTTP_ALLOWED_ORIGINS"),
 	}
 
+	priceInterval, err := getEnvDuration("WORKER_PRICE_INTERVAL")
+	if err != nil {
+		return nil, err
+	}
+
+	worker := &Worker{
+		PriceInterval: priceInterval,
+	}
+
+	currency := &Currency{
+		RatesFile:     os.Getenv("CURRENCY_RATES_FILE"),
+		RoundingMode:  os.Getenv("CURRENCY_ROUNDING_MODE"),
+		DecimalPlaces: os.Getenv("CURRENCY_DECIMAL_PLACES"),
+	}
+
+	report := &Report{
+		TemplateFile: os.Getenv("REPORT_TEMPLATE_FILE"),
+	}
+
+	exportWorkers, err := getEnvInt("EXPORT_WORKERS")
+	if err != nil {
+		return nil, err
+	}
+
+	exportRetention, err := getEnvDuration("EXPORT_RETENTION")
+	if err != nil {
+		return nil, err
+	}
+
+	exportPollInterval, err := getEnvDuration("EXPORT_POLL_INTERVAL")
+	if err != nil {
+		return nil, err
+	}
+
+	export := &Export{
+		Dir:          os.Getenv("EXPORT_DIR"),
+		Workers:      exportWorkers,
+		Retention:    exportRetention,
+		PollInterval: exportPollInterval,
+	}
+
 	return &Container{
 		app,
 		token,
@@ -107,5 +263,39 @@ func New() (*Container, error) {
 		db,
 		geo,
 		http,
+		worker,
+		currency,
+		report,
+		export,
 	}, nil
 }
+
+// getEnvInt reads an integer environment variable, an unset variable is read as zero
+func getEnvInt(key string) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return number, nil
+}
+
+// getEnvDuration reads a duration environment variable such as "30m", an unset variable is read as zero
+func getEnvDuration(key string) (time.Duration, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,130 @@
+package exchangerate
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/shopspring/decimal"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+var (
+	// errUnsupportedFormat is returned for files that are neither CSV nor JSON
+	errUnsupportedFormat = errors.New("exchange rate file must be a .csv or .json file")
+	// errDuplicateCurrency is returned for files giving several rates of a currency
+	errDuplicateCurrency = errors.New("duplicate currency")
+)
+
+// LoadFile reads the exchange rates against domain.DefaultCurrency from a CSV or JSON file.
+// A CSV file holds "currency,rate" rows with an optional header, a JSON file holds
+// either an object such as {"EUR": "0.92"} or a list such as [{"currency": "EUR", "rate": "0.92"}].
+// A currency given several times, whatever its case, fails the whole file
+func LoadFile(path string) ([]domain.ExchangeRate, error) {
+	var parse func(io.Reader) ([]domain.ExchangeRate, error)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		parse = parseCSV
+	case ".json":
+		parse = parseJSON
+	default:
+		return nil, errUnsupportedFormat
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rates, err := parse(file)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(rates))
+	for _, rate := range rates {
+		currency := strings.ToUpper(rate.Currency)
+		if seen[currency] {
+			return nil, fmt.Errorf("%w %q", errDuplicateCurrency, rate.Currency)
+		}
+		seen[currency] = true
+	}
+
+	return rates, nil
+}
+
+// parseCSV reads "currency,rate" rows, skipping a header row
+func parseCSV(r io.Reader) ([]domain.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var rates []domain.ExchangeRate
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(record[0], "currency") {
+			continue
+		}
+
+		rate, err := newExchangeRate(record[0], record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+// parseJSON reads either a currency to rate object or a list of currency and rate objects
+func parseJSON(r io.Reader) ([]domain.ExchangeRate, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []struct {
+		Currency string          `json:"currency"`
+		Rate     decimal.Decimal `json:"rate"`
+	}
+	if err := json.Unmarshal(data, &list); err == nil {
+		rates := make([]domain.ExchangeRate, len(list))
+		for i, item := range list {
+			rates[i] = domain.ExchangeRate{Currency: item.Currency, Rate: item.Rate}
+		}
+		return rates, nil
+	}
+
+	var object map[string]decimal.Decimal
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	rates := make([]domain.ExchangeRate, 0, len(object))
+	for currency, rate := range object {
+		rates = append(rates, domain.ExchangeRate{Currency: currency, Rate: rate})
+	}
+
+	return rates, nil
+}
+
+// newExchangeRate creates an exchange rate from its text fields
+func newExchangeRate(currency, rate string) (domain.ExchangeRate, error) {
+	value, err := decimal.NewFromString(strings.TrimSpace(rate))
+	if err != nil {
+		return domain.ExchangeRate{}, fmt.Errorf("invalid rate %q", rate)
+	}
+
+	return domain.ExchangeRate{Currency: strings.TrimSpace(currency), Rate: value}, nil
+}
@@ -0,0 +1,122 @@
+package exchangerate
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLoadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		// want are the rates as sorted "currency rate" pairs
+		want    []string
+		wantErr bool
+		// errIs is the error wrapped by the failure, nil when any error is expected
+		errIs error
+	}{
+		{
+			name:    "csv with header",
+			file:    "rates.csv",
+			content: "currency,rate\nEUR, 0.92\nvnd,25400\n",
+			want:    []string{"EUR 0.92", "vnd 25400"},
+		},
+		{
+			name:    "csv without header",
+			file:    "rates.CSV",
+			content: "EUR,0.92\n",
+			want:    []string{"EUR 0.92"},
+		},
+		{
+			name:    "json object",
+			file:    "rates.json",
+			content: `{"VND": "25400", "EUR": 0.92}`,
+			want:    []string{"EUR 0.92", "VND 25400"},
+		},
+		{
+			name:    "json list",
+			file:    "rates.json",
+			content: `[{"currency": "EUR", "rate": "0.92"}]`,
+			want:    []string{"EUR 0.92"},
+		},
+		{
+			name:    "csv row missing the rate",
+			file:    "rates.csv",
+			content: "EUR,0.92\nVND\n",
+			wantErr: true,
+		},
+		{
+			name:    "csv row with an invalid rate",
+			file:    "rates.csv",
+			content: "EUR,0.92\nVND,many\n",
+			wantErr: true,
+		},
+		{
+			name:    "json rate not a number",
+			file:    "rates.json",
+			content: `{"EUR": "abc"}`,
+			wantErr: true,
+		},
+		{
+			name:    "duplicate currency in csv",
+			file:    "rates.csv",
+			content: "EUR,0.92\neur,0.93\n",
+			wantErr: true,
+			errIs:   errDuplicateCurrency,
+		},
+		{
+			name:    "duplicate currency in json list",
+			file:    "rates.json",
+			content: `[{"currency": "EUR", "rate": "0.92"}, {"currency": "EUR", "rate": "0.93"}]`,
+			wantErr: true,
+			errIs:   errDuplicateCurrency,
+		},
+		{
+			name:    "unsupported format",
+			file:    "rates.txt",
+			content: "EUR,0.92\n",
+			wantErr: true,
+			errIs:   errUnsupportedFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			rates, err := LoadFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Fatalf("LoadFile() error = %v, want %v", err, tt.errIs)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got := make([]string, len(rates))
+			for i, rate := range rates {
+				got[i] = rate.Currency + " " + rate.Rate.String()
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("LoadFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	_, err := LoadFile(filepath.Join(t.TempDir(), "rates.csv"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadFile() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
@@ -0,0 +1,163 @@
+package export
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// uncategorizedGroup names the group of the products without a category, in a workbook or a report
+const uncategorizedGroup = "Uncategorized"
+
+// productExportRow is a product being exported, with its effective price converted to the requested currency
+type productExportRow struct {
+	product   *domain.Product
+	converted *domain.Money
+}
+
+// newProductExportRow creates the row of a product, converting its effective price when a converter is given
+func newProductExportRow(product *domain.Product, converter *domain.CurrencyConverter) (productExportRow, error) {
+	row := productExportRow{product: product}
+	if converter != nil {
+		converted, err := converter.Convert(product.EffectivePrice)
+		if err != nil {
+			return row, err
+		}
+		row.converted = &converted
+	}
+
+	return row, nil
+}
+
+// productExportColumn is a column of a product export
+type productExportColumn struct {
+	name  string
+	value func(row productExportRow) string
+}
+
+// productExportColumns lists the columns a product export can hold, in their default order
+var productExportColumns = []productExportColumn{
+	{"id", func(row productExportRow) string { return row.product.ID.String() }},
+	{"reference", func(row productExportRow) string { return row.product.Reference }},
+	{"name", func(row productExportRow) string { return row.product.Name }},
+	{"added_date", func(row productExportRow) string { return row.product.AddedDate.Format(time.RFC3339) }},
+	{"status", func(row productExportRow) string { return row.product.Status.String() }},
+	{"category_id", func(row productExportRow) string { return formatOptional(row.product.CategoryID) }},
+	{"category", func(row productExportRow) string {
+		if row.product.Category == nil {
+			return ""
+		}
+		return row.product.Category.Name
+	}},
+	{"price", func(row productExportRow) string { return row.product.Price.StringFixed() }},
+	{"effective_price", func(row productExportRow) string { return row.product.EffectivePrice.StringFixed() }},
+	{"currency", func(row productExportRow) string { return row.product.Price.Currency }},
+	{"converted_price", func(row productExportRow) string {
+		if row.converted == nil {
+			return ""
+		}
+		return row.converted.StringFixed()
+	}},
+	{"converted_currency", func(row productExportRow) string {
+		if row.converted == nil {
+			return ""
+		}
+		return row.converted.Currency
+	}},
+	{"stock_city", func(row productExportRow) string { return row.product.StockCity }},
+	{"supplier_id", func(row productExportRow) string { return formatOptional(row.product.SupplierID) }},
+	{"quantity", func(row productExportRow) string { return strconv.Itoa(row.product.Quantity) }},
+	{"stock_value", func(row productExportRow) string { return stockValue(row.product).StringFixed() }},
+	{"distance_km", func(row productExportRow) string {
+		if row.product.DistanceKM == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*row.product.DistanceKM, 'f', 1, 64)
+	}},
+	{"deleted_at", func(row productExportRow) string {
+		if row.product.DeletedAt == nil {
+			return ""
+		}
+		return row.product.DeletedAt.Format(time.RFC3339)
+	}},
+}
+
+// stockValue returns the value of the stock of a product at its effective price
+func stockValue(product *domain.Product) domain.Money {
+	return product.EffectivePrice.Mul(decimal.NewFromInt(int64(product.Quantity)))
+}
+
+// defaultProductExportColumns are the columns exported when none are requested
+var defaultProductExportColumns = []string{"id", "reference", "name", "added_date", "status", "category", "price", "effective_price", "currency", "stock_city", "quantity"}
+
+// formatOptional formats an optional value, nil is empty
+func formatOptional[T fmt.Stringer](value *T) string {
+	if value == nil {
+		return ""
+	}
+	return (*value).String()
+}
+
+// selectProductExportColumns returns the requested columns, given as repeated or comma separated names
+func selectProductExportColumns(names []string) ([]productExportColumn, error) {
+	var requested []string
+	for _, name := range names {
+		for _, column := range strings.Split(name, ",") {
+			if column = strings.TrimSpace(column); column != "" {
+				requested = append(requested, column)
+			}
+		}
+	}
+	if len(requested) == 0 {
+		requested = defaultProductExportColumns
+	}
+
+	columns := make([]productExportColumn, 0, len(requested))
+	for _, name := range requested {
+		i := indexProductExportColumn(name)
+		if i < 0 {
+			return nil, fmt.Errorf("%w: unknown column %q", domain.ErrInvalidExportOptions, name)
+		}
+		columns = append(columns, productExportColumns[i])
+	}
+
+	return columns, nil
+}
+
+// indexProductExportColumn returns the index of a column in productExportColumns, or -1
+func indexProductExportColumn(name string) int {
+	for i, column := range productExportColumns {
+		if column.name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// errInvalidDelimiter is returned for a delimiter that can not separate CSV fields
+var errInvalidDelimiter = fmt.Errorf("%w: delimiter must be a single character other than a quote or a line break, tab or semicolon", domain.ErrInvalidExportOptions)
+
+// parseDelimiter parses the delimiter of a CSV export, a single character or the name "tab" or "semicolon", a comma by default
+func parseDelimiter(delimiter string) (rune, error) {
+	switch delimiter {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	case "semicolon":
+		// A raw semicolon must be escaped as %3B in a query string
+		return ';', nil
+	}
+
+	r, size := utf8.DecodeRuneInString(delimiter)
+	if size != len(delimiter) || r == utf8.RuneError || r == '"' || r == '\r' || r == '\n' {
+		return 0, errInvalidDelimiter
+	}
+
+	return r, nil
+}
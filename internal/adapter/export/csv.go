@@ -0,0 +1,85 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"io"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// csvFlushRows is the number of rows written between two flushes of a CSV export
+const csvFlushRows = 500
+
+// exportCSV streams all the products matching the filter as a CSV file, flushed every csvFlushRows products
+func (e *Exporter) exportCSV(ctx context.Context, w io.Writer, filter domain.ProductFilter, options domain.ExportOptions, converter *domain.CurrencyConverter, progress func(rows int64)) error {
+	columns, err := selectProductExportColumns(options.Columns)
+	if err != nil {
+		return err
+	}
+
+	delimiter, err := parseDelimiter(options.Delimiter)
+	if err != nil {
+		return err
+	}
+
+	// The byte order mark and the header are buffered along with the first rows
+	buffer := bufio.NewWriter(w)
+	if options.BOM {
+		if _, err := buffer.WriteString("\uFEFF"); err != nil {
+			return err
+		}
+	}
+
+	writer := csv.NewWriter(buffer)
+	writer.Comma = delimiter
+
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.name
+	}
+	if err := writer.Write(record); err != nil {
+		return err
+	}
+
+	var rows int64
+	err = e.svc.ExportProducts(ctx, filter, func(product *domain.Product) error {
+		row, err := newProductExportRow(product, converter)
+		if err != nil {
+			return err
+		}
+
+		for i, column := range columns {
+			record[i] = column.value(row)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+
+		rows++
+		progress(rows)
+		if rows%csvFlushRows == 0 {
+			writer.Flush()
+			if err := writer.Error(); err != nil {
+				return err
+			}
+			if err := buffer.Flush(); err != nil {
+				return err
+			}
+			flush(w)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+
+	return buffer.Flush()
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/report"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// flushRecorder records the lines written to it each time it is flushed, as an http.Flusher
+type flushRecorder struct {
+	bytes.Buffer
+	flushes []int
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes = append(r.flushes, strings.Count(r.String(), "\n"))
+}
+
+func TestExportCSV(t *testing.T) {
+	products := []domain.Product{
+		{ID: uuid.New(), Reference: "R1", Name: `Rice, "jasmine"`, Price: money(t, "12.5", "USD")},
+		{ID: uuid.New(), Reference: "R2", Name: "Tea\nblack", Price: money(t, "4", "EUR")},
+		{ID: uuid.New(), Reference: "R3", Name: "Salt; fine", Price: money(t, "1", "USD")},
+	}
+
+	tests := []struct {
+		name    string
+		options domain.ExportOptions
+		want    string
+	}{
+		{
+			name:    "quoted fields",
+			options: domain.ExportOptions{Columns: []string{"reference,name", "price"}},
+			want:    "reference,name,price\nR1,\"Rice, \"\"jasmine\"\"\",12.50\nR2,\"Tea\nblack\",4.00\nR3,Salt; fine,1.00\n",
+		},
+		{
+			name:    "semicolon delimiter",
+			options: domain.ExportOptions{Columns: []string{"reference", "name"}, Delimiter: "semicolon"},
+			want:    "reference;name\nR1;\"Rice, \"\"jasmine\"\"\"\nR2;\"Tea\nblack\"\nR3;\"Salt; fine\"\n",
+		},
+		{
+			name:    "byte order mark",
+			options: domain.ExportOptions{Columns: []string{"reference"}, BOM: true},
+			want:    "\uFEFFreference\nR1\nR2\nR3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(&fakeProductService{products: products}, nil, nil, report.DefaultTemplate())
+
+			var out bytes.Buffer
+			err := e.ExportProducts(context.Background(), &out, domain.ExportCSV, domain.ProductFilter{}, tt.options, nil)
+			if err != nil {
+				t.Fatalf("ExportProducts() error = %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("ExportProducts() = %q, want %q", got, tt.want)
+			}
+
+			reader := csv.NewReader(strings.NewReader(strings.TrimPrefix(out.String(), "\uFEFF")))
+			reader.Comma, _ = parseDelimiter(tt.options.Delimiter)
+			records, err := reader.ReadAll()
+			if err != nil {
+				t.Fatalf("export read back error = %v", err)
+			}
+			for i, product := range products {
+				if !slices.Contains(records[i+1], product.Reference) || (len(records[i+1]) > 1 && records[i+1][1] != product.Name) {
+					t.Errorf("record %d = %q, want the fields of %q", i+1, records[i+1], product.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestExportCSVStreams(t *testing.T) {
+	products := make([]domain.Product, 2*csvFlushRows+1)
+	for i := range products {
+		products[i] = domain.Product{ID: uuid.New(), Reference: fmt.Sprintf("R%d", i)}
+	}
+	var rows []int64
+	e := New(&fakeProductService{products: products}, nil, nil, report.DefaultTemplate())
+
+	out := &flushRecorder{}
+	err := e.ExportProducts(context.Background(), out, domain.ExportCSV, domain.ProductFilter{}, domain.ExportOptions{Columns: []string{"reference"}}, func(n int64) {
+		rows = append(rows, n)
+	})
+	if err != nil {
+		t.Fatalf("ExportProducts() error = %v", err)
+	}
+
+	// The header is written along with the first products
+	if want := []int{csvFlushRows + 1, 2*csvFlushRows + 1}; !slices.Equal(out.flushes, want) {
+		t.Errorf("lines written at each flush = %v, want %v", out.flushes, want)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != len(products)+1 {
+		t.Errorf("lines written = %d, want %d", lines, len(products)+1)
+	}
+	if len(rows) != len(products) || rows[len(rows)-1] != int64(len(products)) {
+		t.Errorf("progress reported %d times up to %v, want %d", len(rows), rows[len(rows)-1], len(products))
+	}
+}
+
+func TestExportCSVInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options domain.ExportOptions
+	}{
+		{name: "unknown column", options: domain.ExportOptions{Columns: []string{"name,colour"}}},
+		{name: "quote delimiter", options: domain.ExportOptions{Delimiter: `"`}},
+		{name: "several characters delimiter", options: domain.ExportOptions{Delimiter: "||"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(&fakeProductService{products: []domain.Product{{ID: uuid.New()}}}, nil, nil, report.DefaultTemplate())
+
+			var out bytes.Buffer
+			err := e.ExportProducts(context.Background(), &out, domain.ExportCSV, domain.ProductFilter{}, tt.options, nil)
+			if !errors.Is(err, domain.ErrInvalidExportOptions) {
+				t.Errorf("ExportProducts() error = %v, want %v", err, domain.ErrInvalidExportOptions)
+			}
+			if out.Len() != 0 {
+				t.Errorf("ExportProducts() wrote %q, want nothing", out.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/report"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+/**
+ * Exporter implements port.ProductExporter interface
+ * and provides an access to the products written as a CSV file, an Excel workbook or a PDF report
+ */
+type Exporter struct {
+	svc     port.ProductService
+	rateSvc port.ExchangeRateService
+	statSvc port.StatisticService
+	// template lays out the PDF reports
+	template *report.Template
+}
+
+// New creates a new exporter instance
+func New(svc port.ProductService, rateSvc port.ExchangeRateService, statSvc port.StatisticService, template *report.Template) *Exporter {
+	return &Exporter{
+		svc,
+		rateSvc,
+		statSvc,
+		template,
+	}
+}
+
+// ValidateExport checks the columns and the delimiter of an export and that its currency can be converted to
+func (e *Exporter) ValidateExport(ctx context.Context, format domain.ExportFormat, options domain.ExportOptions) error {
+	switch format {
+	case domain.ExportCSV:
+		if _, err := parseDelimiter(options.Delimiter); err != nil {
+			return err
+		}
+		fallthrough
+	case domain.ExportXLSX:
+		if _, err := selectProductExportColumns(options.Columns); err != nil {
+			return err
+		}
+	case domain.ExportPDF:
+	default:
+		return fmt.Errorf("%w: unknown format %q", domain.ErrInvalidExportOptions, format)
+	}
+
+	_, err := e.converter(ctx, options.Currency)
+	return err
+}
+
+// ExportProducts writes the products matching the filter in a format. Nothing is written to w before the first
+// products are, so an error found before them leaves w untouched. When w is an http.Flusher, it is flushed
+// along with the products of a CSV file
+func (e *Exporter) ExportProducts(ctx context.Context, w io.Writer, format domain.ExportFormat, filter domain.ProductFilter, options domain.ExportOptions, progress func(rows int64)) error {
+	if err := e.ValidateExport(ctx, format, options); err != nil {
+		return err
+	}
+
+	converter, err := e.converter(ctx, options.Currency)
+	if err != nil {
+		return err
+	}
+
+	if progress == nil {
+		progress = func(int64) {}
+	}
+
+	switch format {
+	case domain.ExportCSV:
+		return e.exportCSV(ctx, w, filter, options, converter, progress)
+	case domain.ExportXLSX:
+		return e.exportXLSX(ctx, w, filter, options, converter, progress)
+	default:
+		return e.exportPDF(ctx, w, filter, options, converter, progress)
+	}
+}
+
+// converter returns the converter to a currency, or nil when no currency is given
+func (e *Exporter) converter(ctx context.Context, currency string) (*domain.CurrencyConverter, error) {
+	if currency == "" {
+		return nil, nil
+	}
+
+	return e.rateSvc.NewConverter(ctx, currency)
+}
+
+// flush flushes w when it buffers its writes
+func flush(w io.Writer) {
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
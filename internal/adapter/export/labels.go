@@ -0,0 +1,91 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/report"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// maxLabels is the largest number of labels printed at once, and the number printed when no limit is given.
+// The labels are rendered in memory before being written
+const maxLabels = 1000
+
+// ExportLabels exports a page of the products as a PDF sheet of labels in a layout, printed with the font of the
+// report template. The page holds up to maxLabels products, a larger limit is rejected
+func (e *Exporter) ExportLabels(ctx context.Context, w io.Writer, filter domain.ProductFilter, options domain.LabelOptions) error {
+	layout, ok := report.LookupLabelLayout(options.Layout)
+	if !ok {
+		return fmt.Errorf("%w: unknown label layout %q", domain.ErrInvalidExportOptions, options.Layout)
+	}
+
+	symbology := options.Symbology
+	if symbology == "" {
+		symbology = report.Code128
+	}
+	if symbology != report.Code128 && symbology != report.QR {
+		return fmt.Errorf("%w: unknown symbology %q", domain.ErrInvalidExportOptions, symbology)
+	}
+	if options.Encode != "" && options.Encode != "reference" && options.Encode != "id" {
+		return fmt.Errorf("%w: unknown label code %q", domain.ErrInvalidExportOptions, options.Encode)
+	}
+
+	limit := options.Limit
+	if limit == 0 {
+		limit = maxLabels
+	}
+	if limit > maxLabels {
+		return fmt.Errorf("%w: at most %d labels are printed at once", domain.ErrInvalidExportOptions, maxLabels)
+	}
+
+	converter, err := e.converter(ctx, options.Currency)
+	if err != nil {
+		return err
+	}
+
+	products, err := e.svc.ListProducts(ctx, filter, nil, options.Skip, limit)
+	if err != nil {
+		return err
+	}
+
+	labels := make([]report.Label, 0, len(products))
+	for i := range products {
+		product := &products[i]
+		row, err := newProductExportRow(product, converter)
+		if err != nil {
+			return err
+		}
+
+		price := product.EffectivePrice
+		if row.converted != nil {
+			price = *row.converted
+		}
+
+		code := product.Reference
+		if options.Encode == "id" {
+			code = product.ID.String()
+		}
+		if err := report.EncodeLabel(symbology, code); err != nil {
+			return fmt.Errorf("%w: product %s can not be encoded as %s: %v", domain.ErrInvalidExportOptions, product.ID, symbology, err)
+		}
+
+		labels = append(labels, report.Label{
+			Name:      product.Name,
+			Reference: product.Reference,
+			Price:     price.String(),
+			Code:      code,
+		})
+	}
+
+	// The document is only complete once rendered, so it is rendered before being written
+	var buf bytes.Buffer
+	if err := report.RenderLabels(&buf, layout, e.template.Font, symbology, options.Start, labels); err != nil {
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
@@ -0,0 +1,62 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/report"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+// fakeProductService lists a product, recording the page read, and exports its products. Its other methods panic
+type fakeProductService struct {
+	port.ProductService
+	skip, limit uint64
+	products    []domain.Product
+}
+
+func (s *fakeProductService) ExportProducts(_ context.Context, _ domain.ProductFilter, fn func(product *domain.Product) error) error {
+	for i := range s.products {
+		if err := fn(&s.products[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fakeProductService) ListProducts(_ context.Context, _ domain.ProductFilter, _ *domain.ProductView, skip, limit uint64) ([]domain.Product, error) {
+	s.skip, s.limit = skip, limit
+	return []domain.Product{{ID: uuid.New(), Name: "Rice", Reference: "REF-1"}}, nil
+}
+
+func TestExportLabelsLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		limit     uint64
+		wantLimit uint64
+		wantErr   error
+	}{
+		{name: "default", wantLimit: maxLabels},
+		{name: "page", limit: 50, wantLimit: 50},
+		{name: "largest page", limit: maxLabels, wantLimit: maxLabels},
+		{name: "too many", limit: maxLabels + 1, wantErr: domain.ErrInvalidExportOptions},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &fakeProductService{}
+			e := New(svc, nil, nil, report.DefaultTemplate())
+
+			err := e.ExportLabels(context.Background(), io.Discard, domain.ProductFilter{}, domain.LabelOptions{Skip: 10, Limit: tt.limit})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExportLabels() error = %v, want %v", err, tt.wantErr)
+			}
+			if svc.limit != tt.wantLimit || (tt.wantErr == nil && svc.skip != 10) {
+				t.Errorf("ListProducts() page = skip %d, limit %d, want skip 10, limit %d", svc.skip, svc.limit, tt.wantLimit)
+			}
+		})
+	}
+}
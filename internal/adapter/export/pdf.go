@@ -0,0 +1,243 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/report"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// ReportColumns returns the keys the columns of a product report template can name
+func ReportColumns() []string {
+	keys := make([]string, len(productExportColumns))
+	for i, column := range productExportColumns {
+		keys[i] = column.name
+	}
+	return keys
+}
+
+// productReportMoney returns the amounts shown in the money columns of a product report,
+// converted to the requested currency when there is one
+var productReportMoney = map[string]func(row productExportRow, converter *domain.CurrencyConverter) (domain.Money, error){
+	"price": func(row productExportRow, converter *domain.CurrencyConverter) (domain.Money, error) {
+		if converter == nil {
+			return row.product.Price, nil
+		}
+		return converter.Convert(row.product.Price)
+	},
+	"effective_price": func(row productExportRow, converter *domain.CurrencyConverter) (domain.Money, error) {
+		if row.converted != nil {
+			return *row.converted, nil
+		}
+		return row.product.EffectivePrice, nil
+	},
+	"stock_value": func(row productExportRow, converter *domain.CurrencyConverter) (domain.Money, error) {
+		if row.converted != nil {
+			return row.converted.Mul(decimal.NewFromInt(int64(row.product.Quantity))), nil
+		}
+		return stockValue(row.product), nil
+	},
+}
+
+// productReportTotal sums the quantity and the stock value of products, by currency
+type productReportTotal struct {
+	quantity int
+	values   map[string]decimal.Decimal
+}
+
+// add adds the quantity and the stock value of a product
+func (t *productReportTotal) add(quantity int, value domain.Money) {
+	if t.values == nil {
+		t.values = make(map[string]decimal.Decimal)
+	}
+	t.quantity += quantity
+	t.values[value.Currency] = t.values[value.Currency].Add(value.Amount)
+}
+
+// row returns the total as a report row, the stock values of several currencies are listed by currency
+func (t *productReportTotal) row() report.Row {
+	currencies := make([]string, 0, len(t.values))
+	for currency := range t.values {
+		currencies = append(currencies, currency)
+	}
+	slices.Sort(currencies)
+
+	values := make([]string, len(currencies))
+	for i, currency := range currencies {
+		values[i] = domain.Money{Amount: t.values[currency], Currency: currency}.String()
+	}
+
+	return report.Row{
+		"quantity":    strconv.Itoa(t.quantity),
+		"stock_value": strings.Join(values, "\n"),
+	}
+}
+
+// productReportGroup returns the name of the group of a product, its category when the products are grouped by category
+func productReportGroup(product *domain.Product, groupBy string) string {
+	switch {
+	case groupBy != "category":
+		return ""
+	case product.CategoryID == nil:
+		return uncategorizedGroup
+	case product.Category != nil && product.Category.Name != "":
+		return product.Category.Name
+	default:
+		return product.CategoryID.String()
+	}
+}
+
+// productReportFilters describes the filter and the options of an export in the title block of a report
+func productReportFilters(filter domain.ProductFilter, options domain.ExportOptions) []report.Filter {
+	var filters []report.Filter
+	add := func(name, value string) {
+		if value != "" {
+			filters = append(filters, report.Filter{Name: name, Value: value})
+		}
+	}
+	join := func(ids []uuid.UUID) string {
+		values := make([]string, len(ids))
+		for i, id := range ids {
+			values[i] = id.String()
+		}
+		return strings.Join(values, ", ")
+	}
+
+	add("Categories", join(filter.CategoryIDs))
+	add("Warehouses", join(filter.WarehouseIDs))
+	add("Search", filter.Search)
+	if filter.IncludeDeleted {
+		add("Deleted products", "included")
+	}
+	switch {
+	case filter.Near != nil:
+		add("Near", strconv.FormatFloat(filter.Near.Latitude, 'f', -1, 64)+","+strconv.FormatFloat(filter.Near.Longitude, 'f', -1, 64))
+	case filter.NearIP != "":
+		add("Near", "location of the client")
+	}
+	if filter.RadiusKM > 0 {
+		add("Within", strconv.FormatFloat(filter.RadiusKM, 'f', -1, 64)+" km")
+	}
+	add("Sorted by", string(filter.Sort))
+	add("Currency", strings.ToUpper(options.Currency))
+	if options.Limit > 0 {
+		add("Products", strconv.FormatUint(options.Skip+1, 10)+" to "+strconv.FormatUint(options.Skip+options.Limit, 10))
+	}
+
+	return filters
+}
+
+// exportPDF exports the products as a PDF report laid out by the report template, its rows grouped by category
+// with subtotals when requested or when the template groups them, and a total of the quantity and stock value.
+// A page of products is exported when a limit is given, all of them otherwise
+func (e *Exporter) exportPDF(ctx context.Context, w io.Writer, filter domain.ProductFilter, options domain.ExportOptions, converter *domain.CurrencyConverter, progress func(rows int64)) error {
+	var products []domain.Product
+	if options.Limit > 0 {
+		var err error
+		products, err = e.svc.ListProducts(ctx, filter, nil, options.Skip, options.Limit)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := e.svc.ExportProducts(ctx, filter, func(product *domain.Product) error {
+			products = append(products, *product)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	groupBy := options.Group
+	if groupBy == "" {
+		groupBy = e.template.GroupBy
+	}
+
+	content, err := e.productReport(products, groupBy, converter)
+	if err != nil {
+		return err
+	}
+	content.Filters = productReportFilters(filter, options)
+	content.GeneratedAt = time.Now().UTC()
+	progress(int64(len(products)))
+
+	// The document is only complete once rendered, so it is rendered before being written
+	var buf bytes.Buffer
+	if err := report.Render(&buf, e.template, content); err != nil {
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// productReport lays out the products in the rows of the columns of the report template. Grouped by category, the
+// groups are listed by name with the products without a category last, each followed by its subtotal. The total of
+// the quantity and the stock value follows the rows
+func (e *Exporter) productReport(products []domain.Product, groupBy string, converter *domain.CurrencyConverter) (report.Report, error) {
+	var content report.Report
+	groups := make(map[string]int)
+	var subtotals []productReportTotal
+	var total productReportTotal
+	for _, product := range products {
+		row, err := newProductExportRow(&product, converter)
+		if err != nil {
+			return report.Report{}, err
+		}
+
+		cells := make(report.Row, len(e.template.Columns))
+		for _, column := range e.template.Columns {
+			if money, ok := productReportMoney[column.Key]; ok {
+				amount, err := money(row, converter)
+				if err != nil {
+					return report.Report{}, err
+				}
+				cells[column.Key] = amount.String()
+				continue
+			}
+			cells[column.Key] = productExportColumns[indexProductExportColumn(column.Key)].value(row)
+		}
+
+		name := productReportGroup(&product, groupBy)
+		i, ok := groups[name]
+		if !ok {
+			i = len(content.Groups)
+			groups[name] = i
+			content.Groups = append(content.Groups, report.Group{Name: name})
+			subtotals = append(subtotals, productReportTotal{})
+		}
+		content.Groups[i].Rows = append(content.Groups[i].Rows, cells)
+
+		value, _ := productReportMoney["stock_value"](row, converter)
+		subtotals[i].add(product.Quantity, value)
+		total.add(product.Quantity, value)
+	}
+
+	if groupBy == "category" {
+		for i := range content.Groups {
+			content.Groups[i].Subtotal = subtotals[i].row()
+		}
+		slices.SortStableFunc(content.Groups, func(a, b report.Group) int {
+			if (a.Name == uncategorizedGroup) != (b.Name == uncategorizedGroup) {
+				if a.Name == uncategorizedGroup {
+					return 1
+				}
+				return -1
+			}
+			return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		})
+	}
+	if len(products) > 0 {
+		content.Total = total.row()
+	}
+
+	return content, nil
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/report"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// money returns an amount of a currency, failing the test when it is not one
+func money(t *testing.T, amount, currency string) domain.Money {
+	t.Helper()
+
+	m, err := domain.ParseMoney(amount, currency)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q, %q) error = %v", amount, currency, err)
+	}
+	return m
+}
+
+func TestProductReportTotal(t *testing.T) {
+	var total productReportTotal
+	total.add(2, money(t, "10.5", "USD"))
+	total.add(1, money(t, "3", "EUR"))
+	total.add(4, money(t, "0.25", "USD"))
+
+	want := report.Row{"quantity": "7", "stock_value": "3.00 EUR\n10.75 USD"}
+	if got := total.row(); got["quantity"] != want["quantity"] || got["stock_value"] != want["stock_value"] {
+		t.Errorf("row() = %q, want %q", got, want)
+	}
+}
+
+func TestProductReportGroups(t *testing.T) {
+	foods := domain.Category{ID: uuid.New(), Name: "foods"}
+	drinks := domain.Category{ID: uuid.New(), Name: "Drinks"}
+	product := func(name string, category *domain.Category, quantity int, price domain.Money) domain.Product {
+		p := domain.Product{ID: uuid.New(), Name: name, Quantity: quantity, Price: price, EffectivePrice: price}
+		if category != nil {
+			p.CategoryID = &category.ID
+			p.Category = category
+		}
+		return p
+	}
+	products := []domain.Product{
+		product("Salt", nil, 1, money(t, "2", "USD")),
+		product("Rice", &foods, 2, money(t, "10", "USD")),
+		product("Tea", &drinks, 3, money(t, "4", "EUR")),
+		product("Noodles", &foods, 1, money(t, "5", "EUR")),
+	}
+
+	template := report.DefaultTemplate()
+	template.Columns = []report.Column{{Key: "name"}, {Key: "quantity"}, {Key: "stock_value"}}
+	e := New(nil, nil, nil, template)
+
+	tests := []struct {
+		name          string
+		groupBy       string
+		wantGroups    []string
+		wantRows      [][]string
+		wantSubtotals []string
+	}{
+		{
+			name:       "not grouped",
+			groupBy:    "none",
+			wantGroups: []string{""},
+			wantRows:   [][]string{{"Salt", "Rice", "Tea", "Noodles"}},
+		},
+		{
+			name:          "grouped by category",
+			groupBy:       "category",
+			wantGroups:    []string{"Drinks", "foods", uncategorizedGroup},
+			wantRows:      [][]string{{"Tea"}, {"Rice", "Noodles"}, {"Salt"}},
+			wantSubtotals: []string{"12.00 EUR", "5.00 EUR\n20.00 USD", "2.00 USD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := e.productReport(products, tt.groupBy, nil)
+			if err != nil {
+				t.Fatalf("productReport() error = %v", err)
+			}
+
+			var groups, subtotals []string
+			var rows [][]string
+			for _, group := range content.Groups {
+				groups = append(groups, group.Name)
+				var names []string
+				for _, row := range group.Rows {
+					names = append(names, row["name"])
+				}
+				rows = append(rows, names)
+				if group.Subtotal != nil {
+					subtotals = append(subtotals, group.Subtotal["stock_value"])
+				}
+			}
+
+			if !slices.Equal(groups, tt.wantGroups) {
+				t.Errorf("groups = %q, want %q", groups, tt.wantGroups)
+			}
+			if !slices.EqualFunc(rows, tt.wantRows, slices.Equal) {
+				t.Errorf("rows = %q, want %q", rows, tt.wantRows)
+			}
+			if !slices.Equal(subtotals, tt.wantSubtotals) {
+				t.Errorf("subtotals = %q, want %q", subtotals, tt.wantSubtotals)
+			}
+			if content.Total["quantity"] != "7" || content.Total["stock_value"] != "17.00 EUR\n22.00 USD" {
+				t.Errorf("total = %q, want 7 products worth 17.00 EUR and 22.00 USD", content.Total)
+			}
+		})
+	}
+}
+
+func TestProductReportGroup(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		name    string
+		product domain.Product
+		groupBy string
+		want    string
+	}{
+		{name: "not grouped", product: domain.Product{CategoryID: &id}, groupBy: "none", want: ""},
+		{name: "category name", product: domain.Product{CategoryID: &id, Category: &domain.Category{ID: id, Name: "Foods"}}, groupBy: "category", want: "Foods"},
+		{name: "category not loaded", product: domain.Product{CategoryID: &id}, groupBy: "category", want: id.String()},
+		{name: "no category", groupBy: "category", want: uncategorizedGroup},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := productReportGroup(&tt.product, tt.groupBy); got != tt.want {
+				t.Errorf("productReportGroup() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,434 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+// productExportCell is the typed value of a column in a spreadsheet and its number format
+type productExportCell struct {
+	value  func(row productExportRow) any
+	format string
+}
+
+const (
+	// xlsxDateFormat is the number format of the date cells, dates are written in UTC
+	xlsxDateFormat = "yyyy-mm-dd hh:mm:ss"
+	// xlsxAmountFormat is the number format of the price cells
+	xlsxAmountFormat = "#,##0.00"
+	// xlsxPercentageFormat is the built-in number format 0.00%
+	xlsxPercentageFormat = 10
+)
+
+// productExportCells are the columns written as numbers or dates in a spreadsheet, the other columns are text.
+// A nil value is an empty cell
+var productExportCells = map[string]productExportCell{
+	"added_date": {func(row productExportRow) any { return row.product.AddedDate.UTC() }, xlsxDateFormat},
+	"price":      {func(row productExportRow) any { return row.product.Price.Amount.InexactFloat64() }, xlsxAmountFormat},
+	"effective_price": {func(row productExportRow) any {
+		return row.product.EffectivePrice.Amount.InexactFloat64()
+	}, xlsxAmountFormat},
+	"converted_price": {func(row productExportRow) any {
+		if row.converted == nil {
+			return nil
+		}
+		return row.converted.Amount.InexactFloat64()
+	}, xlsxAmountFormat},
+	"quantity": {func(row productExportRow) any { return row.product.Quantity }, "#,##0"},
+	"stock_value": {func(row productExportRow) any {
+		return stockValue(row.product).Amount.InexactFloat64()
+	}, xlsxAmountFormat},
+	"distance_km": {func(row productExportRow) any {
+		if row.product.DistanceKM == nil {
+			return nil
+		}
+		return *row.product.DistanceKM
+	}, "#,##0.0"},
+	"deleted_at": {func(row productExportRow) any {
+		if row.product.DeletedAt == nil {
+			return nil
+		}
+		return row.product.DeletedAt.UTC()
+	}, xlsxDateFormat},
+}
+
+const (
+	// xlsxMaxRows is the number of rows a sheet can hold, the products past it continue on another sheet
+	xlsxMaxRows = excelize.TotalRows
+	// xlsxMaxSheetName is the length of the longest sheet name
+	xlsxMaxSheetName = excelize.MaxSheetNameLength
+	// xlsxSummarySheet is the name of the sheet summarizing the export
+	xlsxSummarySheet = "Summary"
+	// xlsxProductsSheet is the name of the sheet holding all the products when they are not split by category
+	xlsxProductsSheet = "Products"
+)
+
+// xlsxSheet is a sheet of products being streamed
+type xlsxSheet struct {
+	name   string
+	writer *excelize.StreamWriter
+	// rows is the number of rows written, the header included
+	rows int
+}
+
+// productWorkbook is an Excel workbook of products, written one sheet per group of products.
+// Each sheet starts with a frozen header row with filters, the cells are typed as in productExportCells
+type productWorkbook struct {
+	file    *excelize.File
+	columns []productExportColumn
+	// styles holds the style of each column, zero for the text columns
+	styles []int
+	header int
+	// sheets maps a group to the sheet its next products are written to
+	sheets map[string]*xlsxSheet
+	// order lists the product sheets in the order they were created
+	order []*xlsxSheet
+	// names holds the folded names of the sheets, which must be unique regardless of case
+	names map[string]bool
+}
+
+// newProductWorkbook creates a workbook holding the summary sheet
+func newProductWorkbook(columns []productExportColumn) (*productWorkbook, error) {
+	file := excelize.NewFile()
+
+	wb := &productWorkbook{
+		file:    file,
+		columns: columns,
+		styles:  make([]int, len(columns)),
+		sheets:  make(map[string]*xlsxSheet),
+		names:   make(map[string]bool),
+	}
+
+	// A new file holds a first empty sheet, it becomes the summary
+	if err := file.SetSheetName(file.GetSheetName(0), xlsxSummarySheet); err != nil {
+		file.Close()
+		return nil, err
+	}
+	wb.names[strings.ToLower(xlsxSummarySheet)] = true
+	// Excel reserves the name History
+	wb.names["history"] = true
+
+	var err error
+	wb.header, err = file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"D9E1F2"}},
+	})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	for i, column := range columns {
+		cell, ok := productExportCells[column.name]
+		if !ok {
+			continue
+		}
+		wb.styles[i], err = file.NewStyle(&excelize.Style{CustomNumFmt: &cell.format})
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	return wb, nil
+}
+
+// sheet returns the sheet the next product of a group is written to, creating it with the
+// name when the group has none yet or when its sheet is full
+func (wb *productWorkbook) sheet(group, name string) (*xlsxSheet, error) {
+	sheet, ok := wb.sheets[group]
+	if ok && sheet.rows < xlsxMaxRows {
+		return sheet, nil
+	}
+
+	sheet = &xlsxSheet{name: wb.uniqueSheetName(name)}
+	if _, err := wb.file.NewSheet(sheet.name); err != nil {
+		return nil, err
+	}
+
+	var err error
+	sheet.writer, err = wb.file.NewStreamWriter(sheet.name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = sheet.writer.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]any, len(wb.columns))
+	for i, column := range wb.columns {
+		if err := sheet.writer.SetColWidth(i+1, i+1, xlsxColumnWidth(column.name)); err != nil {
+			return nil, err
+		}
+		header[i] = column.name
+	}
+	if err := sheet.writer.SetRow("A1", header, excelize.RowOpts{StyleID: wb.header}); err != nil {
+		return nil, err
+	}
+	sheet.rows = 1
+
+	wb.sheets[group] = sheet
+	wb.order = append(wb.order, sheet)
+
+	return sheet, nil
+}
+
+// xlsxColumnWidth returns the width of a column, in characters
+func xlsxColumnWidth(name string) float64 {
+	switch name {
+	case "id", "category_id", "supplier_id":
+		return 38
+	case "name", "category":
+		return 30
+	case "added_date", "deleted_at":
+		return 20
+	}
+	return max(12, float64(len(name)+2))
+}
+
+// uniqueSheetName makes a valid sheet name that no other sheet has, numbering the name when it is taken
+func (wb *productWorkbook) uniqueSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return ' '
+		}
+		return r
+	}, name)
+	name = strings.Trim(strings.TrimSpace(name), "'")
+	if name == "" {
+		name = xlsxProductsSheet
+	}
+
+	unique := truncateRunes(name, xlsxMaxSheetName)
+	for n := 2; wb.names[strings.ToLower(unique)]; n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		unique = truncateRunes(name, xlsxMaxSheetName-len(suffix)) + suffix
+	}
+	wb.names[strings.ToLower(unique)] = true
+
+	return unique
+}
+
+// truncateRunes returns the first n runes of a string
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// write writes a product on the next row of a sheet
+func (wb *productWorkbook) write(sheet *xlsxSheet, row productExportRow) error {
+	values := make([]any, len(wb.columns))
+	for i, column := range wb.columns {
+		cell, ok := productExportCells[column.name]
+		if !ok {
+			values[i] = column.value(row)
+			continue
+		}
+		if value := cell.value(row); value != nil {
+			values[i] = excelize.Cell{StyleID: wb.styles[i], Value: value}
+		}
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, sheet.rows+1)
+	if err != nil {
+		return err
+	}
+	if err := sheet.writer.SetRow(cell, values); err != nil {
+		return err
+	}
+	sheet.rows++
+
+	return nil
+}
+
+// products returns the number of products written to the workbook
+func (wb *productWorkbook) products() int {
+	products := 0
+	for _, sheet := range wb.order {
+		products += sheet.rows - 1
+	}
+	return products
+}
+
+// flush ends the product sheets, adding the filters of their header rows
+func (wb *productWorkbook) flush() error {
+	for _, sheet := range wb.order {
+		last, err := excelize.CoordinatesToCellName(len(wb.columns), sheet.rows)
+		if err != nil {
+			return err
+		}
+		// The filter is set on the worksheet the stream writer ends with, so before the flush
+		if err := wb.file.AutoFilter(sheet.name, "A1:"+last, nil); err != nil {
+			return err
+		}
+		if err := sheet.writer.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// summarize writes the summary sheet: the number of products of each sheet, then the share of all the products
+// held by each category and each supplier
+func (wb *productWorkbook) summarize(categories []*domain.StatisticCategoryProduct, suppliers []*domain.StatisticSupplierProduct) error {
+	percentage, err := wb.file.NewStyle(&excelize.Style{NumFmt: xlsxPercentageFormat})
+	if err != nil {
+		return err
+	}
+
+	row := 0
+	table := func(header []any, rows [][]any, style int) error {
+		row++
+		cell, _ := excelize.CoordinatesToCellName(1, row)
+		if err := wb.file.SetSheetRow(xlsxSummarySheet, cell, &header); err != nil {
+			return err
+		}
+		last, _ := excelize.CoordinatesToCellName(len(header), row)
+		if err := wb.file.SetCellStyle(xlsxSummarySheet, cell, last, wb.header); err != nil {
+			return err
+		}
+
+		for _, values := range rows {
+			row++
+			cell, _ := excelize.CoordinatesToCellName(1, row)
+			if err := wb.file.SetSheetRow(xlsxSummarySheet, cell, &values); err != nil {
+				return err
+			}
+			if style != 0 {
+				cell, _ = excelize.CoordinatesToCellName(2, row)
+				if err := wb.file.SetCellStyle(xlsxSummarySheet, cell, cell, style); err != nil {
+					return err
+				}
+			}
+		}
+
+		// A blank row separates the tables
+		row++
+		return nil
+	}
+
+	sheets := make([][]any, 0, len(wb.order)+1)
+	for _, sheet := range wb.order {
+		sheets = append(sheets, []any{sheet.name, sheet.rows - 1})
+	}
+	sheets = append(sheets, []any{"Total", wb.products()})
+	if err := table([]any{"Sheet", "Products"}, sheets, 0); err != nil {
+		return err
+	}
+
+	shares := make([][]any, len(categories))
+	for i, stat := range categories {
+		shares[i] = []any{stat.CategoryName, stat.Percentage / 100}
+	}
+	if err := table([]any{"Category", "Share of all products"}, shares, percentage); err != nil {
+		return err
+	}
+
+	shares = make([][]any, len(suppliers))
+	for i, stat := range suppliers {
+		shares[i] = []any{stat.SupplierName, stat.Percentage / 100}
+	}
+	if err := table([]any{"Supplier", "Share of all products"}, shares, percentage); err != nil {
+		return err
+	}
+
+	if err := wb.file.SetColWidth(xlsxSummarySheet, "A", "A", 30); err != nil {
+		return err
+	}
+	return wb.file.SetColWidth(xlsxSummarySheet, "B", "B", 20)
+}
+
+// productSheet returns the group and the sheet name of a product, its category when the products are split by category
+func productSheet(product *domain.Product, byCategory bool) (string, string) {
+	switch {
+	case !byCategory:
+		return "", xlsxProductsSheet
+	case product.CategoryID == nil:
+		return "", uncategorizedGroup
+	case product.Category != nil && product.Category.Name != "":
+		return product.CategoryID.String(), product.Category.Name
+	default:
+		return product.CategoryID.String(), product.CategoryID.String()
+	}
+}
+
+// exportXLSX exports all the products matching the filter as an Excel workbook, with one sheet per category
+// unless a single sheet is requested, and a summary sheet. The workbook is completed before it is written,
+// its sheets are buffered in temporary files past a few megabytes
+func (e *Exporter) exportXLSX(ctx context.Context, w io.Writer, filter domain.ProductFilter, options domain.ExportOptions, converter *domain.CurrencyConverter, progress func(rows int64)) error {
+	columns, err := selectProductExportColumns(options.Columns)
+	if err != nil {
+		return err
+	}
+
+	categories, err := e.statSvc.StatisticCategoryProduct(ctx)
+	if err != nil {
+		return err
+	}
+	suppliers, err := e.statSvc.StatisticSupplierProduct(ctx)
+	if err != nil {
+		return err
+	}
+
+	wb, err := newProductWorkbook(columns)
+	if err != nil {
+		return err
+	}
+	defer wb.file.Close()
+
+	byCategory := options.Sheets != "single"
+	var rows int64
+	err = e.svc.ExportProducts(ctx, filter, func(product *domain.Product) error {
+		row, err := newProductExportRow(product, converter)
+		if err != nil {
+			return err
+		}
+
+		sheet, err := wb.sheet(productSheet(product, byCategory))
+		if err != nil {
+			return err
+		}
+
+		if err := wb.write(sheet, row); err != nil {
+			return err
+		}
+
+		rows++
+		progress(rows)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// An export without products still holds an empty product sheet
+	if len(wb.order) == 0 {
+		if _, err := wb.sheet("", xlsxProductsSheet); err != nil {
+			return err
+		}
+	}
+
+	if err := wb.flush(); err != nil {
+		return err
+	}
+	if err := wb.summarize(categories, suppliers); err != nil {
+		return err
+	}
+
+	return wb.file.Write(w)
+}
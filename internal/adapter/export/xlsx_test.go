@@ -0,0 +1,154 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/report"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/xuri/excelize/v2"
+)
+
+// fakeStatisticService returns the shares of the products of its categories and suppliers
+type fakeStatisticService struct {
+	categories []*domain.StatisticCategoryProduct
+	suppliers  []*domain.StatisticSupplierProduct
+}
+
+func (s *fakeStatisticService) StatisticCategoryProduct(context.Context) ([]*domain.StatisticCategoryProduct, error) {
+	return s.categories, nil
+}
+
+func (s *fakeStatisticService) StatisticSupplierProduct(context.Context) ([]*domain.StatisticSupplierProduct, error) {
+	return s.suppliers, nil
+}
+
+// exportWorkbook exports the products as a workbook and opens it
+func exportWorkbook(t *testing.T, products []domain.Product, options domain.ExportOptions) *excelize.File {
+	t.Helper()
+
+	stats := &fakeStatisticService{
+		categories: []*domain.StatisticCategoryProduct{{CategoryName: "Foods", Percentage: 50}},
+		suppliers:  []*domain.StatisticSupplierProduct{{SupplierName: "Acme", Percentage: 12.5}},
+	}
+	e := New(&fakeProductService{products: products}, nil, stats, report.DefaultTemplate())
+
+	var out bytes.Buffer
+	if err := e.ExportProducts(context.Background(), &out, domain.ExportXLSX, domain.ProductFilter{}, options, nil); err != nil {
+		t.Fatalf("ExportProducts() error = %v", err)
+	}
+
+	file, err := excelize.OpenReader(&out)
+	if err != nil {
+		t.Fatalf("OpenReader() error = %v", err)
+	}
+	t.Cleanup(func() { file.Close() })
+	return file
+}
+
+func TestExportXLSXSheets(t *testing.T) {
+	foods := &domain.Category{ID: uuid.New(), Name: "Foods"}
+	drinks := &domain.Category{ID: uuid.New(), Name: "Drinks: hot/cold"}
+	products := []domain.Product{
+		{ID: uuid.New(), Name: "Rice", CategoryID: &foods.ID, Category: foods},
+		{ID: uuid.New(), Name: "Tea", CategoryID: &drinks.ID, Category: drinks},
+		{ID: uuid.New(), Name: "Noodles", CategoryID: &foods.ID, Category: foods},
+		{ID: uuid.New(), Name: "Salt"},
+	}
+
+	tests := []struct {
+		name       string
+		sheets     string
+		wantSheets []string
+		// wantSummary is the table of the number of products per sheet
+		wantSummary [][]string
+	}{
+		{
+			name:        "by category",
+			wantSheets:  []string{xlsxSummarySheet, "Foods", "Drinks  hot cold", uncategorizedGroup},
+			wantSummary: [][]string{{"Sheet", "Products"}, {"Foods", "2"}, {"Drinks  hot cold", "1"}, {uncategorizedGroup, "1"}, {"Total", "4"}},
+		},
+		{
+			name:        "single sheet",
+			sheets:      "single",
+			wantSheets:  []string{xlsxSummarySheet, xlsxProductsSheet},
+			wantSummary: [][]string{{"Sheet", "Products"}, {xlsxProductsSheet, "4"}, {"Total", "4"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := exportWorkbook(t, products, domain.ExportOptions{Columns: []string{"name"}, Sheets: tt.sheets})
+
+			if got := file.GetSheetList(); !slices.Equal(got, tt.wantSheets) {
+				t.Errorf("sheets = %q, want %q", got, tt.wantSheets)
+			}
+
+			rows, err := file.GetRows(xlsxSummarySheet)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := append(tt.wantSummary, nil,
+				[]string{"Category", "Share of all products"}, []string{"Foods", "50.00%"}, nil,
+				[]string{"Supplier", "Share of all products"}, []string{"Acme", "12.50%"},
+			)
+			if !slices.EqualFunc(rows, want, slices.Equal) {
+				t.Errorf("summary = %q, want %q", rows, want)
+			}
+		})
+	}
+}
+
+func TestExportXLSXCells(t *testing.T) {
+	added := time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("ICT", 7*60*60))
+	product := domain.Product{
+		ID:             uuid.New(),
+		Name:           "=SUM(A1)",
+		AddedDate:      added,
+		Price:          money(t, "1234.5", "USD"),
+		EffectivePrice: money(t, "1000", "USD"),
+		Quantity:       1200,
+	}
+	file := exportWorkbook(t, []domain.Product{product}, domain.ExportOptions{
+		Columns: []string{"name,added_date,price,effective_price,quantity,distance_km"},
+		Sheets:  "single",
+	})
+
+	tests := []struct {
+		cell string
+		// want is the value as displayed, wantRaw the value stored
+		want    string
+		wantRaw string
+	}{
+		{cell: "A1", want: "name", wantRaw: "name"},
+		{cell: "A2", want: "=SUM(A1)", wantRaw: "=SUM(A1)"},
+		{cell: "B2", want: "2024-03-01 02:30:00", wantRaw: "45352.1041666667"},
+		{cell: "C2", want: "1,234.50", wantRaw: "1234.5"},
+		{cell: "D2", want: "1,000.00", wantRaw: "1000"},
+		{cell: "E2", want: "1,200", wantRaw: "1200"},
+		{cell: "F2", want: "", wantRaw: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cell, func(t *testing.T) {
+			got, err := file.GetCellValue(xlsxProductsSheet, tt.cell)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := file.GetCellValue(xlsxProductsSheet, tt.cell, excelize.Options{RawCellValue: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || raw != tt.wantRaw {
+				t.Errorf("cell = %q stored as %q, want %q stored as %q", got, raw, tt.want, tt.wantRaw)
+			}
+		})
+	}
+
+	formula, err := file.GetCellFormula(xlsxProductsSheet, "A2")
+	if err != nil || formula != "" {
+		t.Errorf("A2 formula = %q, %v, want the name kept as text", formula, err)
+	}
+}
@@ -0,0 +1,340 @@
+package geohelper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+	"github.com/sony/gobreaker"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/util"
+)
+
+// CityLocator locates cities by name
+type CityLocator interface {
+	GetCityLocation(ctx context.Context, city string) (float64, float64, error)
+}
+
+// IPLocator locates ip addresses
+type IPLocator interface {
+	GetIPLocation(ctx context.Context, ip string) (float64, float64, error)
+}
+
+// ProviderOptions configures how a provider is called, a zero value calls it once without a deadline
+type ProviderOptions struct {
+	// Timeout bounds each attempt
+	Timeout time.Duration
+	// Retries is the number of attempts made after a failed one, with an exponential backoff
+	Retries int
+	// BreakerFailures is the number of consecutive failures opening the circuit breaker, zero disables it
+	BreakerFailures int
+	// BreakerCooldown is how long the circuit breaker stays open before letting a request through
+	BreakerCooldown time.Duration
+}
+
+// Provider is a locator of a chain, called with its own timeout, retries and circuit breaker.
+// A provider locates cities, ip addresses or both
+type Provider struct {
+	name    string
+	cities  CityLocator
+	ips     IPLocator
+	options ProviderOptions
+	breaker *gobreaker.CircuitBreaker
+}
+
+// NewProvider creates a provider, the locators a provider does not support are nil
+func NewProvider(name string, cities CityLocator, ips IPLocator, options ProviderOptions) *Provider {
+	provider := &Provider{
+		name:    name,
+		cities:  cities,
+		ips:     ips,
+		options: options,
+	}
+
+	if options.BreakerFailures > 0 {
+		provider.breaker = gobreaker.NewCircuitBreaker(gobreaker.Settings{
+			Name:    name,
+			Timeout: options.BreakerCooldown,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= uint32(options.BreakerFailures)
+			},
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				slog.Warn("Geo provider circuit breaker changed state", "provider", name, "from", from.String(), "to", to.String())
+			},
+			IsSuccessful: func(err error) bool {
+				return err == nil || isDefinitive(err) || errors.Is(err, context.Canceled)
+			},
+		})
+	}
+
+	return provider
+}
+
+// locate calls a lookup of the provider, retrying the failures that may not happen again
+func (p *Provider) locate(ctx context.Context, lookup func(ctx context.Context) (float64, float64, error)) (float64, float64, error) {
+	attempt := func() (location, error) {
+		ctx := ctx
+		if p.options.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, p.options.Timeout)
+			defer cancel()
+		}
+
+		call := func() (any, error) {
+			lat, lon, err := lookup(ctx)
+			return location{lat, lon}, err
+		}
+
+		var result any
+		var err error
+		if p.breaker != nil {
+			result, err = p.breaker.Execute(call)
+		} else {
+			result, err = call()
+		}
+		if err != nil {
+			if isDefinitive(err) || errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+				return location{}, backoff.Permanent(err)
+			}
+			return location{}, err
+		}
+
+		return result.(location), nil
+	}
+
+	policy := backoff.NewExponentialBackOff()
+	policy.InitialInterval = 100 * time.Millisecond
+	policy.MaxElapsedTime = 0
+
+	loc, err := backoff.RetryWithData(attempt, backoff.WithContext(backoff.WithMaxRetries(policy, uint64(p.options.Retries)), ctx))
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", p.name, err)
+	}
+
+	return loc.Latitude, loc.Longitude, nil
+}
+
+// location is the cached coordinates of a city or an ip address
+type location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+/**
+ * Chain implements port.GeoClient interface
+ * and provides an access to a list of geo providers, tried in order until one locates the city or the ip address.
+ * Local providers are listed first so remote ones are only called for what they do not know
+ */
+type Chain struct {
+	providers []*Provider
+	cache     port.CacheRepository
+	ttl       time.Duration
+}
+
+// NewChain creates a chain of providers, the coordinates found are cached for the ttl when a cache is given
+func NewChain(cache port.CacheRepository, ttl time.Duration, providers ...*Provider) *Chain {
+	return &Chain{
+		providers,
+		cache,
+		ttl,
+	}
+}
+
+// GetCityLocation returns the coordinates of a city from the cache or the first provider locating it.
+// A city no provider knows fails with domain.ErrUnknownCity
+func (c *Chain) GetCityLocation(ctx context.Context, city string) (float64, float64, error) {
+	key := util.GenerateCacheKey("geo:city", normalizeName(city))
+
+	return c.locate(ctx, key, domain.ErrUnknownCity, func(p *Provider) (float64, float64, error) {
+		if p.cities == nil {
+			return 0, 0, errUnsupported
+		}
+		return p.locate(ctx, func(ctx context.Context) (float64, float64, error) {
+			return p.cities.GetCityLocation(ctx, city)
+		})
+	})
+}
+
+// GetIPLocation returns the coordinates of an ip address from the cache or the first provider locating it.
+// An address that is invalid, not public or that no provider knows fails with domain.ErrUnknownLocation
+func (c *Chain) GetIPLocation(ctx context.Context, ip string) (float64, float64, error) {
+	if _, err := parsePublicIP(ip); err != nil {
+		return 0, 0, fmt.Errorf("%w: %w", domain.ErrUnknownLocation, err)
+	}
+
+	key := util.GenerateCacheKey("geo:ip", ip)
+
+	return c.locate(ctx, key, domain.ErrUnknownLocation, func(p *Provider) (float64, float64, error) {
+		if p.ips == nil {
+			return 0, 0, errUnsupported
+		}
+		return p.locate(ctx, func(ctx context.Context) (float64, float64, error) {
+			return p.ips.GetIPLocation(ctx, ip)
+		})
+	})
+}
+
+// GetDistance returns the great-circle distance in kilometers between two coordinates
+func (c *Chain) GetDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	return haversine(lat1, lon1, lat2, lon2)
+}
+
+// errUnsupported is returned by a provider that does not support a lookup, it is skipped
+var errUnsupported = errors.New("lookup not supported by the provider")
+
+// locate returns the cached coordinates of a key, or looks them up with each provider in turn and caches them.
+// A provider failing is skipped, the errors of all providers are returned when none locates the key. They are
+// wrapped in the unknown error when every provider answered that it does not know the key, rather than failed
+func (c *Chain) locate(ctx context.Context, key string, unknown error, lookup func(p *Provider) (float64, float64, error)) (float64, float64, error) {
+	if c.cache != nil {
+		if value, err := c.cache.Get(ctx, key); err == nil {
+			var loc location
+			if err := util.Deserialize(value, &loc); err == nil {
+				return loc.Latitude, loc.Longitude, nil
+			}
+		}
+	}
+
+	var errs []error
+	definitive := true
+	for _, provider := range c.providers {
+		lat, lon, err := lookup(provider)
+		if errors.Is(err, errUnsupported) {
+			continue
+		}
+		if err != nil {
+			if !isDefinitive(err) {
+				slog.Warn("Geo provider failed", "provider", provider.name, "key", key, "error", err)
+				definitive = false
+			}
+			errs = append(errs, err)
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+
+		if c.cache != nil {
+			value, err := util.Serialize(location{lat, lon})
+			if err == nil {
+				err = c.cache.Set(ctx, key, value, c.ttl)
+			}
+			if err != nil {
+				slog.Warn("Error caching geo location", "key", key, "error", err)
+			}
+		}
+
+		return lat, lon, nil
+	}
+
+	if len(errs) == 0 {
+		return 0, 0, fmt.Errorf("no geo provider for %s", key)
+	}
+	if definitive {
+		return 0, 0, fmt.Errorf("%w: %w", unknown, errors.Join(errs...))
+	}
+
+	return 0, 0, errors.Join(errs...)
+}
+
+// isDefinitive reports whether an error is an answer of the provider rather than a failure to reach it,
+// such an error is not retried and does not count against the circuit breaker
+func isDefinitive(err error) bool {
+	return errors.Is(err, ErrCityNotFound) ||
+		errors.Is(err, ErrIPNotFound) ||
+		errors.Is(err, ErrInvalidIP) ||
+		errors.Is(err, ErrNonPublicIP)
+}
+
+const (
+	// defaultProviders is the provider chain used when none is configured
+	defaultProviders = "opencage,ip-api"
+	// defaultTimeout bounds an attempt of a remote provider when no timeout is configured
+	defaultTimeout = 3 * time.Second
+	// defaultBreakerFailures is the number of consecutive failures opening the circuit breaker of a remote provider
+	defaultBreakerFailures = 5
+	// defaultBreakerCooldown is how long the circuit breaker of a remote provider stays open
+	defaultBreakerCooldown = 30 * time.Second
+	// defaultCacheTTL is how long coordinates are cached when no ttl is configured
+	defaultCacheTTL = 24 * time.Hour
+)
+
+// NewChainFromConfig creates the chain of the configured providers, in order. The gazetteer and mmdb providers
+// read local files and are called directly, the opencage and ip-api providers are remote and called with a timeout,
+// retries and a circuit breaker. The cache is optional
+func NewChainFromConfig(config *config.GEO, cache port.CacheRepository) (*Chain, error) {
+	remote := ProviderOptions{
+		Timeout:         config.Timeout,
+		Retries:         config.Retries,
+		BreakerFailures: config.BreakerFailures,
+		BreakerCooldown: config.BreakerCooldown,
+	}
+	if remote.Timeout == 0 {
+		remote.Timeout = defaultTimeout
+	}
+	if remote.BreakerFailures == 0 {
+		remote.BreakerFailures = defaultBreakerFailures
+	}
+	if remote.BreakerCooldown == 0 {
+		remote.BreakerCooldown = defaultBreakerCooldown
+	}
+
+	ttl := config.CacheTTL
+	if ttl == 0 {
+		ttl = defaultCacheTTL
+	}
+
+	names := config.Providers
+	if names == "" {
+		names = defaultProviders
+	}
+
+	chain := NewChain(cache, ttl)
+	geo := New(config)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "opencage":
+			chain.providers = append(chain.providers, NewProvider(name, geo, nil, remote))
+		case "ip-api":
+			chain.providers = append(chain.providers, NewProvider(name, nil, geo, remote))
+		case "gazetteer":
+			gazetteer, err := LoadGazetteer(config.GazetteerFile, config.DefaultCountry)
+			if err != nil {
+				chain.Close()
+				return nil, fmt.Errorf("loading gazetteer: %w", err)
+			}
+			chain.providers = append(chain.providers, NewProvider(name, gazetteer, nil, ProviderOptions{}))
+		case "mmdb":
+			mmdb, err := OpenMMDB(config.MMDBFile)
+			if err != nil {
+				chain.Close()
+				return nil, fmt.Errorf("opening ip database: %w", err)
+			}
+			chain.providers = append(chain.providers, NewProvider(name, nil, mmdb, ProviderOptions{}))
+		default:
+			chain.Close()
+			return nil, fmt.Errorf("unknown geo provider %q", name)
+		}
+	}
+
+	return chain, nil
+}
+
+// Close releases the files held by the providers of the chain
+func (c *Chain) Close() error {
+	var errs []error
+	for _, provider := range c.providers {
+		if closer, ok := provider.ips.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
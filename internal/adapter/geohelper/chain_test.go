@@ -0,0 +1,266 @@
+package geohelper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// fakeLocator counts its calls and answers them with locate
+type fakeLocator struct {
+	calls  int
+	locate func(ctx context.Context) (float64, float64, error)
+}
+
+func (l *fakeLocator) GetCityLocation(ctx context.Context, _ string) (float64, float64, error) {
+	l.calls++
+	return l.locate(ctx)
+}
+
+func (l *fakeLocator) GetIPLocation(ctx context.Context, _ string) (float64, float64, error) {
+	l.calls++
+	return l.locate(ctx)
+}
+
+// locatedAt returns a locator finding everything at the coordinates
+func locatedAt(lat, lon float64) *fakeLocator {
+	return &fakeLocator{locate: func(context.Context) (float64, float64, error) {
+		return lat, lon, nil
+	}}
+}
+
+// failing returns a locator failing with the error
+func failing(err error) *fakeLocator {
+	return &fakeLocator{locate: func(context.Context) (float64, float64, error) {
+		return 0, 0, err
+	}}
+}
+
+// fakeCache keeps the values in memory without expiring them
+type fakeCache struct {
+	values map[string][]byte
+}
+
+func (c *fakeCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c.values[key] = value
+	return nil
+}
+
+func (c *fakeCache) Get(_ context.Context, key string) ([]byte, error) {
+	value, ok := c.values[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return value, nil
+}
+
+func (c *fakeCache) Delete(_ context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+func (c *fakeCache) DeleteByPrefix(_ context.Context, _ string) error {
+	return nil
+}
+
+func (c *fakeCache) Close() error {
+	return nil
+}
+
+var errUnreachable = errors.New("connection refused")
+
+func TestChainGetCityLocation(t *testing.T) {
+	tests := []struct {
+		name    string
+		first   *fakeLocator
+		options ProviderOptions
+		// wantCalls is the number of calls made to the first provider
+		wantCalls int
+		// wantSecond reports whether the city is located by the second provider
+		wantSecond bool
+	}{
+		{
+			name:      "first provider locates",
+			first:     locatedAt(1, 2),
+			wantCalls: 1,
+		},
+		{
+			name:       "failure moves to the next provider",
+			first:      failing(errUnreachable),
+			wantCalls:  1,
+			wantSecond: true,
+		},
+		{
+			name:       "failure retried",
+			first:      failing(errUnreachable),
+			options:    ProviderOptions{Retries: 2},
+			wantCalls:  3,
+			wantSecond: true,
+		},
+		{
+			name:       "unknown city not retried",
+			first:      failing(fmt.Errorf("%w: Atlantis", ErrCityNotFound)),
+			options:    ProviderOptions{Retries: 2},
+			wantCalls:  1,
+			wantSecond: true,
+		},
+		{
+			name: "attempt timed out",
+			first: &fakeLocator{locate: func(ctx context.Context) (float64, float64, error) {
+				<-ctx.Done()
+				return 0, 0, ctx.Err()
+			}},
+			options:    ProviderOptions{Timeout: 10 * time.Millisecond},
+			wantCalls:  1,
+			wantSecond: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			second := locatedAt(3, 4)
+			chain := NewChain(nil, 0,
+				NewProvider("first", tt.first, nil, tt.options),
+				NewProvider("second", second, nil, ProviderOptions{}),
+			)
+
+			lat, lon, err := chain.GetCityLocation(context.Background(), "Hanoi")
+			if err != nil {
+				t.Fatalf("GetCityLocation() error = %v", err)
+			}
+			if tt.first.calls != tt.wantCalls {
+				t.Errorf("first provider calls = %d, want %d", tt.first.calls, tt.wantCalls)
+			}
+			wantLat, wantLon := 1.0, 2.0
+			if tt.wantSecond {
+				wantLat, wantLon = 3, 4
+			}
+			if lat != wantLat || lon != wantLon {
+				t.Errorf("GetCityLocation() = %v, %v, want %v, %v", lat, lon, wantLat, wantLon)
+			}
+		})
+	}
+}
+
+func TestChainAllProvidersFail(t *testing.T) {
+	tests := []struct {
+		name   string
+		second error
+		// wantUnknown reports whether the city is unknown rather than the lookup failed
+		wantUnknown bool
+	}{
+		{name: "a provider failed", second: errUnreachable},
+		{name: "no provider knows the city", second: fmt.Errorf("%w: Atlantis", ErrCityNotFound), wantUnknown: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := NewChain(nil, 0,
+				NewProvider("first", failing(ErrCityNotFound), nil, ProviderOptions{}),
+				NewProvider("second", failing(tt.second), nil, ProviderOptions{}),
+				NewProvider("ips", nil, locatedAt(1, 2), ProviderOptions{}),
+			)
+
+			_, _, err := chain.GetCityLocation(context.Background(), "Atlantis")
+			if !errors.Is(err, ErrCityNotFound) || !errors.Is(err, tt.second) {
+				t.Errorf("GetCityLocation() error = %v, want the errors of both city providers", err)
+			}
+			if errors.Is(err, domain.ErrUnknownCity) != tt.wantUnknown {
+				t.Errorf("GetCityLocation() error = %v, unknown city %v, want %v", err, !tt.wantUnknown, tt.wantUnknown)
+			}
+		})
+	}
+}
+
+func TestChainGetIPLocationFails(t *testing.T) {
+	tests := []struct {
+		name        string
+		ip          string
+		err         error
+		wantUnknown bool
+	}{
+		{name: "invalid", ip: "8.8.8", wantUnknown: true},
+		{name: "not public", ip: "192.168.1.1", wantUnknown: true},
+		{name: "not found", ip: "8.8.8.8", err: ErrIPNotFound, wantUnknown: true},
+		{name: "provider failed", ip: "8.8.8.8", err: errUnreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := failing(tt.err)
+			chain := NewChain(nil, 0, NewProvider("first", nil, provider, ProviderOptions{}))
+
+			_, _, err := chain.GetIPLocation(context.Background(), tt.ip)
+			if err == nil {
+				t.Fatal("GetIPLocation() error = nil")
+			}
+			if errors.Is(err, domain.ErrUnknownLocation) != tt.wantUnknown {
+				t.Errorf("GetIPLocation() error = %v, unknown location %v, want %v", err, !tt.wantUnknown, tt.wantUnknown)
+			}
+			if tt.err == nil && provider.calls != 0 {
+				t.Errorf("provider calls = %d, want 0", provider.calls)
+			}
+		})
+	}
+}
+
+func TestChainCircuitBreaker(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		// wantCalls is the number of calls made to the first provider over the lookups
+		wantCalls int
+	}{
+		{name: "failures open the breaker", err: errUnreachable, wantCalls: 2},
+		{name: "unknown city does not open the breaker", err: ErrCityNotFound, wantCalls: 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := failing(tt.err)
+			second := locatedAt(3, 4)
+			chain := NewChain(nil, 0,
+				NewProvider("first", first, nil, ProviderOptions{BreakerFailures: 2, BreakerCooldown: time.Hour}),
+				NewProvider("second", second, nil, ProviderOptions{}),
+			)
+
+			for i := 0; i < 4; i++ {
+				lat, lon, err := chain.GetCityLocation(context.Background(), "Hanoi")
+				if err != nil || lat != 3 || lon != 4 {
+					t.Fatalf("GetCityLocation() = %v, %v, %v, want the location of the second provider", lat, lon, err)
+				}
+			}
+			if first.calls != tt.wantCalls {
+				t.Errorf("first provider calls = %d, want %d", first.calls, tt.wantCalls)
+			}
+			if second.calls != 4 {
+				t.Errorf("second provider calls = %d, want 4", second.calls)
+			}
+		})
+	}
+}
+
+func TestChainCache(t *testing.T) {
+	provider := locatedAt(1, 2)
+	cache := &fakeCache{values: make(map[string][]byte)}
+	chain := NewChain(cache, time.Hour, NewProvider("first", provider, provider, ProviderOptions{}))
+
+	for i := 0; i < 2; i++ {
+		if lat, lon, err := chain.GetCityLocation(context.Background(), "Hà Nội"); err != nil || lat != 1 || lon != 2 {
+			t.Fatalf("GetCityLocation() = %v, %v, %v, want 1, 2", lat, lon, err)
+		}
+	}
+	if lat, lon, err := chain.GetCityLocation(context.Background(), "ha noi"); err != nil || lat != 1 || lon != 2 {
+		t.Fatalf("GetCityLocation() of the normalized name = %v, %v, %v, want 1, 2", lat, lon, err)
+	}
+	if lat, lon, err := chain.GetIPLocation(context.Background(), "8.8.8.8"); err != nil || lat != 1 || lon != 2 {
+		t.Fatalf("GetIPLocation() = %v, %v, %v, want 1, 2", lat, lon, err)
+	}
+	if lat, lon, err := chain.GetIPLocation(context.Background(), "8.8.8.8"); err != nil || lat != 1 || lon != 2 {
+		t.Fatalf("GetIPLocation() = %v, %v, %v, want 1, 2", lat, lon, err)
+	}
+
+	if provider.calls != 2 {
+		t.Errorf("provider calls = %d, want 2, the other lookups read from the cache", provider.calls)
+	}
+}
@@ -0,0 +1,235 @@
+package geohelper
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrCityNotFound is returned when no city matches the name
+var ErrCityNotFound = errors.New("city not found")
+
+// gazetteerCity is a city of the gazetteer
+type gazetteerCity struct {
+	name       string
+	country    string
+	latitude   float64
+	longitude  float64
+	population int64
+}
+
+// Gazetteer locates cities from a local file loaded at startup, without any network call.
+// Names are matched ignoring case and accents, a name shared by several cities resolves to
+// the city of the requested country, then of the default country, then to the most populous one
+type Gazetteer struct {
+	cities         map[string][]gazetteerCity
+	defaultCountry string
+}
+
+// LoadGazetteer reads a gazetteer file. A .txt or .tsv file is a GeoNames cities dump such as cities15000.txt,
+// a .csv file has a "name,country,latitude,longitude,population" header, population being optional
+func LoadGazetteer(path, defaultCountry string) (*Gazetteer, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	g := &Gazetteer{
+		cities:         make(map[string][]gazetteerCity),
+		defaultCountry: strings.ToUpper(defaultCountry),
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".txt", ".tsv":
+		err = g.readGeoNames(file)
+	case ".csv":
+		err = g.readCSV(file)
+	default:
+		err = fmt.Errorf("gazetteer file must be a GeoNames .txt or a .csv file: %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// readGeoNames reads the tab separated GeoNames format, indexing the name, the ascii name and the alternate names
+func (g *Gazetteer) readGeoNames(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 15 {
+			continue
+		}
+
+		city, err := newGazetteerCity(fields[1], fields[8], fields[4], fields[5], fields[14])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		names := append([]string{fields[1], fields[2]}, strings.Split(fields[3], ",")...)
+		g.add(city, names...)
+	}
+
+	return scanner.Err()
+}
+
+// readCSV reads a CSV file whose header names the name, country, latitude, longitude and population columns
+func (g *Gazetteer) readCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"name", "country", "latitude", "longitude"} {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("gazetteer csv has no %s column", name)
+		}
+	}
+
+	column := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		city, err := newGazetteerCity(column(record, "name"), column(record, "country"), column(record, "latitude"), column(record, "longitude"), column(record, "population"))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		g.add(city, city.name)
+	}
+}
+
+// newGazetteerCity creates a city from its text fields, an empty population is zero
+func newGazetteerCity(name, country, latitude, longitude, population string) (gazetteerCity, error) {
+	city := gazetteerCity{
+		name:    strings.TrimSpace(name),
+		country: strings.ToUpper(strings.TrimSpace(country)),
+	}
+
+	var err error
+	if city.latitude, err = strconv.ParseFloat(strings.TrimSpace(latitude), 64); err != nil {
+		return city, fmt.Errorf("invalid latitude %q", latitude)
+	}
+	if city.longitude, err = strconv.ParseFloat(strings.TrimSpace(longitude), 64); err != nil {
+		return city, fmt.Errorf("invalid longitude %q", longitude)
+	}
+	if population = strings.TrimSpace(population); population != "" {
+		if city.population, err = strconv.ParseInt(population, 10, 64); err != nil {
+			return city, fmt.Errorf("invalid population %q", population)
+		}
+	}
+
+	return city, nil
+}
+
+// add indexes a city under each of its distinct names
+func (g *Gazetteer) add(city gazetteerCity, names ...string) {
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		key := normalizeName(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		g.cities[key] = append(g.cities[key], city)
+	}
+}
+
+// GetCityLocation returns the coordinates of a city. The name can end with a comma
+// and an ISO 3166 country code, such as "Paris, FR", to pick the city of that country
+func (g *Gazetteer) GetCityLocation(ctx context.Context, city string) (float64, float64, error) {
+	name, country := city, ""
+	if i := strings.LastIndex(city, ","); i >= 0 {
+		if code := strings.TrimSpace(city[i+1:]); len(code) == 2 {
+			name, country = city[:i], strings.ToUpper(code)
+		}
+	}
+
+	candidates := g.cities[normalizeName(name)]
+	if country != "" {
+		candidates = filterCountry(candidates, country)
+	} else if g.defaultCountry != "" {
+		if local := filterCountry(candidates, g.defaultCountry); len(local) != 0 {
+			candidates = local
+		}
+	}
+
+	if len(candidates) == 0 {
+		return 0, 0, fmt.Errorf("%w: %s", ErrCityNotFound, city)
+	}
+
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if candidate.population > best.population {
+			best = candidate
+		}
+	}
+
+	return best.latitude, best.longitude, nil
+}
+
+// filterCountry returns the cities of a country
+func filterCountry(cities []gazetteerCity, country string) []gazetteerCity {
+	var filtered []gazetteerCity
+	for _, city := range cities {
+		if city.country == country {
+			filtered = append(filtered, city)
+		}
+	}
+	return filtered
+}
+
+// normalizeName folds a city name for lookup: accents are removed, letters are lowercased
+// and runs of spaces, hyphens and apostrophes become a single space, so "Hồ Chí Minh" matches "ho-chi minh"
+func normalizeName(name string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, name)
+	if err != nil {
+		folded = name
+	}
+
+	// Letters that do not decompose into a base letter and an accent
+	folded = strings.NewReplacer("đ", "d", "Đ", "d", "ø", "o", "Ø", "o", "ł", "l", "Ł", "l", "ß", "ss").Replace(folded)
+
+	return strings.Join(strings.FieldsFunc(strings.ToLower(folded), func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '\'' || r == '’' || r == '.'
+	}), " ")
+}
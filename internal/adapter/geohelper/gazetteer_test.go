@@ -0,0 +1,140 @@
+package geohelper
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// geoNamesFixture is an extract of a GeoNames cities dump, its columns separated by tabs
+var geoNamesFixture = strings.Join([]string{
+	geoNamesLine("1566083", "Hồ Chí Minh City", "Ho Chi Minh City", "Saigon,Sài Gòn,TP HCM", "10.82302", "106.62965", "VN", "8993082"),
+	geoNamesLine("1581130", "Hà Nội", "Ha Noi", "Hanoi", "21.0245", "105.84117", "VN", "8053663"),
+	geoNamesLine("2988507", "Paris", "Paris", "Lutece", "48.85341", "2.3488", "FR", "2138551"),
+	geoNamesLine("4717560", "Paris", "Paris", "", "33.66094", "-95.55551", "US", "24171"),
+	geoNamesLine("4647963", "Paris", "Paris", "", "36.302", "-88.32671", "US", "10156"),
+	geoNamesLine("2950159", "Berlin", "Berlin", "Berlín", "52.52437", "13.41053", "DE", "3426354"),
+	"a line too short to be a city",
+}, "\n")
+
+// geoNamesLine returns a line of a GeoNames cities dump
+func geoNamesLine(id, name, asciiName, alternateNames, latitude, longitude, country, population string) string {
+	return strings.Join([]string{
+		id, name, asciiName, alternateNames, latitude, longitude, "P", "PPLA", country, "", "", "", "", "", population, "", "10", "Etc/UTC", "2024-01-01",
+	}, "\t")
+}
+
+// csvFixture is a gazetteer CSV file, its columns in any order and the population optional
+const csvFixture = `country,name,latitude,longitude,population
+VN,Đà Nẵng,16.06778,108.22083,752493
+US,Springfield,39.80172,-89.64371,116250
+US,Springfield,37.21533,-93.29824,169176
+FR,Saint-Étienne,45.43389,4.39,
+`
+
+// writeGazetteer writes a gazetteer file in a temporary directory, returning its path
+func writeGazetteer(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+	return path
+}
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "Hồ Chí Minh", want: "ho chi minh"},
+		{name: "  ho-chi   MINH ", want: "ho chi minh"},
+		{name: "Đà Nẵng", want: "da nang"},
+		{name: "Saint-Étienne", want: "saint etienne"},
+		{name: "L'Aquila", want: "l aquila"},
+		{name: "St. Louis", want: "st louis"},
+		{name: "Kraków", want: "krakow"},
+		{name: "Łódź", want: "lodz"},
+		{name: "Tromsø", want: "tromso"},
+		{name: "Gießen", want: "giessen"},
+		{name: "", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeName(tt.name); got != tt.want {
+				t.Errorf("normalizeName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGazetteerGetCityLocation(t *testing.T) {
+	geoNames := writeGazetteer(t, "cities15000.txt", geoNamesFixture)
+	csv := writeGazetteer(t, "cities.csv", csvFixture)
+
+	tests := []struct {
+		name           string
+		path           string
+		defaultCountry string
+		city           string
+		wantLat        float64
+		wantLon        float64
+		wantErr        error
+	}{
+		{name: "name", path: geoNames, city: "Hồ Chí Minh City", wantLat: 10.82302, wantLon: 106.62965},
+		{name: "ascii name", path: geoNames, city: "ha noi", wantLat: 21.0245, wantLon: 105.84117},
+		{name: "alternate name", path: geoNames, city: "SAIGON", wantLat: 10.82302, wantLon: 106.62965},
+		{name: "alternate name with accents", path: geoNames, city: "sai gon", wantLat: 10.82302, wantLon: 106.62965},
+		{name: "most populous", path: geoNames, city: "Paris", wantLat: 48.85341, wantLon: 2.3488},
+		{name: "requested country", path: geoNames, city: "Paris, us", wantLat: 33.66094, wantLon: -95.55551},
+		{name: "default country", path: geoNames, defaultCountry: "us", city: "Paris", wantLat: 33.66094, wantLon: -95.55551},
+		{name: "default country without the city", path: geoNames, defaultCountry: "VN", city: "Berlin", wantLat: 52.52437, wantLon: 13.41053},
+		{name: "requested country without the city", path: geoNames, city: "Berlin, FR", wantErr: ErrCityNotFound},
+		{name: "unknown city", path: geoNames, city: "Atlantis", wantErr: ErrCityNotFound},
+		{name: "csv name", path: csv, city: "da nang", wantLat: 16.06778, wantLon: 108.22083},
+		{name: "csv without population", path: csv, city: "Saint Etienne", wantLat: 45.43389, wantLon: 4.39},
+		{name: "csv most populous", path: csv, city: "Springfield", wantLat: 37.21533, wantLon: -93.29824},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := LoadGazetteer(tt.path, tt.defaultCountry)
+			if err != nil {
+				t.Fatalf("LoadGazetteer() error = %v", err)
+			}
+
+			lat, lon, err := g.GetCityLocation(context.Background(), tt.city)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetCityLocation(%q) error = %v, want %v", tt.city, err, tt.wantErr)
+			}
+			if lat != tt.wantLat || lon != tt.wantLon {
+				t.Errorf("GetCityLocation(%q) = %v, %v, want %v, %v", tt.city, lat, lon, tt.wantLat, tt.wantLon)
+			}
+		})
+	}
+}
+
+func TestLoadGazetteerInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{name: "unknown format", file: "cities.json", content: "[]", wantErr: "must be a GeoNames .txt or a .csv file"},
+		{name: "csv without column", file: "cities.csv", content: "name,latitude,longitude\nHanoi,21,105\n", wantErr: "no country column"},
+		{name: "csv invalid latitude", file: "cities.csv", content: "name,country,latitude,longitude\nHanoi,VN,north,105\n", wantErr: `line 2: invalid latitude "north"`},
+		{name: "geonames invalid population", file: "cities.txt", content: geoNamesLine("1", "Hanoi", "Hanoi", "", "21", "105", "VN", "many"), wantErr: `line 1: invalid population "many"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadGazetteer(writeGazetteer(t, tt.file, tt.content), "")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("LoadGazetteer() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
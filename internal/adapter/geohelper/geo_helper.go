@@ -1,6 +1,7 @@
 package geohelper
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -10,24 +11,22 @@ import (
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
 )
 
+// Geo locates cities with the opencagedata.com geocoding API and ip addresses with the ip-api.com API
 type Geo struct {
 	apiKey string
+	client *http.Client
 }
 
 func New(config *config.GEO) *Geo {
 	return &Geo{
 		apiKey: config.APIKey,
+		client: &http.Client{},
 	}
 }
 
 // Get location of a city using a geocoding API
-func (g *Geo) GetCityLocation(city string) (float64, float64, error) {
+func (g *Geo) GetCityLocation(ctx context.Context, city string) (float64, float64, error) {
 	apiURL := fmt.Sprintf("https://api.opencagedata.com/geocode/v1/json?q=%s&key=%s", url.QueryEscape(city), g.apiKey)
-	resp, err := http.Get(apiURL)
-	if err != nil {
-		return 0, 0, err
-	}
-	defer resp.Body.Close()
 
 	var data struct {
 		Results []struct {
@@ -37,38 +36,68 @@ func (g *Geo) GetCityLocation(city string) (float64, float64, error) {
 			} `json:"geometry"`
 		} `json:"results"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	if err := g.getJSON(ctx, apiURL, &data); err != nil {
 		return 0, 0, err
 	}
 
 	if len(data.Results) == 0 {
-		return 0, 0, fmt.Errorf("no results found for city: %s", city)
+		return 0, 0, fmt.Errorf("%w: %s", ErrCityNotFound, city)
 	}
 
 	return data.Results[0].Geometry.Lat, data.Results[0].Geometry.Lng, nil
 }
 
-func (g *Geo) GetIPLocation(ip string) (float64, float64, error) {
-	// Example API: ip-api.com
-	apiURL := fmt.Sprintf("http://ip-api.com/json/%s", ip)
-	resp, err := http.Get(apiURL)
-	if err != nil {
+func (g *Geo) GetIPLocation(ctx context.Context, ip string) (float64, float64, error) {
+	if _, err := parsePublicIP(ip); err != nil {
 		return 0, 0, err
 	}
-	defer resp.Body.Close()
+
+	// Example API: ip-api.com
+	apiURL := fmt.Sprintf("http://ip-api.com/json/%s", ip)
 
 	var data struct {
-		Lat float64 `json:"lat"`
-		Lon float64 `json:"lon"`
+		Status  string  `json:"status"`
+		Message string  `json:"message"`
+		Lat     float64 `json:"lat"`
+		Lon     float64 `json:"lon"`
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	if err := g.getJSON(ctx, apiURL, &data); err != nil {
 		return 0, 0, err
 	}
 
+	if data.Status != "success" {
+		return 0, 0, fmt.Errorf("%w: %s: %s", ErrIPNotFound, ip, data.Message)
+	}
+
 	return data.Lat, data.Lon, nil
 }
 
 func (g *Geo) GetDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	return haversine(lat1, lon1, lat2, lon2)
+}
+
+// getJSON sends a GET request bound to the context and decodes the JSON response body
+func (g *Geo) getJSON(ctx context.Context, apiURL string, data any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s responded with status %d", req.URL.Host, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(data)
+}
+
+// haversine returns the great-circle distance in kilometers between two coordinates
+func haversine(lat1, lon1, lat2, lon2 float64) float64 {
 	const earthRadius = 6371 // Earth radius in kilometers
 	dLat := (lat2 - lat1) * (math.Pi / 180)
 	dLon := (lon2 - lon1) * (math.Pi / 180)
@@ -0,0 +1,82 @@
+package geohelper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+var (
+	// ErrInvalidIP is returned when the address is not an ip address
+	ErrInvalidIP = errors.New("invalid ip address")
+	// ErrNonPublicIP is returned for private, loopback, link-local and unspecified addresses, which have no location
+	ErrNonPublicIP = errors.New("ip address is not public")
+	// ErrIPNotFound is returned when no location is known for the address
+	ErrIPNotFound = errors.New("ip address not found")
+)
+
+// mmdbRecord is the part of a GeoIP2 or GeoLite2 City record holding the location
+type mmdbRecord struct {
+	Location struct {
+		Latitude  *float64 `maxminddb:"latitude"`
+		Longitude *float64 `maxminddb:"longitude"`
+	} `maxminddb:"location"`
+}
+
+// MMDB locates ip addresses, IPv4 and IPv6, from a MaxMind format city database on disk
+// such as GeoLite2-City.mmdb or dbip-city-lite.mmdb, without any network call
+type MMDB struct {
+	reader *maxminddb.Reader
+}
+
+// OpenMMDB opens a MaxMind format database, the file is memory mapped until the database is closed
+func OpenMMDB(path string) (*MMDB, error) {
+	reader, err := maxminddb.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MMDB{reader}, nil
+}
+
+// Close closes the database
+func (m *MMDB) Close() error {
+	return m.reader.Close()
+}
+
+// GetIPLocation returns the coordinates of an ip address
+func (m *MMDB) GetIPLocation(ctx context.Context, ip string) (float64, float64, error) {
+	addr, err := parsePublicIP(ip)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var record mmdbRecord
+	_, ok, err := m.reader.LookupNetwork(addr, &record)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !ok || record.Location.Latitude == nil || record.Location.Longitude == nil {
+		return 0, 0, fmt.Errorf("%w: %s", ErrIPNotFound, ip)
+	}
+
+	return *record.Location.Latitude, *record.Location.Longitude, nil
+}
+
+// parsePublicIP parses an ip address, rejecting the addresses that can not be located
+func parsePublicIP(ip string) (net.IP, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidIP, ip)
+	}
+
+	if addr.IsPrivate() || addr.IsLoopback() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() || addr.IsMulticast() {
+		return nil, fmt.Errorf("%w: %s", ErrNonPublicIP, ip)
+	}
+
+	return addr, nil
+}
@@ -0,0 +1,44 @@
+package geohelper
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePublicIP(t *testing.T) {
+	tests := []struct {
+		ip      string
+		wantErr error
+	}{
+		{ip: "8.8.8.8"},
+		{ip: "2001:4860:4860::8888"},
+		{ip: "::ffff:8.8.8.8"},
+		{ip: "", wantErr: ErrInvalidIP},
+		{ip: "8.8.8", wantErr: ErrInvalidIP},
+		{ip: "8.8.8.8:80", wantErr: ErrInvalidIP},
+		{ip: "localhost", wantErr: ErrInvalidIP},
+		{ip: "10.0.0.1", wantErr: ErrNonPublicIP},
+		{ip: "172.16.5.4", wantErr: ErrNonPublicIP},
+		{ip: "192.168.1.1", wantErr: ErrNonPublicIP},
+		{ip: "fd00::1", wantErr: ErrNonPublicIP},
+		{ip: "127.0.0.1", wantErr: ErrNonPublicIP},
+		{ip: "::1", wantErr: ErrNonPublicIP},
+		{ip: "0.0.0.0", wantErr: ErrNonPublicIP},
+		{ip: "::", wantErr: ErrNonPublicIP},
+		{ip: "169.254.1.1", wantErr: ErrNonPublicIP},
+		{ip: "fe80::1", wantErr: ErrNonPublicIP},
+		{ip: "224.0.0.1", wantErr: ErrNonPublicIP},
+		{ip: "ff02::1", wantErr: ErrNonPublicIP},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			addr, err := parsePublicIP(tt.ip)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parsePublicIP(%q) error = %v, want %v", tt.ip, err, tt.wantErr)
+			}
+			if (addr != nil) != (tt.wantErr == nil) {
+				t.Errorf("parsePublicIP(%q) = %v, want an address only without error", tt.ip, addr)
+			}
+		})
+	}
+}
@@ -39,7 +39,7 @@ type createCategoryRequest struct {
 //	@Failure		404						{object}	errorResponse			"Data not found error"
 //	@Failure		409						{object}	errorResponse			"Data conflict error"
 //	@Failure		500						{object}	errorResponse			"Internal server error"
-//	@Router			/categories [post]
+//	@Router			/v1/categories [post]
 //	@Security		BearerAuth
 func (ch *CategoryHandler) CreateCategory(ctx *gin.Context) {
 	var req createCategoryRequest
@@ -80,7 +80,7 @@ type getCategoryRequest struct {
 //	@Failure		400	{object}	errorResponse		"Validation error"
 //	@Failure		404	{object}	errorResponse		"Data not found error"
 //	@Failure		500	{object}	errorResponse		"Internal server error"
-//	@Router			/categories/{id} [get]
+//	@Router			/v1/categories/{id} [get]
 //	@Security		BearerAuth
 func (ch *CategoryHandler) GetCategory(ctx *gin.Context) {
 	var req getCategoryRequest
@@ -104,23 +104,31 @@ func (ch *CategoryHandler) GetCategory(ctx *gin.Context) {
 
 // listCategoriesRequest represents a request body for listing categories
 type listCategoriesRequest struct {
-	Skip  uint64 `form:"skip"`
-	Limit uint64 `form:"limit"`
+	IncludeDeleted bool   `form:"include_deleted"`
+	Sort           string `form:"sort" example:"name"`
+	Skip           uint64 `form:"skip"`
+	Limit          uint64 `form:"limit"`
+
+	paging
 }
 
 // ListCategories godoc
 //
 //	@Summary		List categories
-//	@Description	List categories with pagination
+//	@Description	List categories with pagination, newest first unless sorted otherwise.
+//	@Description	With a cursor, the pages follow each other through the next_cursor of the previous page instead of skip
 //	@Tags			Categories
 //	@Accept			json
 //	@Produce		json
-//	@Param			skip	query		uint64			false	"Skip"
-//	@Param			limit	query		uint64			false	"Limit"
-//	@Success		200		{object}	meta			"Categories displayed"
-//	@Failure		400		{object}	errorResponse	"Validation error"
-//	@Failure		500		{object}	errorResponse	"Internal server error"
-//	@Router			/categories [get]
+//	@Param			include_deleted	query		bool			false	"Include soft deleted categories"
+//	@Param			sort			query		string			false	"Comma separated fields among id, name and created_at, each descending when prefixed with -"
+//	@Param			cursor			query		string			false	"Page with a cursor instead of skip, empty for the first page"
+//	@Param			skip			query		uint64			false	"Skip"
+//	@Param			limit			query		uint64			false	"Limit"
+//	@Success		200				{object}	meta			"Categories displayed"
+//	@Failure		400				{object}	errorResponse	"Validation error"
+//	@Failure		500				{object}	errorResponse	"Internal server error"
+//	@Router			/v1/categories [get]
 //	@Security		BearerAuth
 func (ch *CategoryHandler) ListCategories(ctx *gin.Context) {
 	var req listCategoriesRequest
@@ -135,18 +143,46 @@ func (ch *CategoryHandler) ListCategories(ctx *gin.Context) {
 		req.Limit = 10
 	}
 
-	categories, err := ch.svc.ListCategories(ctx, req.Skip, req.Limit)
+	sort, err := domain.ParseCategorySort(req.Sort)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+	filter := domain.CategoryFilter{
+		IncludeDeleted: req.IncludeDeleted,
+		Sort:           sort,
+	}
+
+	skip, limit := req.Skip, req.Limit
+	if req.keyset() {
+		filter.After, err = req.after(string(sort), len(sort.Keys()))
+		if err != nil {
+			handleError(ctx, err)
+			return
+		}
+		// One more category than the limit tells whether the page is the last
+		skip, limit = 0, req.Limit+1
+	}
+
+	categories, err := ch.svc.ListCategories(ctx, filter, skip, limit)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
+	var nextCursor string
+	if req.keyset() && uint64(len(categories)) > req.Limit {
+		categories = categories[:req.Limit]
+		nextCursor = domain.NewCategoryCursor(sort, &categories[len(categories)-1]).Encode()
+	}
+
 	for _, category := range categories {
 		categoriesList = append(categoriesList, newCategoryResponse(&category))
 	}
 
 	total := uint64(len(categoriesList))
 	meta := newMeta(total, req.Limit, req.Skip)
+	meta.NextCursor = nextCursor
 	rsp := toMap(meta, categoriesList, "categories")
 
 	handleSuccess(ctx, rsp)
@@ -174,7 +210,7 @@ type updateCategoryRequest struct {
 //	@Failure		404						{object}	errorResponse			"Data not found error"
 //	@Failure		409						{object}	errorResponse			"Data conflict error"
 //	@Failure		500						{object}	errorResponse			"Internal server error"
-//	@Router			/categories/{id} [patch]
+//	@Router			/v1/categories/{id} [patch]
 //	@Security		BearerAuth
 func (ch *CategoryHandler) UpdateCategory(ctx *gin.Context) {
 	var req updateCategoryRequest
@@ -214,7 +250,7 @@ type deleteCategoryRequest struct {
 // DeleteCategory godoc
 //
 //	@Summary		Delete a category
-//	@Description	Delete a category by id
+//	@Description	Soft delete a category by id, a category used by a product cannot be deleted
 //	@Tags			Categories
 //	@Accept			json
 //	@Produce		json
@@ -225,7 +261,7 @@ type deleteCategoryRequest struct {
 //	@Failure		403	{object}	errorResponse	"Forbidden error"
 //	@Failure		404	{object}	errorResponse	"Data not found error"
 //	@Failure		500	{object}	errorResponse	"Internal server error"
-//	@Router			/categories/{id} [delete]
+//	@Router			/v1/categories/{id} [delete]
 //	@Security		BearerAuth
 func (ch *CategoryHandler) DeleteCategory(ctx *gin.Context) {
 	var req deleteCategoryRequest
@@ -244,3 +280,45 @@ func (ch *CategoryHandler) DeleteCategory(ctx *gin.Context) {
 
 	handleSuccess(ctx, nil)
 }
+
+// restoreCategoryRequest represents a request body for restoring a category
+type restoreCategoryRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// RestoreCategory godoc
+//
+//	@Summary		Restore a category
+//	@Description	Restore a soft deleted category by id
+//	@Tags			Categories
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string				true	"Category ID"
+//	@Success		200	{object}	categoryResponse	"Category restored"
+//	@Failure		400	{object}	errorResponse		"Validation error"
+//	@Failure		401	{object}	errorResponse		"Unauthorized error"
+//	@Failure		403	{object}	errorResponse		"Forbidden error"
+//	@Failure		404	{object}	errorResponse		"Data not found error"
+//	@Failure		409	{object}	errorResponse		"Data conflict error"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
+//	@Router			/v1/categories/{id}/restore [post]
+//	@Security		BearerAuth
+func (ch *CategoryHandler) RestoreCategory(ctx *gin.Context) {
+	var req restoreCategoryRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	id, _ := uuid.Parse(req.ID)
+
+	category, err := ch.svc.RestoreCategory(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newCategoryResponse(category)
+
+	handleSuccess(ctx, rsp)
+}
@@ -0,0 +1,155 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+// ExchangeRateHandler represents the HTTP handler for exchange rate-related requests
+type ExchangeRateHandler struct {
+	svc port.ExchangeRateService
+}
+
+// NewExchangeRateHandler creates a new ExchangeRateHandler instance
+func NewExchangeRateHandler(svc port.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		svc,
+	}
+}
+
+// exchangeRateResponse represents an exchange rate response body
+type exchangeRateResponse struct {
+	Currency  string    `json:"currency" example:"EUR"`
+	Base      string    `json:"base" example:"USD"`
+	Rate      string    `json:"rate" example:"0.92"`
+	UpdatedAt time.Time `json:"updated_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newExchangeRateResponse is a helper function to create a response body for handling exchange rate data
+func newExchangeRateResponse(rate *domain.ExchangeRate) exchangeRateResponse {
+	return exchangeRateResponse{
+		Currency:  rate.Currency,
+		Base:      domain.DefaultCurrency,
+		Rate:      rate.Rate.String(),
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
+
+// ListExchangeRates godoc
+//
+//	@Summary		List exchange rates
+//	@Description	List the exchange rates against the base currency used to convert prices
+//	@Tags			Exchange rates
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	exchangeRateResponse	"Exchange rates retrieved"
+//	@Failure		500	{object}	errorResponse			"Internal server error"
+//	@Router			/v1/exchange-rates [get]
+func (eh *ExchangeRateHandler) ListExchangeRates(ctx *gin.Context) {
+	rates, err := eh.svc.ListExchangeRates(ctx)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	ratesList := make([]exchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		ratesList = append(ratesList, newExchangeRateResponse(&rate))
+	}
+
+	total := uint64(len(ratesList))
+	meta := newMeta(total, total, 0)
+	rsp := toMap(meta, ratesList, "exchange_rates")
+
+	handleSuccess(ctx, rsp)
+}
+
+// setExchangeRateRequest represents a request body for setting an exchange rate
+type setExchangeRateRequest struct {
+	Currency string          `uri:"currency" binding:"required,len=3,alpha" swaggerignore:"true"`
+	Rate     decimal.Decimal `json:"rate" swaggertype:"string" example:"0.92"`
+}
+
+// SetExchangeRate godoc
+//
+//	@Summary		Set an exchange rate
+//	@Description	Create or replace how many units of a currency one unit of the base currency buys
+//	@Tags			Exchange rates
+//	@Accept			json
+//	@Produce		json
+//	@Param			currency				path		string					true	"Currency"
+//	@Param			setExchangeRateRequest	body		setExchangeRateRequest	true	"Set exchange rate request"
+//	@Success		200						{object}	exchangeRateResponse	"Exchange rate set"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		401						{object}	errorResponse			"Unauthorized error"
+//	@Failure		403						{object}	errorResponse			"Forbidden error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/v1/exchange-rates/{currency} [put]
+//	@Security		BearerAuth
+func (eh *ExchangeRateHandler) SetExchangeRate(ctx *gin.Context) {
+	var req setExchangeRateRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	rate := domain.ExchangeRate{
+		Currency: req.Currency,
+		Rate:     req.Rate,
+	}
+
+	_, err := eh.svc.SetExchangeRate(ctx, &rate)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newExchangeRateResponse(&rate)
+
+	handleSuccess(ctx, rsp)
+}
+
+// deleteExchangeRateRequest represents a request body for deleting an exchange rate
+type deleteExchangeRateRequest struct {
+	Currency string `uri:"currency" binding:"required,len=3,alpha"`
+}
+
+// DeleteExchangeRate godoc
+//
+//	@Summary		Delete an exchange rate
+//	@Description	Delete the exchange rate of a currency, prices can no longer be converted to it
+//	@Tags			Exchange rates
+//	@Accept			json
+//	@Produce		json
+//	@Param			currency	path		string			true	"Currency"
+//	@Success		200			{object}	response		"Exchange rate deleted"
+//	@Failure		400			{object}	errorResponse	"Validation error"
+//	@Failure		401			{object}	errorResponse	"Unauthorized error"
+//	@Failure		403			{object}	errorResponse	"Forbidden error"
+//	@Failure		404			{object}	errorResponse	"Data not found error"
+//	@Failure		500			{object}	errorResponse	"Internal server error"
+//	@Router			/v1/exchange-rates/{currency} [delete]
+//	@Security		BearerAuth
+func (eh *ExchangeRateHandler) DeleteExchangeRate(ctx *gin.Context) {
+	var req deleteExchangeRateRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	err := eh.svc.DeleteExchangeRate(ctx, req.Currency)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
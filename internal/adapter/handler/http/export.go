@@ -0,0 +1,294 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+// exportProductsRequest represents a request body for exporting products
+type exportProductsRequest struct {
+	listProductsRequest
+	Format    string   `form:"format" binding:"omitempty,oneof=pdf csv xlsx"`
+	Sheets    string   `form:"sheets" binding:"omitempty,oneof=category single"`
+	Group     string   `form:"group" binding:"omitempty,oneof=category none"`
+	Columns   []string `form:"columns"`
+	Delimiter string   `form:"delimiter"`
+	BOM       bool     `form:"bom"`
+}
+
+// format returns the requested format, a PDF report by default
+func (req exportProductsRequest) format() domain.ExportFormat {
+	if req.Format == "" {
+		return domain.ExportPDF
+	}
+	return domain.ExportFormat(req.Format)
+}
+
+// options returns the export options of the request
+func (req exportProductsRequest) options() domain.ExportOptions {
+	return domain.ExportOptions{
+		Columns:   req.Columns,
+		Delimiter: req.Delimiter,
+		BOM:       req.BOM,
+		Sheets:    req.Sheets,
+		Group:     req.Group,
+		Currency:  req.Currency,
+		Skip:      req.Skip,
+		Limit:     req.Limit,
+	}
+}
+
+// exportResponseWriter writes an export as the response body. The response is only started by the first write,
+// so an export failing before writing anything still returns a JSON error
+type exportResponseWriter struct {
+	ctx *gin.Context
+	// name is the name of the file without its extension
+	name    string
+	format  domain.ExportFormat
+	started bool
+}
+
+// Write starts the response if needed and writes to its body
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.ctx.Header("Content-Type", w.format.ContentType())
+		w.ctx.Header("Content-Disposition", "attachment; filename="+w.name+"."+string(w.format))
+		w.ctx.Status(http.StatusOK)
+	}
+	return w.ctx.Writer.Write(p)
+}
+
+// Flush sends the body written so far to the client
+func (w *exportResponseWriter) Flush() {
+	if w.started {
+		w.ctx.Writer.Flush()
+	}
+}
+
+// fail ends a failed export, with a JSON error when nothing was written yet. Otherwise the status is already
+// sent and the connection is closed before the end of the response, so the client sees the transfer failed
+// instead of a complete file
+func (w *exportResponseWriter) fail(err error) {
+	if !w.started {
+		handleError(w.ctx, err)
+		return
+	}
+
+	slog.Error("Error exporting products", "name", w.name, "format", w.format, "error", err)
+	w.ctx.Abort()
+	if conn, _, err := w.ctx.Writer.Hijack(); err == nil {
+		conn.Close()
+	}
+}
+
+// ExportHandler represents the HTTP handler for export job-related requests
+type ExportHandler struct {
+	svc port.ExportService
+}
+
+// NewExportHandler creates a new ExportHandler instance
+func NewExportHandler(svc port.ExportService) *ExportHandler {
+	return &ExportHandler{
+		svc,
+	}
+}
+
+// createExportRequest represents a request body for creating an export job
+type createExportRequest struct {
+	Format         string   `json:"format" binding:"required,oneof=pdf csv xlsx" example:"csv"`
+	CategoryIDs    []string `json:"category_ids"`
+	WarehouseIDs   []string `json:"warehouse_ids"`
+	Query          string   `json:"q"`
+	IncludeDeleted bool     `json:"include_deleted"`
+	Near           string   `json:"near" binding:"required_with=RadiusKM" example:"21.0285,105.8542"`
+	RadiusKM       float64  `json:"radius_km" binding:"omitempty,gt=0"`
+	Sort           string   `json:"sort" example:"-added_date,name"`
+	Currency       string   `json:"currency" binding:"omitempty,len=3,alpha" example:"EUR"`
+	Columns        []string `json:"columns" example:"reference,name,price"`
+	Delimiter      string   `json:"delimiter" example:";"`
+	BOM            bool     `json:"bom"`
+	Sheets         string   `json:"sheets" binding:"omitempty,oneof=category single"`
+	Group          string   `json:"group" binding:"omitempty,oneof=category none"`
+	Skip           uint64   `json:"skip"`
+	Limit          uint64   `json:"limit"`
+}
+
+// CreateExport godoc
+//
+//	@Summary		Create an export job
+//	@Description	Queue an export of the products matching the filters, generated in the background as a PDF report, a CSV file or an Excel workbook.
+//	@Description	The options are those of the product export, a PDF report holds all the matching products unless a limit is given.
+//	@Description	The job is polled until completed, then its file is downloaded until it expires
+//	@Tags			Exports
+//	@Accept			json
+//	@Produce		json
+//	@Param			createExportRequest	body		createExportRequest	true	"Create export request"
+//	@Success		200					{object}	exportResponse		"Export job created"
+//	@Failure		400					{object}	errorResponse		"Validation error"
+//	@Failure		422					{object}	errorResponse		"Unknown client location error"
+//	@Failure		500					{object}	errorResponse		"Internal server error"
+//	@Router			/v1/exports [post]
+//	@Security		BearerAuth
+func (eh *ExportHandler) CreateExport(ctx *gin.Context) {
+	var req createExportRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	list := listProductsRequest{
+		CategoryIDs:    req.CategoryIDs,
+		WarehouseIDs:   req.WarehouseIDs,
+		Query:          req.Query,
+		IncludeDeleted: req.IncludeDeleted,
+		Near:           req.Near,
+		RadiusKM:       req.RadiusKM,
+		Sort:           req.Sort,
+	}
+	filter, err := list.filter(ctx.ClientIP())
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	job := domain.ExportJob{
+		Format: domain.ExportFormat(req.Format),
+		Filter: filter,
+		Options: domain.ExportOptions{
+			Columns:   req.Columns,
+			Delimiter: req.Delimiter,
+			BOM:       req.BOM,
+			Sheets:    req.Sheets,
+			Group:     req.Group,
+			Currency:  req.Currency,
+			Skip:      req.Skip,
+			Limit:     req.Limit,
+		},
+	}
+
+	created, err := eh.svc.CreateExport(ctx, &job)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newExportResponse(created)
+
+	handleSuccess(ctx, rsp)
+}
+
+// exportRequest represents a request body for an export job
+type exportRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// GetExport godoc
+//
+//	@Summary		Get an export job
+//	@Description	Get the state and the progress of an export job, rows being the number of products exported so far
+//	@Tags			Exports
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string			true	"Export job ID"
+//	@Success		200	{object}	exportResponse	"Export job retrieved"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/exports/{id} [get]
+//	@Security		BearerAuth
+func (eh *ExportHandler) GetExport(ctx *gin.Context) {
+	var req exportRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	id, _ := uuid.Parse(req.ID)
+
+	job, err := eh.svc.GetExport(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newExportResponse(job)
+
+	handleSuccess(ctx, rsp)
+}
+
+// CancelExport godoc
+//
+//	@Summary		Cancel an export job
+//	@Description	Cancel a pending or running export job, a running job stops within a few seconds and its file is removed
+//	@Tags			Exports
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string			true	"Export job ID"
+//	@Success		200	{object}	exportResponse	"Export job canceled"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		409	{object}	errorResponse	"Export job already finished error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/exports/{id}/cancel [post]
+//	@Security		BearerAuth
+func (eh *ExportHandler) CancelExport(ctx *gin.Context) {
+	var req exportRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	id, _ := uuid.Parse(req.ID)
+
+	job, err := eh.svc.CancelExport(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newExportResponse(job)
+
+	handleSuccess(ctx, rsp)
+}
+
+// DownloadExport godoc
+//
+//	@Summary		Download the file of an export job
+//	@Description	Download the file of a completed export job until it expires, range requests are supported
+//	@Tags			Exports
+//	@Produce		application/pdf,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			id	path		string			true	"Export job ID"
+//	@Success		200	{file}		file			"Export file"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		409	{object}	errorResponse	"Export job not completed yet error"
+//	@Failure		410	{object}	errorResponse	"Export job failed, canceled or expired error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/exports/{id}/download [get]
+//	@Security		BearerAuth
+func (eh *ExportHandler) DownloadExport(ctx *gin.Context) {
+	var req exportRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	id, _ := uuid.Parse(req.ID)
+
+	job, file, err := eh.svc.OpenExport(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	defer file.Close()
+
+	ctx.Header("Content-Type", job.Format.ContentType())
+	ctx.Header("Content-Disposition", "attachment; filename="+job.DownloadName())
+	http.ServeContent(ctx.Writer, ctx.Request, job.DownloadName(), *job.FinishedAt, file)
+}
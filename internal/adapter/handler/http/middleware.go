@@ -1,5 +1,12 @@
 package http
 
+import (
+	"github.com/gin-gonic/gin"
+	sloggin "github.com/samber/slog-gin"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/util"
+)
+
 const (
 	// authorizationHeaderKey is the key for authorization header in the request
 	authorizationHeaderKey = "authorization"
@@ -7,8 +14,27 @@ const (
 	authorizationType = "bearer"
 	// authorizationPayloadKey is the key for authorization payload in the context
 	authorizationPayloadKey = "authorization_payload"
+	// claimedActorHeaderKey is the header naming who the client claims to be, recorded as is without being verified
+	claimedActorHeaderKey = "X-Actor"
 )
 
+// requestContextMiddleware is a middleware to carry the actor and the request id
+// assigned by the logger middleware in the request context for the audit trail.
+// The actor is the authenticated user, the actor named by the client is only carried as claimed
+func requestContextMiddleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		reqCtx := ctx.Request.Context()
+		if payload, ok := ctx.Get(authorizationPayloadKey); ok {
+			reqCtx = util.WithActor(reqCtx, payload.(*domain.TokenPayload).UserID.String())
+		}
+		reqCtx = util.WithClaimedActor(reqCtx, ctx.GetHeader(claimedActorHeaderKey))
+		reqCtx = util.WithRequestID(reqCtx, sloggin.GetRequestID(ctx))
+		ctx.Request = ctx.Request.WithContext(reqCtx)
+
+		ctx.Next()
+	}
+}
+
 // authMiddleware is a middleware to check if the user is authenticated
 //func authMiddleware(token port.TokenService) gin.HandlerFunc {
 //	return func(ctx *gin.Context) {
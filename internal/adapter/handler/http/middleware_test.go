@@ -0,0 +1,53 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/util"
+)
+
+func TestRequestContextMiddleware(t *testing.T) {
+	userID := uuid.New()
+	tests := []struct {
+		name             string
+		payload          *domain.TokenPayload
+		header           string
+		wantActor        string
+		wantClaimedActor string
+	}{
+		{name: "anonymous", wantActor: util.DefaultActor},
+		{name: "claimed actor not trusted", header: "admin", wantActor: util.DefaultActor, wantClaimedActor: "admin"},
+		{name: "authenticated", payload: &domain.TokenPayload{UserID: userID}, header: "admin", wantActor: userID.String(), wantClaimedActor: "admin"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			if tt.payload != nil {
+				router.Use(func(ctx *gin.Context) { ctx.Set(authorizationPayloadKey, tt.payload) })
+			}
+			router.Use(requestContextMiddleware())
+
+			var actor, claimedActor string
+			router.GET("/", func(ctx *gin.Context) {
+				actor = util.ActorFromContext(ctx.Request.Context())
+				claimedActor = util.ClaimedActorFromContext(ctx.Request.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(claimedActorHeaderKey, tt.header)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if actor != tt.wantActor || claimedActor != tt.wantClaimedActor {
+				t.Errorf("actor = %q, claimed actor = %q, want %q, %q", actor, claimedActor, tt.wantActor, tt.wantClaimedActor)
+			}
+		})
+	}
+}
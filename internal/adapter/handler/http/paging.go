@@ -1,5 +1,7 @@
 package http
 
+import "github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+
 // paging represents as common pagination logic
 // use it as embedded struct
 const (
@@ -27,3 +29,17 @@ func (p *paging) DefaultPaging() {
 func (p *paging) CursorFirstPage() bool {
 	return p.Cursor == nil || *p.Cursor == "" || *p.Cursor == "\"\""
 }
+
+// keyset reports whether the list is paged with a cursor rather than with skip, an empty cursor asking for the first page
+func (p *paging) keyset() bool {
+	return p.Cursor != nil
+}
+
+// after decodes the cursor of the request for a list with the sort and the number of sort keys,
+// nil for the first page
+func (p *paging) after(sort string, keys int) (*domain.Cursor, error) {
+	if p.CursorFirstPage() {
+		return nil, nil
+	}
+	return domain.DecodeCursor(*p.Cursor, sort, keys)
+}
@@ -0,0 +1,182 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+// PriceHandler represents the HTTP handler for price-related requests
+type PriceHandler struct {
+	svc port.PriceService
+}
+
+// NewPriceHandler creates a new PriceHandler instance
+func NewPriceHandler(svc port.PriceService) *PriceHandler {
+	return &PriceHandler{
+		svc,
+	}
+}
+
+// productPricesRequest represents the path of the price timeline of a product
+type productPricesRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// schedulePriceRequest represents a request body for scheduling a price
+type schedulePriceRequest struct {
+	Price    domain.Money `json:"price" swaggertype:"string" example:"1500.00"`
+	StartsAt time.Time    `json:"starts_at" example:"2030-01-01T00:00:00Z"`
+	EndsAt   *time.Time   `json:"ends_at" example:"2030-01-31T00:00:00Z"`
+}
+
+// SchedulePrice godoc
+//
+//	@Summary		Schedule a price
+//	@Description	Schedule a new price for a product. Without an end the price replaces the base price once it starts, with an end it is a promotion overriding the base price while it lasts. A missing start means now
+//	@Tags			Prices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id						path		string					true	"Product ID"
+//	@Param			schedulePriceRequest	body		schedulePriceRequest	true	"Schedule price request"
+//	@Success		200						{object}	priceChangeResponse		"Price scheduled"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		404						{object}	errorResponse			"Data not found error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/v1/products/{id}/prices [post]
+//	@Security		BearerAuth
+func (ph *PriceHandler) SchedulePrice(ctx *gin.Context) {
+	var uri productPricesRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req schedulePriceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	productID, _ := uuid.Parse(uri.ID)
+
+	change := domain.PriceChange{
+		ProductID: productID,
+		Price:     req.Price,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+	}
+
+	_, err := ph.svc.SchedulePriceChange(ctx, &change)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newPriceChangeResponse(&change)
+
+	handleSuccess(ctx, rsp)
+}
+
+// listPricesRequest represents a request body for listing the price timeline of a product
+type listPricesRequest struct {
+	Skip  uint64 `form:"skip"`
+	Limit uint64 `form:"limit"`
+}
+
+// ListPrices godoc
+//
+//	@Summary		List the prices of a product
+//	@Description	List the price timeline of a product, latest start first, including past, running, scheduled and canceled prices
+//	@Tags			Prices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"Product ID"
+//	@Param			skip	query		uint64				false	"Skip"
+//	@Param			limit	query		uint64				false	"Limit"
+//	@Success		200		{object}	priceChangeResponse	"Prices retrieved"
+//	@Failure		400		{object}	errorResponse		"Validation error"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/v1/products/{id}/prices [get]
+func (ph *PriceHandler) ListPrices(ctx *gin.Context) {
+	var uri productPricesRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req listPricesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if req.Limit == 0 || req.Limit > 1000 {
+		req.Limit = 10
+	}
+
+	productID, _ := uuid.Parse(uri.ID)
+
+	changes, err := ph.svc.ListPriceChanges(ctx, productID, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	pricesList := make([]priceChangeResponse, 0, len(changes))
+	for _, change := range changes {
+		pricesList = append(pricesList, newPriceChangeResponse(&change))
+	}
+
+	total := uint64(len(pricesList))
+	meta := newMeta(total, req.Limit, req.Skip)
+	rsp := toMap(meta, pricesList, "prices")
+
+	handleSuccess(ctx, rsp)
+}
+
+// cancelPriceRequest represents a request body for canceling a price
+type cancelPriceRequest struct {
+	ID      string `uri:"id" binding:"required,uuid"`
+	PriceID string `uri:"price_id" binding:"required,uuid"`
+}
+
+// CancelPrice godoc
+//
+//	@Summary		Cancel a price
+//	@Description	Cancel a scheduled price or end a running promotion. A price that already replaced the base price can not be canceled
+//	@Tags			Prices
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string				true	"Product ID"
+//	@Param			price_id	path		string				true	"Price ID"
+//	@Success		200			{object}	priceChangeResponse	"Price canceled"
+//	@Failure		400			{object}	errorResponse		"Validation error"
+//	@Failure		404			{object}	errorResponse		"Data not found error"
+//	@Failure		409			{object}	errorResponse		"Price already applied error"
+//	@Failure		500			{object}	errorResponse		"Internal server error"
+//	@Router			/v1/products/{id}/prices/{price_id} [delete]
+//	@Security		BearerAuth
+func (ph *PriceHandler) CancelPrice(ctx *gin.Context) {
+	var req cancelPriceRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	productID, _ := uuid.Parse(req.ID)
+	id, _ := uuid.Parse(req.PriceID)
+
+	change, err := ph.svc.CancelPriceChange(ctx, productID, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newPriceChangeResponse(change)
+
+	handleSuccess(ctx, rsp)
+}
@@ -1,27 +1,53 @@
 package http
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/jung-kurt/gofpdf"
 	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
 	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
 )
 
 // ProductHandler represents the HTTP handler for product-related requests
 type ProductHandler struct {
-	svc port.ProductService
+	svc     port.ProductService
+	rateSvc port.ExchangeRateService
+	// exporter writes the exports answered synchronously
+	exporter port.ProductExporter
+	importer port.ProductImporter
 }
 
 // NewProductHandler creates a new ProductHandler instance
-func NewProductHandler(svc port.ProductService) *ProductHandler {
+func NewProductHandler(svc port.ProductService, rateSvc port.ExchangeRateService, exporter port.ProductExporter, importer port.ProductImporter) *ProductHandler {
 	return &ProductHandler{
 		svc,
+		rateSvc,
+		exporter,
+		importer,
 	}
 }
 
+// convertPriceRequest represents the query of a request whose prices are also shown in another currency
+type convertPriceRequest struct {
+	Currency string `form:"currency" binding:"omitempty,len=3,alpha"`
+}
+
+// newConverter returns the converter to the requested currency, or nil when no currency is requested
+func (ph *ProductHandler) newConverter(ctx *gin.Context, req convertPriceRequest) (*domain.CurrencyConverter, error) {
+	if req.Currency == "" {
+		return nil, nil
+	}
+
+	return ph.rateSvc.NewConverter(ctx, req.Currency)
+}
+
 // createProductRequest represents a request body for creating a new product
 type createProductRequest struct {
 	ID         string
@@ -29,7 +55,7 @@ type createProductRequest struct {
 	Name       string
 	Status     string
 	CategoryID string
-	Price      float64
+	Price      domain.Money `swaggertype:"string" example:"12.50"`
 	StockCity  string
 	SupplierID string
 	Quantity   int
@@ -50,7 +76,7 @@ type createProductRequest struct {
 //	@Failure		404						{object}	errorResponse			"Data not found error"
 //	@Failure		409						{object}	errorResponse			"Data conflict error"
 //	@Failure		500						{object}	errorResponse			"Internal server error"
-//	@Router			/products [post]
+//	@Router			/v1/products [post]
 //	@Security		BearerAuth
 func (ph *ProductHandler) CreateProduct(ctx *gin.Context) {
 	var req createProductRequest
@@ -78,11 +104,17 @@ func (ph *ProductHandler) CreateProduct(ctx *gin.Context) {
 		supplierID = &tempID
 	}
 
+	status, err := domain.ParseProductStatus(req.Status)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
 	product := domain.Product{
 		ID:         uuid.UUID{},
 		Reference:  req.Reference,
 		Name:       req.Name,
-		Status:     domain.ParseProductStatus(req.Status),
+		Status:     status,
 		CategoryID: categoryID,
 		Price:      req.Price,
 		StockCity:  req.StockCity,
@@ -90,7 +122,7 @@ func (ph *ProductHandler) CreateProduct(ctx *gin.Context) {
 		Quantity:   req.Quantity,
 	}
 
-	_, err := ph.svc.CreateProduct(ctx, &product)
+	_, err = ph.svc.CreateProduct(ctx, &product)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -106,19 +138,27 @@ type getProductRequest struct {
 	ID string `uri:"id" binding:"required,uuid"`
 }
 
+// getProductQuery represents the query parameters for retrieving a product
+type getProductQuery struct {
+	convertPriceRequest
+	IncludeDeleted bool `form:"include_deleted"`
+}
+
 // GetProduct godoc
 //
 //	@Summary		Get a product
-//	@Description	get a product by id with its category
+//	@Description	get a product by id with its category, with its price converted when a currency is given
 //	@Tags			Products
 //	@Accept			json
 //	@Produce		json
-//	@Param			id	path		string			true	"Product ID"
-//	@Success		200	{object}	productResponse	"Product retrieved"
-//	@Failure		400	{object}	errorResponse	"Validation error"
-//	@Failure		404	{object}	errorResponse	"Data not found error"
-//	@Failure		500	{object}	errorResponse	"Internal server error"
-//	@Router			/products/{id} [get]
+//	@Param			id				path		string			true	"Product ID"
+//	@Param			currency		query		string			false	"Currency to convert the price to"
+//	@Param			include_deleted	query		bool			false	"Include soft deleted products"
+//	@Success		200				{object}	productResponse	"Product retrieved"
+//	@Failure		400				{object}	errorResponse	"Validation error"
+//	@Failure		404				{object}	errorResponse	"Data not found error"
+//	@Failure		500				{object}	errorResponse	"Internal server error"
+//	@Router			/v1/products/{id} [get]
 //	@Security		BearerAuth
 func (ph *ProductHandler) GetProduct(ctx *gin.Context) {
 	var req getProductRequest
@@ -126,19 +166,131 @@ func (ph *ProductHandler) GetProduct(ctx *gin.Context) {
 		validationError(ctx, err)
 		return
 	}
+	var query getProductQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		validationError(ctx, err)
+		return
+	}
 	id, err := uuid.Parse(req.ID)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
 	}
 
-	product, err := ph.svc.GetProduct(ctx, id)
+	converter, err := ph.newConverter(ctx, query.convertPriceRequest)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	product, err := ph.svc.GetProduct(ctx, id, query.IncludeDeleted)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
 	rsp := newProductResponse(product)
+	if err := rsp.convertPrice(product, converter); err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// maxBatchGetIDs is the largest number of product ids of a batch get
+const maxBatchGetIDs = 100
+
+// batchGetProductsRequest represents a request body for getting products by id
+type batchGetProductsRequest struct {
+	IDs      []string `json:"ids" binding:"required,min=1,max=100,dive,uuid" example:"6b7a4b0e-3f0a-4c8e-9a51-0e4b8b2f6d2a"`
+	Currency string   `json:"currency" binding:"omitempty,len=3,alpha" example:"EUR"`
+}
+
+// BatchGetProducts godoc
+//
+//	@Summary		Get products by id
+//	@Description	Get up to 100 products by id with their category, in the order of the ids, each id once.
+//	@Description	The ids matching no product, deleted ones included, are listed in missing_ids. GET /v1/products?ids=a,b,c does the same for short lists
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			batchGetProductsRequest	body		batchGetProductsRequest		true	"Batch get products request"
+//	@Success		200						{object}	batchGetProductsResponse	"Products retrieved"
+//	@Failure		400						{object}	errorResponse				"Validation error"
+//	@Failure		500						{object}	errorResponse				"Internal server error"
+//	@Router			/v1/products:batchGet [post]
+//	@Security		BearerAuth
+func (ph *ProductHandler) BatchGetProducts(ctx *gin.Context) {
+	var req batchGetProductsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	ids := make([]uuid.UUID, len(req.IDs))
+	for i, id := range req.IDs {
+		ids[i] = uuid.MustParse(id)
+	}
+
+	ph.getProducts(ctx, ids, convertPriceRequest{Currency: req.Currency})
+}
+
+// parseProductIDs parses the ids query of a batch get, whose values are comma separated ids
+func parseProductIDs(values []string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for _, value := range values {
+		for _, field := range strings.Split(value, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+
+			id, err := uuid.Parse(field)
+			if err != nil {
+				return nil, fmt.Errorf("ids: %q is not a valid id", field)
+			}
+			ids = append(ids, id)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, errors.New("ids: at least one id is required")
+	}
+	if len(ids) > maxBatchGetIDs {
+		return nil, fmt.Errorf("ids: at most %d ids can be requested at once", maxBatchGetIDs)
+	}
+
+	return ids, nil
+}
+
+// getProducts responds with the products with the ids and the ids matching no product
+func (ph *ProductHandler) getProducts(ctx *gin.Context, ids []uuid.UUID, convertReq convertPriceRequest) {
+	converter, err := ph.newConverter(ctx, convertReq)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	products, missing, err := ph.svc.GetProducts(ctx, ids, nil)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := batchGetProductsResponse{
+		Products:   make([]productResponse, len(products)),
+		MissingIDs: make([]uuid.UUID, len(missing)),
+	}
+	copy(rsp.MissingIDs, missing)
+
+	for i := range products {
+		rsp.Products[i] = newProductResponse(&products[i])
+		if err := rsp.Products[i].convertPrice(&products[i], converter); err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
 
 	handleSuccess(ctx, rsp)
 }
@@ -154,8 +306,9 @@ func (ph *ProductHandler) GetProduct(ctx *gin.Context) {
 //	@Success		200	{object}	productResponse	"Product retrieved"
 //	@Failure		400	{object}	errorResponse	"Validation error"
 //	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		422	{object}	errorResponse	"Unknown stock city or client location error"
 //	@Failure		500	{object}	errorResponse	"Internal server error"
-//	@Router			/products/{id}/distance [get]
+//	@Router			/v1/products/{id}/distance [get]
 //	@Security		BearerAuth
 func (ph *ProductHandler) GetProductDistance(ctx *gin.Context) {
 	var req getProductRequest
@@ -181,36 +334,179 @@ func (ph *ProductHandler) GetProductDistance(ctx *gin.Context) {
 	handleSuccess(ctx, rsp)
 }
 
+// locationRequest represents the optional location of the client in a query, set together
+type locationRequest struct {
+	Lat *float64 `form:"lat" binding:"required_with=Lon,omitempty,min=-90,max=90"`
+	Lon *float64 `form:"lon" binding:"required_with=Lat,omitempty,min=-180,max=180"`
+}
+
+// coordinates returns the requested location, or nil when none is given
+func (lr locationRequest) coordinates() *domain.Coordinates {
+	if lr.Lat == nil || lr.Lon == nil {
+		return nil
+	}
+	return &domain.Coordinates{Latitude: *lr.Lat, Longitude: *lr.Lon}
+}
+
+// GetProductAvailability godoc
+//
+//	@Summary		Get the availability of a product near a location
+//	@Description	List the warehouses holding stock of a product with their quantity and their distance from the client, nearest first.
+//	@Description	The client is located with lat and lon, or from its ip when they are not given. Warehouses without coordinates come last
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string						true	"Product ID"
+//	@Param			lat	query		number						false	"Latitude of the client"
+//	@Param			lon	query		number						false	"Longitude of the client"
+//	@Success		200	{object}	productAvailabilityResponse	"Product availability retrieved"
+//	@Failure		400	{object}	errorResponse				"Validation error"
+//	@Failure		404	{object}	errorResponse				"Data not found error"
+//	@Failure		422	{object}	errorResponse				"Unknown client location error"
+//	@Failure		500	{object}	errorResponse				"Internal server error"
+//	@Router			/v1/products/{id}/availability [get]
+func (ph *ProductHandler) GetProductAvailability(ctx *gin.Context) {
+	var uri getProductRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req locationRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	id, _ := uuid.Parse(uri.ID)
+
+	availability, err := ph.svc.GetProductAvailability(ctx, id, req.coordinates(), ctx.ClientIP())
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newProductAvailabilityResponse(availability)
+
+	handleSuccess(ctx, rsp)
+}
+
 // listProductsRequest represents a request body for listing products
 type listProductsRequest struct {
-	CategoryIDs []string `form:"category_ids"`
-	Query       string   `form:"q"`
-	Skip        uint64   `form:"skip"`
-	Limit       uint64   `form:"limit"`
+	CategoryIDs    []string `form:"category_ids"`
+	WarehouseIDs   []string `form:"warehouse_ids"`
+	Query          string   `form:"q"`
+	IncludeDeleted bool     `form:"include_deleted"`
+	Near           string   `form:"near" binding:"required_with=RadiusKM"`
+	RadiusKM       float64  `form:"radius_km" binding:"omitempty,gt=0"`
+	Sort           string   `form:"sort" example:"-added_date,name"`
+	Skip           uint64   `form:"skip"`
+	Limit          uint64   `form:"limit"`
 
+	convertPriceRequest
 	paging
 }
 
+// filter returns the product filter of the request. Near is "latitude,longitude",
+// or "ip" for the location of the client ip address, and is needed to sort by distance
+func (req listProductsRequest) filter(clientIP string) (domain.ProductFilter, error) {
+	filter := domain.ProductFilter{
+		Search:         req.Query,
+		CategoryIDs:    make([]uuid.UUID, len(req.CategoryIDs)),
+		WarehouseIDs:   make([]uuid.UUID, len(req.WarehouseIDs)),
+		IncludeDeleted: req.IncludeDeleted,
+		RadiusKM:       req.RadiusKM,
+	}
+
+	sort, err := domain.ParseProductSort(req.Sort)
+	if err != nil {
+		return filter, err
+	}
+	if sort.SortsBy("distance") && req.Near == "" {
+		return filter, fmt.Errorf("%w: sorting by distance needs near", domain.ErrInvalidSort)
+	}
+	filter.Sort = sort
+
+	for i, id := range req.CategoryIDs {
+		categoryID, err := uuid.Parse(id)
+		if err != nil {
+			return filter, err
+		}
+		filter.CategoryIDs[i] = categoryID
+	}
+
+	for i, id := range req.WarehouseIDs {
+		warehouseID, err := uuid.Parse(id)
+		if err != nil {
+			return filter, err
+		}
+		filter.WarehouseIDs[i] = warehouseID
+	}
+
+	switch req.Near {
+	case "":
+	case "ip":
+		filter.NearIP = clientIP
+	default:
+		near, err := domain.ParseCoordinates(req.Near)
+		if err != nil {
+			return filter, err
+		}
+		filter.Near = &near
+	}
+
+	return filter, nil
+}
+
 // ListProducts godoc
 //
 //	@Summary		List products
-//	@Description	List products with pagination
+//	@Description	List products with pagination.
+//	@Description	Near a location, each product carries its distance_km to the closest of its stock city and the warehouses holding it, radius_km keeps the products within that distance and sort=distance lists the closest first
+//	@Description	The products are listed newest first unless sorted otherwise. With a cursor, the pages follow each other through the next_cursor of the previous page instead of skip
+//	@Description	With ids, the products with those comma separated ids are returned as by POST /v1/products:batchGet and the other filters are ignored
 //	@Tags			Products
 //	@Accept			json
 //	@Produce		json
+//	@Param			ids				query		string			false	"Comma separated IDs of the products to get, up to 100"
 //	@Param			category_ids	query		[]string		false	"Category IDs"
+//	@Param			warehouse_ids	query		[]string		false	"IDs of warehouses holding stock of the products"
 //	@Param			q				query		string			false	"Query"
+//	@Param			include_deleted	query		bool			false	"Include soft deleted products"
+//	@Param			near			query		string			false	"Origin as latitude,longitude, or ip for the location of the client"
+//	@Param			radius_km		query		number			false	"Keep the products within that distance of the origin"
+//	@Param			sort			query		string			false	"Comma separated fields among id, reference, name, added_date, status, price, quantity, stock_city and distance, each descending when prefixed with -. Newest first by default"
+//	@Param			currency		query		string			false	"Currency to convert the prices to"
+//	@Param			cursor			query		string			false	"Page with a cursor instead of skip, empty for the first page then the next_cursor of the previous page"
 //	@Param			skip			query		uint64			false	"Skip"
 //	@Param			limit			query		uint64			false	"Limit"
 //	@Success		200				{object}	meta			"Products retrieved"
 //	@Failure		400				{object}	errorResponse	"Validation error"
+//	@Failure		422				{object}	errorResponse	"Unknown client location error"
 //	@Failure		500				{object}	errorResponse	"Internal server error"
-//	@Router			/products [get]
+//	@Router			/v1/products [get]
 //	@Security		BearerAuth
 func (ph *ProductHandler) ListProducts(ctx *gin.Context) {
 	var req listProductsRequest
 	var productsList []productResponse
 
+	if values, ok := ctx.GetQueryArray("ids"); ok {
+		ids, err := parseProductIDs(values)
+		if err != nil {
+			validationError(ctx, err)
+			return
+		}
+
+		var convertReq convertPriceRequest
+		if err := ctx.ShouldBindQuery(&convertReq); err != nil {
+			validationError(ctx, err)
+			return
+		}
+
+		ph.getProducts(ctx, ids, convertReq)
+		return
+	}
+
 	if err := ctx.ShouldBind(&req); err != nil {
 		validationError(ctx, err)
 		return
@@ -220,51 +516,99 @@ func (ph *ProductHandler) ListProducts(ctx *gin.Context) {
 		req.Limit = 10
 	}
 
-	categories := make([]uuid.UUID, len(req.CategoryIDs))
-	for i, id := range req.CategoryIDs {
-		categoryID, err := uuid.Parse(id)
-		if err != nil {
-			validationError(ctx, err)
-			return
-		}
-		categories[i] = categoryID
+	filter, err := req.filter(ctx.ClientIP())
+	if err != nil {
+		validationError(ctx, err)
+		return
 	}
 
-	products, err := ph.svc.ListProducts(ctx, req.Query, categories, req.Skip, req.Limit)
+	converter, err := ph.newConverter(ctx, req.convertPriceRequest)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	products, nextCursor, err := ph.listProducts(ctx, req, filter, nil)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
 	for _, product := range products {
-		productsList = append(productsList, newProductResponse(&product))
+		rsp := newProductResponse(&product)
+		if err := rsp.convertPrice(&product, converter); err != nil {
+			handleError(ctx, err)
+			return
+		}
+		productsList = append(productsList, rsp)
 	}
 
 	total := uint64(len(productsList))
 	meta := newMeta(total, req.Limit, req.Skip)
+	meta.NextCursor = nextCursor
 	rsp := toMap(meta, productsList, "products")
 
 	handleSuccess(ctx, rsp)
 }
 
+// listProducts lists a page of the products of the filter. A page of a list paged with a cursor starts after it,
+// one more product than the limit being read to tell the last page, and comes with the cursor of the next page
+func (ph *ProductHandler) listProducts(ctx *gin.Context, req listProductsRequest, filter domain.ProductFilter, view *domain.ProductView) ([]domain.Product, string, error) {
+	if !req.keyset() {
+		products, err := ph.svc.ListProducts(ctx, filter, view, req.Skip, req.Limit)
+		return products, "", err
+	}
+
+	after, err := req.after(string(filter.Sort), len(filter.Sort.Keys()))
+	if err != nil {
+		return nil, "", err
+	}
+	filter.After = after
+
+	products, err := ph.svc.ListProducts(ctx, filter, view, 0, req.Limit+1)
+	if err != nil || uint64(len(products)) <= req.Limit {
+		return products, "", err
+	}
+
+	products = products[:req.Limit]
+	return products, domain.NewProductCursor(filter.Sort, &products[len(products)-1]).Encode(), nil
+}
+
 // ExportProducts godoc
 //
 //	@Summary		Export products
-//	@Description	Export a list of products as a PDF file
+//	@Description	Export a page of products as a PDF report, or all the products matching the filters as a CSV file streamed as it is read
+//	@Description	or as an Excel workbook. The workbook has a summary sheet with the share of all the products held by each category and supplier,
+//	@Description	then one sheet of products per category, or a single one, each with a frozen header row with filters. Prices, quantities and distances are numbers, dates are dates in UTC.
+//	@Description	The PDF report is laid out by the configured template, it starts with the filters and the generation time, and may group the products by category with subtotals of the quantity and stock value.
+//	@Description	The columns of a CSV or Excel export are chosen with columns, among id, reference, name, added_date, status, category_id, category, price, effective_price, currency, converted_price, converted_currency, stock_city, supplier_id, quantity, stock_value, distance_km and deleted_at
 //	@Tags			Products
 //	@Accept			json
-//	@Produce		application/pdf
+//	@Produce		application/pdf,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format			query		string			false	"File format"												Enums(pdf, csv, xlsx)	default(pdf)
+//	@Param			sheets			query		string			false	"Excel sheets of products"									Enums(category, single)	default(category)
+//	@Param			group			query		string			false	"Grouping of the PDF report, the template one by default"	Enums(category, none)
 //	@Param			category_ids	query		[]string		false	"Category IDs"
+//	@Param			warehouse_ids	query		[]string		false	"IDs of warehouses holding stock of the products"
 //	@Param			q				query		string			false	"Query"
-//	@Param			skip			query		uint64			false	"Skip"
-//	@Param			limit			query		uint64			false	"Limit"
-//	@Success		200				{file}		application/pdf	"PDF file generated"
+//	@Param			include_deleted	query		bool			false	"Include soft deleted products"
+//	@Param			near			query		string			false	"Origin as latitude,longitude, or ip for the location of the client"
+//	@Param			radius_km		query		number			false	"Keep the products within that distance of the origin"
+//	@Param			sort			query		string			false	"Comma separated fields among id, reference, name, added_date, status, price, quantity, stock_city and distance, each descending when prefixed with -. Newest first by default"
+//	@Param			currency		query		string			false	"Currency to convert the prices to"
+//	@Param			columns			query		[]string		false	"CSV or Excel columns, comma separated or repeated"
+//	@Param			delimiter		query		string			false	"CSV delimiter, a single character, tab or semicolon"	default(,)
+//	@Param			bom				query		bool			false	"Start the CSV file with a UTF-8 byte order mark"
+//	@Param			skip			query		uint64			false	"Skip, PDF only"
+//	@Param			limit			query		uint64			false	"Limit, PDF only"
+//	@Success		200				{file}		application/pdf	"File generated"
 //	@Failure		400				{object}	errorResponse	"Validation error"
+//	@Failure		422				{object}	errorResponse	"Unknown client location error"
 //	@Failure		500				{object}	errorResponse	"Internal server error"
-//	@Router			/products/export [get]
+//	@Router			/v1/products/export [get]
 //	@Security		BearerAuth
 func (ph *ProductHandler) ExportProducts(ctx *gin.Context) {
-	var req listProductsRequest
+	var req exportProductsRequest
 
 	if err := ctx.ShouldBind(&req); err != nil {
 		validationError(ctx, err)
@@ -275,56 +619,333 @@ func (ph *ProductHandler) ExportProducts(ctx *gin.Context) {
 		req.Limit = 10
 	}
 
-	categories := make([]uuid.UUID, len(req.CategoryIDs))
-	for i, id := range req.CategoryIDs {
-		categoryID, err := uuid.Parse(id)
+	filter, err := req.filter(ctx.ClientIP())
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	w := &exportResponseWriter{ctx: ctx, name: "products", format: req.format()}
+	if err := ph.exporter.ExportProducts(ctx, w, req.format(), filter, req.options(), nil); err != nil {
+		w.fail(err)
+	}
+}
+
+// productLabelsRequest represents a request body for printing product labels
+type productLabelsRequest struct {
+	listProductsRequest
+	Layout    string `form:"layout" binding:"omitempty,oneof=avery-l7159 avery-l7160 avery-l7163 avery-5160"`
+	Symbology string `form:"symbology" binding:"omitempty,oneof=code128 qr"`
+	Encode    string `form:"encode" binding:"omitempty,oneof=reference id"`
+	Start     int    `form:"start" binding:"omitempty,min=1"`
+}
+
+// PrintProductLabels godoc
+//
+//	@Summary		Print product labels
+//	@Description	Print a page of the products matching the filters as a PDF sheet of shelf labels, at most 1000 labels, the first 1000 when no limit is given.
+//	@Description	Each label shows the name, reference and price of a product with a Code128 barcode or a QR code of its reference or ID.
+//	@Description	The layouts are avery-l7159 (3x8 labels on A4), avery-l7160 (3x7 on A4), avery-l7163 (2x7 on A4) and avery-5160 (3x10 on Letter).
+//	@Description	Start prints from a position of the first sheet, to reuse a partly used sheet. IDs are long for a barcode, a QR code scans better
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		application/pdf
+//	@Param			layout			query		string			false	"Label sheet"										Enums(avery-l7159, avery-l7160, avery-l7163, avery-5160)	default(avery-l7159)
+//	@Param			symbology		query		string			false	"Code printed on the labels"						Enums(code128, qr)											default(code128)
+//	@Param			encode			query		string			false	"Product field the code holds"						Enums(reference, id)										default(reference)
+//	@Param			start			query		int				false	"Position of the first label on the first sheet"	minimum(1)													default(1)
+//	@Param			category_ids	query		[]string		false	"Category IDs"
+//	@Param			warehouse_ids	query		[]string		false	"IDs of warehouses holding stock of the products"
+//	@Param			q				query		string			false	"Query"
+//	@Param			include_deleted	query		bool			false	"Include soft deleted products"
+//	@Param			near			query		string			false	"Origin as latitude,longitude, or ip for the location of the client"
+//	@Param			radius_km		query		number			false	"Keep the products within that distance of the origin"
+//	@Param			sort			query		string			false	"Comma separated fields among id, reference, name, added_date, status, price, quantity, stock_city and distance, each descending when prefixed with -. Newest first by default"
+//	@Param			currency		query		string			false	"Currency to convert the prices to"
+//	@Param			skip			query		uint64			false	"Skip"
+//	@Param			limit			query		uint64			false	"Limit"	maximum(1000)	default(1000)
+//	@Success		200				{file}		application/pdf	"Labels printed"
+//	@Failure		400				{object}	errorResponse	"Validation error"
+//	@Failure		422				{object}	errorResponse	"Unknown client location error"
+//	@Failure		500				{object}	errorResponse	"Internal server error"
+//	@Router			/v1/products/labels [get]
+//	@Security		BearerAuth
+func (ph *ProductHandler) PrintProductLabels(ctx *gin.Context) {
+	var req productLabelsRequest
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	filter, err := req.filter(ctx.ClientIP())
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	options := domain.LabelOptions{
+		Layout:    req.Layout,
+		Symbology: req.Symbology,
+		Encode:    req.Encode,
+		Currency:  req.Currency,
+		Start:     req.Start,
+		Skip:      req.Skip,
+		Limit:     req.Limit,
+	}
+
+	w := &exportResponseWriter{ctx: ctx, name: "labels", format: domain.ExportPDF}
+	if err := ph.exporter.ExportLabels(ctx, w, filter, options); err != nil {
+		w.fail(err)
+	}
+}
+
+// maxImportSize is the largest file a product import accepts
+const maxImportSize = 32 << 20
+
+// importProductsRequest represents the query of a request importing products
+type importProductsRequest struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	DryRun bool   `form:"dry_run"`
+	Upsert bool   `form:"upsert"`
+	Mode   string `form:"mode" binding:"omitempty,oneof=atomic partial"`
+}
+
+// format returns the format of the imported file, from the format parameter or else from its content type or name
+func (r importProductsRequest) format(contentType, name string) (domain.ImportFormat, error) {
+	if r.Format != "" {
+		return domain.ImportFormat(r.Format), nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/csv":
+		return domain.ImportCSV, nil
+	case "application/x-ndjson", "application/jsonl", "application/x-jsonlines":
+		return domain.ImportJSONL, nil
+	}
+
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return domain.ImportCSV, nil
+	case ".jsonl", ".ndjson":
+		return domain.ImportJSONL, nil
+	}
+
+	return "", fmt.Errorf("%w: unknown format, set the format parameter", domain.ErrInvalidImportFile)
+}
+
+// ImportProducts godoc
+//
+//	@Summary		Import products
+//	@Description	Import products from a CSV file or a JSON Lines file, sent as the request body or as the file field of a multipart form.
+//	@Description	The columns, or the keys of the JSON objects, are reference, name, status, category_id, price, currency, stock_city, supplier_id and quantity, only reference and name are required.
+//	@Description	The format is read from the format parameter, the content type (text/csv or application/x-ndjson) or the file extension.
+//	@Description	Every row is validated as a created product, its category and supplier must exist and its reference must be new unless upsert is set.
+//	@Description	An atomic import writes nothing when a row is rejected, a partial one writes the valid rows, a dry run only validates them.
+//	@Description	The report lists the rejected rows by line with their errors
+//	@Tags			Products
+//	@Accept			text/csv,application/x-ndjson,multipart/form-data
+//	@Produce		json
+//	@Param			format	query		string					false	"File format"	Enums(csv, jsonl)
+//	@Param			dry_run	query		bool					false	"Validate the rows without writing them"
+//	@Param			upsert	query		bool					false	"Update the products whose reference exists, empty values are kept"
+//	@Param			mode	query		string					false	"Import nothing or the valid rows when a row is rejected"	Enums(atomic, partial)	default(atomic)
+//	@Param			file	formData	file					false	"Imported file, when sent as a multipart form"
+//	@Success		200		{object}	importReportResponse	"Import report"
+//	@Failure		400		{object}	errorResponse			"Validation error"
+//	@Failure		401		{object}	errorResponse			"Unauthorized error"
+//	@Failure		403		{object}	errorResponse			"Forbidden error"
+//	@Failure		409		{object}	errorResponse			"Data conflict error"
+//	@Failure		500		{object}	errorResponse			"Internal server error"
+//	@Router			/v1/products/import [post]
+//	@Security		BearerAuth
+func (ph *ProductHandler) ImportProducts(ctx *gin.Context) {
+	var req importProductsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportSize)
+
+	body, contentType, name := io.Reader(ctx.Request.Body), ctx.ContentType(), ""
+	if contentType == "multipart/form-data" {
+		header, err := ctx.FormFile("file")
 		if err != nil {
-			validationError(ctx, err)
+			handleError(ctx, importBodyError(err))
+			return
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			handleError(ctx, err)
 			return
 		}
-		categories[i] = categoryID
+		defer file.Close()
+
+		body, contentType, name = file, header.Header.Get("Content-Type"), header.Filename
 	}
 
-	products, err := ph.svc.ListProducts(ctx, req.Query, categories, req.Skip, req.Limit)
+	format, err := req.format(contentType, name)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
 
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 16)
-	pdf.Cell(40, 10, "Product List")
+	rows, err := ph.importer.ParseProducts(body, format)
+	if err != nil {
+		handleError(ctx, importBodyError(err))
+		return
+	}
 
-	pdf.SetFont("Arial", "", 12)
-	for _, product := range products {
-		pdf.Ln(10)
-		pdf.Cell(35, 5, product.Reference)
-		pdf.Cell(35, 5, product.Name)
-		pdf.Cell(35, 5, product.AddedDate.Format("2006/01/02"))
-		pdf.Cell(35, 5, product.Status.String())
-		pdf.Cell(35, 5, product.Category.Name)
-		pdf.Cell(35, 5, fmt.Sprintf("%.2f", product.Price))
-	}
-
-	ctx.Header("Content-Type", "application/pdf")
-	ctx.Header("Content-Disposition", "attachment; filename=products.pdf")
-	ctx.Header("Content-Transfer-Encoding", "binary")
-	err = pdf.Output(ctx.Writer)
+	options := domain.ImportOptions{
+		DryRun: req.DryRun,
+		Upsert: req.Upsert,
+		Mode:   domain.ImportMode(req.Mode),
+	}
+
+	report, err := ph.svc.ImportProducts(ctx, rows, options)
 	if err != nil {
 		handleError(ctx, err)
 		return
 	}
+
+	rsp := newImportReportResponse(report)
+
+	handleSuccess(ctx, rsp)
+}
+
+// importBodyError reports an imported file larger than maxImportSize as an invalid file
+func importBodyError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: larger than %d MB", domain.ErrInvalidImportFile, maxImportSize>>20)
+	}
+	if errors.Is(err, http.ErrMissingFile) {
+		return fmt.Errorf("%w: missing file field", domain.ErrInvalidImportFile)
+	}
+	return err
+}
+
+// batchProductOperationRequest represents an operation of a product batch request. A create takes the fields of a
+// new product, an update the id of the product and the fields to update, and a delete the id of the product
+type batchProductOperationRequest struct {
+	Method     string       `json:"method" binding:"required,oneof=create update delete" example:"update"`
+	ID         string       `json:"id" binding:"omitempty,uuid" example:"0b5e6e3c-8f4d-4a59-9f0c-2f1d6c7b8a90"`
+	Reference  string       `json:"reference" example:"SKU-0001"`
+	Name       string       `json:"name" example:"Steel water bottle"`
+	Status     string       `json:"status" enums:"Available,On Order,Out of Stock" example:"Available"`
+	CategoryID string       `json:"category_id" binding:"omitempty,uuid" example:"2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"`
+	Price      domain.Money `json:"price" swaggertype:"string" example:"12.50"`
+	StockCity  string       `json:"stock_city" example:"Hanoi"`
+	SupplierID string       `json:"supplier_id" binding:"omitempty,uuid" example:"6f1f7b0e-3c2a-4d5e-8f9a-0b1c2d3e4f5a"`
+	Quantity   *int         `json:"quantity" binding:"omitempty,min=0" example:"200"`
+}
+
+// operation converts the request into a product operation
+func (r batchProductOperationRequest) operation() (domain.ProductOperation, error) {
+	method := domain.ProductOperationMethod(r.Method)
+	if method != domain.OperationCreate && r.ID == "" {
+		return domain.ProductOperation{}, fmt.Errorf("id is required to %s a product", method)
+	}
+
+	status, err := domain.ParseProductStatus(r.Status)
+	if err != nil {
+		return domain.ProductOperation{}, err
+	}
+
+	product := domain.Product{
+		Reference: r.Reference,
+		Name:      r.Name,
+		Status:    status,
+		Price:     r.Price,
+		StockCity: r.StockCity,
+	}
+	if r.Quantity != nil {
+		product.Quantity = *r.Quantity
+	}
+	if r.ID != "" {
+		product.ID = uuid.MustParse(r.ID)
+	}
+	if r.CategoryID != "" {
+		id := uuid.MustParse(r.CategoryID)
+		product.CategoryID = &id
+	}
+	if r.SupplierID != "" {
+		id := uuid.MustParse(r.SupplierID)
+		product.SupplierID = &id
+	}
+
+	return domain.ProductOperation{Method: method, Product: product, QuantitySet: r.Quantity != nil}, nil
+}
+
+// batchProductsRequest represents a request body for a batch of product operations
+type batchProductsRequest struct {
+	Mode       string                         `json:"mode" binding:"omitempty,oneof=atomic best_effort" example:"atomic"`
+	Operations []batchProductOperationRequest `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+// BatchProducts godoc
+//
+//	@Summary		Create, update and delete products in a batch
+//	@Description	Apply up to 100 creations, updates and deletions of products in a single transaction, each validated as the single request it stands for.
+//	@Description	An update only sets the fields that are not empty. A product can only be updated or deleted by one operation of a batch.
+//	@Description	An atomic batch applies all the operations or none of them, the operations it did not apply fail with status 424.
+//	@Description	A best effort batch applies the operations that succeed, the operations still pending after a few failures being written fail with status 424.
+//	@Description	The result of each operation holds its status code and its product or errors
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			batchProductsRequest	body		batchProductsRequest	true	"Batch products request"
+//	@Success		200						{object}	batchProductsResponse	"Batch applied, in whole or in part"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		401						{object}	errorResponse			"Unauthorized error"
+//	@Failure		403						{object}	errorResponse			"Forbidden error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/v1/products:batch [post]
+//	@Security		BearerAuth
+func (ph *ProductHandler) BatchProducts(ctx *gin.Context) {
+	var req batchProductsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	operations := make([]domain.ProductOperation, len(req.Operations))
+	for i, operationReq := range req.Operations {
+		operation, err := operationReq.operation()
+		if err != nil {
+			validationError(ctx, fmt.Errorf("operation %d: %w", i, err))
+			return
+		}
+		operations[i] = operation
+	}
+
+	mode := domain.BatchMode(req.Mode)
+	if mode == "" {
+		mode = domain.BatchAtomic
+	}
+
+	results, err := ph.svc.BatchProducts(ctx, operations, mode)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newBatchProductsResponse(mode, results)
+
+	handleSuccess(ctx, rsp)
 }
 
 // updateProductRequest represents a request body for updating a product
 type updateProductRequest struct {
-	ID         string  `uri:"id" binding:"required,uuid"`
-	CategoryID string  `json:"category_id" binding:"omitempty,required,uuid"`
-	Name       string  `json:"name" binding:"omitempty"`
-	Price      float64 `json:"price" binding:"omitempty,min=0" example:"2000"`
-	Stock      int64   `json:"stock" binding:"omitempty,min=0" example:"200"`
-	Status     string  `json:"status" binding:"omitempty,oneof=Available On Order Out of Stock"`
+	ID         string       `uri:"id" binding:"required,uuid"`
+	CategoryID string       `json:"category_id" binding:"omitempty,required,uuid"`
+	Name       string       `json:"name" binding:"omitempty"`
+	Price      domain.Money `json:"price" swaggertype:"string" example:"2000.00"`
+	Stock      *int64       `json:"stock" binding:"omitempty,min=0" example:"200"`
+	Status     string       `json:"status" enums:"Available,On Order,Out of Stock" example:"Available"`
 }
 
 // UpdateProduct godoc
@@ -343,7 +964,7 @@ type updateProductRequest struct {
 //	@Failure		404						{object}	errorResponse			"Data not found error"
 //	@Failure		409						{object}	errorResponse			"Data conflict error"
 //	@Failure		500						{object}	errorResponse			"Internal server error"
-//	@Router			/products/{id} [patch]
+//	@Router			/v1/products/{id} [patch]
 //	@Security		BearerAuth
 func (ph *ProductHandler) UpdateProduct(ctx *gin.Context) {
 	var req updateProductRequest
@@ -372,15 +993,24 @@ func (ph *ProductHandler) UpdateProduct(ctx *gin.Context) {
 		categoryID = &tempId
 	}
 
+	status, err := domain.ParseProductStatus(req.Status)
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
 	product := domain.Product{
 		ID:         id,
 		CategoryID: categoryID,
 		Name:       req.Name,
 		Price:      req.Price,
-		Status:     domain.ParseProductStatus(req.Status),
+		Status:     status,
+	}
+	if req.Stock != nil {
+		product.Quantity = int(*req.Stock)
 	}
 
-	_, err = ph.svc.UpdateProduct(ctx, &product)
+	_, err = ph.svc.UpdateProduct(ctx, &product, req.Stock != nil)
 	if err != nil {
 		handleError(ctx, err)
 		return
@@ -399,7 +1029,7 @@ type deleteProductRequest struct {
 // DeleteProduct godoc
 //
 //	@Summary		Delete a product
-//	@Description	Delete a product by id
+//	@Description	Soft delete a product by id, it can be restored until it is purged
 //	@Tags			Products
 //	@Accept			json
 //	@Produce		json
@@ -410,7 +1040,7 @@ type deleteProductRequest struct {
 //	@Failure		403	{object}	errorResponse	"Forbidden error"
 //	@Failure		404	{object}	errorResponse	"Data not found error"
 //	@Failure		500	{object}	errorResponse	"Internal server error"
-//	@Router			/products/{id} [delete]
+//	@Router			/v1/products/{id} [delete]
 //	@Security		BearerAuth
 func (ph *ProductHandler) DeleteProduct(ctx *gin.Context) {
 	var req deleteProductRequest
@@ -427,3 +1057,104 @@ func (ph *ProductHandler) DeleteProduct(ctx *gin.Context) {
 
 	handleSuccess(ctx, nil)
 }
+
+// restoreProductRequest represents a request body for restoring a product
+type restoreProductRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// RestoreProduct godoc
+//
+//	@Summary		Restore a product
+//	@Description	Restore a soft deleted product by id, its category being restored first when it was deleted too
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string			true	"Product ID"
+//	@Success		200	{object}	productResponse	"Product restored"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		409	{object}	errorResponse	"Data conflict error"
+//	@Failure		422	{object}	errorResponse	"Category deleted error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/products/{id}/restore [post]
+//	@Security		BearerAuth
+func (ph *ProductHandler) RestoreProduct(ctx *gin.Context) {
+	var req restoreProductRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	id, _ := uuid.Parse(req.ID)
+
+	product, err := ph.svc.RestoreProduct(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newProductResponse(product)
+
+	handleSuccess(ctx, rsp)
+}
+
+// getProductHistoryRequest represents a request body for retrieving the history of a product
+type getProductHistoryRequest struct {
+	ID    string `uri:"id" binding:"required,uuid"`
+	Skip  uint64 `form:"skip"`
+	Limit uint64 `form:"limit"`
+}
+
+// GetProductHistory godoc
+//
+//	@Summary		Get the history of a product
+//	@Description	List the changes made to a product, newest first, with the actor, request id and the changed fields.
+//	@Description	The actor is the authenticated user or anonymous, the claimed_actor is the unverified name sent by the client in the X-Actor header
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"Product ID"
+//	@Param			skip	query		uint64				false	"Skip"
+//	@Param			limit	query		uint64				false	"Limit"
+//	@Success		200		{object}	auditEntryResponse	"Product history retrieved"
+//	@Failure		400		{object}	errorResponse		"Validation error"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/v1/products/{id}/history [get]
+//	@Security		BearerAuth
+func (ph *ProductHandler) GetProductHistory(ctx *gin.Context) {
+	var req getProductHistoryRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if req.Limit == 0 || req.Limit > 1000 {
+		req.Limit = 10
+	}
+
+	id, _ := uuid.Parse(req.ID)
+
+	entries, err := ph.svc.GetProductHistory(ctx, id, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	entriesList := make([]auditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		entriesList = append(entriesList, newAuditEntryResponse(&entry))
+	}
+
+	total := uint64(len(entriesList))
+	meta := newMeta(total, req.Limit, req.Skip)
+	rsp := toMap(meta, entriesList, "history")
+
+	handleSuccess(ctx, rsp)
+}
@@ -0,0 +1,229 @@
+package http
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// productViewRequest represents the query selecting the fields and the related objects of the products of a v2 response
+type productViewRequest struct {
+	Fields  string `form:"fields" example:"id,name,price"`
+	Include string `form:"include" example:"category,supplier,warehouses"`
+}
+
+// view returns the product view of the request, all the fields without relations by default
+func (req productViewRequest) view() (*domain.ProductView, error) {
+	view, err := domain.ParseProductView(req.Fields, req.Include)
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
+
+// listProductsV2Request represents a request body for listing products with the v2 API
+type listProductsV2Request struct {
+	listProductsRequest
+	productViewRequest
+}
+
+// getProductV2Request represents a request body for getting a product with the v2 API
+type getProductV2Request struct {
+	convertPriceRequest
+	productViewRequest
+}
+
+// batchGetProductsV2Response represents a v2 response body of products got by id
+type batchGetProductsV2Response struct {
+	Products   []productV2Response `json:"products"`
+	MissingIDs []uuid.UUID         `json:"missing_ids" example:"6b7a4b0e-3f0a-4c8e-9a51-0e4b8b2f6d2a"`
+}
+
+// ListProductsV2 godoc
+//
+//	@Summary		List products
+//	@Description	List products with pagination as ListProducts does, with snake_case keys.
+//	@Description	Fields selects the fields represented, the id is always represented. A list only reads the columns the fields need.
+//	@Description	Include embeds related objects: category, supplier, and warehouses for the stock of the product per warehouse.
+//	@Description	The products are listed newest first unless sorted otherwise. With a cursor, the pages follow each other through the next_cursor of the previous page instead of skip
+//	@Description	With ids, the products with those comma separated ids are returned in their order with the ids matching no product, and the other filters are ignored.
+//	@Description	Products got by ids are read whole whatever the fields, as they are cached whole
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			fields			query		string			false	"Comma separated fields: id, reference, name, added_date, status, category_id, price, effective_price, stock_city, supplier_id, quantity, distance_km, deleted_at. All of them by default"
+//	@Param			include			query		string			false	"Comma separated relations: category, supplier, warehouses"
+//	@Param			ids				query		string			false	"Comma separated IDs of the products to get, up to 100"
+//	@Param			category_ids	query		[]string		false	"Category IDs"
+//	@Param			warehouse_ids	query		[]string		false	"IDs of warehouses holding stock of the products"
+//	@Param			q				query		string			false	"Query"
+//	@Param			include_deleted	query		bool			false	"Include soft deleted products"
+//	@Param			near			query		string			false	"Origin as latitude,longitude, or ip for the location of the client"
+//	@Param			radius_km		query		number			false	"Keep the products within that distance of the origin"
+//	@Param			sort			query		string			false	"Comma separated fields among id, reference, name, added_date, status, price, quantity, stock_city and distance, each descending when prefixed with -. Newest first by default"
+//	@Param			currency		query		string			false	"Currency to convert the effective price to, when it is selected"
+//	@Param			cursor			query		string			false	"Page with a cursor instead of skip, empty for the first page then the next_cursor of the previous page"
+//	@Param			skip			query		uint64			false	"Skip"
+//	@Param			limit			query		uint64			false	"Limit"
+//	@Success		200				{object}	meta			"Products retrieved"
+//	@Failure		400				{object}	errorResponse	"Validation error"
+//	@Failure		422				{object}	errorResponse	"Unknown client location error"
+//	@Failure		500				{object}	errorResponse	"Internal server error"
+//	@Router			/v2/products [get]
+//	@Security		BearerAuth
+func (ph *ProductHandler) ListProductsV2(ctx *gin.Context) {
+	var req listProductsV2Request
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	view, err := req.view()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	if values, ok := ctx.GetQueryArray("ids"); ok {
+		ids, err := parseProductIDs(values)
+		if err != nil {
+			validationError(ctx, err)
+			return
+		}
+
+		ph.getProductsV2(ctx, ids, req.convertPriceRequest, view)
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 10
+	}
+
+	filter, err := req.filter(ctx.ClientIP())
+	if err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	converter, err := ph.newConverter(ctx, req.convertPriceRequest)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	products, nextCursor, err := ph.listProducts(ctx, req.listProductsRequest, filter, view)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	productsList := make([]productV2Response, len(products))
+	for i := range products {
+		productsList[i] = newProductV2Response(&products[i], view)
+		if err := productsList[i].convertPrice(&products[i], converter); err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	total := uint64(len(productsList))
+	meta := newMeta(total, req.Limit, req.Skip)
+	meta.NextCursor = nextCursor
+	rsp := toMap(meta, productsList, "products")
+
+	handleSuccess(ctx, rsp)
+}
+
+// GetProductV2 godoc
+//
+//	@Summary		Get a product
+//	@Description	Get a product by id with snake_case keys. Fields selects the fields represented, the id is always represented.
+//	@Description	The product is read whole whatever the fields, as it is cached whole.
+//	@Description	Include embeds related objects: category, supplier, and warehouses for the stock of the product per warehouse
+//	@Tags			Products
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string				true	"Product ID"
+//	@Param			fields		query		string				false	"Comma separated fields, all of them by default"
+//	@Param			include		query		string				false	"Comma separated relations: category, supplier, warehouses"
+//	@Param			currency	query		string				false	"Currency to convert the effective price to, when it is selected"
+//	@Success		200			{object}	productV2Response	"Product retrieved"
+//	@Failure		400			{object}	errorResponse		"Validation error"
+//	@Failure		404			{object}	errorResponse		"Data not found error"
+//	@Failure		500			{object}	errorResponse		"Internal server error"
+//	@Router			/v2/products/{id} [get]
+//	@Security		BearerAuth
+func (ph *ProductHandler) GetProductV2(ctx *gin.Context) {
+	var uriReq getProductRequest
+	if err := ctx.ShouldBindUri(&uriReq); err != nil {
+		validationError(ctx, err)
+		return
+	}
+	var req getProductV2Request
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	view, err := req.view()
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	converter, err := ph.newConverter(ctx, req.convertPriceRequest)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	products, _, err := ph.svc.GetProducts(ctx, []uuid.UUID{uuid.MustParse(uriReq.ID)}, view)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+	if len(products) == 0 {
+		handleError(ctx, domain.ErrDataNotFound)
+		return
+	}
+
+	rsp := newProductV2Response(&products[0], view)
+	if err := rsp.convertPrice(&products[0], converter); err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, rsp)
+}
+
+// getProductsV2 responds with the products with the ids in the v2 representation and the ids matching no product.
+// The view only selects the fields represented, the products are read whole through the cache
+func (ph *ProductHandler) getProductsV2(ctx *gin.Context, ids []uuid.UUID, convertReq convertPriceRequest, view *domain.ProductView) {
+	converter, err := ph.newConverter(ctx, convertReq)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	products, missing, err := ph.svc.GetProducts(ctx, ids, view)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := batchGetProductsV2Response{
+		Products:   make([]productV2Response, len(products)),
+		MissingIDs: make([]uuid.UUID, len(missing)),
+	}
+	copy(rsp.MissingIDs, missing)
+
+	for i := range products {
+		rsp.Products[i] = newProductV2Response(&products[i], view)
+		if err := rsp.Products[i].convertPrice(&products[i], converter); err != nil {
+			handleError(ctx, err)
+			return
+		}
+	}
+
+	handleSuccess(ctx, rsp)
+}
@@ -1,8 +1,11 @@
 package http
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -32,6 +35,8 @@ type meta struct {
 	Total uint64 `json:"total" example:"100"`
 	Limit uint64 `json:"limit" example:"10"`
 	Skip  uint64 `json:"skip" example:"0"`
+	// NextCursor is the cursor of the next page of a list paged with a cursor, empty on the last page
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzb3J0IjoiIiwidmFsdWVzIjpbXX0"`
 }
 
 // newMeta is a helper function to create metadata for a paginated response
@@ -66,8 +71,10 @@ type userResponse struct {
 
 // categoryResponse represents a category response body
 type categoryResponse struct {
-	ID   uuid.UUID `json:"id,omitempty" example:"1"`
-	Name string    `json:"name,omitempty" example:"Foods"`
+	ID        uuid.UUID  `json:"id,omitempty" example:"1"`
+	Name      string     `json:"name,omitempty" example:"Foods"`
+	CreatedAt time.Time  `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"1970-01-01T00:00:00Z"`
 }
 
 // newCategoryResponse is a helper function to create a response body for handling category data
@@ -76,8 +83,24 @@ func newCategoryResponse(category *domain.Category) categoryResponse {
 		return categoryResponse{}
 	}
 	return categoryResponse{
-		ID:   category.ID,
-		Name: category.Name,
+		ID:        category.ID,
+		Name:      category.Name,
+		CreatedAt: category.CreatedAt,
+		DeletedAt: category.DeletedAt,
+	}
+}
+
+// moneyResponse represents an amount in a currency, the amount is a string to keep it exact
+type moneyResponse struct {
+	Amount   string `json:"amount" example:"12.50"`
+	Currency string `json:"currency" example:"USD"`
+}
+
+// newMoneyResponse is a helper function to create a response body for handling money data
+func newMoneyResponse(money domain.Money) moneyResponse {
+	return moneyResponse{
+		Amount:   money.StringFixed(),
+		Currency: money.Currency,
 	}
 }
 
@@ -89,30 +112,398 @@ type productResponse struct {
 	AddedDate  time.Time
 	Status     string
 	CategoryID *uuid.UUID
-	Price      float64
+	Price      json.Number `swaggertype:"number" example:"12.50"`
+	// EffectivePrice is the price the product currently sells at, including a running promotion
+	EffectivePrice json.Number `swaggertype:"number" example:"12.50"`
+	// Currency is the currency of both the price and the effective price
+	Currency   string `example:"USD"`
 	StockCity  string
 	SupplierID *uuid.UUID
 	Quantity   int
+	// ConvertedPrice is the effective price converted to the requested currency
+	ConvertedPrice *moneyResponse `json:"converted_price,omitempty"`
+	// Stock is the availability of the product, in total and per warehouse
+	Stock stockResponse `json:"stock"`
+	// DistanceKM is the distance from the origin of a listing near a location
+	DistanceKM *float64         `json:"distance_km,omitempty"`
+	DeletedAt  *time.Time       `json:"deleted_at,omitempty"`
 	Category   categoryResponse `json:"category,omitempty"`
 }
 
+// stockResponse represents the availability of a product, in total and per warehouse.
+// Unassigned is the stock not held by any warehouse
+type stockResponse struct {
+	Total      int                      `json:"total" example:"12"`
+	Unassigned int                      `json:"unassigned" example:"0"`
+	Warehouses []warehouseStockResponse `json:"warehouses"`
+}
+
+// warehouseStockResponse represents the stock of a product in a warehouse
+type warehouseStockResponse struct {
+	WarehouseID uuid.UUID `json:"warehouse_id" example:"8c2f5d1e-3b7a-4f9c-a1d2-6e4b0c9f7a35"`
+	Name        string    `json:"name" example:"Hanoi North"`
+	City        string    `json:"city" example:"Hanoi"`
+	Quantity    int       `json:"quantity" example:"12"`
+}
+
+// newStockResponse is a helper function to create a response body for handling the stock of a product
+func newStockResponse(product *domain.Product) stockResponse {
+	levels := domain.NewStockLevels(product.Quantity, product.Stocks)
+	rsp := stockResponse{
+		Total:      product.Quantity,
+		Unassigned: levels.Unassigned(),
+		Warehouses: make([]warehouseStockResponse, 0, len(product.Stocks)),
+	}
+	for _, stock := range product.Stocks {
+		rsp.Warehouses = append(rsp.Warehouses, newWarehouseStockResponse(&stock))
+	}
+	return rsp
+}
+
+// newWarehouseStockResponse is a helper function to create a response body for handling the stock of a product in a warehouse
+func newWarehouseStockResponse(stock *domain.WarehouseStock) warehouseStockResponse {
+	return warehouseStockResponse{
+		WarehouseID: stock.Warehouse.ID,
+		Name:        stock.Warehouse.Name,
+		City:        stock.Warehouse.City,
+		Quantity:    stock.Quantity,
+	}
+}
+
 // newProductResponse is a helper function to create a response body for handling product data
 func newProductResponse(product *domain.Product) productResponse {
 	return productResponse{
-		ID:         product.ID,
-		Reference:  product.Reference,
-		Name:       product.Name,
-		AddedDate:  product.AddedDate,
-		Status:     product.Status.String(),
-		CategoryID: product.CategoryID,
-		Price:      product.Price,
-		StockCity:  product.StockCity,
-		SupplierID: product.SupplierID,
-		Quantity:   product.Quantity,
-		Category:   newCategoryResponse(product.Category),
+		ID:             product.ID,
+		Reference:      product.Reference,
+		Name:           product.Name,
+		AddedDate:      product.AddedDate,
+		Status:         product.Status.String(),
+		CategoryID:     product.CategoryID,
+		Price:          json.Number(product.Price.StringFixed()),
+		EffectivePrice: json.Number(product.EffectivePrice.StringFixed()),
+		Currency:       product.Price.Currency,
+		StockCity:      product.StockCity,
+		SupplierID:     product.SupplierID,
+		Quantity:       product.Quantity,
+		Stock:          newStockResponse(product),
+		DistanceKM:     product.DistanceKM,
+		DeletedAt:      product.DeletedAt,
+		Category:       newCategoryResponse(product.Category),
+	}
+}
+
+// convertPrice sets the converted price of the product when a converter is given
+func (pr *productResponse) convertPrice(product *domain.Product, converter *domain.CurrencyConverter) error {
+	if converter == nil {
+		return nil
+	}
+
+	price, err := converter.Convert(product.EffectivePrice)
+	if err != nil {
+		return err
+	}
+
+	converted := newMoneyResponse(price)
+	pr.ConvertedPrice = &converted
+	return nil
+}
+
+// supplierResponse represents a supplier response body
+type supplierResponse struct {
+	ID   uuid.UUID `json:"id" example:"4f1c2b7e-9d3a-4e6b-8a5c-1f2e3d4c5b6a"`
+	Name string    `json:"name" example:"Acme"`
+}
+
+// productV2Response represents a product response body of the v2 API, whose keys are all snake_case.
+// Only the selected fields and the included relations are represented, a selected field without a value
+// being null. The distance is only represented for a listing near a location, and the converted price
+// next to the effective price when a currency is requested
+type productV2Response struct {
+	ID             uuid.UUID                `json:"id" example:"6b7a4b0e-3f0a-4c8e-9a51-0e4b8b2f6d2a"`
+	Reference      string                   `json:"reference" example:"PRD-0001"`
+	Name           string                   `json:"name" example:"Chiko Chicken"`
+	AddedDate      time.Time                `json:"added_date" example:"1970-01-01T00:00:00Z"`
+	Status         string                   `json:"status" example:"Available"`
+	CategoryID     *uuid.UUID               `json:"category_id"`
+	Price          moneyResponse            `json:"price"`
+	EffectivePrice moneyResponse            `json:"effective_price"`
+	ConvertedPrice *moneyResponse           `json:"converted_price,omitempty"`
+	StockCity      string                   `json:"stock_city" example:"Hanoi"`
+	SupplierID     *uuid.UUID               `json:"supplier_id"`
+	Quantity       int                      `json:"quantity" example:"12"`
+	DistanceKM     *float64                 `json:"distance_km,omitempty"`
+	DeletedAt      *time.Time               `json:"deleted_at"`
+	Category       *categoryResponse        `json:"category,omitempty"`
+	Supplier       *supplierResponse        `json:"supplier,omitempty"`
+	Warehouses     []warehouseStockResponse `json:"warehouses,omitempty"`
+
+	view *domain.ProductView
+}
+
+// newProductV2Response is a helper function to create a v2 response body for handling product data
+func newProductV2Response(product *domain.Product, view *domain.ProductView) productV2Response {
+	rsp := productV2Response{
+		ID:             product.ID,
+		Reference:      product.Reference,
+		Name:           product.Name,
+		AddedDate:      product.AddedDate,
+		Status:         product.Status.String(),
+		CategoryID:     product.CategoryID,
+		Price:          newMoneyResponse(product.Price),
+		EffectivePrice: newMoneyResponse(product.EffectivePrice),
+		StockCity:      product.StockCity,
+		SupplierID:     product.SupplierID,
+		Quantity:       product.Quantity,
+		DistanceKM:     product.DistanceKM,
+		DeletedAt:      product.DeletedAt,
+		view:           view,
+	}
+
+	if product.Category != nil {
+		category := newCategoryResponse(product.Category)
+		rsp.Category = &category
+	}
+	if product.Supplier != nil {
+		rsp.Supplier = &supplierResponse{
+			ID:   product.Supplier.ID,
+			Name: product.Supplier.Name,
+		}
+	}
+	if view.Includes(domain.ProductRelationWarehouses) {
+		rsp.Warehouses = make([]warehouseStockResponse, len(product.Stocks))
+		for i := range product.Stocks {
+			rsp.Warehouses[i] = newWarehouseStockResponse(&product.Stocks[i])
+		}
+	}
+
+	return rsp
+}
+
+// convertPrice sets the converted price of the product when a converter is given and the effective price is selected
+func (pr *productV2Response) convertPrice(product *domain.Product, converter *domain.CurrencyConverter) error {
+	if converter == nil || !pr.view.Selects(domain.ProductFieldEffectivePrice) {
+		return nil
+	}
+
+	price, err := converter.Convert(product.EffectivePrice)
+	if err != nil {
+		return err
+	}
+
+	converted := newMoneyResponse(price)
+	pr.ConvertedPrice = &converted
+	return nil
+}
+
+// MarshalJSON encodes the selected fields of the product in the order of domain.ProductFields,
+// followed by the converted price and the included relations
+func (pr productV2Response) MarshalJSON() ([]byte, error) {
+	values := map[domain.ProductField]any{
+		domain.ProductFieldID:             pr.ID,
+		domain.ProductFieldReference:      pr.Reference,
+		domain.ProductFieldName:           pr.Name,
+		domain.ProductFieldAddedDate:      pr.AddedDate,
+		domain.ProductFieldStatus:         pr.Status,
+		domain.ProductFieldCategoryID:     pr.CategoryID,
+		domain.ProductFieldPrice:          pr.Price,
+		domain.ProductFieldEffectivePrice: pr.EffectivePrice,
+		domain.ProductFieldStockCity:      pr.StockCity,
+		domain.ProductFieldSupplierID:     pr.SupplierID,
+		domain.ProductFieldQuantity:       pr.Quantity,
+		domain.ProductFieldDistanceKM:     pr.DistanceKM,
+		domain.ProductFieldDeletedAt:      pr.DeletedAt,
+	}
+
+	var buf bytes.Buffer
+	write := func(key string, value any) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		if buf.Len() > 0 {
+			buf.WriteByte(',')
+		} else {
+			buf.WriteByte('{')
+		}
+		buf.WriteString(strconv.Quote(key))
+		buf.WriteByte(':')
+		buf.Write(data)
+		return nil
+	}
+
+	for _, field := range domain.ProductFields {
+		if !pr.view.Selects(field) || field == domain.ProductFieldDistanceKM && pr.DistanceKM == nil {
+			continue
+		}
+		if err := write(string(field), values[field]); err != nil {
+			return nil, err
+		}
+
+		if field == domain.ProductFieldEffectivePrice && pr.ConvertedPrice != nil {
+			if err := write("converted_price", pr.ConvertedPrice); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	relations := map[domain.ProductRelation]any{
+		domain.ProductRelationCategory:   pr.Category,
+		domain.ProductRelationSupplier:   pr.Supplier,
+		domain.ProductRelationWarehouses: pr.Warehouses,
+	}
+	for _, relation := range domain.ProductRelations {
+		if !pr.view.Includes(relation) {
+			continue
+		}
+		if err := write(string(relation), relations[relation]); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// priceChangeResponse represents a price change response body
+type priceChangeResponse struct {
+	ID        uuid.UUID     `json:"id" example:"2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"`
+	ProductID uuid.UUID     `json:"product_id" example:"0b5e6e3c-8f4d-4a59-9f0c-2f1d6c7b8a90"`
+	Price     moneyResponse `json:"price"`
+	StartsAt  time.Time     `json:"starts_at" example:"2030-01-01T00:00:00Z"`
+	EndsAt    *time.Time    `json:"ends_at,omitempty" example:"2030-01-31T00:00:00Z"`
+	Status    string        `json:"status" example:"scheduled"`
+	AppliedAt *time.Time    `json:"applied_at,omitempty"`
+	CreatedAt time.Time     `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newPriceChangeResponse is a helper function to create a response body for handling price change data
+func newPriceChangeResponse(change *domain.PriceChange) priceChangeResponse {
+	return priceChangeResponse{
+		ID:        change.ID,
+		ProductID: change.ProductID,
+		Price:     newMoneyResponse(change.Price),
+		StartsAt:  change.StartsAt,
+		EndsAt:    change.EndsAt,
+		Status:    string(change.Status(time.Now())),
+		AppliedAt: change.AppliedAt,
+		CreatedAt: change.CreatedAt,
+	}
+}
+
+// auditEntryResponse represents an audit entry response body
+type auditEntryResponse struct {
+	ID     uuid.UUID `json:"id" example:"2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"`
+	Action string    `json:"action" example:"update"`
+	Actor  string    `json:"actor" example:"anonymous"`
+	// ClaimedActor is the actor named by the client in the X-Actor header, which is not verified
+	ClaimedActor string                `json:"claimed_actor,omitempty" example:"jane"`
+	RequestID    string                `json:"request_id" example:"0b5e6e3c-8f4d-4a59-9f0c-2f1d6c7b8a90"`
+	Changes      []fieldChangeResponse `json:"changes"`
+	CreatedAt    time.Time             `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// fieldChangeResponse represents the change of a single field in an audit entry
+type fieldChangeResponse struct {
+	Field  string `json:"field" example:"price"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// newAuditEntryResponse is a helper function to create a response body for handling audit entry data
+func newAuditEntryResponse(entry *domain.AuditEntry) auditEntryResponse {
+	changes := make([]fieldChangeResponse, len(entry.Changes))
+	for i, change := range entry.Changes {
+		changes[i] = fieldChangeResponse{
+			Field:  change.Field,
+			Before: change.Before,
+			After:  change.After,
+		}
+	}
+
+	return auditEntryResponse{
+		ID:           entry.ID,
+		Action:       string(entry.Action),
+		Actor:        entry.Actor,
+		ClaimedActor: entry.ClaimedActor,
+		RequestID:    entry.RequestID,
+		Changes:      changes,
+		CreatedAt:    entry.CreatedAt,
+	}
+}
+
+// stockMovementResponse represents a stock movement response body
+type stockMovementResponse struct {
+	ID          uuid.UUID  `json:"id" example:"2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"`
+	ProductID   uuid.UUID  `json:"product_id" example:"8c2f5d1e-3b7a-4f9c-a1d2-6e4b0c9f7a35"`
+	WarehouseID *uuid.UUID `json:"warehouse_id,omitempty" example:"4d7b1a2c-9e3f-4c8d-b6a5-1f0e2d3c4b5a"`
+	TransferID  *uuid.UUID `json:"transfer_id,omitempty" example:"6f1e2d3c-4b5a-4c8d-9e3f-2a1c8c3e7e4b"`
+	Type        string     `json:"type" example:"sale"`
+	Quantity    int        `json:"quantity" example:"-2"`
+	Balance     int        `json:"balance" example:"8"`
+	Reason      string     `json:"reason" example:"Order 1042"`
+	Actor       string     `json:"actor" example:"anonymous"`
+	RequestID   string     `json:"request_id" example:"0b5e6e3c-8f4d-4a59-9f0c-2f1d6c7b8a90"`
+	CreatedAt   time.Time  `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newStockMovementResponse is a helper function to create a response body for handling stock movement data
+func newStockMovementResponse(movement *domain.StockMovement) stockMovementResponse {
+	return stockMovementResponse{
+		ID:          movement.ID,
+		ProductID:   movement.ProductID,
+		WarehouseID: movement.WarehouseID,
+		TransferID:  movement.TransferID,
+		Type:        string(movement.Type),
+		Quantity:    movement.Quantity,
+		Balance:     movement.Balance,
+		Reason:      movement.Reason,
+		Actor:       movement.Actor,
+		RequestID:   movement.RequestID,
+		CreatedAt:   movement.CreatedAt,
 	}
 }
 
+// productAvailabilityResponse represents the availability of a product near a location
+type productAvailabilityResponse struct {
+	ProductID  uuid.UUID                       `json:"product_id" example:"2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"`
+	Latitude   float64                         `json:"latitude" example:"21.0285"`
+	Longitude  float64                         `json:"longitude" example:"105.8542"`
+	Total      int                             `json:"total" example:"12"`
+	Warehouses []warehouseAvailabilityResponse `json:"warehouses"`
+}
+
+// warehouseAvailabilityResponse represents the stock of a product in a warehouse and its distance
+type warehouseAvailabilityResponse struct {
+	warehouseStockResponse
+	Latitude   *float64 `json:"latitude,omitempty" example:"21.0285"`
+	Longitude  *float64 `json:"longitude,omitempty" example:"105.8542"`
+	DistanceKM *float64 `json:"distance_km,omitempty" example:"1.5"`
+}
+
+// newProductAvailabilityResponse is a helper function to create a response body for handling product availability data
+func newProductAvailabilityResponse(availability *domain.ProductAvailability) productAvailabilityResponse {
+	rsp := productAvailabilityResponse{
+		ProductID:  availability.ProductID,
+		Latitude:   availability.Origin.Latitude,
+		Longitude:  availability.Origin.Longitude,
+		Total:      availability.Total,
+		Warehouses: make([]warehouseAvailabilityResponse, 0, len(availability.Warehouses)),
+	}
+	for _, item := range availability.Warehouses {
+		warehouse := warehouseAvailabilityResponse{
+			warehouseStockResponse: newWarehouseStockResponse(&item.WarehouseStock),
+			DistanceKM:             item.DistanceKM,
+		}
+		if coordinates := item.Warehouse.Coordinates; coordinates != nil {
+			warehouse.Latitude = &coordinates.Latitude
+			warehouse.Longitude = &coordinates.Longitude
+		}
+		rsp.Warehouses = append(rsp.Warehouses, warehouse)
+	}
+	return rsp
+}
+
 type ProductDistancesResponse struct {
 	DistanceKM float64 `json:"distance_km" example:"1.5"`
 }
@@ -122,6 +513,127 @@ func newProductDistancesResponse(distances float64) ProductDistancesResponse {
 	return ProductDistancesResponse{DistanceKM: distances}
 }
 
+// exportResponse represents an export job response body
+type exportResponse struct {
+	ID          uuid.UUID  `json:"id" example:"2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"`
+	Format      string     `json:"format" example:"csv"`
+	State       string     `json:"state" example:"running"`
+	Rows        int64      `json:"rows" example:"1200"`
+	Size        int64      `json:"size,omitempty" example:"524288"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" example:"1970-01-01T00:00:00Z"`
+	StartedAt   *time.Time `json:"started_at,omitempty" example:"1970-01-01T00:00:00Z"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty" example:"1970-01-01T00:00:00Z"`
+	ExpiresAt   time.Time  `json:"expires_at" example:"1970-01-02T00:00:00Z"`
+	DownloadURL string     `json:"download_url,omitempty" example:"/v1/exports/2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10/download"`
+}
+
+// newExportResponse is a helper function to create a response body for handling export job data
+func newExportResponse(job *domain.ExportJob) exportResponse {
+	rsp := exportResponse{
+		ID:         job.ID,
+		Format:     string(job.Format),
+		State:      string(job.State),
+		Rows:       job.Rows,
+		Size:       job.Size,
+		Error:      job.Error,
+		CreatedAt:  job.CreatedAt,
+		StartedAt:  job.StartedAt,
+		FinishedAt: job.FinishedAt,
+		ExpiresAt:  job.ExpiresAt,
+	}
+	if job.State == domain.ExportCompleted && !job.IsExpired(time.Now()) {
+		rsp.DownloadURL = "/v1/exports/" + job.ID.String() + "/download"
+	}
+	return rsp
+}
+
+// importRowErrorResponse represents a rejected row of an import report
+type importRowErrorResponse struct {
+	Line      int      `json:"line" example:"3"`
+	Reference string   `json:"reference,omitempty" example:"SKU-0001"`
+	Errors    []string `json:"errors" example:"price: must not be negative"`
+}
+
+// importReportResponse represents a product import report response body
+type importReportResponse struct {
+	Rows      int                      `json:"rows" example:"120"`
+	Created   int                      `json:"created" example:"100"`
+	Updated   int                      `json:"updated" example:"15"`
+	Unchanged int                      `json:"unchanged" example:"3"`
+	Failed    int                      `json:"failed" example:"2"`
+	DryRun    bool                     `json:"dry_run" example:"false"`
+	Committed bool                     `json:"committed" example:"true"`
+	Errors    []importRowErrorResponse `json:"errors"`
+}
+
+// newImportReportResponse is a helper function to create a response body for handling import report data
+func newImportReportResponse(report *domain.ImportReport) importReportResponse {
+	errs := make([]importRowErrorResponse, len(report.Errors))
+	for i, rowErr := range report.Errors {
+		errs[i] = importRowErrorResponse(rowErr)
+	}
+
+	return importReportResponse{
+		Rows:      report.Rows,
+		Created:   report.Created,
+		Updated:   report.Updated,
+		Unchanged: report.Unchanged,
+		Failed:    report.Failed,
+		DryRun:    report.DryRun,
+		Committed: report.Committed,
+		Errors:    errs,
+	}
+}
+
+// batchProductResultResponse represents the result of an operation of a product batch
+type batchProductResultResponse struct {
+	Index   int              `json:"index" example:"0"`
+	Status  int              `json:"status" example:"200"`
+	Product *productResponse `json:"product,omitempty"`
+	Errors  []string         `json:"errors,omitempty" example:"data not found"`
+}
+
+// batchProductsResponse represents a product batch response body
+type batchProductsResponse struct {
+	Mode      string                       `json:"mode" example:"atomic"`
+	Succeeded int                          `json:"succeeded" example:"3"`
+	Failed    int                          `json:"failed" example:"0"`
+	Results   []batchProductResultResponse `json:"results"`
+}
+
+// newBatchProductsResponse is a helper function to create a response body for handling the results of a product batch
+func newBatchProductsResponse(mode domain.BatchMode, results []domain.ProductOperationResult) batchProductsResponse {
+	rsp := batchProductsResponse{
+		Mode:    string(mode),
+		Results: make([]batchProductResultResponse, len(results)),
+	}
+
+	for i, result := range results {
+		rsp.Results[i] = batchProductResultResponse{Index: i, Status: http.StatusOK}
+		if result.Err != nil {
+			rsp.Failed++
+			rsp.Results[i].Status = errorStatusCode(result.Err)
+			rsp.Results[i].Errors = parseError(result.Err)
+			continue
+		}
+
+		rsp.Succeeded++
+		if result.Product != nil {
+			product := newProductResponse(result.Product)
+			rsp.Results[i].Product = &product
+		}
+	}
+
+	return rsp
+}
+
+// batchGetProductsResponse represents a response body of products got by id
+type batchGetProductsResponse struct {
+	Products   []productResponse `json:"products"`
+	MissingIDs []uuid.UUID       `json:"missing_ids" example:"6b7a4b0e-3f0a-4c8e-9a51-0e4b8b2f6d2a"`
+}
+
 // orderProductResponse represents an order product response body
 type orderProductResponse struct {
 	ID               uint64          `json:"id" example:"1"`
@@ -136,23 +648,68 @@ type orderProductResponse struct {
 	UpdatedAt        time.Time       `json:"updated_at" example:"1970-01-01T00:00:00Z"`
 }
 
-// errorStatusMap is a map of defined error messages and their corresponding http status codes
-var errorStatusMap = map[error]int{
-	domain.ErrInternal:                   http.StatusInternalServerError,
-	domain.ErrDataNotFound:               http.StatusNotFound,
-	domain.ErrConflictingData:            http.StatusConflict,
-	domain.ErrInvalidCredentials:         http.StatusUnauthorized,
-	domain.ErrUnauthorized:               http.StatusUnauthorized,
-	domain.ErrEmptyAuthorizationHeader:   http.StatusUnauthorized,
-	domain.ErrInvalidAuthorizationHeader: http.StatusUnauthorized,
-	domain.ErrInvalidAuthorizationType:   http.StatusUnauthorized,
-	domain.ErrInvalidToken:               http.StatusUnauthorized,
-	domain.ErrExpiredToken:               http.StatusUnauthorized,
-	domain.ErrForbidden:                  http.StatusForbidden,
-	domain.ErrNoUpdatedData:              http.StatusBadRequest,
-	domain.ErrInsufficientStock:          http.StatusBadRequest,
-	domain.ErrInsufficientPayment:        http.StatusBadRequest,
-	domain.ErrInvalidStatus:              http.StatusBadRequest,
+// errorStatus is a defined error and its corresponding http status code
+type errorStatus struct {
+	err        error
+	statusCode int
+}
+
+// errorStatuses are the defined errors and their corresponding http status codes,
+// the first one an error matches gives its status code
+var errorStatuses = []errorStatus{
+	{domain.ErrInternal, http.StatusInternalServerError},
+	{domain.ErrDataNotFound, http.StatusNotFound},
+	{domain.ErrConflictingData, http.StatusConflict},
+	{domain.ErrForeignKeyViolation, http.StatusConflict},
+	{domain.ErrInvalidReference, http.StatusUnprocessableEntity},
+	{domain.ErrMissingRequiredData, http.StatusBadRequest},
+	{domain.ErrConstraintViolation, http.StatusUnprocessableEntity},
+	{domain.ErrConcurrentUpdate, http.StatusConflict},
+	{domain.ErrInvalidCredentials, http.StatusUnauthorized},
+	{domain.ErrUnauthorized, http.StatusUnauthorized},
+	{domain.ErrEmptyAuthorizationHeader, http.StatusUnauthorized},
+	{domain.ErrInvalidAuthorizationHeader, http.StatusUnauthorized},
+	{domain.ErrInvalidAuthorizationType, http.StatusUnauthorized},
+	{domain.ErrInvalidToken, http.StatusUnauthorized},
+	{domain.ErrExpiredToken, http.StatusUnauthorized},
+	{domain.ErrForbidden, http.StatusForbidden},
+	{domain.ErrNoUpdatedData, http.StatusBadRequest},
+	{domain.ErrInsufficientStock, http.StatusBadRequest},
+	{domain.ErrInsufficientPayment, http.StatusBadRequest},
+	{domain.ErrInvalidStatus, http.StatusBadRequest},
+	{domain.ErrInvalidPriceSchedule, http.StatusBadRequest},
+	{domain.ErrPriceChangeApplied, http.StatusConflict},
+	{domain.ErrInvalidMoney, http.StatusBadRequest},
+	{domain.ErrInvalidCurrency, http.StatusBadRequest},
+	{domain.ErrCurrencyMismatch, http.StatusBadRequest},
+	{domain.ErrUnknownCurrency, http.StatusUnprocessableEntity},
+	{domain.ErrInvalidExchangeRate, http.StatusBadRequest},
+	{domain.ErrInvalidStockMovement, http.StatusBadRequest},
+	{domain.ErrInvalidCoordinates, http.StatusBadRequest},
+	{domain.ErrUnknownLocation, http.StatusUnprocessableEntity},
+	{domain.ErrUnknownCity, http.StatusUnprocessableEntity},
+	{domain.ErrInvalidExportOptions, http.StatusBadRequest},
+	{domain.ErrExportNotReady, http.StatusConflict},
+	{domain.ErrExportUnavailable, http.StatusGone},
+	{domain.ErrExportFinished, http.StatusConflict},
+	{domain.ErrInvalidImportFile, http.StatusBadRequest},
+	{domain.ErrInvalidOperation, http.StatusBadRequest},
+	{domain.ErrInvalidProductView, http.StatusBadRequest},
+	{domain.ErrInvalidSort, http.StatusBadRequest},
+	{domain.ErrInvalidCursor, http.StatusBadRequest},
+	{domain.ErrDuplicateOperation, http.StatusConflict},
+	{domain.ErrBatchAborted, http.StatusFailedDependency},
+}
+
+// errorStatusCode returns the http status code of a defined error, including wrapped ones
+func errorStatusCode(err error) int {
+	for _, status := range errorStatuses {
+		if errors.Is(err, status.err) {
+			return status.statusCode
+		}
+	}
+
+	return http.StatusInternalServerError
 }
 
 // validationError sends an error response for some specific request validation error
@@ -164,10 +721,7 @@ func validationError(ctx *gin.Context, err error) {
 
 // handleError determines the status code of an error and returns a JSON response with the error message and status code
 func handleError(ctx *gin.Context, err error) {
-	statusCode, ok := errorStatusMap[err]
-	if !ok {
-		statusCode = http.StatusInternalServerError
-	}
+	statusCode := errorStatusCode(err)
 
 	errMsg := parseError(err)
 	errRsp := newErrorResponse(errMsg)
@@ -176,10 +730,7 @@ func handleError(ctx *gin.Context, err error) {
 
 // handleAbort sends an error response and aborts the request with the specified status code and error message
 func handleAbort(ctx *gin.Context, err error) {
-	statusCode, ok := errorStatusMap[err]
-	if !ok {
-		statusCode = http.StatusInternalServerError
-	}
+	statusCode := errorStatusCode(err)
 
 	errMsg := parseError(err)
 	errRsp := newErrorResponse(errMsg)
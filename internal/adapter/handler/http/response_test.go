@@ -0,0 +1,57 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+func TestErrorStatusCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "defined", err: domain.ErrDataNotFound, want: http.StatusNotFound},
+		{name: "wrapped", err: fmt.Errorf("%w: 3 left", domain.ErrInsufficientStock), want: http.StatusBadRequest},
+		{name: "matching several, the first listed", err: errors.Join(domain.ErrInvalidStatus, domain.ErrDataNotFound), want: http.StatusNotFound},
+		{name: "undefined", err: errors.New("connection refused"), want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				if got := errorStatusCode(tt.err); got != tt.want {
+					t.Fatalf("errorStatusCode(%v) = %d, want %d", tt.err, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestProductResponsePrice(t *testing.T) {
+	price, _ := domain.ParseMoney("12.5", "EUR")
+	effectivePrice, _ := domain.ParseMoney("10", "EUR")
+	product := &domain.Product{ID: uuid.New(), Price: price, EffectivePrice: effectivePrice}
+
+	data, err := json.Marshal(newProductResponse(product))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Price          float64
+		EffectivePrice float64
+		Currency       string
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("v1 prices are not numbers: %v", err)
+	}
+	if got.Price != 12.5 || got.EffectivePrice != 10 || got.Currency != "EUR" {
+		t.Errorf("product response prices = %v, %v %v, want 12.5, 10 EUR", got.Price, got.EffectivePrice, got.Currency)
+	}
+}
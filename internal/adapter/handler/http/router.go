@@ -1,8 +1,12 @@
 package http
 
 import (
+	"context"
+	"errors"
 	"log/slog"
+	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -11,6 +15,7 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/tuan1kdt/soa-ba-test/docs"
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
 )
 
 // Router is a wrapper for HTTP router
@@ -23,7 +28,12 @@ func NewRouter(
 	config *config.HTTP,
 	categoryHandler CategoryHandler,
 	productHandler ProductHandler,
+	priceHandler PriceHandler,
+	stockHandler StockHandler,
+	warehouseHandler WarehouseHandler,
+	exchangeRateHandler ExchangeRateHandler,
 	statisticHandler StatisticHandler,
+	exportHandler ExportHandler,
 ) (*Router, error) {
 	// Disable debug mode in production
 	if config.Env == "production" {
@@ -37,7 +47,9 @@ func NewRouter(
 	ginConfig.AllowOrigins = originsList
 
 	router := gin.New()
-	router.Use(sloggin.New(slog.Default()), gin.Recovery(), cors.New(ginConfig))
+	// Let services read the values carried by the request context through *gin.Context
+	router.ContextWithFallback = true
+	router.Use(sloggin.New(slog.Default()), gin.Recovery(), cors.New(ginConfig), requestContextMiddleware())
 
 	// Custom validators
 	//v, ok := binding.Validator.Engine().(*validator.Validate)
@@ -64,20 +76,65 @@ func NewRouter(
 				admin.POST("/", categoryHandler.CreateCategory)
 				admin.PATCH("/:id", categoryHandler.UpdateCategory)
 				admin.DELETE("/:id", categoryHandler.DeleteCategory)
+				admin.POST("/:id/restore", categoryHandler.RestoreCategory)
 			}
 		}
 		product := v1.Group("/products")
 		{
 			product.GET("/", productHandler.ListProducts)
 			product.GET("/export", productHandler.ExportProducts)
+			product.GET("/labels", productHandler.PrintProductLabels)
 			product.GET("/:id", productHandler.GetProduct)
 			product.GET("/:id/distance", productHandler.GetProductDistance)
+			product.GET("/:id/availability", productHandler.GetProductAvailability)
+			product.GET("/:id/history", productHandler.GetProductHistory)
+			product.GET("/:id/prices", priceHandler.ListPrices)
+			product.GET("/:id/stock-movements", stockHandler.ListStockMovements)
 
 			admin := product
 			{
 				admin.POST("/", productHandler.CreateProduct)
+				admin.POST("/import", productHandler.ImportProducts)
 				admin.PATCH("/:id", productHandler.UpdateProduct)
 				admin.DELETE("/:id", productHandler.DeleteProduct)
+				admin.POST("/:id/restore", productHandler.RestoreProduct)
+				admin.POST("/:id/prices", priceHandler.SchedulePrice)
+				admin.DELETE("/:id/prices/:price_id", priceHandler.CancelPrice)
+			}
+		}
+		// Gin reads the colon of a custom method such as /products:batch as a parameter holding ":batch"
+		v1.POST("/products:method", customMethods(map[string]gin.HandlerFunc{
+			":batch":    productHandler.BatchProducts,
+			":batchGet": productHandler.BatchGetProducts,
+		}))
+		stockMovement := v1.Group("/stock-movements")
+		{
+			admin := stockMovement
+			{
+				admin.POST("/", stockHandler.PostStockMovements)
+				admin.POST("/transfers", stockHandler.TransferStock)
+			}
+		}
+		warehouse := v1.Group("/warehouses")
+		{
+			warehouse.GET("/", warehouseHandler.ListWarehouses)
+			warehouse.GET("/:id", warehouseHandler.GetWarehouse)
+
+			admin := warehouse
+			{
+				admin.POST("/", warehouseHandler.CreateWarehouse)
+				admin.PATCH("/:id", warehouseHandler.UpdateWarehouse)
+				admin.DELETE("/:id", warehouseHandler.DeleteWarehouse)
+			}
+		}
+		exchangeRate := v1.Group("/exchange-rates")
+		{
+			exchangeRate.GET("/", exchangeRateHandler.ListExchangeRates)
+
+			admin := exchangeRate
+			{
+				admin.PUT("/:currency", exchangeRateHandler.SetExchangeRate)
+				admin.DELETE("/:currency", exchangeRateHandler.DeleteExchangeRate)
 			}
 		}
 		statistic := v1.Group("/statistics")
@@ -85,6 +142,23 @@ func NewRouter(
 			statistic.GET("/products-per-category", statisticHandler.GetCategoryProduct)
 			statistic.GET("/products-per-supplier", statisticHandler.GetSupplierProduct)
 		}
+		export := v1.Group("/exports")
+		{
+			export.POST("/", exportHandler.CreateExport)
+			export.GET("/:id", exportHandler.GetExport)
+			export.POST("/:id/cancel", exportHandler.CancelExport)
+			export.GET("/:id/download", exportHandler.DownloadExport)
+		}
+	}
+
+	// v2 represents resources with snake_case keys, sparse fieldsets and opt-in relations
+	v2 := router.Group("/v2")
+	{
+		product := v2.Group("/products")
+		{
+			product.GET("/", productHandler.ListProductsV2)
+			product.GET("/:id", productHandler.GetProductV2)
+		}
 	}
 
 	return &Router{
@@ -92,7 +166,50 @@ func NewRouter(
 	}, nil
 }
 
-// Serve starts the HTTP server
-func (r *Router) Serve(listenAddr string) error {
-	return r.Run(listenAddr)
+// shutdownTimeout is how long the requests in flight are given to complete when the server stops
+const shutdownTimeout = 10 * time.Second
+
+// Serve starts the HTTP server and shuts it down gracefully once the context is canceled
+func (r *Router) Serve(ctx context.Context, listenAddr string) error {
+	server := &http.Server{
+		Addr:    listenAddr,
+		Handler: r.Engine,
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("Shutting down the HTTP server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errs; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// customMethods routes the custom methods of a collection, named after a colon as in /products:batch,
+// any other name is not found
+func customMethods(methods map[string]gin.HandlerFunc) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		handler, ok := methods[ctx.Param("method")]
+		if !ok {
+			handleError(ctx, domain.ErrDataNotFound)
+			return
+		}
+		handler(ctx)
+	}
 }
@@ -0,0 +1,245 @@
+package http
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+// StockHandler represents the HTTP handler for stock-related requests
+type StockHandler struct {
+	svc port.StockService
+}
+
+// NewStockHandler creates a new StockHandler instance
+func NewStockHandler(svc port.StockService) *StockHandler {
+	return &StockHandler{
+		svc,
+	}
+}
+
+// stockMovementRequest represents a single stock movement in a request body
+type stockMovementRequest struct {
+	ProductID   string `json:"product_id" binding:"required,uuid" example:"2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"`
+	WarehouseID string `json:"warehouse_id" binding:"omitempty,uuid" example:"8c2f5d1e-3b7a-4f9c-a1d2-6e4b0c9f7a35"`
+	Type        string `json:"type" binding:"required" enums:"receipt,sale,return,adjustment,transfer" example:"receipt"`
+	Quantity    int    `json:"quantity" binding:"required" example:"10"`
+	Reason      string `json:"reason" example:"Purchase order 1042"`
+}
+
+// postStockMovementsRequest represents a request body for posting stock movements
+type postStockMovementsRequest struct {
+	Movements []stockMovementRequest `json:"movements" binding:"required,min=1,max=1000,dive"`
+}
+
+// PostStockMovements godoc
+//
+//	@Summary		Post stock movements
+//	@Description	Append movements to the stock ledger of products and update their quantity and status, either all of them or none.
+//	@Description	The quantity of a receipt, a sale or a return is the positive number of units moved, the quantity of an adjustment or a transfer is signed and they require a reason.
+//	@Description	A movement with a warehouse changes the stock of that warehouse, a movement without one changes the stock not held by any warehouse
+//	@Tags			Stock
+//	@Accept			json
+//	@Produce		json
+//	@Param			postStockMovementsRequest	body		postStockMovementsRequest	true	"Post stock movements request"
+//	@Success		200							{object}	stockMovementResponse		"Stock movements posted"
+//	@Failure		400							{object}	errorResponse				"Validation error"
+//	@Failure		404							{object}	errorResponse				"Data not found error"
+//	@Failure		500							{object}	errorResponse				"Internal server error"
+//	@Router			/v1/stock-movements [post]
+//	@Security		BearerAuth
+func (sh *StockHandler) PostStockMovements(ctx *gin.Context) {
+	var req postStockMovementsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	movements := make([]domain.StockMovement, len(req.Movements))
+	for i, item := range req.Movements {
+		productID, _ := uuid.Parse(item.ProductID)
+
+		movementType, err := domain.ParseStockMovementType(item.Type)
+		if err != nil {
+			validationError(ctx, fmt.Errorf("movement %d: %w", i, err))
+			return
+		}
+
+		movement, err := domain.NewStockMovement(productID, movementType, item.Quantity, item.Reason)
+		if err != nil {
+			validationError(ctx, fmt.Errorf("movement %d: %w", i, err))
+			return
+		}
+
+		if item.WarehouseID != "" {
+			warehouseID, _ := uuid.Parse(item.WarehouseID)
+			movement.WarehouseID = &warehouseID
+		}
+
+		movements[i] = *movement
+	}
+
+	movements, err := sh.svc.PostStockMovements(ctx, movements)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	movementsList := make([]stockMovementResponse, 0, len(movements))
+	for _, movement := range movements {
+		movementsList = append(movementsList, newStockMovementResponse(&movement))
+	}
+
+	total := uint64(len(movementsList))
+	meta := newMeta(total, total, 0)
+	rsp := toMap(meta, movementsList, "stock_movements")
+
+	handleSuccess(ctx, rsp)
+}
+
+// listStockMovementsRequest represents a request body for listing the stock movements of a product
+type listStockMovementsRequest struct {
+	Types       []string   `form:"type"`
+	WarehouseID string     `form:"warehouse_id" binding:"omitempty,uuid"`
+	From        *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To          *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Skip        uint64     `form:"skip"`
+	Limit       uint64     `form:"limit"`
+}
+
+// ListStockMovements godoc
+//
+//	@Summary		List the stock movements of a product
+//	@Description	List the stock ledger of a product, newest first, with the stock left after each movement
+//	@Tags			Stock
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string					true	"Product ID"
+//	@Param			type			query		[]string				false	"Movement types"
+//	@Param			warehouse_id	query		string					false	"Warehouse ID"
+//	@Param			from			query		string					false	"Moved at or after (RFC 3339)"
+//	@Param			to				query		string					false	"Moved before (RFC 3339)"
+//	@Param			skip			query		uint64					false	"Skip"
+//	@Param			limit			query		uint64					false	"Limit"
+//	@Success		200				{object}	stockMovementResponse	"Stock movements retrieved"
+//	@Failure		400				{object}	errorResponse			"Validation error"
+//	@Failure		500				{object}	errorResponse			"Internal server error"
+//	@Router			/v1/products/{id}/stock-movements [get]
+func (sh *StockHandler) ListStockMovements(ctx *gin.Context) {
+	var uri getProductRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req listStockMovementsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if req.Limit == 0 || req.Limit > 1000 {
+		req.Limit = 10
+	}
+
+	filter := domain.StockMovementFilter{
+		From: req.From,
+		To:   req.To,
+	}
+	for _, name := range req.Types {
+		movementType, err := domain.ParseStockMovementType(name)
+		if err != nil {
+			validationError(ctx, err)
+			return
+		}
+		filter.Types = append(filter.Types, movementType)
+	}
+	if req.WarehouseID != "" {
+		warehouseID, _ := uuid.Parse(req.WarehouseID)
+		filter.WarehouseID = &warehouseID
+	}
+
+	productID, _ := uuid.Parse(uri.ID)
+
+	movements, err := sh.svc.ListStockMovements(ctx, productID, filter, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	movementsList := make([]stockMovementResponse, 0, len(movements))
+	for _, movement := range movements {
+		movementsList = append(movementsList, newStockMovementResponse(&movement))
+	}
+
+	total := uint64(len(movementsList))
+	meta := newMeta(total, req.Limit, req.Skip)
+	rsp := toMap(meta, movementsList, "stock_movements")
+
+	handleSuccess(ctx, rsp)
+}
+
+// transferStockRequest represents a request body for transferring stock between warehouses
+type transferStockRequest struct {
+	ProductID       string `json:"product_id" binding:"required,uuid" example:"2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"`
+	FromWarehouseID string `json:"from_warehouse_id" binding:"required,uuid" example:"8c2f5d1e-3b7a-4f9c-a1d2-6e4b0c9f7a35"`
+	ToWarehouseID   string `json:"to_warehouse_id" binding:"required,uuid,nefield=FromWarehouseID" example:"4d7b1a2c-9e3f-4c8d-b6a5-1f0e2d3c4b5a"`
+	Quantity        int    `json:"quantity" binding:"required,min=1" example:"5"`
+	Reason          string `json:"reason" example:"Rebalancing for the sale"`
+}
+
+// TransferStock godoc
+//
+//	@Summary		Transfer stock between warehouses
+//	@Description	Move units of a product from one warehouse to another. The transfer is recorded as a pair of transfer movements sharing a transfer id, the total stock of the product is unchanged
+//	@Tags			Stock
+//	@Accept			json
+//	@Produce		json
+//	@Param			transferStockRequest	body		transferStockRequest	true	"Transfer stock request"
+//	@Success		200						{object}	stockMovementResponse	"Stock transferred"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		404						{object}	errorResponse			"Data not found error"
+//	@Failure		422						{object}	errorResponse			"Unknown warehouse error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/v1/stock-movements/transfers [post]
+//	@Security		BearerAuth
+func (sh *StockHandler) TransferStock(ctx *gin.Context) {
+	var req transferStockRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	productID, _ := uuid.Parse(req.ProductID)
+	fromID, _ := uuid.Parse(req.FromWarehouseID)
+	toID, _ := uuid.Parse(req.ToWarehouseID)
+
+	transfer := domain.WarehouseTransfer{
+		ProductID:       productID,
+		FromWarehouseID: fromID,
+		ToWarehouseID:   toID,
+		Quantity:        req.Quantity,
+		Reason:          req.Reason,
+	}
+
+	movements, err := sh.svc.TransferStock(ctx, &transfer)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	movementsList := make([]stockMovementResponse, 0, len(movements))
+	for _, movement := range movements {
+		movementsList = append(movementsList, newStockMovementResponse(&movement))
+	}
+
+	total := uint64(len(movementsList))
+	meta := newMeta(total, total, 0)
+	rsp := toMap(meta, movementsList, "stock_movements")
+
+	handleSuccess(ctx, rsp)
+}
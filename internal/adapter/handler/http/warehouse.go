@@ -0,0 +1,287 @@
+package http
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+// WarehouseHandler represents the HTTP handler for warehouse-related requests
+type WarehouseHandler struct {
+	svc port.WarehouseService
+}
+
+// NewWarehouseHandler creates a new WarehouseHandler instance
+func NewWarehouseHandler(svc port.WarehouseService) *WarehouseHandler {
+	return &WarehouseHandler{
+		svc,
+	}
+}
+
+// warehouseResponse represents a warehouse response body
+type warehouseResponse struct {
+	ID        uuid.UUID `json:"id" example:"2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10"`
+	Name      string    `json:"name" example:"Hanoi North"`
+	City      string    `json:"city" example:"Hanoi"`
+	Latitude  *float64  `json:"latitude,omitempty" example:"21.0285"`
+	Longitude *float64  `json:"longitude,omitempty" example:"105.8542"`
+	CreatedAt time.Time `json:"created_at" example:"1970-01-01T00:00:00Z"`
+}
+
+// newWarehouseResponse is a helper function to create a response body for handling warehouse data
+func newWarehouseResponse(warehouse *domain.Warehouse) warehouseResponse {
+	rsp := warehouseResponse{
+		ID:        warehouse.ID,
+		Name:      warehouse.Name,
+		City:      warehouse.City,
+		CreatedAt: warehouse.CreatedAt,
+	}
+	if warehouse.Coordinates != nil {
+		rsp.Latitude = &warehouse.Coordinates.Latitude
+		rsp.Longitude = &warehouse.Coordinates.Longitude
+	}
+	return rsp
+}
+
+// coordinatesRequest represents optional coordinates in a request body, set together
+type coordinatesRequest struct {
+	Latitude  *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90" example:"21.0285"`
+	Longitude *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180" example:"105.8542"`
+}
+
+// coordinates returns the requested coordinates, or nil when none are given
+func (cr coordinatesRequest) coordinates() *domain.Coordinates {
+	if cr.Latitude == nil || cr.Longitude == nil {
+		return nil
+	}
+	return &domain.Coordinates{Latitude: *cr.Latitude, Longitude: *cr.Longitude}
+}
+
+// createWarehouseRequest represents a request body for creating a new warehouse
+type createWarehouseRequest struct {
+	Name string `json:"name" binding:"required" example:"Hanoi North"`
+	City string `json:"city" binding:"required" example:"Hanoi"`
+	coordinatesRequest
+}
+
+// CreateWarehouse godoc
+//
+//	@Summary		Create a new warehouse
+//	@Description	Create a new warehouse with a name, a city and its coordinates
+//	@Tags			Warehouses
+//	@Accept			json
+//	@Produce		json
+//	@Param			createWarehouseRequest	body		createWarehouseRequest	true	"Create warehouse request"
+//	@Success		200						{object}	warehouseResponse		"Warehouse created"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		401						{object}	errorResponse			"Unauthorized error"
+//	@Failure		403						{object}	errorResponse			"Forbidden error"
+//	@Failure		409						{object}	errorResponse			"Data conflict error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/v1/warehouses [post]
+//	@Security		BearerAuth
+func (wh *WarehouseHandler) CreateWarehouse(ctx *gin.Context) {
+	var req createWarehouseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	warehouse := domain.Warehouse{
+		Name:        req.Name,
+		City:        req.City,
+		Coordinates: req.coordinates(),
+	}
+
+	_, err := wh.svc.CreateWarehouse(ctx, &warehouse)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newWarehouseResponse(&warehouse)
+
+	handleSuccess(ctx, rsp)
+}
+
+// getWarehouseRequest represents a request body for retrieving a warehouse
+type getWarehouseRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// GetWarehouse godoc
+//
+//	@Summary		Get a warehouse
+//	@Description	get a warehouse by id
+//	@Tags			Warehouses
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string				true	"Warehouse ID"
+//	@Success		200	{object}	warehouseResponse	"Warehouse retrieved"
+//	@Failure		400	{object}	errorResponse		"Validation error"
+//	@Failure		404	{object}	errorResponse		"Data not found error"
+//	@Failure		500	{object}	errorResponse		"Internal server error"
+//	@Router			/v1/warehouses/{id} [get]
+func (wh *WarehouseHandler) GetWarehouse(ctx *gin.Context) {
+	var req getWarehouseRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	id, _ := uuid.Parse(req.ID)
+
+	warehouse, err := wh.svc.GetWarehouse(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newWarehouseResponse(warehouse)
+
+	handleSuccess(ctx, rsp)
+}
+
+// listWarehousesRequest represents a request body for listing warehouses
+type listWarehousesRequest struct {
+	Skip  uint64 `form:"skip"`
+	Limit uint64 `form:"limit"`
+}
+
+// ListWarehouses godoc
+//
+//	@Summary		List warehouses
+//	@Description	List warehouses by name with pagination
+//	@Tags			Warehouses
+//	@Accept			json
+//	@Produce		json
+//	@Param			skip	query		uint64				false	"Skip"
+//	@Param			limit	query		uint64				false	"Limit"
+//	@Success		200		{object}	warehouseResponse	"Warehouses displayed"
+//	@Failure		400		{object}	errorResponse		"Validation error"
+//	@Failure		500		{object}	errorResponse		"Internal server error"
+//	@Router			/v1/warehouses [get]
+func (wh *WarehouseHandler) ListWarehouses(ctx *gin.Context) {
+	var req listWarehousesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	if req.Limit == 0 || req.Limit > 1000 {
+		req.Limit = 10
+	}
+
+	warehouses, err := wh.svc.ListWarehouses(ctx, req.Skip, req.Limit)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	warehousesList := make([]warehouseResponse, 0, len(warehouses))
+	for _, warehouse := range warehouses {
+		warehousesList = append(warehousesList, newWarehouseResponse(&warehouse))
+	}
+
+	total := uint64(len(warehousesList))
+	meta := newMeta(total, req.Limit, req.Skip)
+	rsp := toMap(meta, warehousesList, "warehouses")
+
+	handleSuccess(ctx, rsp)
+}
+
+// updateWarehouseRequest represents a request body for updating a warehouse
+type updateWarehouseRequest struct {
+	Name string `json:"name" example:"Hanoi North"`
+	City string `json:"city" example:"Hanoi"`
+	coordinatesRequest
+}
+
+// UpdateWarehouse godoc
+//
+//	@Summary		Update a warehouse
+//	@Description	Update the name, city or coordinates of a warehouse by id, the fields left empty are kept
+//	@Tags			Warehouses
+//	@Accept			json
+//	@Produce		json
+//	@Param			id						path		string					true	"Warehouse ID"
+//	@Param			updateWarehouseRequest	body		updateWarehouseRequest	true	"Update warehouse request"
+//	@Success		200						{object}	warehouseResponse		"Warehouse updated"
+//	@Failure		400						{object}	errorResponse			"Validation error"
+//	@Failure		401						{object}	errorResponse			"Unauthorized error"
+//	@Failure		403						{object}	errorResponse			"Forbidden error"
+//	@Failure		404						{object}	errorResponse			"Data not found error"
+//	@Failure		409						{object}	errorResponse			"Data conflict error"
+//	@Failure		500						{object}	errorResponse			"Internal server error"
+//	@Router			/v1/warehouses/{id} [patch]
+//	@Security		BearerAuth
+func (wh *WarehouseHandler) UpdateWarehouse(ctx *gin.Context) {
+	var uri getWarehouseRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	var req updateWarehouseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	id, _ := uuid.Parse(uri.ID)
+
+	warehouse := domain.Warehouse{
+		ID:          id,
+		Name:        req.Name,
+		City:        req.City,
+		Coordinates: req.coordinates(),
+	}
+
+	_, err := wh.svc.UpdateWarehouse(ctx, &warehouse)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	rsp := newWarehouseResponse(&warehouse)
+
+	handleSuccess(ctx, rsp)
+}
+
+// DeleteWarehouse godoc
+//
+//	@Summary		Delete a warehouse
+//	@Description	Delete a warehouse by id, a warehouse still holding stock cannot be deleted
+//	@Tags			Warehouses
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string			true	"Warehouse ID"
+//	@Success		200	{object}	response		"Warehouse deleted"
+//	@Failure		400	{object}	errorResponse	"Validation error"
+//	@Failure		401	{object}	errorResponse	"Unauthorized error"
+//	@Failure		403	{object}	errorResponse	"Forbidden error"
+//	@Failure		404	{object}	errorResponse	"Data not found error"
+//	@Failure		409	{object}	errorResponse	"Data conflict error"
+//	@Failure		500	{object}	errorResponse	"Internal server error"
+//	@Router			/v1/warehouses/{id} [delete]
+//	@Security		BearerAuth
+func (wh *WarehouseHandler) DeleteWarehouse(ctx *gin.Context) {
+	var req getWarehouseRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		validationError(ctx, err)
+		return
+	}
+
+	id, _ := uuid.Parse(req.ID)
+
+	err := wh.svc.DeleteWarehouse(ctx, id)
+	if err != nil {
+		handleError(ctx, err)
+		return
+	}
+
+	handleSuccess(ctx, nil)
+}
@@ -0,0 +1,331 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// Columns are the columns of a product import, a CSV file names them in its header and a JSON Lines file
+// uses them as the keys of its objects. Only reference and name are required
+var Columns = []string{"reference", "name", "status", "category_id", "price", "currency", "stock_city", "supplier_id", "quantity"}
+
+// requiredColumns are the columns a CSV header must hold
+var requiredColumns = []string{"reference", "name"}
+
+// maxLineSize is the longest line of a JSON Lines file
+const maxLineSize = 1 << 20
+
+/**
+ * Importer implements port.ProductImporter interface
+ * and provides an access to the products read from a CSV or JSON Lines file
+ */
+type Importer struct{}
+
+// New creates a new importer instance
+func New() *Importer {
+	return &Importer{}
+}
+
+// ParseProducts reads the rows of a product import in a format. A row that can not be read is returned with its errors,
+// a file that can not be read at all, such as a CSV file without header, returns domain.ErrInvalidImportFile
+func (i *Importer) ParseProducts(r io.Reader, format domain.ImportFormat) ([]domain.ImportRow, error) {
+	switch format {
+	case domain.ImportCSV:
+		return parseCSV(r)
+	case domain.ImportJSONL:
+		return parseJSONL(r)
+	default:
+		return nil, fmt.Errorf("%w: unknown format %q", domain.ErrInvalidImportFile, format)
+	}
+}
+
+// parseCSV reads a CSV file whose header names its columns. The delimiter is a comma,
+// or a semicolon or a tab when the header holds one of them and no comma
+func parseCSV(r io.Reader) ([]domain.ImportRow, error) {
+	br := bufio.NewReader(r)
+	// A UTF-8 byte order mark starts the files saved by some spreadsheets
+	if bom, _ := br.Peek(3); bytes.Equal(bom, []byte("\uFEFF")) {
+		br.Discard(3)
+	}
+
+	header, _ := br.Peek(br.Buffered())
+	if len(header) == 0 {
+		header, _ = br.Peek(4096)
+	}
+	if i := bytes.IndexByte(header, '\n'); i >= 0 {
+		header = header[:i]
+	}
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if !bytes.ContainsRune(header, ',') {
+		switch {
+		case bytes.ContainsRune(header, ';'):
+			reader.Comma = ';'
+		case bytes.ContainsRune(header, '\t'):
+			reader.Comma = '\t'
+		}
+	}
+
+	names, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: empty file", domain.ErrInvalidImportFile)
+		}
+		return nil, fmt.Errorf("%w: %v", domain.ErrInvalidImportFile, err)
+	}
+
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(Columns, name) {
+			return nil, fmt.Errorf("%w: unknown column %q", domain.ErrInvalidImportFile, name)
+		}
+		if _, ok := index[name]; ok {
+			return nil, fmt.Errorf("%w: duplicate column %q", domain.ErrInvalidImportFile, name)
+		}
+		index[name] = i
+	}
+	for _, name := range requiredColumns {
+		if _, ok := index[name]; !ok {
+			return nil, fmt.Errorf("%w: missing column %q", domain.ErrInvalidImportFile, name)
+		}
+	}
+
+	var rows []domain.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			// The reader resumes on the next line after a malformed one
+			rows = append(rows, domain.ImportRow{Line: parseErr.StartLine, Errors: []string{parseErr.Err.Error()}})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		if len(record) != len(names) {
+			row := domain.ImportRow{Line: line, Errors: []string{fmt.Sprintf("expected %d fields, got %d", len(names), len(record))}}
+			if i := index["reference"]; i < len(record) {
+				row.Product.Reference = strings.TrimSpace(record[i])
+			}
+			rows = append(rows, row)
+			continue
+		}
+
+		value := func(name string) string {
+			if i, ok := index[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		rows = append(rows, newCSVRow(line, value))
+	}
+
+	return rows, nil
+}
+
+// newCSVRow creates the row of a CSV record, whose values are read by column
+func newCSVRow(line int, value func(name string) string) domain.ImportRow {
+	row := domain.ImportRow{Line: line}
+	errorf := func(format string, args ...any) {
+		row.Errors = append(row.Errors, fmt.Sprintf(format, args...))
+	}
+
+	row.Product = newProduct(value("reference"), value("name"), value("stock_city"), errorf)
+	row.Product.Status = parseStatus(value("status"), errorf)
+	row.Product.CategoryID = parseID("category_id", value("category_id"), errorf)
+	row.Product.SupplierID = parseID("supplier_id", value("supplier_id"), errorf)
+
+	if price := value("price"); price != "" {
+		amount, err := decimal.NewFromString(price)
+		if err != nil {
+			errorf("price: must be a decimal number")
+		}
+		row.Product.Price.Amount = amount
+	}
+	row.Product.Price.Currency = parseCurrency(value("currency"), errorf)
+
+	if quantity := value("quantity"); quantity != "" {
+		n, err := strconv.Atoi(quantity)
+		if err != nil {
+			errorf("quantity: must be a whole number")
+		}
+		row.Product.Quantity = n
+		row.QuantitySet = true
+	}
+	checkQuantity(row.Product.Quantity, errorf)
+
+	return row
+}
+
+// jsonRecord is a line of a JSON Lines file, the price is a number, a string or an object with its currency
+type jsonRecord struct {
+	Reference  string          `json:"reference"`
+	Name       string          `json:"name"`
+	Status     string          `json:"status"`
+	CategoryID string          `json:"category_id"`
+	Price      json.RawMessage `json:"price"`
+	Currency   string          `json:"currency"`
+	StockCity  string          `json:"stock_city"`
+	SupplierID string          `json:"supplier_id"`
+	Quantity   *int            `json:"quantity"`
+}
+
+// parseJSONL reads a JSON Lines file, an object per line, skipping blank lines
+func parseJSONL(r io.Reader) ([]domain.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	var rows []domain.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		data := bytes.TrimSpace(scanner.Bytes())
+		if line == 1 {
+			data = bytes.TrimPrefix(data, []byte("\uFEFF"))
+		}
+		if len(data) == 0 {
+			continue
+		}
+
+		row := domain.ImportRow{Line: line}
+		errorf := func(format string, args ...any) {
+			row.Errors = append(row.Errors, fmt.Sprintf(format, args...))
+		}
+
+		var record jsonRecord
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			// A value of the wrong type leaves the other fields decoded
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &typeErr) {
+				errorf("%s", strings.TrimPrefix(err.Error(), "json: "))
+				rows = append(rows, row)
+				continue
+			}
+			errorf("%s: must be %s", typeErr.Field, jsonType(typeErr.Type))
+		}
+
+		row.Product = newProduct(record.Reference, record.Name, record.StockCity, errorf)
+		row.Product.Status = parseStatus(record.Status, errorf)
+		row.Product.CategoryID = parseID("category_id", strings.TrimSpace(record.CategoryID), errorf)
+		row.Product.SupplierID = parseID("supplier_id", strings.TrimSpace(record.SupplierID), errorf)
+		if len(record.Price) > 0 {
+			if err := json.Unmarshal(record.Price, &row.Product.Price); err != nil {
+				errorf("price: must be a decimal number or an object with an amount and a currency")
+			}
+		}
+		if row.Product.Price.Currency == "" {
+			row.Product.Price.Currency = parseCurrency(strings.TrimSpace(record.Currency), errorf)
+		}
+		if record.Quantity != nil {
+			row.Product.Quantity = *record.Quantity
+			row.QuantitySet = true
+		}
+		checkQuantity(row.Product.Quantity, errorf)
+
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: line longer than %d bytes", domain.ErrInvalidImportFile, maxLineSize)
+		}
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// jsonType describes the type of a field of a line of a JSON Lines file
+func jsonType(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Int:
+		return "a whole number"
+	case reflect.String:
+		return "a string"
+	default:
+		return "a " + t.String()
+	}
+}
+
+// newProduct creates a product with its required reference and name
+func newProduct(reference, name, stockCity string, errorf func(format string, args ...any)) domain.Product {
+	product := domain.Product{
+		Reference: strings.TrimSpace(reference),
+		Name:      strings.TrimSpace(name),
+		StockCity: strings.TrimSpace(stockCity),
+	}
+	if product.Reference == "" {
+		errorf("reference: is required")
+	}
+	if product.Name == "" {
+		errorf("name: is required")
+	}
+	return product
+}
+
+// parseStatus parses the status of a row, an empty status is set from the quantity
+func parseStatus(value string, errorf func(format string, args ...any)) domain.ProductStatus {
+	status, err := domain.ParseProductStatus(value)
+	if err != nil {
+		errorf("status: must be one of %s, %s or %s", domain.StatusAvailable, domain.StatusOnOrDer, domain.StatusOutOfStock)
+	}
+	return status
+}
+
+// parseID parses an optional id of a row
+func parseID(name, value string, errorf func(format string, args ...any)) *uuid.UUID {
+	if value == "" {
+		return nil
+	}
+
+	id, err := uuid.Parse(value)
+	if err != nil {
+		errorf("%s: must be a UUID", name)
+		return nil
+	}
+	return &id
+}
+
+// parseCurrency parses the optional currency of the price of a row
+func parseCurrency(value string, errorf func(format string, args ...any)) string {
+	if value == "" {
+		return ""
+	}
+
+	currency, err := domain.ParseCurrency(value)
+	if err != nil {
+		errorf("currency: must be a three letter ISO 4217 code")
+	}
+	return currency
+}
+
+// checkQuantity rejects a negative quantity
+func checkQuantity(quantity int, errorf func(format string, args ...any)) {
+	if quantity < 0 {
+		errorf("quantity: must not be negative")
+	}
+}
@@ -0,0 +1,174 @@
+package importer
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// parse reads an import, failing the test on an error
+func parse(t *testing.T, format domain.ImportFormat, content string) []domain.ImportRow {
+	t.Helper()
+
+	rows, err := New().ParseProducts(strings.NewReader(content), format)
+	if err != nil {
+		t.Fatalf("ParseProducts() error = %v", err)
+	}
+	return rows
+}
+
+func TestParseCSVDelimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{name: "comma", content: "reference,name,quantity\nR1,Rice,5\n"},
+		{name: "semicolon", content: "reference;name;quantity\nR1;Rice;5\n"},
+		{name: "tab", content: "reference\tname\tquantity\nR1\tRice\t5\n"},
+		{name: "byte order mark", content: "\uFEFFreference;name;quantity\r\nR1;Rice;5\r\n"},
+		{name: "no trailing line break", content: "reference,name,quantity\nR1,Rice,5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := parse(t, domain.ImportCSV, tt.content)
+			if len(rows) != 1 {
+				t.Fatalf("ParseProducts() = %d rows, want 1", len(rows))
+			}
+			row := rows[0]
+			if len(row.Errors) > 0 || row.Line != 2 || row.Product.Reference != "R1" || row.Product.Name != "Rice" || row.Product.Quantity != 5 {
+				t.Errorf("ParseProducts() row = %+v, want R1 Rice with 5 on line 2", row)
+			}
+		})
+	}
+}
+
+func TestParseCSVDelimiterCommaFirst(t *testing.T) {
+	rows := parse(t, domain.ImportCSV, "reference,name\nR1,Rice; long grain\n")
+	if len(rows) != 1 || rows[0].Product.Name != "Rice; long grain" {
+		t.Errorf("ParseProducts() = %+v, want the name holding a semicolon", rows)
+	}
+}
+
+func TestParseCSVHeader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{name: "empty file", content: "", wantErr: "empty file"},
+		{name: "unknown column", content: "reference,name,colour\n", wantErr: `unknown column "colour"`},
+		{name: "duplicate column", content: "reference,name, Name\n", wantErr: `duplicate column "name"`},
+		{name: "missing column", content: "reference,price\n", wantErr: `missing column "name"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New().ParseProducts(strings.NewReader(tt.content), domain.ImportCSV)
+			if !errors.Is(err, domain.ErrInvalidImportFile) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseProducts() error = %v, want %v: %s", err, domain.ErrInvalidImportFile, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseCSVLines(t *testing.T) {
+	content := strings.Join([]string{
+		"reference,name,quantity",
+		"R1,Rice,5",
+		`R2,Bad "quote,3`,
+		"",
+		`R3,"Noodles`,
+		`instant",2`,
+		"R4,Tea",
+		"R5,Coffee,lots",
+	}, "\n")
+
+	rows := parse(t, domain.ImportCSV, content)
+
+	want := []struct {
+		line      int
+		reference string
+		errors    bool
+	}{
+		{line: 2, reference: "R1"},
+		{line: 3, errors: true},
+		{line: 5, reference: "R3"},
+		{line: 7, reference: "R4", errors: true},
+		{line: 8, reference: "R5", errors: true},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("ParseProducts() = %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.Line != w.line || row.Product.Reference != w.reference || (len(row.Errors) > 0) != w.errors {
+			t.Errorf("row %d = line %d, reference %q, errors %v, want line %d, reference %q, errors %v",
+				i, row.Line, row.Product.Reference, row.Errors, w.line, w.reference, w.errors)
+		}
+	}
+	if rows[2].Product.Name != "Noodles\ninstant" {
+		t.Errorf("row 2 name = %q, want the quoted line break kept", rows[2].Product.Name)
+	}
+	if !slices.Contains(rows[4].Errors, "quantity: must be a whole number") {
+		t.Errorf("row 4 errors = %v, want the quantity rejected", rows[4].Errors)
+	}
+}
+
+func TestParseJSONL(t *testing.T) {
+	content := strings.Join([]string{
+		"\uFEFF" + `{"reference":"R1","name":"Rice","price":"12.50","currency":"usd","quantity":5}`,
+		"",
+		`{"reference":"R2","name":"Tea","price":{"amount":"3","currency":"EUR"}}`,
+		`{"reference":"R3","name":"Salt","quantity":"many"}`,
+		`{"reference":"R4","name":12}`,
+		`{"reference":"R5","name":"Coffee","colour":"brown"}`,
+		`{"reference":"R6",`,
+		`{"reference":"R7","name":"Sugar","quantity":0}`,
+	}, "\n")
+
+	rows := parse(t, domain.ImportJSONL, content)
+
+	want := []struct {
+		line        int
+		reference   string
+		errors      []string
+		quantitySet bool
+	}{
+		{line: 1, reference: "R1", quantitySet: true},
+		{line: 3, reference: "R2"},
+		{line: 4, reference: "R3", errors: []string{"quantity: must be a whole number"}, quantitySet: true},
+		{line: 5, reference: "R4", errors: []string{"name: must be a string", "name: is required"}},
+		{line: 6, errors: []string{`unknown field "colour"`}},
+		{line: 7, errors: []string{"unexpected EOF"}},
+		{line: 8, reference: "R7", quantitySet: true},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("ParseProducts() = %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		row := rows[i]
+		if row.Line != w.line || row.Product.Reference != w.reference || !slices.Equal(row.Errors, w.errors) || row.QuantitySet != w.quantitySet {
+			t.Errorf("row %d = line %d, reference %q, errors %q, quantity set %v, want line %d, reference %q, errors %q, quantity set %v",
+				i, row.Line, row.Product.Reference, row.Errors, row.QuantitySet, w.line, w.reference, w.errors, w.quantitySet)
+		}
+	}
+
+	if price := rows[0].Product.Price; price.Amount.String() != "12.5" || price.Currency != "USD" {
+		t.Errorf("row 0 price = %v, want 12.5 USD", price)
+	}
+	if price := rows[1].Product.Price; price.Amount.String() != "3" || price.Currency != "EUR" {
+		t.Errorf("row 1 price = %v, want 3 EUR", price)
+	}
+}
+
+func TestParseQuantitySet(t *testing.T) {
+	rows := parse(t, domain.ImportCSV, "reference,name,quantity\nR1,Rice,0\nR2,Tea,\n")
+	if len(rows) != 2 {
+		t.Fatalf("ParseProducts() = %d rows, want 2", len(rows))
+	}
+	if !rows[0].QuantitySet || rows[1].QuantitySet {
+		t.Errorf("ParseProducts() quantity set = %v, %v, want true for a zero, false for an empty value", rows[0].QuantitySet, rows[1].QuantitySet)
+	}
+}
@@ -0,0 +1,321 @@
+package report
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Symbologies of the codes printed on labels
+const (
+	Code128 = "code128"
+	QR      = "qr"
+)
+
+// LabelLayout is a sheet of labels, its sizes in millimeters
+type LabelLayout struct {
+	Name        string
+	Description string
+	// PageSize is A4 or Letter, in portrait
+	PageSize string
+	Columns  int
+	Rows     int
+	Width    float64
+	Height   float64
+	// Top and Left are the position of the first label, PitchX and PitchY the distance between the starts of two labels
+	Top    float64
+	Left   float64
+	PitchX float64
+	PitchY float64
+}
+
+// labelLayouts are the label sheets labels can be printed on, the first one by default
+var labelLayouts = []LabelLayout{
+	{Name: "avery-l7159", Description: "Avery L7159, 3x8 labels of 63.5x33.9 mm on A4", PageSize: "A4", Columns: 3, Rows: 8, Width: 63.5, Height: 33.9, Top: 12.9, Left: 7.25, PitchX: 66, PitchY: 33.9},
+	{Name: "avery-l7160", Description: "Avery L7160, 3x7 labels of 63.5x38.1 mm on A4", PageSize: "A4", Columns: 3, Rows: 7, Width: 63.5, Height: 38.1, Top: 15.15, Left: 7.25, PitchX: 66, PitchY: 38.1},
+	{Name: "avery-l7163", Description: "Avery L7163, 2x7 labels of 99.1x38.1 mm on A4", PageSize: "A4", Columns: 2, Rows: 7, Width: 99.1, Height: 38.1, Top: 15.15, Left: 4.65, PitchX: 101.6, PitchY: 38.1},
+	{Name: "avery-5160", Description: "Avery 5160, 3x10 labels of 2.625x1 in on Letter", PageSize: "Letter", Columns: 3, Rows: 10, Width: 66.675, Height: 25.4, Top: 12.7, Left: 4.7625, PitchX: 69.85, PitchY: 25.4},
+}
+
+// LookupLabelLayout returns a label sheet by name, the default one when the name is empty
+func LookupLabelLayout(name string) (LabelLayout, bool) {
+	if name == "" {
+		return labelLayouts[0], true
+	}
+
+	i := slices.IndexFunc(labelLayouts, func(layout LabelLayout) bool { return layout.Name == name })
+	if i < 0 {
+		return LabelLayout{}, false
+	}
+	return labelLayouts[i], true
+}
+
+// perSheet returns the number of labels of a sheet
+func (l LabelLayout) perSheet() int {
+	return l.Columns * l.Rows
+}
+
+// place returns the position on its sheet of the label at an index when the first label is printed at the start
+// position, counted from 1, and whether the label begins a sheet
+func (l LabelLayout) place(start, i int) (float64, float64, bool) {
+	position := max(start-1, 0)%l.perSheet() + i
+	cell := position % l.perSheet()
+
+	x := l.Left + float64(cell%l.Columns)*l.PitchX
+	y := l.Top + float64(cell/l.Columns)*l.PitchY
+	return x, y, i == 0 || cell == 0
+}
+
+// Label is the content of a label
+type Label struct {
+	Name      string
+	Reference string
+	Price     string
+	// Code is the content of the barcode or the QR code
+	Code string
+}
+
+// EncodeLabel checks the code of a label can be encoded in a symbology, Code128 only encodes ASCII text
+func EncodeLabel(symbology, code string) error {
+	_, err := encode(symbology, code)
+	return err
+}
+
+// encode encodes a code in a symbology
+func encode(symbology, code string) (barcode.Barcode, error) {
+	if code == "" {
+		return nil, fmt.Errorf("empty %s code", symbology)
+	}
+
+	switch symbology {
+	case Code128:
+		return code128.Encode(code)
+	case QR:
+		return qr.Encode(code, qr.M, qr.Auto)
+	default:
+		return nil, fmt.Errorf("unknown symbology %q", symbology)
+	}
+}
+
+const (
+	// labelPadding is the inner margin of a label in millimeters
+	labelPadding = 2
+	// barcodeQuietZone is the number of blank modules required on both sides of a Code128 barcode
+	barcodeQuietZone = 10
+	// qrQuietZone is the number of blank modules around a QR code, the label padding adds to it
+	qrQuietZone = 2
+	// maxBarcodeHeight keeps the barcodes of large labels from taking all their height, in millimeters
+	maxBarcodeHeight = 15
+)
+
+// labelRenderer lays out labels on the sheets of a PDF document
+type labelRenderer struct {
+	pdf       *gofpdf.Fpdf
+	layout    LabelLayout
+	font      Font
+	symbology string
+	translate func(string) string
+	utf8      bool
+	// size is the font size of the labels, in points, set from their height
+	size float64
+}
+
+// RenderLabels writes labels printed on sheets of a layout as a PDF document, each with its name, reference,
+// price and code as a Code128 barcode or a QR code. The first label is printed at the start position of the first
+// sheet, counted from 1, leaving the labels before it blank
+func RenderLabels(w io.Writer, layout LabelLayout, font Font, symbology string, start int, labels []Label) error {
+	pdf := gofpdf.New("P", "mm", layout.PageSize, "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+
+	r := &labelRenderer{
+		pdf:       pdf,
+		layout:    layout,
+		font:      font,
+		symbology: symbology,
+		translate: func(s string) string { return s },
+		size:      min(max(layout.Height/4, 6), 10),
+	}
+
+	if font.File != "" {
+		bold := font.BoldFile
+		if bold == "" {
+			bold = font.File
+		}
+		pdf.AddUTF8Font(font.Family, "", font.File)
+		pdf.AddUTF8Font(font.Family, "B", bold)
+		r.utf8 = true
+	} else {
+		r.translate = pdf.UnicodeTranslatorFromDescriptor("")
+	}
+
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+	for i, label := range labels {
+		x, y, newSheet := layout.place(start, i)
+		if newSheet {
+			pdf.AddPage()
+		}
+
+		if err := r.label(label, x, y); err != nil {
+			return fmt.Errorf("label %d: %w", i+1, err)
+		}
+	}
+
+	return pdf.Output(w)
+}
+
+// label prints a label at a position, its code below its text for a barcode or beside it for a QR code
+func (r *labelRenderer) label(label Label, x, y float64) error {
+	code, err := encode(r.symbology, label.Code)
+	if err != nil {
+		return err
+	}
+
+	x, y = x+labelPadding, y+labelPadding
+	width, height := r.layout.Width-2*labelPadding, r.layout.Height-2*labelPadding
+	lineH := r.size / pointsPerMillimeter * lineHeight
+
+	if r.symbology == QR {
+		side := min(height, width*0.4)
+		r.drawQR(code, x+width-side, y+(height-side)/2, side)
+		width -= side + labelPadding
+		r.text(label, x, y, width, height, lineH)
+		return nil
+	}
+
+	// The barcode takes the height left below the text, printed with a smaller line for the code under the bars
+	textH := 2*lineH + lineH*0.8
+	barH := min(height-textH-lineH*0.8, maxBarcodeHeight)
+	r.text(label, x, y, width, textH, lineH)
+	r.drawBarcode(code, x, y+height-barH-lineH*0.8, width, barH)
+
+	r.pdf.SetFont(r.font.Family, "", r.size*0.8)
+	r.pdf.SetXY(x, y+height-lineH*0.8)
+	r.pdf.CellFormat(width, lineH*0.8, r.fit(label.Code, width), "", 0, "C", false, 0, "")
+	return nil
+}
+
+// text prints the name of a label, wrapped on the lines left above its reference and price
+func (r *labelRenderer) text(label Label, x, y, width, height, lineH float64) {
+	details := []string{label.Reference, label.Price}
+	if r.symbology == Code128 {
+		// The barcode leaves a single line for the reference and the price
+		details = []string{strings.TrimSpace(label.Reference + "   " + label.Price)}
+	}
+
+	nameLines := max(int(height/lineH)-len(details), 1)
+	r.pdf.SetFont(r.font.Family, "B", r.size)
+	for i, line := range r.wrap(label.Name, width, nameLines) {
+		r.pdf.SetXY(x, y+float64(i)*lineH)
+		r.pdf.CellFormat(width, lineH, line, "", 0, "L", false, 0, "")
+	}
+
+	r.pdf.SetFont(r.font.Family, "", r.size)
+	for i, detail := range details {
+		r.pdf.SetXY(x, y+float64(nameLines+i)*lineH)
+		r.pdf.CellFormat(width, lineH, r.fit(detail, width), "", 0, "L", false, 0, "")
+	}
+}
+
+// drawBarcode draws a Code128 barcode centered in a box, each run of dark modules as a bar
+func (r *labelRenderer) drawBarcode(code barcode.Barcode, x, y, width, height float64) {
+	modules := code.Bounds().Dx()
+	module := width / float64(modules+2*barcodeQuietZone)
+	x += (width - module*float64(modules)) / 2
+
+	r.pdf.SetFillColor(0, 0, 0)
+	for start := 0; start < modules; {
+		if !isDark(code, start, 0) {
+			start++
+			continue
+		}
+		end := start
+		for end < modules && isDark(code, end, 0) {
+			end++
+		}
+		r.pdf.Rect(x+float64(start)*module, y, float64(end-start)*module, height, "F")
+		start = end
+	}
+}
+
+// drawQR draws a QR code in a square, each run of dark modules of a row as a rectangle
+func (r *labelRenderer) drawQR(code barcode.Barcode, x, y, side float64) {
+	modules := code.Bounds().Dx()
+	module := side / float64(modules+2*qrQuietZone)
+	x, y = x+qrQuietZone*module, y+qrQuietZone*module
+
+	r.pdf.SetFillColor(0, 0, 0)
+	for row := 0; row < modules; row++ {
+		for start := 0; start < modules; {
+			if !isDark(code, start, row) {
+				start++
+				continue
+			}
+			end := start
+			for end < modules && isDark(code, end, row) {
+				end++
+			}
+			r.pdf.Rect(x+float64(start)*module, y+float64(row)*module, float64(end-start)*module, module, "F")
+			start = end
+		}
+	}
+}
+
+// isDark reports whether the module of a code at a position is dark
+func isDark(code image.Image, x, y int) bool {
+	bounds := code.Bounds()
+	gray := color.GrayModel.Convert(code.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.Gray)
+	return gray.Y < 128
+}
+
+// wrap translates a text and wraps it to a width on at most a number of lines, the last one cut when it is too long
+func (r *labelRenderer) wrap(text string, width float64, lines int) []string {
+	var wrapped []string
+	if r.utf8 {
+		wrapped = r.pdf.SplitText(text, width)
+	} else {
+		for _, line := range r.pdf.SplitLines([]byte(r.translate(text)), width) {
+			wrapped = append(wrapped, string(line))
+		}
+	}
+
+	if len(wrapped) <= lines {
+		return wrapped
+	}
+	wrapped = wrapped[:lines]
+	wrapped[lines-1] = r.cut(wrapped[lines-1]+"...", width)
+	return wrapped
+}
+
+// fit translates a text and cuts it to a width
+func (r *labelRenderer) fit(text string, width float64) string {
+	text = r.translate(text)
+	if r.pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	return r.cut(text, width)
+}
+
+// cut shortens a translated text ending with "..." until it fits a width
+func (r *labelRenderer) cut(text string, width float64) string {
+	text = strings.TrimSuffix(text, "...")
+	for text != "" && r.pdf.GetStringWidth(text+"...") > width {
+		if r.utf8 {
+			runes := []rune(text)
+			text = string(runes[:len(runes)-1])
+		} else {
+			text = text[:len(text)-1]
+		}
+	}
+	return strings.TrimRight(text, " ") + "..."
+}
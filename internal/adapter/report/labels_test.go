@@ -0,0 +1,112 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"testing"
+)
+
+func TestLookupLabelLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout string
+		want   string
+		wantOK bool
+	}{
+		{name: "default", want: "avery-l7159", wantOK: true},
+		{name: "known", layout: "avery-5160", want: "avery-5160", wantOK: true},
+		{name: "unknown", layout: "avery-9999"},
+		{name: "case sensitive", layout: "Avery-L7160"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := LookupLabelLayout(tt.layout)
+			if ok != tt.wantOK || got.Name != tt.want {
+				t.Errorf("LookupLabelLayout(%q) = %q, %v, want %q, %v", tt.layout, got.Name, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestLabelLayoutPlace(t *testing.T) {
+	// avery-l7163 has 2 columns of 7 labels
+	layout, _ := LookupLabelLayout("avery-l7163")
+	column := func(i int) float64 { return layout.Left + float64(i)*layout.PitchX }
+	row := func(i int) float64 { return layout.Top + float64(i)*layout.PitchY }
+
+	tests := []struct {
+		name         string
+		start        int
+		i            int
+		wantX, wantY float64
+		wantNewSheet bool
+	}{
+		{name: "first label", start: 1, i: 0, wantX: column(0), wantY: row(0), wantNewSheet: true},
+		{name: "no start", start: 0, i: 0, wantX: column(0), wantY: row(0), wantNewSheet: true},
+		{name: "second column", start: 1, i: 1, wantX: column(1), wantY: row(0)},
+		{name: "second row", start: 1, i: 2, wantX: column(0), wantY: row(1)},
+		{name: "last of the sheet", start: 1, i: 13, wantX: column(1), wantY: row(6)},
+		{name: "first of the next sheet", start: 1, i: 14, wantX: column(0), wantY: row(0), wantNewSheet: true},
+		{name: "first label at the start", start: 4, i: 0, wantX: column(1), wantY: row(1), wantNewSheet: true},
+		{name: "after the start", start: 4, i: 1, wantX: column(0), wantY: row(2)},
+		{name: "next sheet after the start", start: 4, i: 11, wantX: column(0), wantY: row(0), wantNewSheet: true},
+		{name: "start beyond the sheet", start: 16, i: 0, wantX: column(1), wantY: row(0), wantNewSheet: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			x, y, newSheet := layout.place(tt.start, tt.i)
+			if x != tt.wantX || y != tt.wantY || newSheet != tt.wantNewSheet {
+				t.Errorf("place(%d, %d) = %v, %v, %v, want %v, %v, %v", tt.start, tt.i, x, y, newSheet, tt.wantX, tt.wantY, tt.wantNewSheet)
+			}
+		})
+	}
+}
+
+// pageObject matches the page objects of a PDF document, not its page tree
+var pageObject = regexp.MustCompile(`/Type /Page\b[^s]`)
+
+func TestRenderLabelsPages(t *testing.T) {
+	// avery-l7159 has 24 labels a sheet
+	layout, _ := LookupLabelLayout("avery-l7159")
+
+	tests := []struct {
+		name      string
+		start     int
+		labels    int
+		wantPages int
+	}{
+		{name: "no label", start: 1, labels: 0, wantPages: 1},
+		{name: "one sheet", start: 1, labels: 24, wantPages: 1},
+		{name: "two sheets", start: 1, labels: 25, wantPages: 2},
+		{name: "start filling the sheet", start: 20, labels: 5, wantPages: 1},
+		{name: "start overflowing the sheet", start: 20, labels: 6, wantPages: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels := make([]Label, tt.labels)
+			for i := range labels {
+				code := fmt.Sprintf("REF-%03d", i)
+				labels[i] = Label{Name: "Jasmine rice 5kg", Reference: code, Price: "12.50 USD", Code: code}
+			}
+
+			var buf bytes.Buffer
+			if err := RenderLabels(&buf, layout, DefaultTemplate().Font, Code128, tt.start, labels); err != nil {
+				t.Fatalf("RenderLabels() error = %v", err)
+			}
+			if pages := len(pageObject.FindAll(buf.Bytes(), -1)); pages != tt.wantPages {
+				t.Errorf("RenderLabels() pages = %d, want %d", pages, tt.wantPages)
+			}
+		})
+	}
+}
+
+func TestRenderLabelsInvalidCode(t *testing.T) {
+	layout, _ := LookupLabelLayout("")
+	labels := []Label{{Name: "Rice", Code: "REF-1"}, {Name: "Phở", Code: "PHỞ-1"}}
+
+	var buf bytes.Buffer
+	if err := RenderLabels(&buf, layout, DefaultTemplate().Font, Code128, 1, labels); err == nil {
+		t.Error("RenderLabels() of a code Code128 can not encode succeeded")
+	}
+}
@@ -0,0 +1,273 @@
+package report
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Row is a row of the table of a report, its values keyed by column
+type Row map[string]string
+
+// Group is a group of rows, followed by their subtotal when it has one
+type Group struct {
+	Name     string
+	Rows     []Row
+	Subtotal Row
+}
+
+// Filter is a filter the rows of a report were selected with
+type Filter struct {
+	Name  string
+	Value string
+}
+
+// Report is the content of a report, its layout is given by a template
+type Report struct {
+	Filters     []Filter
+	GeneratedAt time.Time
+	// Groups holds the rows, a report without grouping holds a single group without a name
+	Groups []Group
+	Total  Row
+}
+
+const (
+	// lineHeight is the height of a line of text, relative to the font size in millimeters
+	lineHeight = 1.25
+	// cellPadding is the vertical padding of the cells in millimeters
+	cellPadding = 1
+	// pointsPerMillimeter converts font sizes to millimeters
+	pointsPerMillimeter = 72 / 25.4
+)
+
+// renderer lays out a report on the pages of a PDF document
+type renderer struct {
+	pdf      *gofpdf.Fpdf
+	template *Template
+	report   Report
+	// translate encodes UTF-8 text for the core fonts, it leaves the text as is with a TrueType font
+	translate func(string) string
+	utf8      bool
+	lineH     float64
+}
+
+// Render writes a report laid out by a template as a PDF document. The title block, with the filters and the
+// generation time, starts the first page, the column header is repeated on each page and each page has a footer
+func Render(w io.Writer, template *Template, report Report) error {
+	pdf := gofpdf.New(template.Orientation, "mm", template.PageSize, "")
+	pdf.SetMargins(template.Margins.Left, template.Margins.Top, template.Margins.Right)
+	// Rows are kept whole, the page breaks are made before the row that does not fit
+	pdf.SetAutoPageBreak(false, template.Margins.Bottom)
+	pdf.AliasNbPages("{nb}")
+
+	r := &renderer{
+		pdf:       pdf,
+		template:  template,
+		report:    report,
+		translate: func(s string) string { return s },
+		lineH:     template.Font.Size / pointsPerMillimeter * lineHeight,
+	}
+
+	if template.Font.File != "" {
+		bold := template.Font.BoldFile
+		if bold == "" {
+			bold = template.Font.File
+		}
+		pdf.AddUTF8Font(template.Font.Family, "", template.Font.File)
+		pdf.AddUTF8Font(template.Font.Family, "B", bold)
+		r.utf8 = true
+	} else {
+		r.translate = pdf.UnicodeTranslatorFromDescriptor("")
+	}
+
+	pdf.SetHeaderFunc(r.header)
+	pdf.SetFooterFunc(r.footer)
+	pdf.AddPage()
+
+	for _, group := range report.Groups {
+		if group.Name != "" {
+			r.heading(group.Name)
+		}
+		for _, row := range group.Rows {
+			r.row(row, "", false)
+		}
+		if group.Subtotal != nil {
+			r.total(group.Subtotal, template.SubtotalLabel)
+		}
+	}
+	if report.Total != nil {
+		r.total(report.Total, template.TotalLabel)
+	}
+
+	return pdf.Output(w)
+}
+
+// header prints the title block on the first page and the column header on each page
+func (r *renderer) header() {
+	if r.pdf.PageNo() == 1 {
+		r.titleBlock()
+	}
+
+	cells := make(Row, len(r.template.Columns))
+	for _, column := range r.template.Columns {
+		cells[column.Key] = column.Title
+	}
+	r.fill()
+	r.cells(cells, "B", true)
+}
+
+// titleBlock prints the title, the generation time and the filters of the report
+func (r *renderer) titleBlock() {
+	left, _, right, _ := r.pdf.GetMargins()
+	width, _ := r.pdf.GetPageSize()
+	width -= left + right
+
+	r.pdf.SetFont(r.template.Font.Family, "B", r.template.Font.Size*1.6)
+	r.pdf.CellFormat(width, r.lineH*1.8, r.translate(r.template.Title), "", 1, "L", false, 0, "")
+
+	r.pdf.SetFont(r.template.Font.Family, "", r.template.Font.Size)
+	generated := "Generated " + r.report.GeneratedAt.Format(r.template.DateFormat)
+	r.pdf.CellFormat(width, r.lineH, r.translate(generated), "", 1, "L", false, 0, "")
+
+	for _, filter := range r.report.Filters {
+		r.pdf.SetFont(r.template.Font.Family, "B", r.template.Font.Size)
+		label := r.translate(filter.Name + ": ")
+		labelWidth := r.pdf.GetStringWidth(label) + 2
+		r.pdf.CellFormat(labelWidth, r.lineH, label, "", 0, "L", false, 0, "")
+
+		r.pdf.SetFont(r.template.Font.Family, "", r.template.Font.Size)
+		for i, line := range r.split(filter.Value, width-labelWidth) {
+			if i > 0 {
+				r.pdf.SetX(left + labelWidth)
+			}
+			r.pdf.CellFormat(width-labelWidth, r.lineH, line, "", 1, "L", false, 0, "")
+		}
+	}
+
+	r.pdf.Ln(r.lineH / 2)
+}
+
+// footer prints the footer of the page
+func (r *renderer) footer() {
+	_, height := r.pdf.GetPageSize()
+	left, _, right, bottom := r.pdf.GetMargins()
+	width, _ := r.pdf.GetPageSize()
+
+	text := strings.NewReplacer("{page}", strconv.Itoa(r.pdf.PageNo()), "{pages}", "{nb}").Replace(r.template.Footer)
+
+	r.pdf.SetY(height - bottom + (bottom-r.lineH)/2)
+	r.pdf.SetFont(r.template.Font.Family, "", r.template.Font.Size*0.9)
+	r.pdf.CellFormat(width-left-right, r.lineH, r.translate(text), "", 0, "C", false, 0, "")
+}
+
+// heading prints the name of a group across the table
+func (r *renderer) heading(name string) {
+	r.pdf.SetFont(r.template.Font.Family, "B", r.template.Font.Size*1.1)
+	height := r.lineH + 2*cellPadding
+	r.breakPage(height * 2)
+
+	r.pdf.CellFormat(r.tableWidth(), height, r.translate(name), "B", 1, "L", false, 0, "")
+}
+
+// total prints a subtotal or a total row, labelled in its first empty column
+func (r *renderer) total(row Row, label string) {
+	cells := make(Row, len(row)+1)
+	for key, value := range row {
+		cells[key] = value
+	}
+	for _, column := range r.template.Columns {
+		if cells[column.Key] == "" {
+			cells[column.Key] = label
+			break
+		}
+	}
+
+	r.fill()
+	r.row(cells, "B", true)
+}
+
+// row prints a row, on the next page when it does not fit on this one
+func (r *renderer) row(row Row, style string, fill bool) {
+	r.pdf.SetFont(r.template.Font.Family, style, r.template.Font.Size)
+	r.breakPage(r.rowHeight(row))
+	r.cells(row, style, fill)
+}
+
+// breakPage starts a new page when the height does not fit above the bottom margin
+func (r *renderer) breakPage(height float64) {
+	_, pageHeight := r.pdf.GetPageSize()
+	_, _, _, bottom := r.pdf.GetMargins()
+	if r.pdf.GetY()+height > pageHeight-bottom {
+		r.pdf.AddPage()
+	}
+}
+
+// rowHeight returns the height of a row, given by its cell with the most lines
+func (r *renderer) rowHeight(row Row) float64 {
+	lines := 1
+	for _, column := range r.template.Columns {
+		lines = max(lines, len(r.split(row[column.Key], column.Width)))
+	}
+	return float64(lines)*r.lineH + 2*cellPadding
+}
+
+// cells prints the cells of a row with the current font, wrapping their text to the width of their column
+func (r *renderer) cells(row Row, style string, fill bool) {
+	r.pdf.SetFont(r.template.Font.Family, style, r.template.Font.Size)
+	height := r.rowHeight(row)
+
+	left, _, _, _ := r.pdf.GetMargins()
+	x, y := left, r.pdf.GetY()
+	for _, column := range r.template.Columns {
+		rectStyle := "D"
+		if fill {
+			rectStyle = "FD"
+		}
+		r.pdf.Rect(x, y, column.Width, height, rectStyle)
+
+		for i, line := range r.split(row[column.Key], column.Width) {
+			r.pdf.SetXY(x, y+cellPadding+float64(i)*r.lineH)
+			r.pdf.CellFormat(column.Width, r.lineH, line, "", 0, column.Align, false, 0, "")
+		}
+		x += column.Width
+	}
+
+	r.pdf.SetXY(left, y+height)
+}
+
+// split translates a text and wraps it to a width, an empty text is a single empty line
+func (r *renderer) split(text string, width float64) []string {
+	if text == "" {
+		return []string{""}
+	}
+	if r.utf8 {
+		return r.pdf.SplitText(text, width)
+	}
+
+	var lines []string
+	for _, line := range r.pdf.SplitLines([]byte(r.translate(text)), width) {
+		lines = append(lines, string(line))
+	}
+	if len(lines) == 0 {
+		return []string{""}
+	}
+	return lines
+}
+
+// fill sets the fill color of the header and total rows
+func (r *renderer) fill() {
+	red, green, blue, _ := parseColor(r.template.HeaderFill)
+	r.pdf.SetFillColor(red, green, blue)
+}
+
+// tableWidth returns the width of the table, the sum of the widths of its columns
+func (r *renderer) tableWidth() float64 {
+	width := 0.0
+	for _, column := range r.template.Columns {
+		width += column.Width
+	}
+	return width
+}
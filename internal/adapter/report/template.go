@@ -0,0 +1,172 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// Column is a column of the table of a report
+type Column struct {
+	// Key names the value of the rows shown in the column
+	Key string `json:"key"`
+	// Title is the header of the column
+	Title string `json:"title"`
+	// Width is the width of the column in millimeters, the text longer than it wraps
+	Width float64 `json:"width"`
+	// Align is L, C or R
+	Align string `json:"align"`
+}
+
+// Font is the font of a report. Family is a core font such as Arial, Times or Courier, which only
+// prints Western European characters, unless File names a TrueType font printing any UTF-8 text
+type Font struct {
+	Family   string  `json:"family"`
+	File     string  `json:"file"`
+	BoldFile string  `json:"bold_file"`
+	Size     float64 `json:"size"`
+}
+
+// Margins are the margins of the pages in millimeters
+type Margins struct {
+	Top    float64 `json:"top"`
+	Right  float64 `json:"right"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+}
+
+// Template is the layout of a report. The fields missing from a template file keep their default value
+type Template struct {
+	Title string `json:"title"`
+	// Orientation is P for portrait or L for landscape
+	Orientation string `json:"orientation"`
+	// PageSize is A3, A4, A5, Letter or Legal
+	PageSize string  `json:"page_size"`
+	Margins  Margins `json:"margins"`
+	Font     Font    `json:"font"`
+	// HeaderFill is the background color of the column header and the total rows, such as #D9E1F2
+	HeaderFill string `json:"header_fill"`
+	// DateFormat is the Go layout of the generation time
+	DateFormat string `json:"date_format"`
+	// Footer is printed at the bottom of each page, {page} and {pages} are replaced by the page number and count
+	Footer        string `json:"footer"`
+	SubtotalLabel string `json:"subtotal_label"`
+	TotalLabel    string `json:"total_label"`
+	// GroupBy groups the rows of the report, "category" or "none"
+	GroupBy string   `json:"group_by"`
+	Columns []Column `json:"columns"`
+}
+
+// DefaultTemplate returns the layout used when no template file is configured, an A4 portrait page of products
+func DefaultTemplate() *Template {
+	return &Template{
+		Title:       "Product List",
+		Orientation: "P",
+		PageSize:    "A4",
+		Margins:     Margins{Top: 10, Right: 10, Bottom: 15, Left: 10},
+		Font: Font{
+			Family: "Arial",
+			Size:   9,
+		},
+		HeaderFill:    "#D9E1F2",
+		DateFormat:    "2006-01-02 15:04 MST",
+		Footer:        "Page {page} of {pages}",
+		SubtotalLabel: "Subtotal",
+		TotalLabel:    "Total",
+		GroupBy:       "none",
+		Columns: []Column{
+			{Key: "reference", Title: "Reference", Width: 25, Align: "L"},
+			{Key: "name", Title: "Name", Width: 50, Align: "L"},
+			{Key: "category", Title: "Category", Width: 30, Align: "L"},
+			{Key: "status", Title: "Status", Width: 20, Align: "L"},
+			{Key: "quantity", Title: "Quantity", Width: 16, Align: "R"},
+			{Key: "effective_price", Title: "Price", Width: 22, Align: "R"},
+			{Key: "stock_value", Title: "Stock value", Width: 27, Align: "R"},
+		},
+	}
+}
+
+// LoadTemplate reads a JSON template file over the default template, its columns must name one of the keys
+func LoadTemplate(path string, keys []string) (*Template, error) {
+	template := DefaultTemplate()
+	if path == "" {
+		return template, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, template); err != nil {
+		return nil, fmt.Errorf("report template %s: %w", path, err)
+	}
+
+	if err := template.validate(keys); err != nil {
+		return nil, fmt.Errorf("report template %s: %w", path, err)
+	}
+
+	return template, nil
+}
+
+// pageSizes are the page sizes a template can use
+var pageSizes = []string{"a3", "a4", "a5", "letter", "legal"}
+
+// validate checks the template can lay out a report
+func (t *Template) validate(keys []string) error {
+	t.Orientation = strings.ToUpper(t.Orientation)
+	if t.Orientation != "P" && t.Orientation != "L" {
+		return fmt.Errorf("orientation must be P or L, not %q", t.Orientation)
+	}
+	if !slices.Contains(pageSizes, strings.ToLower(t.PageSize)) {
+		return fmt.Errorf("unknown page size %q", t.PageSize)
+	}
+	if t.Margins.Top < 0 || t.Margins.Right < 0 || t.Margins.Bottom < 0 || t.Margins.Left < 0 {
+		return errors.New("margins can not be negative")
+	}
+	if t.Font.Size <= 0 {
+		return errors.New("font size must be positive")
+	}
+	if _, _, _, ok := parseColor(t.HeaderFill); !ok {
+		return fmt.Errorf("header fill must be a color such as #D9E1F2, not %q", t.HeaderFill)
+	}
+	if t.GroupBy != "none" && t.GroupBy != "category" {
+		return fmt.Errorf("group by must be none or category, not %q", t.GroupBy)
+	}
+
+	if len(t.Columns) == 0 {
+		return errors.New("no column")
+	}
+	for i, column := range t.Columns {
+		if !slices.Contains(keys, column.Key) {
+			return fmt.Errorf("column %d: unknown key %q", i+1, column.Key)
+		}
+		if column.Width <= 0 {
+			return fmt.Errorf("column %s: width must be positive", column.Key)
+		}
+		t.Columns[i].Align = strings.ToUpper(column.Align)
+		switch t.Columns[i].Align {
+		case "":
+			t.Columns[i].Align = "L"
+		case "L", "C", "R":
+		default:
+			return fmt.Errorf("column %s: align must be L, C or R, not %q", column.Key, column.Align)
+		}
+	}
+
+	return nil
+}
+
+// parseColor parses a #RRGGBB color
+func parseColor(color string) (int, int, int, bool) {
+	var r, g, b int
+	if len(color) != 7 {
+		return 0, 0, 0, false
+	}
+	if _, err := fmt.Sscanf(color, "#%02x%02x%02x", &r, &g, &b); err != nil {
+		return 0, 0, 0, false
+	}
+	return r, g, b, true
+}
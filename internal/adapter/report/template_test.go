@@ -0,0 +1,71 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemplateValidate(t *testing.T) {
+	keys := []string{"reference", "name", "category", "status", "quantity", "effective_price", "stock_value"}
+
+	tests := []struct {
+		name    string
+		edit    func(template *Template)
+		wantErr string
+	}{
+		{name: "default", edit: func(*Template) {}},
+		{name: "landscape in lower case", edit: func(template *Template) { template.Orientation = "l" }},
+		{name: "page size in any case", edit: func(template *Template) { template.PageSize = "LETTER" }},
+		{name: "unknown orientation", edit: func(template *Template) { template.Orientation = "portrait" }, wantErr: "orientation must be P or L"},
+		{name: "unknown page size", edit: func(template *Template) { template.PageSize = "B5" }, wantErr: `unknown page size "B5"`},
+		{name: "negative margin", edit: func(template *Template) { template.Margins.Left = -1 }, wantErr: "margins can not be negative"},
+		{name: "no font size", edit: func(template *Template) { template.Font.Size = 0 }, wantErr: "font size must be positive"},
+		{name: "color name", edit: func(template *Template) { template.HeaderFill = "blue" }, wantErr: "header fill must be a color"},
+		{name: "short color", edit: func(template *Template) { template.HeaderFill = "#DDD" }, wantErr: "header fill must be a color"},
+		{name: "not hexadecimal color", edit: func(template *Template) { template.HeaderFill = "#GGHHII" }, wantErr: "header fill must be a color"},
+		{name: "unknown grouping", edit: func(template *Template) { template.GroupBy = "supplier" }, wantErr: "group by must be none or category"},
+		{name: "no column", edit: func(template *Template) { template.Columns = nil }, wantErr: "no column"},
+		{name: "unknown column key", edit: func(template *Template) { template.Columns[1].Key = "colour" }, wantErr: `column 2: unknown key "colour"`},
+		{name: "no column width", edit: func(template *Template) { template.Columns[0].Width = 0 }, wantErr: "column reference: width must be positive"},
+		{name: "unknown align", edit: func(template *Template) { template.Columns[0].Align = "J" }, wantErr: "column reference: align must be L, C or R"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := DefaultTemplate()
+			tt.edit(template)
+
+			err := template.validate(keys)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTemplateValidateNormalizes(t *testing.T) {
+	template := DefaultTemplate()
+	template.Orientation = "l"
+	template.Columns = []Column{
+		{Key: "name", Width: 50},
+		{Key: "quantity", Width: 20, Align: "r"},
+		{Key: "status", Width: 20, Align: "c"},
+	}
+
+	if err := template.validate([]string{"name", "quantity", "status"}); err != nil {
+		t.Fatalf("validate() error = %v", err)
+	}
+	if template.Orientation != "L" {
+		t.Errorf("orientation = %q, want L", template.Orientation)
+	}
+	for i, want := range []string{"L", "R", "C"} {
+		if got := template.Columns[i].Align; got != want {
+			t.Errorf("column %s align = %q, want %q", template.Columns[i].Key, got, want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
+)
+
+// defaultExportDir is used when no export directory is configured
+const defaultExportDir = "exports"
+
+/**
+ * ExportStorage implements port.ExportStorage interface
+ * and provides an access to the files of the export jobs in a local directory
+ */
+type ExportStorage struct {
+	dir string
+}
+
+// NewExportStorage creates a new export storage instance, creating its directory when missing
+func NewExportStorage(config *config.Export) (*ExportStorage, error) {
+	dir := config.Dir
+	if dir == "" {
+		dir = defaultExportDir
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &ExportStorage{
+		dir,
+	}, nil
+}
+
+// Create creates a file. It is written under a temporary name and only takes its name once closed,
+// so a file being written is never opened
+func (es *ExportStorage) Create(_ context.Context, name string) (io.WriteCloser, error) {
+	path, err := es.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.CreateTemp(es.dir, name+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	return &exportFile{file, path}, nil
+}
+
+// Open opens a file for reading
+func (es *ExportStorage) Open(_ context.Context, name string) (io.ReadSeekCloser, error) {
+	path, err := es.path(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+// Remove removes a file, a missing file is not an error
+func (es *ExportStorage) Remove(_ context.Context, name string) error {
+	path, err := es.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// path returns the path of a file, which must be named without any directory
+func (es *ExportStorage) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid export file name %q", name)
+	}
+
+	return filepath.Join(es.dir, name), nil
+}
+
+// exportFile is a file written under a temporary name, renamed to its path once closed
+type exportFile struct {
+	*os.File
+	path string
+}
+
+// Close closes the file and renames it to its path, it is removed when either fails
+func (ef *exportFile) Close() error {
+	err := ef.File.Close()
+	if err == nil {
+		err = os.Rename(ef.Name(), ef.path)
+	}
+	if err != nil {
+		os.Remove(ef.Name())
+	}
+	return err
+}
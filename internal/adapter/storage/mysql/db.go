@@ -51,7 +51,7 @@ func New(ctx context.Context, config *config.DB) (*DB, error) {
 		sqlDB.SetMaxIdleConns(config.MaxIdleConns)
 	}
 	if config.MaxOpenConns > 0 {
-		sqlDB.SetMaxOpenConns(config.MaxIdleConns)
+		sqlDB.SetMaxOpenConns(config.MaxOpenConns)
 	}
 	if config.MaxLifetime > 0 {
 		sqlDB.SetConnMaxLifetime(config.MaxLifetime)
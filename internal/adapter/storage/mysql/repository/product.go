@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/mysql"
 	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
@@ -3,10 +3,11 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
 
 	"github.com/Masterminds/squirrel"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
 )
@@ -16,32 +17,31 @@ import (
  * that uses pgxpool as database driver.
  * It also holds a reference to squirrel.StatementBuilderType
  * which is used to build SQL queries that compatible with PostgreSQL syntax
+ * and an optional read replica pool for read-only queries
  */
 type DB struct {
 	*pgxpool.Pool
 	QueryBuilder *squirrel.StatementBuilderType
 	url          string
+	replica      *pgxpool.Pool
 }
 
 // New creates a new PostgreSQL database instance
 func New(ctx context.Context, config *config.DB) (*DB, error) {
-	url := fmt.Sprintf("%s://%s:%s@%s:%s/%s?sslmode=disable",
-		config.Connection,
-		config.User,
-		config.Password,
-		config.Host,
-		config.Port,
-		config.Name,
-	)
-
-	db, err := pgxpool.New(ctx, url)
+	url := dsn(config)
+
+	db, err := newPool(ctx, url, config)
 	if err != nil {
 		return nil, err
 	}
 
-	err = db.Ping(ctx)
-	if err != nil {
-		return nil, err
+	var replica *pgxpool.Pool
+	if config.ReplicaURL != "" {
+		replica, err = newPool(ctx, config.ReplicaURL, config)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("read replica: %w", err)
+		}
 	}
 
 	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
@@ -50,9 +50,98 @@ func New(ctx context.Context, config *config.DB) (*DB, error) {
 		db,
 		&psql,
 		url,
+		replica,
 	}, nil
 }
 
+// dsn builds the primary connection string from the database config
+func dsn(config *config.DB) string {
+	sslMode := config.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+
+	query := url.Values{}
+	query.Set("sslmode", sslMode)
+	if config.SSLRootCert != "" {
+		query.Set("sslrootcert", config.SSLRootCert)
+	}
+	if config.SSLCert != "" {
+		query.Set("sslcert", config.SSLCert)
+	}
+	if config.SSLKey != "" {
+		query.Set("sslkey", config.SSLKey)
+	}
+
+	u := url.URL{
+		Scheme:   config.Connection,
+		User:     url.UserPassword(config.User, config.Password),
+		Host:     net.JoinHostPort(config.Host, config.Port),
+		Path:     config.Name,
+		RawQuery: query.Encode(),
+	}
+
+	return u.String()
+}
+
+// newPool opens and pings a connection pool tuned with the pool settings of the database config
+func newPool(ctx context.Context, url string, config *config.DB) (*pgxpool.Pool, error) {
+	poolConfig, err := newPoolConfig(url, config)
+	if err != nil {
+		return nil, err
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	err = pool.Ping(ctx)
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+
+	return pool, nil
+}
+
+// newPoolConfig parses a connection string into a pool config tuned with the pool settings of the database config
+func newPoolConfig(url string, config *config.DB) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.MaxOpenConns > 0 {
+		poolConfig.MaxConns = int32(config.MaxOpenConns)
+	}
+	// pgxpool has no idle connection cap, keep that many connections warm instead
+	if config.MaxIdleConns > 0 {
+		poolConfig.MinConns = int32(config.MaxIdleConns)
+	}
+	if poolConfig.MinConns > poolConfig.MaxConns {
+		poolConfig.MinConns = poolConfig.MaxConns
+	}
+	if config.MaxLifetime > 0 {
+		poolConfig.MaxConnLifetime = config.MaxLifetime
+	}
+	if config.MaxIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = config.MaxIdleTime
+	}
+
+	return poolConfig, nil
+}
+
+// Replica returns the read replica pool for list, search, statistic and export queries,
+// or the primary pool when no replica is configured.
+// Writes and reads that must see the caller's own writes always use the primary pool
+func (db *DB) Replica() *pgxpool.Pool {
+	if db.replica != nil {
+		return db.replica
+	}
+	return db.Pool
+}
+
 // Migrate runs the database migration
 func (db *DB) Migrate() error {
 	//driver, err := iofs.New(migrationsFS, "migrations")
@@ -73,13 +162,10 @@ func (db *DB) Migrate() error {
 	return nil
 }
 
-// ErrorCode returns the error code of the given error
-func (db *DB) ErrorCode(err error) string {
-	pgErr := err.(*pgconn.PgError)
-	return pgErr.Code
-}
-
-// Close closes the database connection
+// Close closes the database connections
 func (db *DB) Close() {
+	if db.replica != nil {
+		db.replica.Close()
+	}
 	db.Pool.Close()
 }
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	codeNotNullViolation     = "23502"
+	codeForeignKeyViolation  = "23503"
+	codeUniqueViolation      = "23505"
+	codeCheckViolation       = "23514"
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// ErrorCode returns the SQLSTATE code of the given error,
+// or an empty string if it is not a PostgreSQL error
+func (db *DB) ErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return ""
+	}
+	return pgErr.Code
+}
+
+// TranslateError maps a database error to the matching domain error.
+// Constraint violations keep the constraint (or column) name in the message,
+// errors that are not recognized are returned unchanged
+func (db *DB) TranslateError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.ErrDataNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case codeUniqueViolation:
+		return withConstraint(domain.ErrConflictingData, pgErr.ConstraintName)
+	case codeForeignKeyViolation:
+		// The referenced side reports "update or delete on table ... violates foreign key constraint",
+		// the referencing side reports "insert or update on table ... violates foreign key constraint"
+		if strings.HasPrefix(pgErr.Message, "update or delete") {
+			return withConstraint(domain.ErrForeignKeyViolation, pgErr.ConstraintName)
+		}
+		return withConstraint(domain.ErrInvalidReference, pgErr.ConstraintName)
+	case codeNotNullViolation:
+		if pgErr.ColumnName != "" {
+			return fmt.Errorf("%w (column %s)", domain.ErrMissingRequiredData, pgErr.ColumnName)
+		}
+		return domain.ErrMissingRequiredData
+	case codeCheckViolation:
+		return withConstraint(domain.ErrConstraintViolation, pgErr.ConstraintName)
+	case codeSerializationFailure, codeDeadlockDetected:
+		return domain.ErrConcurrentUpdate
+	default:
+		return err
+	}
+}
+
+// withConstraint wraps a domain error with the name of the violated constraint
+func withConstraint(err error, constraint string) error {
+	if constraint == "" {
+		return err
+	}
+	return fmt.Errorf("%w (constraint %s)", err, constraint)
+}
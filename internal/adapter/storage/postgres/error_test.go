@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+func TestDB_TranslateError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    error
+		wantMsg string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "no rows",
+			err:  pgx.ErrNoRows,
+			want: domain.ErrDataNotFound,
+		},
+		{
+			name: "context canceled is not a pg error",
+			err:  context.Canceled,
+			want: context.Canceled,
+		},
+		{
+			name:    "unique violation",
+			err:     &pgconn.PgError{Code: "23505", ConstraintName: "products_reference_key"},
+			want:    domain.ErrConflictingData,
+			wantMsg: "data conflicts with existing data in unique column (constraint products_reference_key)",
+		},
+		{
+			name: "foreign key violation on insert",
+			err: &pgconn.PgError{
+				Code:           "23503",
+				Message:        `insert or update on table "products" violates foreign key constraint "products_category_id_fkey"`,
+				ConstraintName: "products_category_id_fkey",
+			},
+			want: domain.ErrInvalidReference,
+		},
+		{
+			name: "foreign key violation on delete",
+			err: &pgconn.PgError{
+				Code:           "23503",
+				Message:        `update or delete on table "categories" violates foreign key constraint "products_category_id_fkey" on table "products"`,
+				ConstraintName: "products_category_id_fkey",
+			},
+			want: domain.ErrForeignKeyViolation,
+		},
+		{
+			name:    "not null violation",
+			err:     &pgconn.PgError{Code: "23502", ColumnName: "name"},
+			want:    domain.ErrMissingRequiredData,
+			wantMsg: "required data is missing (column name)",
+		},
+		{
+			name: "check violation",
+			err:  &pgconn.PgError{Code: "23514", ConstraintName: "products_quantity_check"},
+			want: domain.ErrConstraintViolation,
+		},
+		{
+			name: "serialization failure wrapped",
+			err:  fmt.Errorf("commit: %w", &pgconn.PgError{Code: "40001"}),
+			want: domain.ErrConcurrentUpdate,
+		},
+	}
+	db := &DB{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := db.TranslateError(tt.err)
+			if !errors.Is(got, tt.want) {
+				t.Errorf("TranslateError() = %v, want %v", got, tt.want)
+			}
+			if tt.wantMsg != "" && got.Error() != tt.wantMsg {
+				t.Errorf("TranslateError() message = %q, want %q", got.Error(), tt.wantMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS "categories" (
+    "id" uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    "name" varchar NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS "suppliers" (
+    "id" uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    "name" varchar NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS "products" (
+    "id" uuid PRIMARY KEY,
+    "reference" varchar NOT NULL UNIQUE,
+    "name" varchar NOT NULL,
+    "added_date" timestamptz NOT NULL DEFAULT now(),
+    "status" varchar NOT NULL,
+    "category_id" uuid REFERENCES "categories" ("id"),
+    "price" numeric(18, 2) NOT NULL DEFAULT 0,
+    "stock_city" varchar NOT NULL DEFAULT '',
+    "supplier_id" uuid REFERENCES "suppliers" ("id"),
+    "quantity" integer NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS "products_category_id" ON "products" ("category_id");
+CREATE INDEX IF NOT EXISTS "products_supplier_id" ON "products" ("supplier_id");
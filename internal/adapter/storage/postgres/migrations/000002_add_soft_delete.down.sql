@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "products_deleted_at";
+DROP INDEX IF EXISTS "categories_deleted_at";
+
+ALTER TABLE "products" DROP COLUMN "deleted_at";
+ALTER TABLE "categories" DROP COLUMN "deleted_at";
@@ -0,0 +1,5 @@
+ALTER TABLE "categories" ADD COLUMN "deleted_at" timestamptz;
+ALTER TABLE "products" ADD COLUMN "deleted_at" timestamptz;
+
+CREATE INDEX "categories_deleted_at" ON "categories" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+CREATE INDEX "products_deleted_at" ON "products" ("deleted_at") WHERE "deleted_at" IS NOT NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE "audit_entries" (
+    "id" uuid PRIMARY KEY,
+    "entity_type" varchar NOT NULL,
+    "entity_id" uuid NOT NULL,
+    "action" varchar NOT NULL,
+    "actor" varchar NOT NULL,
+    "claimed_actor" varchar NOT NULL DEFAULT '',
+    "request_id" varchar NOT NULL DEFAULT '',
+    "changes" jsonb NOT NULL DEFAULT '[]',
+    "created_at" timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX "audit_entries_entity" ON "audit_entries" ("entity_type", "entity_id", "created_at" DESC);
@@ -0,0 +1,15 @@
+CREATE TABLE "product_prices" (
+    "id" uuid PRIMARY KEY,
+    "product_id" uuid NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+    "price" numeric(18,2) NOT NULL CHECK ("price" > 0),
+    "starts_at" timestamptz NOT NULL,
+    "ends_at" timestamptz CHECK ("ends_at" > "starts_at"),
+    "applied_at" timestamptz,
+    "canceled_at" timestamptz,
+    "attempts" integer NOT NULL DEFAULT 0,
+    "retry_at" timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX "product_prices_product" ON "product_prices" ("product_id", "starts_at" DESC);
+CREATE INDEX "product_prices_pending" ON "product_prices" ("starts_at") WHERE "applied_at" IS NULL AND "canceled_at" IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE "product_prices" DROP COLUMN IF EXISTS "currency";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "currency";
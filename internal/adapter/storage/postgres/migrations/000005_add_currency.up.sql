@@ -0,0 +1,2 @@
+ALTER TABLE "products" ADD COLUMN "currency" char(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE "product_prices" ADD COLUMN "currency" char(3) NOT NULL DEFAULT 'USD';
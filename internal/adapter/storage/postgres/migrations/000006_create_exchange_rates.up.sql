@@ -0,0 +1,5 @@
+CREATE TABLE "exchange_rates" (
+    "currency" char(3) PRIMARY KEY,
+    "rate" numeric(24,10) NOT NULL CHECK ("rate" > 0),
+    "updated_at" timestamptz NOT NULL DEFAULT now()
+);
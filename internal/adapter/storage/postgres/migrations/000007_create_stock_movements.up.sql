@@ -0,0 +1,20 @@
+CREATE TABLE "stock_movements" (
+    "id" uuid PRIMARY KEY,
+    "product_id" uuid NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+    "type" varchar NOT NULL CHECK ("type" IN ('receipt', 'sale', 'return', 'adjustment', 'transfer')),
+    "quantity" integer NOT NULL CHECK ("quantity" <> 0),
+    "balance" integer NOT NULL CHECK ("balance" >= 0),
+    "reason" varchar NOT NULL DEFAULT '',
+    "actor" varchar NOT NULL,
+    "request_id" varchar NOT NULL DEFAULT '',
+    -- clock_timestamp keeps the movements posted in a single transaction in order
+    "created_at" timestamptz NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX "stock_movements_product" ON "stock_movements" ("product_id", "created_at" DESC);
+
+-- Open the ledger of the existing products with their current stock
+INSERT INTO "stock_movements" ("id", "product_id", "type", "quantity", "balance", "reason", "actor")
+SELECT gen_random_uuid(), "id", 'adjustment', "quantity", "quantity", 'opening balance', 'system'
+FROM "products"
+WHERE "quantity" > 0;
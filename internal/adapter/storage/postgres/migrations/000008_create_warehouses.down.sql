@@ -0,0 +1,4 @@
+ALTER TABLE "stock_movements" DROP COLUMN IF EXISTS "transfer_id";
+ALTER TABLE "stock_movements" DROP COLUMN IF EXISTS "warehouse_id";
+DROP TABLE IF EXISTS "warehouse_stocks";
+DROP TABLE IF EXISTS "warehouses";
@@ -0,0 +1,40 @@
+CREATE TABLE "warehouses" (
+    "id" uuid PRIMARY KEY,
+    "name" varchar NOT NULL,
+    "city" varchar NOT NULL,
+    "latitude" double precision CHECK ("latitude" BETWEEN -90 AND 90),
+    "longitude" double precision CHECK ("longitude" BETWEEN -180 AND 180),
+    "created_at" timestamptz NOT NULL DEFAULT now(),
+    CHECK (("latitude" IS NULL) = ("longitude" IS NULL))
+);
+
+CREATE UNIQUE INDEX "warehouses_name" ON "warehouses" ("name");
+
+CREATE TABLE "warehouse_stocks" (
+    "warehouse_id" uuid NOT NULL REFERENCES "warehouses" ("id"),
+    "product_id" uuid NOT NULL REFERENCES "products" ("id") ON DELETE CASCADE,
+    "quantity" integer NOT NULL CHECK ("quantity" >= 0),
+    PRIMARY KEY ("warehouse_id", "product_id")
+);
+
+CREATE INDEX "warehouse_stocks_product" ON "warehouse_stocks" ("product_id");
+
+ALTER TABLE "stock_movements" ADD COLUMN "warehouse_id" uuid REFERENCES "warehouses" ("id") ON DELETE SET NULL;
+ALTER TABLE "stock_movements" ADD COLUMN "transfer_id" uuid;
+
+-- Turn the stock city of the products into warehouses holding their stock
+INSERT INTO "warehouses" ("id", "name", "city")
+SELECT gen_random_uuid(), "stock_city", "stock_city"
+FROM (SELECT DISTINCT "stock_city" FROM "products" WHERE "stock_city" <> '') AS "cities";
+
+INSERT INTO "warehouse_stocks" ("warehouse_id", "product_id", "quantity")
+SELECT "warehouses"."id", "products"."id", "products"."quantity"
+FROM "products"
+JOIN "warehouses" ON "warehouses"."city" = "products"."stock_city"
+WHERE "products"."quantity" > 0;
+
+UPDATE "stock_movements"
+SET "warehouse_id" = "warehouse_stocks"."warehouse_id"
+FROM "warehouse_stocks"
+WHERE "stock_movements"."product_id" = "warehouse_stocks"."product_id"
+  AND "stock_movements"."reason" = 'opening balance';
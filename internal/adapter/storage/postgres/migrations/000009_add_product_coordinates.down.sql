@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "warehouses_coordinates";
+DROP INDEX IF EXISTS "products_stock_coordinates";
+ALTER TABLE "products" DROP CONSTRAINT IF EXISTS "products_stock_coordinates_pair";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "stock_longitude";
+ALTER TABLE "products" DROP COLUMN IF EXISTS "stock_latitude";
@@ -0,0 +1,17 @@
+ALTER TABLE "products" ADD COLUMN "stock_latitude" double precision CHECK ("stock_latitude" BETWEEN -90 AND 90);
+ALTER TABLE "products" ADD COLUMN "stock_longitude" double precision CHECK ("stock_longitude" BETWEEN -180 AND 180);
+ALTER TABLE "products" ADD CONSTRAINT "products_stock_coordinates_pair" CHECK (("stock_latitude" IS NULL) = ("stock_longitude" IS NULL));
+
+CREATE INDEX "products_stock_coordinates" ON "products" ("stock_latitude", "stock_longitude") WHERE "stock_latitude" IS NOT NULL;
+CREATE INDEX "warehouses_coordinates" ON "warehouses" ("latitude", "longitude") WHERE "latitude" IS NOT NULL;
+
+-- Locate the stock city of the products from the warehouses of that city already located
+UPDATE "products"
+SET "stock_latitude" = "located"."latitude", "stock_longitude" = "located"."longitude"
+FROM (
+    SELECT DISTINCT ON ("city") "city", "latitude", "longitude"
+    FROM "warehouses"
+    WHERE "latitude" IS NOT NULL
+    ORDER BY "city", "created_at"
+) AS "located"
+WHERE "located"."city" = "products"."stock_city";
@@ -0,0 +1,19 @@
+CREATE TABLE "export_jobs" (
+    "id" uuid PRIMARY KEY,
+    "format" varchar NOT NULL CHECK ("format" IN ('pdf', 'csv', 'xlsx')),
+    "filter" jsonb NOT NULL,
+    "options" jsonb NOT NULL,
+    "state" varchar NOT NULL CHECK ("state" IN ('pending', 'running', 'completed', 'failed', 'canceled', 'expired')),
+    "row_count" bigint NOT NULL DEFAULT 0,
+    "size" bigint NOT NULL DEFAULT 0,
+    "error" varchar NOT NULL DEFAULT '',
+    "attempts" integer NOT NULL DEFAULT 0,
+    "created_at" timestamptz NOT NULL DEFAULT now(),
+    "started_at" timestamptz,
+    "heartbeat_at" timestamptz,
+    "finished_at" timestamptz,
+    "expires_at" timestamptz NOT NULL
+);
+
+CREATE INDEX "export_jobs_state_created_at" ON "export_jobs" ("state", "created_at");
+CREATE INDEX "export_jobs_expires_at" ON "export_jobs" ("expires_at") WHERE "state" <> 'expired';
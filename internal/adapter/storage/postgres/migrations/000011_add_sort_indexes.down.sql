@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "products_added_date";
+DROP INDEX IF EXISTS "categories_created_at";
+
+ALTER TABLE "categories" DROP COLUMN IF EXISTS "created_at";
@@ -0,0 +1,5 @@
+ALTER TABLE "categories" ADD COLUMN "created_at" timestamptz NOT NULL DEFAULT now();
+
+-- The default orders of the lists, newest first, with the id breaking ties for keyset pagination
+CREATE INDEX "categories_created_at" ON "categories" ("created_at", "id");
+CREATE INDEX "products_added_date" ON "products" ("added_date", "id");
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// fieldChange is the JSON representation of a domain.FieldChange in the changes column
+type fieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+/**
+ * AuditRepository implements port.AuditRepository interface
+ * and provides an access to the postgres database
+ */
+type AuditRepository struct {
+	db *postgres.DB
+}
+
+// NewAuditRepository creates a new audit repository instance
+func NewAuditRepository(db *postgres.DB) *AuditRepository {
+	return &AuditRepository{
+		db,
+	}
+}
+
+// CreateAuditEntry creates a new audit entry record in the database
+func (ar *AuditRepository) CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	changes := make([]fieldChange, len(entry.Changes))
+	for i, change := range entry.Changes {
+		changes[i] = fieldChange(change)
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+
+	query := ar.db.QueryBuilder.Insert("audit_entries").
+		Columns("id", "entity_type", "entity_id", "action", "actor", "claimed_actor", "request_id", "changes", "created_at").
+		Values(
+			entry.ID,
+			entry.EntityType,
+			entry.EntityID,
+			entry.Action,
+			entry.Actor,
+			entry.ClaimedActor,
+			entry.RequestID,
+			changesJSON,
+			entry.CreatedAt,
+		)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = ar.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return ar.db.TranslateError(err)
+	}
+
+	return nil
+}
+
+// CreateAuditEntries creates audit entry records in the database at once with a copy
+func (ar *AuditRepository) CreateAuditEntries(ctx context.Context, entries []domain.AuditEntry) error {
+	rows := make([][]any, len(entries))
+	for i, entry := range entries {
+		changes := make([]fieldChange, len(entry.Changes))
+		for j, change := range entry.Changes {
+			changes[j] = fieldChange(change)
+		}
+
+		changesJSON, err := json.Marshal(changes)
+		if err != nil {
+			return err
+		}
+
+		rows[i] = []any{
+			entry.ID,
+			entry.EntityType,
+			entry.EntityID,
+			entry.Action,
+			entry.Actor,
+			entry.ClaimedActor,
+			entry.RequestID,
+			changesJSON,
+			entry.CreatedAt,
+		}
+	}
+
+	_, err := ar.db.Conn(ctx).CopyFrom(ctx,
+		pgx.Identifier{"audit_entries"},
+		[]string{"id", "entity_type", "entity_id", "action", "actor", "claimed_actor", "request_id", "changes", "created_at"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return ar.db.TranslateError(err)
+	}
+
+	return nil
+}
+
+// ListAuditEntries retrieves the audit entries of an entity from the database, newest first
+func (ar *AuditRepository) ListAuditEntries(ctx context.Context, entityType domain.AuditEntity, entityID uuid.UUID, skip, limit uint64) ([]domain.AuditEntry, error) {
+	var entries []domain.AuditEntry
+
+	query := ar.db.QueryBuilder.Select("id", "entity_type", "entity_id", "action", "actor", "claimed_actor", "request_id", "changes", "created_at").
+		From("audit_entries").
+		Where(sq.Eq{"entity_type": entityType, "entity_id": entityID}).
+		OrderBy("created_at DESC", "id").
+		Limit(limit).
+		Offset(skip * limit)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := ar.db.Replica().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.AuditEntry
+		var changesJSON []byte
+
+		err := rows.Scan(
+			&entry.ID,
+			&entry.EntityType,
+			&entry.EntityID,
+			&entry.Action,
+			&entry.Actor,
+			&entry.ClaimedActor,
+			&entry.RequestID,
+			&changesJSON,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var changes []fieldChange
+		if err := json.Unmarshal(changesJSON, &changes); err != nil {
+			return nil, err
+		}
+		for _, change := range changes {
+			entry.Changes = append(entry.Changes, domain.FieldChange(change))
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
@@ -10,6 +12,24 @@ import (
 	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
 )
 
+// categoryColumns is the list of category columns in the order scanCategory reads them
+var categoryColumns = []string{
+	"id",
+	"name",
+	"created_at",
+	"deleted_at",
+}
+
+// scanCategory scans a row selected with categoryColumns into a category
+func scanCategory(row pgx.Row, category *domain.Category) error {
+	return row.Scan(
+		&category.ID,
+		&category.Name,
+		&category.CreatedAt,
+		&category.DeletedAt,
+	)
+}
+
 /**
  * CategoryRepository implements port.CategoryRepository interface
  * and provides an access to the postgres database
@@ -30,81 +50,138 @@ func (cr *CategoryRepository) CreateCategory(ctx context.Context, category *doma
 	query := cr.db.QueryBuilder.Insert("categories").
 		Columns("name").
 		Values(category.Name).
-		Suffix("RETURNING *")
+		Suffix("RETURNING " + strings.Join(categoryColumns, ", "))
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	err = cr.db.QueryRow(ctx, sql, args...).Scan(
-		&category.ID,
-		&category.Name,
-	)
+	err = scanCategory(cr.db.Conn(ctx).QueryRow(ctx, sql, args...), category)
 	if err != nil {
-		if errCode := cr.db.ErrorCode(err); errCode == "23505" {
-			return nil, domain.ErrConflictingData
-		}
-		return nil, err
+		return nil, cr.db.TranslateError(err)
 	}
 
 	return category, nil
 }
 
 // GetCategoryByID retrieves a category record from the database by id
-func (cr *CategoryRepository) GetCategoryByID(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+func (cr *CategoryRepository) GetCategoryByID(ctx context.Context, id uuid.UUID, includeDeleted bool) (*domain.Category, error) {
 	var category domain.Category
 
-	query := cr.db.QueryBuilder.Select("*").
+	query := cr.db.QueryBuilder.Select(categoryColumns...).
 		From("categories").
 		Where(sq.Eq{"id": id.String()}).
 		Limit(1)
+	if !includeDeleted {
+		query = query.Where(sq.Eq{"deleted_at": nil})
+	}
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	err = cr.db.QueryRow(ctx, sql, args...).Scan(
-		&category.ID,
-		&category.Name,
-	)
+	err = scanCategory(cr.db.Conn(ctx).QueryRow(ctx, sql, args...), &category)
+	if err != nil {
+		return nil, cr.db.TranslateError(err)
+	}
+
+	return &category, nil
+}
+
+// GetCategoryForUpdate retrieves a category record from the database by id and locks it until the end of the transaction
+func (cr *CategoryRepository) GetCategoryForUpdate(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	var category domain.Category
+
+	query := cr.db.QueryBuilder.Select(categoryColumns...).
+		From("categories").
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		Suffix("FOR UPDATE")
+
+	sql, args, err := query.ToSql()
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, domain.ErrDataNotFound
-		}
 		return nil, err
 	}
 
+	err = scanCategory(cr.db.Conn(ctx).QueryRow(ctx, sql, args...), &category)
+	if err != nil {
+		return nil, cr.db.TranslateError(err)
+	}
+
 	return &category, nil
 }
 
-// ListCategories retrieves a list of categories from the database
-func (cr *CategoryRepository) ListCategories(ctx context.Context, skip, limit uint64) ([]domain.Category, error) {
+// ListCategoryIDs retrieves the ids of the category records in the database among the given ones, soft deleted ones excluded
+func (cr *CategoryRepository) ListCategoryIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var existing []uuid.UUID
+
+	query := cr.db.QueryBuilder.Select("id").
+		From("categories").
+		Where(sq.Eq{"id": ids, "deleted_at": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := cr.db.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		existing = append(existing, id)
+	}
+
+	return existing, rows.Err()
+}
+
+// ListCategories retrieves a list of categories from the database in the order of the filter.
+// With a cursor in the filter, the categories after it are listed
+func (cr *CategoryRepository) ListCategories(ctx context.Context, filter domain.CategoryFilter, skip, limit uint64) ([]domain.Category, error) {
 	var category domain.Category
 	var categories []domain.Category
 
-	query := cr.db.QueryBuilder.Select("*").
+	keys := filter.Sort.Keys()
+	query := cr.db.QueryBuilder.Select(categoryColumns...).
 		From("categories").
-		OrderBy("id").
+		OrderBy(sortOrderBy(keys, categorySortColumns)...).
 		Limit(limit).
 		Offset((skip) * limit)
 
+	if !filter.IncludeDeleted {
+		query = query.Where(sq.Eq{"deleted_at": nil})
+	}
+
+	if filter.After != nil {
+		after, err := sortAfter(keys, categorySortColumns, filter.After)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(after)
+	}
+
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := cr.db.Query(ctx, sql, args...)
+	rows, err := cr.db.Replica().Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
-		err := rows.Scan(
-			&category.ID,
-			&category.Name,
-		)
+		err := scanCategory(rows, &category)
 		if err != nil {
 			return nil, err
 		}
@@ -119,42 +196,86 @@ func (cr *CategoryRepository) ListCategories(ctx context.Context, skip, limit ui
 func (cr *CategoryRepository) UpdateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
 	query := cr.db.QueryBuilder.Update("categories").
 		Set("name", category.Name).
-		Where(sq.Eq{"id": category.ID}).
-		Suffix("RETURNING *")
+		Where(sq.Eq{"id": category.ID, "deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(categoryColumns, ", "))
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	err = cr.db.QueryRow(ctx, sql, args...).Scan(
-		&category.ID,
-		&category.Name,
-	)
+	err = scanCategory(cr.db.Conn(ctx).QueryRow(ctx, sql, args...), category)
 	if err != nil {
-		if errCode := cr.db.ErrorCode(err); errCode == "23505" {
-			return nil, domain.ErrConflictingData
-		}
-		return nil, err
+		return nil, cr.db.TranslateError(err)
 	}
 
 	return category, nil
 }
 
-// DeleteCategory deletes a category record from the database by id
+// DeleteCategory soft deletes a category record in the database by id.
+// A category still used by a product that is not deleted is kept and domain.ErrForeignKeyViolation is returned
 func (cr *CategoryRepository) DeleteCategory(ctx context.Context, id uuid.UUID) error {
-	query := cr.db.QueryBuilder.Delete("categories").
-		Where(sq.Eq{"id": id})
+	query := cr.db.QueryBuilder.Update("categories").
+		Set("deleted_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		Where("NOT EXISTS (SELECT 1 FROM products WHERE category_id = ? AND deleted_at IS NULL)", id)
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return err
 	}
 
-	_, err = cr.db.Exec(ctx, sql, args...)
+	tag, err := cr.db.Conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return err
+		return cr.db.TranslateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrForeignKeyViolation
 	}
 
 	return nil
 }
+
+// RestoreCategory clears the deletion mark of a soft deleted category record in the database by id
+func (cr *CategoryRepository) RestoreCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	var category domain.Category
+
+	query := cr.db.QueryBuilder.Update("categories").
+		Set("deleted_at", nil).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(categoryColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanCategory(cr.db.Conn(ctx).QueryRow(ctx, sql, args...), &category)
+	if err != nil {
+		return nil, cr.db.TranslateError(err)
+	}
+
+	return &category, nil
+}
+
+// PurgeCategories permanently deletes the category records soft deleted before the given time
+// that are no longer referenced by any product, including soft deleted ones
+func (cr *CategoryRepository) PurgeCategories(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	query := cr.db.QueryBuilder.Delete("categories").
+		Where(sq.Lt{"deleted_at": deletedBefore}).
+		Where("NOT EXISTS (SELECT 1 FROM products WHERE products.category_id = categories.id)")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return 0, err
+	}
+
+	tag, err := cr.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return 0, cr.db.TranslateError(err)
+	}
+
+	return tag.RowsAffected(), nil
+}
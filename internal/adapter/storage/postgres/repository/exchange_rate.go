@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+/**
+ * ExchangeRateRepository implements port.ExchangeRateRepository interface
+ * and provides an access to the postgres database
+ */
+type ExchangeRateRepository struct {
+	db *postgres.DB
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository instance
+func NewExchangeRateRepository(db *postgres.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{
+		db,
+	}
+}
+
+// UpsertExchangeRate inserts the exchange rate record of a currency or replaces the existing one
+func (er *ExchangeRateRepository) UpsertExchangeRate(ctx context.Context, rate *domain.ExchangeRate) (*domain.ExchangeRate, error) {
+	query := er.db.QueryBuilder.Insert("exchange_rates").
+		Columns("currency", "rate", "updated_at").
+		Values(rate.Currency, rate.Rate, sq.Expr("now()")).
+		Suffix("ON CONFLICT (currency) DO UPDATE SET rate = EXCLUDED.rate, updated_at = EXCLUDED.updated_at RETURNING currency, rate, updated_at")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = er.db.Conn(ctx).QueryRow(ctx, sql, args...).Scan(&rate.Currency, &rate.Rate, &rate.UpdatedAt)
+	if err != nil {
+		return nil, er.db.TranslateError(err)
+	}
+
+	return rate, nil
+}
+
+// ListExchangeRates retrieves all exchange rate records from the database ordered by currency
+func (er *ExchangeRateRepository) ListExchangeRates(ctx context.Context) ([]domain.ExchangeRate, error) {
+	var rates []domain.ExchangeRate
+
+	query := er.db.QueryBuilder.Select("currency", "rate", "updated_at").
+		From("exchange_rates").
+		OrderBy("currency")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := er.db.Replica().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var rate domain.ExchangeRate
+		if err := rows.Scan(&rate.Currency, &rate.Rate, &rate.UpdatedAt); err != nil {
+			return nil, err
+		}
+
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+// DeleteExchangeRate deletes the exchange rate record of a currency
+func (er *ExchangeRateRepository) DeleteExchangeRate(ctx context.Context, currency string) error {
+	query := er.db.QueryBuilder.Delete("exchange_rates").
+		Where(sq.Eq{"currency": currency})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := er.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return er.db.TranslateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
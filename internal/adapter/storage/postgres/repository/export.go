@@ -0,0 +1,359 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// exportJobColumns is the list of export job columns in the order scanExportJob reads them
+var exportJobColumns = []string{
+	"id",
+	"format",
+	"filter",
+	"options",
+	"state",
+	"row_count",
+	"size",
+	"error",
+	"attempts",
+	"created_at",
+	"started_at",
+	"heartbeat_at",
+	"finished_at",
+	"expires_at",
+}
+
+// productFilter is the JSON representation of a domain.ProductFilter in the filter column
+type productFilter struct {
+	Search         string              `json:"search,omitempty"`
+	CategoryIDs    []uuid.UUID         `json:"category_ids,omitempty"`
+	WarehouseIDs   []uuid.UUID         `json:"warehouse_ids,omitempty"`
+	IncludeDeleted bool                `json:"include_deleted,omitempty"`
+	Near           *domain.Coordinates `json:"near,omitempty"`
+	NearIP         string              `json:"near_ip,omitempty"`
+	RadiusKM       float64             `json:"radius_km,omitempty"`
+	Sort           domain.ProductSort  `json:"sort,omitempty"`
+	// After is not stored, an export streams the whole list
+	After *domain.Cursor `json:"-"`
+}
+
+// scanExportJob scans a row selected with exportJobColumns into an export job
+func scanExportJob(row pgx.Row, job *domain.ExportJob) error {
+	var filterJSON, optionsJSON []byte
+
+	err := row.Scan(
+		&job.ID,
+		&job.Format,
+		&filterJSON,
+		&optionsJSON,
+		&job.State,
+		&job.Rows,
+		&job.Size,
+		&job.Error,
+		&job.Attempts,
+		&job.CreatedAt,
+		&job.StartedAt,
+		&job.HeartbeatAt,
+		&job.FinishedAt,
+		&job.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	var filter productFilter
+	if err := json.Unmarshal(filterJSON, &filter); err != nil {
+		return err
+	}
+	job.Filter = domain.ProductFilter(filter)
+
+	job.Options = domain.ExportOptions{}
+	return json.Unmarshal(optionsJSON, &job.Options)
+}
+
+/**
+ * ExportRepository implements port.ExportRepository interface
+ * and provides an access to the postgres database
+ */
+type ExportRepository struct {
+	db *postgres.DB
+}
+
+// NewExportRepository creates a new export repository instance
+func NewExportRepository(db *postgres.DB) *ExportRepository {
+	return &ExportRepository{
+		db,
+	}
+}
+
+// CreateExportJob creates a new export job record in the database
+func (er *ExportRepository) CreateExportJob(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	filterJSON, err := json.Marshal(productFilter(job.Filter))
+	if err != nil {
+		return nil, err
+	}
+
+	optionsJSON, err := json.Marshal(job.Options)
+	if err != nil {
+		return nil, err
+	}
+
+	query := er.db.QueryBuilder.Insert("export_jobs").
+		Columns("id", "format", "filter", "options", "state", "created_at", "expires_at").
+		Values(
+			job.ID,
+			job.Format,
+			filterJSON,
+			optionsJSON,
+			job.State,
+			job.CreatedAt,
+			job.ExpiresAt,
+		).
+		Suffix("RETURNING " + strings.Join(exportJobColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanExportJob(er.db.Conn(ctx).QueryRow(ctx, sql, args...), job)
+	if err != nil {
+		return nil, er.db.TranslateError(err)
+	}
+
+	return job, nil
+}
+
+// GetExportJobByID retrieves an export job record from the database by id
+func (er *ExportRepository) GetExportJobByID(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error) {
+	var job domain.ExportJob
+
+	query := er.db.QueryBuilder.Select(exportJobColumns...).
+		From("export_jobs").
+		Where(sq.Eq{"id": id}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanExportJob(er.db.Conn(ctx).QueryRow(ctx, sql, args...), &job)
+	if err != nil {
+		return nil, er.db.TranslateError(err)
+	}
+
+	return &job, nil
+}
+
+// ClaimExportJob marks the oldest pending export job record that has not expired, or a running one whose heartbeat
+// is older than staleBefore, as running and counts its attempt. Records locked by another worker are skipped
+func (er *ExportRepository) ClaimExportJob(ctx context.Context, staleBefore time.Time, maxAttempts int) (*domain.ExportJob, error) {
+	var job domain.ExportJob
+
+	next := sq.Select("id").
+		From("export_jobs").
+		Where(sq.Or{
+			sq.Eq{"state": domain.ExportPending},
+			sq.And{sq.Eq{"state": domain.ExportRunning}, sq.Lt{"heartbeat_at": staleBefore}},
+		}).
+		Where(sq.Lt{"attempts": maxAttempts}).
+		Where("expires_at > now()").
+		OrderBy("created_at").
+		Limit(1).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	query := er.db.QueryBuilder.Update("export_jobs").
+		Set("state", domain.ExportRunning).
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("row_count", 0).
+		Set("started_at", sq.Expr("now()")).
+		Set("heartbeat_at", sq.Expr("now()")).
+		Where(sq.Expr("id = (?)", next)).
+		Suffix("RETURNING " + strings.Join(exportJobColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanExportJob(er.db.Conn(ctx).QueryRow(ctx, sql, args...), &job)
+	if err != nil {
+		return nil, er.db.TranslateError(err)
+	}
+
+	return &job, nil
+}
+
+// UpdateExportProgress updates the row count and the heartbeat of an attempt of a running export job record
+func (er *ExportRepository) UpdateExportProgress(ctx context.Context, id uuid.UUID, attempt int, rows int64) error {
+	query := er.db.QueryBuilder.Update("export_jobs").
+		Set("row_count", rows).
+		Set("heartbeat_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id, "state": domain.ExportRunning, "attempts": attempt})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := er.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return er.db.TranslateError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// FinishExportJob updates the state, the result and the expiry of an attempt of a running export job record
+func (er *ExportRepository) FinishExportJob(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	query := er.db.QueryBuilder.Update("export_jobs").
+		Set("state", job.State).
+		Set("row_count", job.Rows).
+		Set("size", job.Size).
+		Set("error", job.Error).
+		Set("finished_at", job.FinishedAt).
+		Set("expires_at", job.ExpiresAt).
+		Where(sq.Eq{"id": job.ID, "state": domain.ExportRunning, "attempts": job.Attempts}).
+		Suffix("RETURNING " + strings.Join(exportJobColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanExportJob(er.db.Conn(ctx).QueryRow(ctx, sql, args...), job)
+	if err != nil {
+		return nil, er.db.TranslateError(err)
+	}
+
+	return job, nil
+}
+
+// CancelExportJob marks a pending or running export job record as canceled
+func (er *ExportRepository) CancelExportJob(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error) {
+	var job domain.ExportJob
+
+	query := er.db.QueryBuilder.Update("export_jobs").
+		Set("state", domain.ExportCanceled).
+		Set("finished_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id, "state": []domain.ExportState{domain.ExportPending, domain.ExportRunning}}).
+		Suffix("RETURNING " + strings.Join(exportJobColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanExportJob(er.db.Conn(ctx).QueryRow(ctx, sql, args...), &job)
+	if err != nil {
+		return nil, er.db.TranslateError(err)
+	}
+
+	return &job, nil
+}
+
+// ListExpiredExportJobs retrieves the export job records that expired at the given time and are not marked
+// as expired, except the running ones whose heartbeat is not older than staleBefore
+func (er *ExportRepository) ListExpiredExportJobs(ctx context.Context, at, staleBefore time.Time, limit uint64) ([]domain.ExportJob, error) {
+	var job domain.ExportJob
+	var jobs []domain.ExportJob
+
+	query := er.db.QueryBuilder.Select(exportJobColumns...).
+		From("export_jobs").
+		Where(sq.NotEq{"state": domain.ExportExpired}).
+		Where(sq.LtOrEq{"expires_at": at}).
+		Where(sq.Or{sq.NotEq{"state": domain.ExportRunning}, sq.Lt{"heartbeat_at": staleBefore}}).
+		OrderBy("expires_at").
+		Limit(limit)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := er.db.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, er.db.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := scanExportJob(rows, &job)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// ExpireExportJob marks an export job record as expired
+func (er *ExportRepository) ExpireExportJob(ctx context.Context, id uuid.UUID) error {
+	query := er.db.QueryBuilder.Update("export_jobs").
+		Set("state", domain.ExportExpired).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = er.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return er.db.TranslateError(err)
+	}
+
+	return nil
+}
+
+// FailAbandonedExportJobs marks the running export job records whose heartbeat is older than staleBefore
+// and that ran maxAttempts times as failed with the error message, and returns them
+func (er *ExportRepository) FailAbandonedExportJobs(ctx context.Context, staleBefore time.Time, maxAttempts int, message string) ([]domain.ExportJob, error) {
+	var job domain.ExportJob
+	var jobs []domain.ExportJob
+
+	query := er.db.QueryBuilder.Update("export_jobs").
+		Set("state", domain.ExportFailed).
+		Set("error", message).
+		Set("finished_at", sq.Expr("now()")).
+		Where(sq.Eq{"state": domain.ExportRunning}).
+		Where(sq.Lt{"heartbeat_at": staleBefore}).
+		Where(sq.GtOrEq{"attempts": maxAttempts}).
+		Suffix("RETURNING " + strings.Join(exportJobColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := er.db.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, er.db.TranslateError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := scanExportJob(rows, &job)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
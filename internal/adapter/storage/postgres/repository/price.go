@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// priceChangeColumns is the list of price change columns in the order scanPriceChange reads them
+var priceChangeColumns = []string{
+	"id",
+	"product_id",
+	"price",
+	"currency",
+	"starts_at",
+	"ends_at",
+	"applied_at",
+	"canceled_at",
+	"attempts",
+	"retry_at",
+	"created_at",
+}
+
+// scanPriceChange scans a row selected with priceChangeColumns into a price change
+func scanPriceChange(row pgx.Row, change *domain.PriceChange) error {
+	return row.Scan(
+		&change.ID,
+		&change.ProductID,
+		&change.Price,
+		&change.Price.Currency,
+		&change.StartsAt,
+		&change.EndsAt,
+		&change.AppliedAt,
+		&change.CanceledAt,
+		&change.Attempts,
+		&change.RetryAt,
+		&change.CreatedAt,
+	)
+}
+
+/**
+ * PriceRepository implements port.PriceRepository interface
+ * and provides an access to the postgres database
+ */
+type PriceRepository struct {
+	db *postgres.DB
+}
+
+// NewPriceRepository creates a new price repository instance
+func NewPriceRepository(db *postgres.DB) *PriceRepository {
+	return &PriceRepository{
+		db,
+	}
+}
+
+// CreatePriceChange creates a new price change record in the database
+func (pr *PriceRepository) CreatePriceChange(ctx context.Context, change *domain.PriceChange) (*domain.PriceChange, error) {
+	query := pr.db.QueryBuilder.Insert("product_prices").
+		Columns("id", "product_id", "price", "currency", "starts_at", "ends_at", "applied_at").
+		Values(
+			change.ID,
+			change.ProductID,
+			change.Price,
+			change.Price.Currency,
+			change.StartsAt,
+			change.EndsAt,
+			change.AppliedAt,
+		).
+		Suffix("RETURNING " + strings.Join(priceChangeColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanPriceChange(pr.db.Conn(ctx).QueryRow(ctx, sql, args...), change)
+	if err != nil {
+		return nil, pr.db.TranslateError(err)
+	}
+
+	return change, nil
+}
+
+// CreatePriceChanges creates price change records in the database at once with a copy
+func (pr *PriceRepository) CreatePriceChanges(ctx context.Context, changes []domain.PriceChange) error {
+	rows := make([][]any, len(changes))
+	for i, change := range changes {
+		rows[i] = []any{
+			change.ID,
+			change.ProductID,
+			change.Price,
+			change.Price.Currency,
+			change.StartsAt,
+			change.EndsAt,
+			change.AppliedAt,
+		}
+	}
+
+	_, err := pr.db.Conn(ctx).CopyFrom(ctx,
+		pgx.Identifier{"product_prices"},
+		[]string{"id", "product_id", "price", "currency", "starts_at", "ends_at", "applied_at"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return pr.db.TranslateError(err)
+	}
+
+	return nil
+}
+
+// GetPriceChangeByID retrieves a price change record of a product from the database by id
+func (pr *PriceRepository) GetPriceChangeByID(ctx context.Context, productID, id uuid.UUID) (*domain.PriceChange, error) {
+	var change domain.PriceChange
+
+	query := pr.db.QueryBuilder.Select(priceChangeColumns...).
+		From("product_prices").
+		Where(sq.Eq{"id": id, "product_id": productID}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanPriceChange(pr.db.Conn(ctx).QueryRow(ctx, sql, args...), &change)
+	if err != nil {
+		return nil, pr.db.TranslateError(err)
+	}
+
+	return &change, nil
+}
+
+// ListPriceChanges retrieves the price timeline of a product from the database, latest start first
+func (pr *PriceRepository) ListPriceChanges(ctx context.Context, productID uuid.UUID, skip, limit uint64) ([]domain.PriceChange, error) {
+	var change domain.PriceChange
+	var changes []domain.PriceChange
+
+	query := pr.db.QueryBuilder.Select(priceChangeColumns...).
+		From("product_prices").
+		Where(sq.Eq{"product_id": productID}).
+		OrderBy("starts_at DESC", "created_at DESC").
+		Limit(limit).
+		Offset(skip * limit)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pr.db.Replica().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := scanPriceChange(rows, &change)
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// CancelPriceChange marks a price change record that is neither applied nor canceled as canceled
+func (pr *PriceRepository) CancelPriceChange(ctx context.Context, id uuid.UUID) (*domain.PriceChange, error) {
+	var change domain.PriceChange
+
+	query := pr.db.QueryBuilder.Update("product_prices").
+		Set("canceled_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id, "applied_at": nil, "canceled_at": nil}).
+		Suffix("RETURNING " + strings.Join(priceChangeColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanPriceChange(pr.db.Conn(ctx).QueryRow(ctx, sql, args...), &change)
+	if err != nil {
+		return nil, pr.db.TranslateError(err)
+	}
+
+	return &change, nil
+}
+
+// GetEffectivePrices retrieves, for each product, the latest started price change that is running at the given time.
+// Applied price changes are skipped since their price already is the base price of the product
+func (pr *PriceRepository) GetEffectivePrices(ctx context.Context, productIDs []uuid.UUID, at time.Time) (map[uuid.UUID]domain.Money, error) {
+	prices := make(map[uuid.UUID]domain.Money, len(productIDs))
+	if len(productIDs) == 0 {
+		return prices, nil
+	}
+
+	query := pr.db.QueryBuilder.Select("DISTINCT ON (product_id) product_id", "price", "currency").
+		From("product_prices").
+		Where(sq.Eq{"product_id": productIDs, "applied_at": nil, "canceled_at": nil}).
+		Where(sq.LtOrEq{"starts_at": at}).
+		Where(sq.Or{sq.Eq{"ends_at": nil}, sq.Gt{"ends_at": at}}).
+		OrderBy("product_id", "starts_at DESC", "created_at DESC")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pr.db.Replica().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var productID uuid.UUID
+		var price domain.Money
+		if err := rows.Scan(&productID, &price, &price.Currency); err != nil {
+			return nil, err
+		}
+
+		prices[productID] = price
+	}
+
+	return prices, nil
+}
+
+// ClaimDuePriceChange retrieves and locks until the end of the transaction the oldest price change record without an
+// end that started at the given time, is neither applied nor canceled and is not delayed. Records locked by another
+// worker are skipped
+func (pr *PriceRepository) ClaimDuePriceChange(ctx context.Context, at time.Time) (*domain.PriceChange, error) {
+	var change domain.PriceChange
+
+	query := pr.db.QueryBuilder.Select(priceChangeColumns...).
+		From("product_prices").
+		Where(sq.Eq{"ends_at": nil, "applied_at": nil, "canceled_at": nil}).
+		Where(sq.LtOrEq{"starts_at": at}).
+		Where(sq.Or{sq.Eq{"retry_at": nil}, sq.LtOrEq{"retry_at": at}}).
+		OrderBy("starts_at", "created_at").
+		Limit(1).
+		Suffix("FOR UPDATE SKIP LOCKED")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanPriceChange(pr.db.Conn(ctx).QueryRow(ctx, sql, args...), &change)
+	if err != nil {
+		return nil, pr.db.TranslateError(err)
+	}
+
+	return &change, nil
+}
+
+// MarkPriceChangeApplied sets the time a price change record replaced the base price of its product,
+// a record that already is applied is left as is and domain.ErrPriceChangeApplied is returned
+func (pr *PriceRepository) MarkPriceChangeApplied(ctx context.Context, id uuid.UUID, appliedAt time.Time) error {
+	query := pr.db.QueryBuilder.Update("product_prices").
+		Set("applied_at", appliedAt).
+		Where(sq.Eq{"id": id, "applied_at": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := pr.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return pr.db.TranslateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrPriceChangeApplied
+	}
+
+	return nil
+}
+
+// DelayPriceChange counts a failed attempt to apply a price change record and delays it until retryAt
+func (pr *PriceRepository) DelayPriceChange(ctx context.Context, id uuid.UUID, retryAt time.Time) error {
+	query := pr.db.QueryBuilder.Update("product_prices").
+		Set("attempts", sq.Expr("attempts + 1")).
+		Set("retry_at", retryAt).
+		Where(sq.Eq{"id": id})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = pr.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return pr.db.TranslateError(err)
+	}
+
+	return nil
+}
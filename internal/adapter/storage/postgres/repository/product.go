@@ -2,6 +2,10 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/google/uuid"
@@ -10,6 +14,116 @@ import (
 	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
 )
 
+// productColumns is the list of product columns in the order scanProduct reads them
+var productColumns = []string{
+	"id",
+	"reference",
+	"name",
+	"added_date",
+	"status",
+	"category_id",
+	"price",
+	"currency",
+	"stock_city",
+	"stock_latitude",
+	"stock_longitude",
+	"supplier_id",
+	"quantity",
+	"deleted_at",
+}
+
+// productColumnFields maps each product column to the field it is read for
+var productColumnFields = map[string]domain.ProductField{
+	"id":              domain.ProductFieldID,
+	"reference":       domain.ProductFieldReference,
+	"name":            domain.ProductFieldName,
+	"added_date":      domain.ProductFieldAddedDate,
+	"status":          domain.ProductFieldStatus,
+	"category_id":     domain.ProductFieldCategoryID,
+	"price":           domain.ProductFieldPrice,
+	"currency":        domain.ProductFieldPrice,
+	"stock_city":      domain.ProductFieldStockCity,
+	"stock_latitude":  domain.ProductFieldStockCity,
+	"stock_longitude": domain.ProductFieldStockCity,
+	"supplier_id":     domain.ProductFieldSupplierID,
+	"quantity":        domain.ProductFieldQuantity,
+	"deleted_at":      domain.ProductFieldDeletedAt,
+}
+
+// viewProductColumns returns the product columns read for the view, in the order of productColumns
+func viewProductColumns(view *domain.ProductView) []string {
+	if view == nil {
+		return productColumns
+	}
+
+	var columns []string
+	for _, column := range productColumns {
+		if view.Reads(productColumnFields[column]) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// scanProduct scans a row selected with productColumns, followed by the extra columns, into a product
+func scanProduct(row pgx.Row, product *domain.Product, extra ...any) error {
+	return scanProductColumns(row, product, productColumns, extra...)
+}
+
+// scanProductColumns scans a row selected with some of the product columns, in the order of productColumns,
+// followed by the extra columns, into a product. The fields of the columns not selected are left as they are
+func scanProductColumns(row pgx.Row, product *domain.Product, columns []string, extra ...any) error {
+	var latitude, longitude *float64
+	dest := make([]any, 0, len(columns)+len(extra))
+	for _, column := range columns {
+		switch column {
+		case "id":
+			dest = append(dest, &product.ID)
+		case "reference":
+			dest = append(dest, &product.Reference)
+		case "name":
+			dest = append(dest, &product.Name)
+		case "added_date":
+			dest = append(dest, &product.AddedDate)
+		case "status":
+			dest = append(dest, &product.Status)
+		case "category_id":
+			dest = append(dest, &product.CategoryID)
+		case "price":
+			dest = append(dest, &product.Price)
+		case "currency":
+			dest = append(dest, &product.Price.Currency)
+		case "stock_city":
+			dest = append(dest, &product.StockCity)
+		case "stock_latitude":
+			dest = append(dest, &latitude)
+		case "stock_longitude":
+			dest = append(dest, &longitude)
+		case "supplier_id":
+			dest = append(dest, &product.SupplierID)
+		case "quantity":
+			dest = append(dest, &product.Quantity)
+		case "deleted_at":
+			dest = append(dest, &product.DeletedAt)
+		default:
+			return fmt.Errorf("unknown product column %q", column)
+		}
+	}
+	dest = append(dest, extra...)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return err
+	}
+
+	product.StockCoordinates = nil
+	if latitude != nil && longitude != nil {
+		product.StockCoordinates = &domain.Coordinates{Latitude: *latitude, Longitude: *longitude}
+	}
+
+	return nil
+}
+
 /**
  * ProductRepository implements port.ProductRepository interface
  * and provides an access to the postgres database
@@ -27,8 +141,25 @@ func NewProductRepository(db *postgres.DB) *ProductRepository {
 
 // CreateProduct creates a new product record in the database
 func (pr *ProductRepository) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	query := pr.db.QueryBuilder.Insert("products").
-		Columns("id", "reference", "name", "added_date", "status", "category_id", "price", "stock_city", "supplier_id", "quantity").
+	sql, args, err := pr.createProductQuery(product).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanProduct(pr.db.Conn(ctx).QueryRow(ctx, sql, args...), product)
+	if err != nil {
+		return nil, pr.db.TranslateError(err)
+	}
+
+	return product, nil
+}
+
+// createProductQuery builds the query inserting a product and returning it
+func (pr *ProductRepository) createProductQuery(product *domain.Product) sq.InsertBuilder {
+	latitude, longitude := coordinateValues(product.StockCoordinates)
+
+	return pr.db.QueryBuilder.Insert("products").
+		Columns("id", "reference", "name", "added_date", "status", "category_id", "price", "currency", "stock_city", "stock_latitude", "stock_longitude", "supplier_id", "quantity").
 		Values(
 			product.ID,
 			product.Reference,
@@ -37,117 +168,266 @@ func (pr *ProductRepository) CreateProduct(ctx context.Context, product *domain.
 			product.Status,
 			product.CategoryID,
 			product.Price,
+			product.Price.Currency,
 			product.StockCity,
+			latitude,
+			longitude,
 			product.SupplierID,
 			product.Quantity,
 		).
-		Suffix("RETURNING *")
+		Suffix("RETURNING " + strings.Join(productColumns, ", "))
+}
 
-	sql, args, err := query.ToSql()
-	if err != nil {
-		return nil, err
+// CreateProducts creates product records in the database at once with a copy, returning how many were created
+func (pr *ProductRepository) CreateProducts(ctx context.Context, products []domain.Product) (int64, error) {
+	rows := make([][]any, len(products))
+	for i, product := range products {
+		latitude, longitude := coordinateValues(product.StockCoordinates)
+		rows[i] = []any{
+			product.ID,
+			product.Reference,
+			product.Name,
+			product.AddedDate,
+			product.Status,
+			product.CategoryID,
+			product.Price,
+			product.Price.Currency,
+			product.StockCity,
+			latitude,
+			longitude,
+			product.SupplierID,
+			product.Quantity,
+		}
 	}
 
-	err = pr.db.QueryRow(ctx, sql, args...).Scan(
-		&product.ID,
-		&product.Reference,
-		&product.Name,
-		&product.AddedDate,
-		&product.Status,
-		&product.CategoryID,
-		&product.Price,
-		&product.StockCity,
-		&product.SupplierID,
-		&product.Quantity,
+	created, err := pr.db.Conn(ctx).CopyFrom(ctx,
+		pgx.Identifier{"products"},
+		[]string{"id", "reference", "name", "added_date", "status", "category_id", "price", "currency", "stock_city", "stock_latitude", "stock_longitude", "supplier_id", "quantity"},
+		pgx.CopyFromRows(rows),
 	)
 	if err != nil {
-		if errCode := pr.db.ErrorCode(err); errCode == "23505" {
-			return nil, domain.ErrConflictingData
-		}
-		return nil, err
+		return 0, pr.db.TranslateError(err)
 	}
 
-	return product, nil
+	return created, nil
 }
 
 // GetProductByID retrieves a product record from the database by id
-func (pr *ProductRepository) GetProductByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+func (pr *ProductRepository) GetProductByID(ctx context.Context, id uuid.UUID, includeDeleted bool) (*domain.Product, error) {
 	var product domain.Product
 
-	query := pr.db.QueryBuilder.Select("*").
+	query := pr.db.QueryBuilder.Select(productColumns...).
 		From("products").
 		Where(sq.Eq{"id": id}).
 		Limit(1)
+	if !includeDeleted {
+		query = query.Where(sq.Eq{"deleted_at": nil})
+	}
 
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	err = pr.db.QueryRow(ctx, sql, args...).Scan(
-		&product.ID,
-		&product.Reference,
-		&product.Name,
-		&product.AddedDate,
-		&product.Status,
-		&product.CategoryID,
-		&product.Price,
-		&product.StockCity,
-		&product.SupplierID,
-		&product.Quantity,
-	)
+	err = scanProduct(pr.db.Conn(ctx).QueryRow(ctx, sql, args...), &product)
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, domain.ErrDataNotFound
-		}
-		return nil, err
+		return nil, pr.db.TranslateError(err)
 	}
 
 	return &product, nil
 }
 
-// ListProducts retrieves a list of products from the database
-func (pr *ProductRepository) ListProducts(ctx context.Context, search string, categoryIds []uuid.UUID, skip, limit uint64) ([]domain.Product, error) {
+// GetProductForUpdate retrieves a product record from the database by id and locks it until the end of the transaction
+func (pr *ProductRepository) GetProductForUpdate(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
 	var product domain.Product
+
+	query := pr.db.QueryBuilder.Select(productColumns...).
+		From("products").
+		Where(sq.Eq{"id": id, "deleted_at": nil}).
+		Suffix("FOR UPDATE")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanProduct(pr.db.Conn(ctx).QueryRow(ctx, sql, args...), &product)
+	if err != nil {
+		return nil, pr.db.TranslateError(err)
+	}
+
+	return &product, nil
+}
+
+// GetProductsForUpdate retrieves the product records from the database by id and locks them until the end
+// of the transaction, in the order of their ids so that concurrent transactions lock them in the same order
+func (pr *ProductRepository) GetProductsForUpdate(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
 	var products []domain.Product
 
-	query := pr.db.QueryBuilder.Select("*").
+	query := pr.db.QueryBuilder.Select(productColumns...).
 		From("products").
+		Where(sq.Eq{"id": ids, "deleted_at": nil}).
 		OrderBy("id").
-		Limit(limit).
-		Offset((skip) * limit)
+		Suffix("FOR UPDATE")
 
-	if len(categoryIds) != 0 {
-		query = query.Where(sq.Eq{"category_id": categoryIds})
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pr.db.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product domain.Product
+		err := scanProduct(rows, &product)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+// GetProductsByIDs retrieves the product records from the database with one of the ids in a single query,
+// each with its category joined. The products are not in the order of the ids
+func (pr *ProductRepository) GetProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
+	var products []domain.Product
+
+	columns := make([]string, len(productColumns), len(productColumns)+len(categoryColumns))
+	for i, column := range productColumns {
+		columns[i] = "products." + column
 	}
+	for _, column := range categoryColumns {
+		columns = append(columns, "categories."+column)
+	}
+
+	query := pr.db.QueryBuilder.Select(columns...).
+		From("products").
+		LeftJoin("categories ON categories.id = products.category_id AND categories.deleted_at IS NULL").
+		Where(sq.Expr("products.id = ANY(?)", ids)).
+		Where(sq.Eq{"products.deleted_at": nil})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pr.db.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product domain.Product
+		var categoryID *uuid.UUID
+		var categoryName *string
+		var categoryCreatedAt, categoryDeletedAt *time.Time
+
+		err := scanProduct(rows, &product, &categoryID, &categoryName, &categoryCreatedAt, &categoryDeletedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if categoryID != nil {
+			product.Category = &domain.Category{
+				ID:        *categoryID,
+				Name:      *categoryName,
+				CreatedAt: *categoryCreatedAt,
+				DeletedAt: categoryDeletedAt,
+			}
+		}
 
-	if search != "" {
-		query = query.Where(sq.ILike{"name": "%" + search + "%"})
+		products = append(products, product)
 	}
 
+	return products, rows.Err()
+}
+
+// GetProductsByReferences retrieves the product records from the database with one of the references,
+// soft deleted ones included since their references stay taken
+func (pr *ProductRepository) GetProductsByReferences(ctx context.Context, references []string) ([]domain.Product, error) {
+	var products []domain.Product
+
+	query := pr.db.QueryBuilder.Select(productColumns...).
+		From("products").
+		Where(sq.Eq{"reference": references})
+
 	sql, args, err := query.ToSql()
 	if err != nil {
 		return nil, err
 	}
 
-	rows, err := pr.db.Query(ctx, sql, args...)
+	rows, err := pr.db.Conn(ctx).Query(ctx, sql, args...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
-		err := rows.Scan(
-			&product.ID,
-			&product.Reference,
-			&product.Name,
-			&product.AddedDate,
-			&product.Status,
-			&product.CategoryID,
-			&product.Price,
-			&product.StockCity,
-			&product.SupplierID,
-			&product.Quantity,
-		)
+		var product domain.Product
+		err := scanProduct(rows, &product)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+// sortedProductColumns returns the product columns read for the view and for the sort, the values
+// of the sort fields of the last product of a page making the cursor of the next one
+func sortedProductColumns(view *domain.ProductView, sort domain.ProductSort) []string {
+	if view == nil || len(view.Fields) == 0 {
+		return viewProductColumns(view)
+	}
+
+	sorted := domain.ProductView{Fields: slices.Clone(view.Fields), Include: view.Include}
+	for _, key := range sort.Keys() {
+		// The distance is not a product column, it is read near an origin
+		if field := domain.ProductField(key.Field); slices.Contains(domain.ProductFields, field) {
+			sorted.Fields = append(sorted.Fields, field)
+		}
+	}
+	return viewProductColumns(&sorted)
+}
+
+// ListProducts retrieves a list of products from the database, reading the columns of the fields of the view
+// and of the sort. With a cursor in the filter, the products after it are listed
+func (pr *ProductRepository) ListProducts(ctx context.Context, filter domain.ProductFilter, view *domain.ProductView, skip, limit uint64) ([]domain.Product, error) {
+	var product domain.Product
+	var products []domain.Product
+
+	columns := sortedProductColumns(view, filter.Sort)
+	query, err := pr.listProductsQuery(filter, columns)
+	if err != nil {
+		return nil, err
+	}
+	query = query.
+		Limit(limit).
+		Offset((skip) * limit)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pr.db.Replica().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := scanListedProduct(rows, filter, columns, &product)
 		if err != nil {
 			return nil, err
 		}
@@ -158,8 +438,195 @@ func (pr *ProductRepository) ListProducts(ctx context.Context, search string, ca
 	return products, nil
 }
 
+// StreamProducts reads all the products matching the filter through a server-side cursor and passes them to fn
+// in batches of the given size, so memory does not grow with the number of products. The products are read from
+// a single snapshot, fn must not keep the batch it is passed
+func (pr *ProductRepository) StreamProducts(ctx context.Context, filter domain.ProductFilter, batchSize int, fn func(products []domain.Product) error) error {
+	query, err := pr.listProductsQuery(filter, productColumns)
+	if err != nil {
+		return err
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tx, err := pr.db.Replica().BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	_, err = tx.Exec(ctx, "DECLARE products_stream NO SCROLL CURSOR FOR "+sql, args...)
+	if err != nil {
+		return err
+	}
+
+	products := make([]domain.Product, 0, batchSize)
+	for {
+		rows, err := tx.Query(ctx, fmt.Sprintf("FETCH FORWARD %d FROM products_stream", batchSize))
+		if err != nil {
+			return err
+		}
+
+		products = products[:0]
+		for rows.Next() {
+			var product domain.Product
+			err := scanListedProduct(rows, filter, productColumns, &product)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+
+			products = append(products, product)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if len(products) == 0 {
+			break
+		}
+
+		err = fn(products)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// listProductsQuery builds the query selecting the product columns of the products matching the filter, in the order of the filter
+// and after its cursor if any. Near an origin, the distance of each product is the distance to the closest of its stock city and the
+// warehouses holding it, and the products within the radius are prefiltered with a bounding box
+func (pr *ProductRepository) listProductsQuery(filter domain.ProductFilter, columns []string) (sq.SelectBuilder, error) {
+	query := pr.db.QueryBuilder.Select(columns...).
+		From("products")
+
+	if len(filter.CategoryIDs) != 0 {
+		query = query.Where(sq.Eq{"category_id": filter.CategoryIDs})
+	}
+
+	if len(filter.WarehouseIDs) != 0 {
+		stocked := sq.Select("1").
+			From("warehouse_stocks").
+			Where("warehouse_stocks.product_id = products.id").
+			Where(sq.Eq{"warehouse_stocks.warehouse_id": filter.WarehouseIDs}).
+			Where(sq.Gt{"warehouse_stocks.quantity": 0})
+		query = query.Where(sq.Expr("EXISTS (?)", stocked))
+	}
+
+	if filter.Search != "" {
+		query = query.Where(sq.ILike{"name": "%" + filter.Search + "%"})
+	}
+
+	if !filter.IncludeDeleted {
+		query = query.Where(sq.Eq{"deleted_at": nil})
+	}
+
+	if filter.Near != nil {
+		query = query.Column(sq.Alias(productDistance(*filter.Near), "distance_km"))
+
+		if filter.RadiusKM > 0 {
+			query = query.Where(productWithinBox(filter.Near.BoundingBox(filter.RadiusKM)))
+		}
+
+		// The distance is only known once selected, so the radius and the cursor filter the selection
+		if filter.RadiusKM > 0 || filter.After != nil {
+			query = pr.db.QueryBuilder.Select("*").
+				FromSelect(query.PlaceholderFormat(sq.Question), "products")
+		}
+		if filter.RadiusKM > 0 {
+			query = query.Where(sq.LtOrEq{"distance_km": filter.RadiusKM})
+		}
+	}
+
+	keys := filter.Sort.Keys()
+	if filter.After != nil {
+		after, err := sortAfter(keys, productSortColumns, filter.After)
+		if err != nil {
+			return query, err
+		}
+		query = query.Where(after)
+	}
+
+	return query.OrderBy(sortOrderBy(keys, productSortColumns)...), nil
+}
+
+// scanListedProduct scans a row selected by listProductsQuery with the product columns into a product
+func scanListedProduct(row pgx.Row, filter domain.ProductFilter, columns []string, product *domain.Product) error {
+	if filter.Near == nil {
+		return scanProductColumns(row, product, columns)
+	}
+
+	product.DistanceKM = nil
+	return scanProductColumns(row, product, columns, &product.DistanceKM)
+}
+
+// haversineDistance returns the great-circle distance in kilometers between the origin and the coordinate columns
+func haversineDistance(origin domain.Coordinates, latitude, longitude string) sq.Sqlizer {
+	return sq.Expr(
+		"2 * 6371 * asin(least(1, sqrt(power(sin(radians("+latitude+" - ?) / 2), 2) + "+
+			"cos(radians(?)) * cos(radians("+latitude+")) * power(sin(radians("+longitude+" - ?) / 2), 2))))",
+		origin.Latitude, origin.Latitude, origin.Longitude,
+	)
+}
+
+// productDistance returns the distance from the origin to the closest of the stock city of the product
+// and the warehouses holding it, null when none of them has coordinates
+func productDistance(origin domain.Coordinates) sq.Sqlizer {
+	closestWarehouse := sq.Select().
+		Column(sq.Expr("min(?)", haversineDistance(origin, "warehouses.latitude", "warehouses.longitude"))).
+		From("warehouse_stocks").
+		Join("warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+		Where("warehouse_stocks.product_id = products.id").
+		Where(sq.Gt{"warehouse_stocks.quantity": 0}).
+		Where(sq.NotEq{"warehouses.latitude": nil})
+
+	return sq.Expr("least((?), ?)", closestWarehouse, haversineDistance(origin, "products.stock_latitude", "products.stock_longitude"))
+}
+
+// productWithinBox keeps the products whose stock city, or a warehouse holding them, is within the bounding box
+func productWithinBox(box domain.BoundingBox) sq.Sqlizer {
+	stockedWithin := sq.Select("1").
+		From("warehouse_stocks").
+		Join("warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+		Where("warehouse_stocks.product_id = products.id").
+		Where(sq.Gt{"warehouse_stocks.quantity": 0}).
+		Where(sq.Expr("warehouses.latitude BETWEEN ? AND ?", box.MinLatitude, box.MaxLatitude)).
+		Where(sq.Expr("warehouses.longitude BETWEEN ? AND ?", box.MinLongitude, box.MaxLongitude))
+
+	return sq.Or{
+		sq.And{
+			sq.Expr("products.stock_latitude BETWEEN ? AND ?", box.MinLatitude, box.MaxLatitude),
+			sq.Expr("products.stock_longitude BETWEEN ? AND ?", box.MinLongitude, box.MaxLongitude),
+		},
+		sq.Expr("EXISTS (?)", stockedWithin),
+	}
+}
+
 // UpdateProduct updates a product record in the database
 func (pr *ProductRepository) UpdateProduct(ctx context.Context, product *domain.Product, updatedFields ...string) (*domain.Product, error) {
+	sql, args, err := pr.updateProductQuery(product, updatedFields).ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanProduct(pr.db.Conn(ctx).QueryRow(ctx, sql, args...), product)
+	if err != nil {
+		return nil, pr.db.TranslateError(err)
+	}
+
+	return product, nil
+}
+
+// updateProductQuery builds the query updating the fields of a product and returning it
+func (pr *ProductRepository) updateProductQuery(product *domain.Product, updatedFields []string) sq.UpdateBuilder {
 	query := pr.db.QueryBuilder.Update("products")
 
 	for _, field := range updatedFields {
@@ -175,9 +642,14 @@ func (pr *ProductRepository) UpdateProduct(ctx context.Context, product *domain.
 		case "category_id":
 			query = query.Set(field, product.CategoryID)
 		case "price":
-			query = query.Set(field, nullFloat64(product.Price))
+			query = query.Set(field, product.Price).
+				Set("currency", product.Price.Currency)
 		case "stock_city":
 			query = query.Set(field, product.StockCity)
+		case "stock_coordinates":
+			latitude, longitude := coordinateValues(product.StockCoordinates)
+			query = query.Set("stock_latitude", latitude).
+				Set("stock_longitude", longitude)
 		case "supplier_id":
 			query = query.Set(field, product.SupplierID)
 		case "quantity":
@@ -185,64 +657,202 @@ func (pr *ProductRepository) UpdateProduct(ctx context.Context, product *domain.
 		}
 	}
 
-	query = query.Where(sq.Eq{"id": product.ID}).Suffix("RETURNING *")
+	return query.Where(sq.Eq{"id": product.ID, "deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(productColumns, ", "))
+}
 
-	sql, args, err := query.ToSql()
+// DeleteProduct soft deletes a product record in the database by id, a missing or already deleted one is not found
+func (pr *ProductRepository) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	sql, args, err := pr.deleteProductQuery(id).ToSql()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	err = pr.db.QueryRow(ctx, sql, args...).Scan(
-		&product.ID,
-		&product.Reference,
-		&product.Name,
-		&product.AddedDate,
-		&product.Status,
-		&product.CategoryID,
-		&product.Price,
-		&product.StockCity,
-		&product.SupplierID,
-		&product.Quantity,
-	)
+	tag, err := pr.db.Conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		if errCode := pr.db.ErrorCode(err); errCode == "23505" {
-			return nil, domain.ErrConflictingData
+		return pr.db.TranslateError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
+
+// deleteProductQuery builds the query soft deleting a product
+func (pr *ProductRepository) deleteProductQuery(id uuid.UUID) sq.UpdateBuilder {
+	return pr.db.QueryBuilder.Update("products").
+		Set("deleted_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": id, "deleted_at": nil})
+}
+
+// WriteProducts creates, updates and soft deletes product records in the database, pipelining the queries in a
+// single round trip. The created and updated products are set to their records. The first write that fails
+// stops the others and is returned as a domain.BatchError, a deleted product that is missing fails with domain.ErrDataNotFound
+func (pr *ProductRepository) WriteProducts(ctx context.Context, writes []domain.ProductWrite) error {
+	batch := &pgx.Batch{}
+	for _, write := range writes {
+		var query sq.Sqlizer
+		switch write.Method {
+		case domain.OperationCreate:
+			query = pr.createProductQuery(write.Product)
+		case domain.OperationUpdate:
+			query = pr.updateProductQuery(write.Product, write.Fields)
+		case domain.OperationDelete:
+			query = pr.deleteProductQuery(write.Product.ID)
+		default:
+			return fmt.Errorf("unknown product write %q", write.Method)
+		}
+
+		sql, args, err := query.ToSql()
+		if err != nil {
+			return err
+		}
+		batch.Queue(sql, args...)
+	}
+
+	results := pr.db.Conn(ctx).SendBatch(ctx, batch)
+	defer results.Close()
+
+	for i, write := range writes {
+		if write.Method == domain.OperationDelete {
+			tag, err := results.Exec()
+			if err == nil && tag.RowsAffected() == 0 {
+				err = domain.ErrDataNotFound
+			}
+			if err != nil {
+				return &domain.BatchError{Index: i, Err: pr.db.TranslateError(err)}
+			}
+			continue
+		}
+
+		err := scanProduct(results.QueryRow(), write.Product)
+		if err != nil {
+			return &domain.BatchError{Index: i, Err: pr.db.TranslateError(err)}
 		}
+	}
+
+	return results.Close()
+}
+
+// RestoreProduct clears the deletion mark of a soft deleted product record in the database by id
+func (pr *ProductRepository) RestoreProduct(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	var product domain.Product
+
+	query := pr.db.QueryBuilder.Update("products").
+		Set("deleted_at", nil).
+		Where(sq.Eq{"id": id}).
+		Where(sq.NotEq{"deleted_at": nil}).
+		Suffix("RETURNING " + strings.Join(productColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
 		return nil, err
 	}
 
-	return product, nil
+	err = scanProduct(pr.db.Conn(ctx).QueryRow(ctx, sql, args...), &product)
+	if err != nil {
+		return nil, pr.db.TranslateError(err)
+	}
+
+	return &product, nil
 }
 
-// DeleteProduct deletes a product record from the database by id
-func (pr *ProductRepository) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+// PurgeProducts permanently deletes the product records soft deleted before the given time
+func (pr *ProductRepository) PurgeProducts(ctx context.Context, deletedBefore time.Time) (int64, error) {
 	query := pr.db.QueryBuilder.Delete("products").
-		Where(sq.Eq{"id": id})
+		Where(sq.Lt{"deleted_at": deletedBefore})
 
 	sql, args, err := query.ToSql()
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	_, err = pr.db.Exec(ctx, sql, args...)
+	tag, err := pr.db.Conn(ctx).Exec(ctx, sql, args...)
 	if err != nil {
-		return err
+		return 0, pr.db.TranslateError(err)
 	}
 
-	return nil
+	return tag.RowsAffected(), nil
+}
+
+// ListSuppliers retrieves the supplier records from the database with one of the ids
+func (pr *ProductRepository) ListSuppliers(ctx context.Context, ids []uuid.UUID) ([]domain.Supplier, error) {
+	var suppliers []domain.Supplier
+
+	query := pr.db.QueryBuilder.Select("id", "name").
+		From("suppliers").
+		Where(sq.Eq{"id": ids})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pr.db.Replica().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var supplier domain.Supplier
+		err := rows.Scan(&supplier.ID, &supplier.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		suppliers = append(suppliers, supplier)
+	}
+
+	return suppliers, rows.Err()
+}
+
+// ListSupplierIDs retrieves the ids of the supplier records in the database among the given ones
+func (pr *ProductRepository) ListSupplierIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var existing []uuid.UUID
+
+	query := pr.db.QueryBuilder.Select("id").
+		From("suppliers").
+		Where(sq.Eq{"id": ids})
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := pr.db.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id uuid.UUID
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		existing = append(existing, id)
+	}
+
+	return existing, rows.Err()
 }
 
 func (pr *ProductRepository) StatisticSupplierProduct(ctx context.Context) ([]*domain.StatisticSupplierProduct, error) {
 	query := `
-		SELECT 
-			p.supplier_id, 
-			s.name, 
+		SELECT
+			p.supplier_id,
+			s.name,
 			COUNT(*) * 100.0 / SUM(COUNT(*)) OVER () AS percentage
 		FROM products AS p
 		INNER JOIN public.suppliers s ON p.supplier_id = s.id
+		WHERE p.deleted_at IS NULL
 		GROUP BY p.supplier_id, s.name;
 	`
-	rows, err := pr.db.Query(ctx, query)
+	rows, err := pr.db.Replica().Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -264,15 +874,16 @@ func (pr *ProductRepository) StatisticSupplierProduct(ctx context.Context) ([]*d
 
 func (pr *ProductRepository) StatisticCategoryProduct(ctx context.Context) ([]*domain.StatisticCategoryProduct, error) {
 	query := `
-		SELECT 
-			p.category_id, 
-			c.name, 
+		SELECT
+			p.category_id,
+			c.name,
 			COUNT(*) * 100.0 / SUM(COUNT(*)) OVER () AS percentage
 		FROM products AS p
 		INNER JOIN public.categories c ON p.category_id = c.id
+		WHERE p.deleted_at IS NULL AND c.deleted_at IS NULL
 		GROUP BY p.category_id, c.name;
 	`
-	rows, err := pr.db.Query(ctx, query)
+	rows, err := pr.db.Replica().Query(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// sortColumn is the column a sort field is read from
+type sortColumn struct {
+	name string
+	// parse parses the value of the field in a cursor into the type of the column
+	parse func(value string) (any, error)
+	// nullable columns sort the rows without a value last in either direction
+	nullable bool
+}
+
+// productSortColumns maps each product sort field to its column. The price is the base price of the product
+var productSortColumns = map[string]sortColumn{
+	"id":         {name: "id", parse: parseUUIDValue},
+	"reference":  {name: "reference", parse: parseTextValue},
+	"name":       {name: "name", parse: parseTextValue},
+	"added_date": {name: "added_date", parse: parseTimeValue},
+	"status":     {name: "status", parse: parseTextValue},
+	"price":      {name: "price", parse: parseDecimalValue},
+	"quantity":   {name: "quantity", parse: parseIntValue},
+	"stock_city": {name: "stock_city", parse: parseTextValue},
+	"distance":   {name: "distance_km", parse: parseFloatValue, nullable: true},
+}
+
+// categorySortColumns maps each category sort field to its column
+var categorySortColumns = map[string]sortColumn{
+	"id":         {name: "id", parse: parseUUIDValue},
+	"name":       {name: "name", parse: parseTextValue},
+	"created_at": {name: "created_at", parse: parseTimeValue},
+}
+
+func parseTextValue(value string) (any, error) {
+	return value, nil
+}
+
+func parseUUIDValue(value string) (any, error) {
+	return uuid.Parse(value)
+}
+
+func parseTimeValue(value string) (any, error) {
+	return time.Parse(time.RFC3339Nano, value)
+}
+
+func parseDecimalValue(value string) (any, error) {
+	return decimal.NewFromString(value)
+}
+
+func parseIntValue(value string) (any, error) {
+	return strconv.Atoi(value)
+}
+
+func parseFloatValue(value string) (any, error) {
+	return strconv.ParseFloat(value, 64)
+}
+
+// sortOrderBy returns the ORDER BY clauses of the sort keys
+func sortOrderBy(keys []domain.SortKey, columns map[string]sortColumn) []string {
+	orderBy := make([]string, len(keys))
+	for i, key := range keys {
+		column := columns[key.Field]
+		orderBy[i] = column.name
+		if key.Desc {
+			orderBy[i] += " DESC"
+		}
+		if column.nullable {
+			orderBy[i] += " NULLS LAST"
+		}
+	}
+	return orderBy
+}
+
+// sortAfter returns the condition keeping the rows after the cursor in the order of the sort keys, as
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ... with < for the descending keys. The rows without a value of a
+// nullable column come after the rows with one and are equal to each other. It returns domain.ErrInvalidCursor
+// for a value of the cursor that can not be read as a value of its column
+func sortAfter(keys []domain.SortKey, columns map[string]sortColumn, cursor *domain.Cursor) (sq.Sqlizer, error) {
+	if len(cursor.Values) != len(keys) {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	var after sq.Or
+	var equal sq.And
+	for i, key := range keys {
+		column := columns[key.Field]
+
+		if cursor.Values[i] == nil {
+			if !column.nullable {
+				return nil, domain.ErrInvalidCursor
+			}
+			// No row sorts after a null of this key, the rows after the cursor differ in a later key
+			equal = append(equal, sq.Eq{column.name: nil})
+			continue
+		}
+
+		value, err := column.parse(*cursor.Values[i])
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+
+		var beyond sq.Sqlizer = sq.Gt{column.name: value}
+		if key.Desc {
+			beyond = sq.Lt{column.name: value}
+		}
+		if column.nullable {
+			beyond = sq.Or{beyond, sq.Eq{column.name: nil}}
+		}
+
+		after = append(after, append(slices.Clone(equal), beyond))
+		equal = append(equal, sq.Eq{column.name: value})
+	}
+
+	return after, nil
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// stockMovementColumns is the list of stock movement columns in the order scanStockMovement reads them
+var stockMovementColumns = []string{
+	"id",
+	"product_id",
+	"warehouse_id",
+	"transfer_id",
+	"type",
+	"quantity",
+	"balance",
+	"reason",
+	"actor",
+	"request_id",
+	"created_at",
+}
+
+// scanStockMovement scans a row selected with stockMovementColumns into a stock movement
+func scanStockMovement(row pgx.Row, movement *domain.StockMovement) error {
+	return row.Scan(
+		&movement.ID,
+		&movement.ProductID,
+		&movement.WarehouseID,
+		&movement.TransferID,
+		&movement.Type,
+		&movement.Quantity,
+		&movement.Balance,
+		&movement.Reason,
+		&movement.Actor,
+		&movement.RequestID,
+		&movement.CreatedAt,
+	)
+}
+
+/**
+ * StockRepository implements port.StockRepository interface
+ * and provides an access to the postgres database
+ */
+type StockRepository struct {
+	db *postgres.DB
+}
+
+// NewStockRepository creates a new stock repository instance
+func NewStockRepository(db *postgres.DB) *StockRepository {
+	return &StockRepository{
+		db,
+	}
+}
+
+// CreateStockMovement creates a new stock movement record in the database
+func (sr *StockRepository) CreateStockMovement(ctx context.Context, movement *domain.StockMovement) (*domain.StockMovement, error) {
+	query := sr.db.QueryBuilder.Insert("stock_movements").
+		Columns("id", "product_id", "warehouse_id", "transfer_id", "type", "quantity", "balance", "reason", "actor", "request_id").
+		Values(
+			movement.ID,
+			movement.ProductID,
+			movement.WarehouseID,
+			movement.TransferID,
+			movement.Type,
+			movement.Quantity,
+			movement.Balance,
+			movement.Reason,
+			movement.Actor,
+			movement.RequestID,
+		).
+		Suffix("RETURNING " + strings.Join(stockMovementColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanStockMovement(sr.db.Conn(ctx).QueryRow(ctx, sql, args...), movement)
+	if err != nil {
+		return nil, sr.db.TranslateError(err)
+	}
+
+	return movement, nil
+}
+
+// CreateStockMovements creates stock movement records in the database at once with a copy
+func (sr *StockRepository) CreateStockMovements(ctx context.Context, movements []domain.StockMovement) error {
+	rows := make([][]any, len(movements))
+	for i, movement := range movements {
+		rows[i] = []any{
+			movement.ID,
+			movement.ProductID,
+			movement.WarehouseID,
+			movement.TransferID,
+			movement.Type,
+			movement.Quantity,
+			movement.Balance,
+			movement.Reason,
+			movement.Actor,
+			movement.RequestID,
+		}
+	}
+
+	_, err := sr.db.Conn(ctx).CopyFrom(ctx,
+		pgx.Identifier{"stock_movements"},
+		[]string{"id", "product_id", "warehouse_id", "transfer_id", "type", "quantity", "balance", "reason", "actor", "request_id"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return sr.db.TranslateError(err)
+	}
+
+	return nil
+}
+
+// ListStockMovements retrieves the stock movements of a product from the database, newest first
+func (sr *StockRepository) ListStockMovements(ctx context.Context, productID uuid.UUID, filter domain.StockMovementFilter, skip, limit uint64) ([]domain.StockMovement, error) {
+	var movement domain.StockMovement
+	var movements []domain.StockMovement
+
+	query := sr.db.QueryBuilder.Select(stockMovementColumns...).
+		From("stock_movements").
+		Where(sq.Eq{"product_id": productID}).
+		OrderBy("created_at DESC", "id").
+		Limit(limit).
+		Offset(skip * limit)
+
+	if len(filter.Types) != 0 {
+		query = query.Where(sq.Eq{"type": filter.Types})
+	}
+
+	if filter.WarehouseID != nil {
+		query = query.Where(sq.Eq{"warehouse_id": *filter.WarehouseID})
+	}
+
+	if filter.From != nil {
+		query = query.Where(sq.GtOrEq{"created_at": *filter.From})
+	}
+
+	if filter.To != nil {
+		query = query.Where(sq.Lt{"created_at": *filter.To})
+	}
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sr.db.Replica().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := scanStockMovement(rows, &movement)
+		if err != nil {
+			return nil, err
+		}
+
+		movements = append(movements, movement)
+	}
+
+	return movements, nil
+}
+
+// GetWarehouseStocks retrieves the stock records of the products with their warehouse from the database, by product id
+func (sr *StockRepository) GetWarehouseStocks(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]domain.WarehouseStock, error) {
+	stocks := make(map[uuid.UUID][]domain.WarehouseStock, len(productIDs))
+	if len(productIDs) == 0 {
+		return stocks, nil
+	}
+
+	columns := []string{"warehouse_stocks.product_id", "warehouse_stocks.quantity"}
+	for _, column := range warehouseColumns {
+		columns = append(columns, "warehouses."+column)
+	}
+
+	query := sr.db.QueryBuilder.Select(columns...).
+		From("warehouse_stocks").
+		Join("warehouses ON warehouses.id = warehouse_stocks.warehouse_id").
+		Where(sq.Eq{"warehouse_stocks.product_id": productIDs}).
+		OrderBy("warehouses.name")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := sr.db.Conn(ctx).Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var stock domain.WarehouseStock
+		var latitude, longitude *float64
+		err := rows.Scan(
+			&stock.ProductID,
+			&stock.Quantity,
+			&stock.Warehouse.ID,
+			&stock.Warehouse.Name,
+			&stock.Warehouse.City,
+			&latitude,
+			&longitude,
+			&stock.Warehouse.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if latitude != nil && longitude != nil {
+			stock.Warehouse.Coordinates = &domain.Coordinates{Latitude: *latitude, Longitude: *longitude}
+		}
+
+		stocks[stock.ProductID] = append(stocks[stock.ProductID], stock)
+	}
+
+	return stocks, nil
+}
+
+// SetWarehouseStock creates or replaces the stock record of a product in a warehouse in the database
+func (sr *StockRepository) SetWarehouseStock(ctx context.Context, productID, warehouseID uuid.UUID, quantity int) error {
+	query := sr.db.QueryBuilder.Insert("warehouse_stocks").
+		Columns("warehouse_id", "product_id", "quantity").
+		Values(warehouseID, productID, quantity).
+		Suffix("ON CONFLICT (warehouse_id, product_id) DO UPDATE SET quantity = EXCLUDED.quantity")
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = sr.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return sr.db.TranslateError(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/storage/postgres"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// warehouseColumns is the list of warehouse columns in the order scanWarehouse reads them
+var warehouseColumns = []string{
+	"id",
+	"name",
+	"city",
+	"latitude",
+	"longitude",
+	"created_at",
+}
+
+// scanWarehouse scans a row selected with warehouseColumns into a warehouse
+func scanWarehouse(row pgx.Row, warehouse *domain.Warehouse) error {
+	var latitude, longitude *float64
+	err := row.Scan(
+		&warehouse.ID,
+		&warehouse.Name,
+		&warehouse.City,
+		&latitude,
+		&longitude,
+		&warehouse.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	warehouse.Coordinates = nil
+	if latitude != nil && longitude != nil {
+		warehouse.Coordinates = &domain.Coordinates{Latitude: *latitude, Longitude: *longitude}
+	}
+
+	return nil
+}
+
+// coordinateValues returns the latitude and longitude columns of optional coordinates
+func coordinateValues(coordinates *domain.Coordinates) (latitude, longitude *float64) {
+	if coordinates == nil {
+		return nil, nil
+	}
+	return &coordinates.Latitude, &coordinates.Longitude
+}
+
+/**
+ * WarehouseRepository implements port.WarehouseRepository interface
+ * and provides an access to the postgres database
+ */
+type WarehouseRepository struct {
+	db *postgres.DB
+}
+
+// NewWarehouseRepository creates a new warehouse repository instance
+func NewWarehouseRepository(db *postgres.DB) *WarehouseRepository {
+	return &WarehouseRepository{
+		db,
+	}
+}
+
+// CreateWarehouse creates a new warehouse record in the database
+func (wr *WarehouseRepository) CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	latitude, longitude := coordinateValues(warehouse.Coordinates)
+
+	query := wr.db.QueryBuilder.Insert("warehouses").
+		Columns("id", "name", "city", "latitude", "longitude").
+		Values(warehouse.ID, warehouse.Name, warehouse.City, latitude, longitude).
+		Suffix("RETURNING " + strings.Join(warehouseColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanWarehouse(wr.db.Conn(ctx).QueryRow(ctx, sql, args...), warehouse)
+	if err != nil {
+		return nil, wr.db.TranslateError(err)
+	}
+
+	return warehouse, nil
+}
+
+// GetWarehouseByID retrieves a warehouse record from the database by id
+func (wr *WarehouseRepository) GetWarehouseByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	var warehouse domain.Warehouse
+
+	query := wr.db.QueryBuilder.Select(warehouseColumns...).
+		From("warehouses").
+		Where(sq.Eq{"id": id}).
+		Limit(1)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanWarehouse(wr.db.Conn(ctx).QueryRow(ctx, sql, args...), &warehouse)
+	if err != nil {
+		return nil, wr.db.TranslateError(err)
+	}
+
+	return &warehouse, nil
+}
+
+// ListWarehouses retrieves a list of warehouses from the database, by name
+func (wr *WarehouseRepository) ListWarehouses(ctx context.Context, skip, limit uint64) ([]domain.Warehouse, error) {
+	var warehouse domain.Warehouse
+	var warehouses []domain.Warehouse
+
+	query := wr.db.QueryBuilder.Select(warehouseColumns...).
+		From("warehouses").
+		OrderBy("name").
+		Limit(limit).
+		Offset(skip * limit)
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := wr.db.Replica().Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err := scanWarehouse(rows, &warehouse)
+		if err != nil {
+			return nil, err
+		}
+
+		warehouses = append(warehouses, warehouse)
+	}
+
+	return warehouses, nil
+}
+
+// UpdateWarehouse updates the name, city and coordinates of a warehouse record in the database
+func (wr *WarehouseRepository) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	latitude, longitude := coordinateValues(warehouse.Coordinates)
+
+	query := wr.db.QueryBuilder.Update("warehouses").
+		Set("name", warehouse.Name).
+		Set("city", warehouse.City).
+		Set("latitude", latitude).
+		Set("longitude", longitude).
+		Where(sq.Eq{"id": warehouse.ID}).
+		Suffix("RETURNING " + strings.Join(warehouseColumns, ", "))
+
+	sql, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	err = scanWarehouse(wr.db.Conn(ctx).QueryRow(ctx, sql, args...), warehouse)
+	if err != nil {
+		return nil, wr.db.TranslateError(err)
+	}
+
+	return warehouse, nil
+}
+
+// DeleteWarehouse deletes a warehouse record from the database by id.
+// Its empty stock records are removed, a warehouse still holding stock can not be deleted
+func (wr *WarehouseRepository) DeleteWarehouse(ctx context.Context, id uuid.UUID) error {
+	stocksQuery := wr.db.QueryBuilder.Delete("warehouse_stocks").
+		Where(sq.Eq{"warehouse_id": id, "quantity": 0})
+
+	sql, args, err := stocksQuery.ToSql()
+	if err != nil {
+		return err
+	}
+
+	_, err = wr.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return wr.db.TranslateError(err)
+	}
+
+	query := wr.db.QueryBuilder.Delete("warehouses").
+		Where(sq.Eq{"id": id})
+
+	sql, args, err = query.ToSql()
+	if err != nil {
+		return err
+	}
+
+	tag, err := wr.db.Conn(ctx).Exec(ctx, sql, args...)
+	if err != nil {
+		return wr.db.TranslateError(err)
+	}
+	if tag.RowsAffected() == 0 {
+		return domain.ErrDataNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// txContextKey is the context key of the transaction started by WithinTransaction
+type txContextKey struct{}
+
+// Querier is the set of query methods shared by the connection pool and a transaction
+type Querier interface {
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+// Conn returns the transaction carried by the context,
+// or the primary pool when the call is not part of a transaction
+func (db *DB) Conn(ctx context.Context) Querier {
+	if tx, ok := ctx.Value(txContextKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db.Pool
+}
+
+// WithinTransaction implements port.Transactor, it runs fn in a transaction
+// that is committed when fn returns nil and rolled back otherwise.
+// A call made within a running transaction joins it
+func (db *DB) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txContextKey{}).(pgx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return db.TranslateError(err)
+	}
+	defer func() {
+		// Rolling back a committed transaction is a no-op
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	err = fn(context.WithValue(ctx, txContextKey{}, tx))
+	if err != nil {
+		return err
+	}
+
+	return db.TranslateError(tx.Commit(ctx))
+}
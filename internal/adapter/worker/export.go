@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+const (
+	// defaultExportWorkers is used when no number of export workers is configured
+	defaultExportWorkers = 2
+	// defaultExportPollInterval is used when no export poll interval is configured
+	defaultExportPollInterval = 2 * time.Second
+	// exportCleanupInterval is how often the export jobs past their retention are expired
+	exportCleanupInterval = time.Minute
+)
+
+// ExportWorker runs the pending export jobs with a pool of workers and expires the ones past their retention
+type ExportWorker struct {
+	svc          port.ExportService
+	workers      int
+	pollInterval time.Duration
+}
+
+// NewExportWorker creates a new export worker instance
+func NewExportWorker(config *config.Export, svc port.ExportService) *ExportWorker {
+	workers := config.Workers
+	if workers <= 0 {
+		workers = defaultExportWorkers
+	}
+
+	pollInterval := config.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultExportPollInterval
+	}
+
+	return &ExportWorker{
+		svc,
+		workers,
+		pollInterval,
+	}
+}
+
+// Run runs the export jobs and expires them until the context is canceled.
+// The jobs running when it is canceled are claimed again after a restart
+func (ew *ExportWorker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for range ew.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ew.runJobs(ctx)
+		}()
+	}
+
+	ew.expire(ctx)
+	wg.Wait()
+}
+
+// runJobs runs the pending export jobs one after the other, polling for new ones when none is left
+func (ew *ExportWorker) runJobs(ctx context.Context) {
+	ticker := time.NewTicker(ew.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			ran, err := ew.svc.RunNextExport(ctx)
+			if err != nil {
+				slog.Error("Error running export job", "error", err)
+				break
+			}
+			if !ran {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expire expires the export jobs past their retention on every tick
+func (ew *ExportWorker) expire(ctx context.Context) {
+	ticker := time.NewTicker(exportCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		for ctx.Err() == nil {
+			expired, err := ew.svc.ExpireExports(ctx)
+			if err != nil {
+				slog.Error("Error expiring export jobs", "error", err)
+				break
+			}
+			if expired == 0 {
+				break
+			}
+
+			slog.Info("Expired export jobs", "count", expired)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
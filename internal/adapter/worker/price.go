@@ -0,0 +1,71 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/adapter/config"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/util"
+)
+
+const (
+	// defaultPriceInterval is used when no price worker interval is configured
+	defaultPriceInterval = time.Minute
+	// priceActor is the actor recorded in the audit trail for the prices applied by the worker
+	priceActor = "price-scheduler"
+)
+
+// PriceWorker periodically applies the scheduled prices that started to the base price of their product
+type PriceWorker struct {
+	svc      port.PriceService
+	interval time.Duration
+}
+
+// NewPriceWorker creates a new price worker instance
+func NewPriceWorker(config *config.Worker, svc port.PriceService) *PriceWorker {
+	interval := config.PriceInterval
+	if interval <= 0 {
+		interval = defaultPriceInterval
+	}
+
+	return &PriceWorker{
+		svc,
+		interval,
+	}
+}
+
+// Run applies the due prices on every tick until the context is canceled
+func (pw *PriceWorker) Run(ctx context.Context) {
+	ctx = util.WithActor(ctx, priceActor)
+
+	ticker := time.NewTicker(pw.interval)
+	defer ticker.Stop()
+
+	for {
+		pw.apply(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// apply applies the due prices in batches until none is left
+func (pw *PriceWorker) apply(ctx context.Context) {
+	for ctx.Err() == nil {
+		applied, err := pw.svc.ApplyDuePriceChanges(ctx)
+		if err != nil {
+			slog.Error("Error applying scheduled prices", "error", err)
+			return
+		}
+		if applied == 0 {
+			return
+		}
+
+		slog.Info("Applied scheduled prices", "count", applied)
+	}
+}
@@ -0,0 +1,176 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction is the kind of change recorded by an audit entry
+type AuditAction string
+
+const (
+	AuditActionCreate  AuditAction = "create"
+	AuditActionUpdate  AuditAction = "update"
+	AuditActionDelete  AuditAction = "delete"
+	AuditActionRestore AuditAction = "restore"
+	// AuditActionSchedulePrice and AuditActionCancelPrice record changes to the price timeline of a product
+	AuditActionSchedulePrice AuditAction = "schedule_price"
+	AuditActionCancelPrice   AuditAction = "cancel_price"
+)
+
+// AuditEntity is the kind of entity an audit entry refers to
+type AuditEntity string
+
+const (
+	AuditEntityProduct   AuditEntity = "product"
+	AuditEntityCategory  AuditEntity = "category"
+	AuditEntityWarehouse AuditEntity = "warehouse"
+)
+
+// AuditEntry is an entity that represents a change made to a product or a category
+type AuditEntry struct {
+	ID         uuid.UUID
+	EntityType AuditEntity
+	EntityID   uuid.UUID
+	Action     AuditAction
+	Actor      string
+	// ClaimedActor is the actor the client claimed to be, which is not verified
+	ClaimedActor string
+	RequestID    string
+	Changes      []FieldChange
+	CreatedAt    time.Time
+}
+
+// FieldChange is the value of a single field before and after a change
+type FieldChange struct {
+	Field  string
+	Before any
+	After  any
+}
+
+// DiffProducts returns the fields that differ between two states of a product.
+// A nil before describes a creation and a nil after describes a deletion
+func DiffProducts(before, after *Product) []FieldChange {
+	var b, a Product
+	if before != nil {
+		b = *before
+	}
+	if after != nil {
+		a = *after
+	}
+
+	d := differ{created: before == nil, deleted: after == nil}
+	diffField(&d, "reference", b.Reference, a.Reference)
+	diffField(&d, "name", b.Name, a.Name)
+	diffField(&d, "status", b.Status.String(), a.Status.String())
+	diffID(&d, "category_id", b.CategoryID, a.CategoryID)
+	diffMoney(&d, "price", b.Price, a.Price)
+	diffField(&d, "stock_city", b.StockCity, a.StockCity)
+	diffID(&d, "supplier_id", b.SupplierID, a.SupplierID)
+	diffField(&d, "quantity", b.Quantity, a.Quantity)
+
+	return d.changes
+}
+
+// DiffCategories returns the fields that differ between two states of a category.
+// A nil before describes a creation and a nil after describes a deletion
+func DiffCategories(before, after *Category) []FieldChange {
+	var b, a Category
+	if before != nil {
+		b = *before
+	}
+	if after != nil {
+		a = *after
+	}
+
+	d := differ{created: before == nil, deleted: after == nil}
+	diffField(&d, "name", b.Name, a.Name)
+
+	return d.changes
+}
+
+// DiffWarehouses returns the fields that differ between two states of a warehouse.
+// A nil before describes a creation and a nil after describes a deletion
+func DiffWarehouses(before, after *Warehouse) []FieldChange {
+	var b, a Warehouse
+	if before != nil {
+		b = *before
+	}
+	if after != nil {
+		a = *after
+	}
+
+	var bc, ac Coordinates
+	if b.Coordinates != nil {
+		bc = *b.Coordinates
+	}
+	if a.Coordinates != nil {
+		ac = *a.Coordinates
+	}
+
+	d := differ{created: before == nil, deleted: after == nil}
+	diffField(&d, "name", b.Name, a.Name)
+	diffField(&d, "city", b.City, a.City)
+	diffField(&d, "latitude", bc.Latitude, ac.Latitude)
+	diffField(&d, "longitude", bc.Longitude, ac.Longitude)
+
+	return d.changes
+}
+
+// differ collects the field changes between two states of an entity
+type differ struct {
+	created bool
+	deleted bool
+	changes []FieldChange
+}
+
+// diffField records the change of a field when its value differs,
+// the missing side of a creation or a deletion is left nil
+func diffField[T comparable](d *differ, field string, before, after T) {
+	var zero T
+	switch {
+	case d.created:
+		if after != zero {
+			d.changes = append(d.changes, FieldChange{Field: field, After: after})
+		}
+	case d.deleted:
+		if before != zero {
+			d.changes = append(d.changes, FieldChange{Field: field, Before: before})
+		}
+	case before != after:
+		d.changes = append(d.changes, FieldChange{Field: field, Before: before, After: after})
+	}
+}
+
+// diffMoney records the change of an amount like diffField, comparing amounts by value
+func diffMoney(d *differ, field string, before, after Money) {
+	switch {
+	case d.created:
+		if !after.IsZero() {
+			d.changes = append(d.changes, FieldChange{Field: field, After: after})
+		}
+	case d.deleted:
+		if !before.IsZero() {
+			d.changes = append(d.changes, FieldChange{Field: field, Before: before})
+		}
+	case !before.Equal(after):
+		d.changes = append(d.changes, FieldChange{Field: field, Before: before, After: after})
+	}
+}
+
+// diffID records the change of an optional id, an absent id is recorded as nil
+func diffID(d *differ, field string, before, after *uuid.UUID) {
+	var b, a any
+	if before != nil && !d.created {
+		b = *before
+	}
+	if after != nil && !d.deleted {
+		a = *after
+	}
+	if b == a {
+		return
+	}
+
+	d.changes = append(d.changes, FieldChange{Field: field, Before: b, After: a})
+}
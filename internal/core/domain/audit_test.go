@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestDiffProducts(t *testing.T) {
+	categoryID := uuid.MustParse("2a1c8c3e-7e4b-4b4e-9a43-8b0e1c3f5a10")
+	price10, _ := ParseMoney("10", "USD")
+	price12, _ := ParseMoney("12", "USD")
+	product := Product{
+		Reference:  "REF-1",
+		Name:       "Apple",
+		Status:     StatusAvailable,
+		CategoryID: &categoryID,
+		Price:      price10,
+		Quantity:   5,
+	}
+	updated := product
+	updated.Price = price12
+	updated.Quantity = 0
+	updated.CategoryID = nil
+
+	tests := []struct {
+		name   string
+		before *Product
+		after  *Product
+		want   []FieldChange
+	}{
+		{
+			name:   "create",
+			before: nil,
+			after:  &product,
+			want: []FieldChange{
+				{Field: "reference", After: "REF-1"},
+				{Field: "name", After: "Apple"},
+				{Field: "status", After: "Available"},
+				{Field: "category_id", After: categoryID},
+				{Field: "price", After: price10},
+				{Field: "quantity", After: 5},
+			},
+		},
+		{
+			name:   "update",
+			before: &product,
+			after:  &updated,
+			want: []FieldChange{
+				{Field: "category_id", Before: categoryID},
+				{Field: "price", Before: price10, After: price12},
+				{Field: "quantity", Before: 5, After: 0},
+			},
+		},
+		{
+			name:   "no change",
+			before: &product,
+			after:  &product,
+			want:   nil,
+		},
+		{
+			name:   "delete",
+			before: &updated,
+			after:  nil,
+			want: []FieldChange{
+				{Field: "reference", Before: "REF-1"},
+				{Field: "name", Before: "Apple"},
+				{Field: "status", Before: "Available"},
+				{Field: "price", Before: price12},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DiffProducts(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffProducts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
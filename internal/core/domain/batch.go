@@ -0,0 +1,59 @@
+package domain
+
+import "fmt"
+
+// ProductOperationMethod is the change an operation of a batch makes to a product
+type ProductOperationMethod string
+
+const (
+	OperationCreate ProductOperationMethod = "create"
+	OperationUpdate ProductOperationMethod = "update"
+	OperationDelete ProductOperationMethod = "delete"
+)
+
+// BatchMode decides what happens to the other operations of a batch when one of them fails
+type BatchMode string
+
+const (
+	// BatchAtomic applies all the operations of a batch or none of them
+	BatchAtomic BatchMode = "atomic"
+	// BatchBestEffort applies the operations that succeed and reports the failed ones
+	BatchBestEffort BatchMode = "best_effort"
+)
+
+// ProductOperation is an operation of a product batch. As for a single update, an update only sets
+// the fields of its product that are not empty, and its quantity when QuantitySet. A delete only reads
+// the id of its product
+type ProductOperation struct {
+	Method      ProductOperationMethod
+	Product     Product
+	QuantitySet bool
+}
+
+// ProductOperationResult is the result of an operation of a product batch,
+// the product it created or updated, or the error it failed with
+type ProductOperationResult struct {
+	Product *Product
+	Err     error
+}
+
+// ProductWrite is the write of a product made by an operation of a batch, Fields lists the fields set by an update
+type ProductWrite struct {
+	Method  ProductOperationMethod
+	Product *Product
+	Fields  []string
+}
+
+// BatchError is the error of a write of a batch, which stops the batch at its index
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("write %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
@@ -1,11 +1,70 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
 // Category is an entity that represents a category of product
 type Category struct {
-	ID   uuid.UUID
-	Name string
+	ID        uuid.UUID
+	Name      string
+	CreatedAt time.Time
+	DeletedAt *time.Time
+}
+
+// CategorySort is the order of a list of categories, made of comma separated fields among CategorySortFields,
+// each descending when prefixed with "-". Categories are listed newest first by default
+type CategorySort string
+
+// CategorySortFields are the fields categories can be sorted by
+var CategorySortFields = []string{
+	"id",
+	"name",
+	"created_at",
+}
+
+// categorySortDefault is the order of categories when none is given, newest first
+var categorySortDefault = []SortKey{{Field: "created_at", Desc: true}}
+
+// ParseCategorySort parses a category sort, returning it normalized or ErrInvalidSort for a field that can not be sorted by
+func ParseCategorySort(sort string) (CategorySort, error) {
+	keys, err := parseSort(sort, CategorySortFields)
+	if err != nil {
+		return "", err
+	}
+	return CategorySort(formatSort(keys)), nil
+}
+
+// Keys returns the keys the categories are sorted by, ending with the id so that the order is total
+func (s CategorySort) Keys() []SortKey {
+	return sortKeys(string(s), CategorySortFields, categorySortDefault)
+}
+
+// CategoryFilter narrows and orders a list of categories
+type CategoryFilter struct {
+	IncludeDeleted bool
+	Sort           CategorySort
+	// After lists the categories after the cursor in the order of the sort, for a page of a keyset pagination
+	After *Cursor
+}
+
+// NewCategoryCursor returns the cursor of a page of categories sorted by the sort whose last category is the given one
+func NewCategoryCursor(sort CategorySort, category *Category) *Cursor {
+	keys := sort.Keys()
+	cursor := &Cursor{Sort: string(sort), Values: make([]*string, len(keys))}
+	for i, key := range keys {
+		var value string
+		switch key.Field {
+		case "id":
+			value = category.ID.String()
+		case "name":
+			value = category.Name
+		case "created_at":
+			value = category.CreatedAt.Format(time.RFC3339Nano)
+		}
+		cursor.Values[i] = &value
+	}
+	return cursor
 }
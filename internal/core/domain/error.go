@@ -13,6 +13,16 @@ var (
 	ErrNoUpdatedData = errors.New("no data to update")
 	// ErrConflictingData is an error for when data conflicts with existing data
 	ErrConflictingData = errors.New("data conflicts with existing data in unique column")
+	// ErrForeignKeyViolation is an error for when data is still referenced by other data
+	ErrForeignKeyViolation = errors.New("data is still referenced by other data")
+	// ErrInvalidReference is an error for when data references data that does not exist
+	ErrInvalidReference = errors.New("referenced data does not exist")
+	// ErrMissingRequiredData is an error for when a required column is left empty
+	ErrMissingRequiredData = errors.New("required data is missing")
+	// ErrConstraintViolation is an error for when data violates a check constraint
+	ErrConstraintViolation = errors.New("data violates a check constraint")
+	// ErrConcurrentUpdate is an error for when data was modified by a concurrent transaction
+	ErrConcurrentUpdate = errors.New("data was modified concurrently, please retry")
 	// ErrInsufficientStock is an error for when product stock is not enough
 	ErrInsufficientStock = errors.New("product stock is not enough")
 	// ErrInsufficientPayment is an error for when total paid is less than total price
@@ -39,4 +49,50 @@ var (
 	ErrForbidden = errors.New("user is forbidden to access the resource")
 	// ErrInvalidStatus is an error for when the status is invalid
 	ErrInvalidStatus = errors.New("invalid status")
+	// ErrInvalidPriceSchedule is an error for when a price change has a non positive price or ends before it starts
+	ErrInvalidPriceSchedule = errors.New("price must be positive and end after it starts and after now")
+	// ErrPriceChangeApplied is an error for when a price change that already replaced the base price is canceled
+	ErrPriceChangeApplied = errors.New("price change has already been applied")
+	// ErrInvalidMoney is an error for when an amount is not a decimal number
+	ErrInvalidMoney = errors.New("amount must be a decimal number")
+	// ErrInvalidCurrency is an error for when a currency is not a three letter ISO 4217 code
+	ErrInvalidCurrency = errors.New("currency must be a three letter ISO 4217 code")
+	// ErrCurrencyMismatch is an error for when amounts in different currencies are combined
+	ErrCurrencyMismatch = errors.New("amounts are in different currencies")
+	// ErrUnknownCurrency is an error for when no exchange rate is known for a currency
+	ErrUnknownCurrency = errors.New("no exchange rate is known for the currency")
+	// ErrInvalidExchangeRate is an error for when an exchange rate is not positive or is set for the default currency
+	ErrInvalidExchangeRate = errors.New("exchange rate must be positive and not for the default currency")
+	// ErrInvalidRoundingRule is an error for when a rounding mode or decimal places setting is invalid
+	ErrInvalidRoundingRule = errors.New("invalid rounding rule")
+	// ErrInvalidStockMovement is an error for when a stock movement has an unknown type, a quantity of the wrong sign or lacks a reason
+	ErrInvalidStockMovement = errors.New("stock movement has an invalid type, quantity or reason")
+	// ErrInvalidCoordinates is an error for when a latitude or a longitude is out of range
+	ErrInvalidCoordinates = errors.New("latitude must be between -90 and 90 and longitude between -180 and 180")
+	// ErrUnknownLocation is an error for when the location of the client can not be determined from its ip
+	ErrUnknownLocation = errors.New("location of the client could not be determined from its ip address")
+	// ErrUnknownCity is an error for when the stock city of a product can not be located
+	ErrUnknownCity = errors.New("stock city of the product could not be located")
+	// ErrInvalidExportOptions is an error for when an export has unknown columns or an invalid delimiter
+	ErrInvalidExportOptions = errors.New("invalid export options")
+	// ErrExportNotReady is an error for when the file of an export job that is still pending or running is downloaded
+	ErrExportNotReady = errors.New("export file is not ready yet")
+	// ErrExportUnavailable is an error for when the file of an export job that failed, was canceled or expired is downloaded
+	ErrExportUnavailable = errors.New("export job failed, was canceled or expired and has no file")
+	// ErrExportFinished is an error for when an export job that is no longer pending or running is canceled
+	ErrExportFinished = errors.New("export job has already finished")
+	// ErrInvalidImportFile is an error for when an import file can not be read, such as a CSV file with an unknown column
+	ErrInvalidImportFile = errors.New("invalid import file")
+	// ErrInvalidOperation is an error for when an operation of a batch has an unknown method
+	ErrInvalidOperation = errors.New("unknown batch operation")
+	// ErrDuplicateOperation is an error for when an operation of a batch changes a product already changed by another one
+	ErrDuplicateOperation = errors.New("product is already changed by another operation of the batch")
+	// ErrBatchAborted is an error for when an operation of an atomic batch is not applied because another one failed
+	ErrBatchAborted = errors.New("operation not applied, another operation of the batch failed")
+	// ErrInvalidProductView is an error for when the fields or relations requested for products are unknown
+	ErrInvalidProductView = errors.New("invalid product fields or relations")
+	// ErrInvalidSort is an error for when a list is sorted by a field that can not be sorted by
+	ErrInvalidSort = errors.New("invalid sort")
+	// ErrInvalidCursor is an error for when a cursor is malformed or was made for a list sorted differently
+	ErrInvalidCursor = errors.New("invalid cursor, it must be one returned for a list with the same sort")
 )
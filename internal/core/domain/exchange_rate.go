@@ -0,0 +1,160 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRate is an entity that represents how many units of a currency one unit of DefaultCurrency buys
+type ExchangeRate struct {
+	Currency  string
+	Rate      decimal.Decimal
+	UpdatedAt time.Time
+}
+
+// Validate checks that the exchange rate has a valid currency other than DefaultCurrency and a positive rate
+func (er *ExchangeRate) Validate() error {
+	currency, err := ParseCurrency(er.Currency)
+	if err != nil {
+		return err
+	}
+	if currency == DefaultCurrency || !er.Rate.IsPositive() {
+		return ErrInvalidExchangeRate
+	}
+
+	er.Currency = currency
+	return nil
+}
+
+// RoundingMode is how a converted amount is rounded to the decimal places of its currency
+type RoundingMode string
+
+const (
+	RoundHalfUp   RoundingMode = "half_up"
+	RoundHalfEven RoundingMode = "half_even"
+	RoundUp       RoundingMode = "up"
+	RoundDown     RoundingMode = "down"
+)
+
+// RoundingRules holds how converted amounts are rounded, per currency decimal places default to MoneyPlaces
+type RoundingRules struct {
+	Mode   RoundingMode
+	Places map[string]int32
+}
+
+// ParseRoundingRules parses a rounding mode, empty for half_up, and a list of per currency decimal places such as "JPY:0,VND:0"
+func ParseRoundingRules(mode, places string) (RoundingRules, error) {
+	rules := RoundingRules{
+		Mode:   RoundingMode(mode),
+		Places: make(map[string]int32),
+	}
+
+	switch rules.Mode {
+	case "":
+		rules.Mode = RoundHalfUp
+	case RoundHalfUp, RoundHalfEven, RoundUp, RoundDown:
+	default:
+		return RoundingRules{}, ErrInvalidRoundingRule
+	}
+
+	for _, item := range strings.Split(places, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		currency, value, ok := strings.Cut(item, ":")
+		if !ok {
+			return RoundingRules{}, ErrInvalidRoundingRule
+		}
+
+		currency, err := ParseCurrency(strings.TrimSpace(currency))
+		if err != nil {
+			return RoundingRules{}, err
+		}
+
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 32)
+		if err != nil || n < 0 {
+			return RoundingRules{}, ErrInvalidRoundingRule
+		}
+
+		rules.Places[currency] = int32(n)
+	}
+
+	return rules, nil
+}
+
+// Round rounds money to the decimal places of its currency with the rounding mode
+func (r RoundingRules) Round(m Money) Money {
+	places, ok := r.Places[m.Currency]
+	if !ok {
+		places = MoneyPlaces
+	}
+
+	switch r.Mode {
+	case RoundHalfEven:
+		m.Amount = m.Amount.RoundBank(places)
+	case RoundUp:
+		m.Amount = m.Amount.RoundUp(places)
+	case RoundDown:
+		m.Amount = m.Amount.RoundDown(places)
+	default:
+		m.Amount = m.Amount.Round(places)
+	}
+
+	return m
+}
+
+// CurrencyConverter converts money to a target currency through the rates against DefaultCurrency
+type CurrencyConverter struct {
+	target   string
+	rates    map[string]decimal.Decimal
+	rounding RoundingRules
+}
+
+// NewCurrencyConverter creates a converter to the target currency, which must be DefaultCurrency or have a rate
+func NewCurrencyConverter(target string, rates []ExchangeRate, rounding RoundingRules) (*CurrencyConverter, error) {
+	target, err := ParseCurrency(target)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CurrencyConverter{
+		target:   target,
+		rates:    map[string]decimal.Decimal{DefaultCurrency: decimal.NewFromInt(1)},
+		rounding: rounding,
+	}
+	for _, rate := range rates {
+		c.rates[rate.Currency] = rate.Rate
+	}
+
+	if _, ok := c.rates[target]; !ok {
+		return nil, ErrUnknownCurrency
+	}
+
+	return c, nil
+}
+
+// Currency returns the target currency of the converter
+func (c *CurrencyConverter) Currency() string {
+	return c.target
+}
+
+// Convert converts money to the target currency and rounds it with the rounding rules
+func (c *CurrencyConverter) Convert(m Money) (Money, error) {
+	if m.Currency == c.target {
+		return m, nil
+	}
+
+	from, ok := c.rates[m.Currency]
+	if !ok {
+		return Money{}, ErrUnknownCurrency
+	}
+
+	amount := m.Amount.Mul(c.rates[c.target]).DivRound(from, 16)
+
+	return c.rounding.Round(Money{amount, c.target}), nil
+}
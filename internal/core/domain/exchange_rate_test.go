@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCurrencyConverter(t *testing.T) {
+	rates := []ExchangeRate{
+		{Currency: "EUR", Rate: decimal.RequireFromString("0.9")},
+		{Currency: "JPY", Rate: decimal.RequireFromString("150.55")},
+	}
+	rounding, err := ParseRoundingRules("half_up", "JPY:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		amount  string
+		from    string
+		want    string
+		wantErr error
+	}{
+		{name: "from default currency", target: "EUR", amount: "10.00", from: "USD", want: "9.00"},
+		{name: "to default currency", target: "USD", amount: "9.00", from: "EUR", want: "10.00"},
+		{name: "cross rate with zero places", target: "JPY", amount: "10.00", from: "EUR", want: "1673.00"},
+		{name: "same currency", target: "EUR", amount: "1.23", from: "EUR", want: "1.23"},
+		{name: "unknown source", target: "EUR", amount: "1.00", from: "GBP", wantErr: ErrUnknownCurrency},
+		{name: "unknown target", target: "GBP", amount: "1.00", from: "USD", wantErr: ErrUnknownCurrency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewCurrencyConverter(tt.target, rates, rounding)
+			if err == nil {
+				money, _ := ParseMoney(tt.amount, tt.from)
+				var got Money
+				got, err = converter.Convert(money)
+				if err == nil && (got.StringFixed() != tt.want || got.Currency != tt.target) {
+					t.Errorf("Convert() = %v, want %v %v", got, tt.want, tt.target)
+				}
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Convert() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRoundingRules(t *testing.T) {
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{mode: "half_up", want: "2.35"},
+		{mode: "half_even", want: "2.34"},
+		{mode: "up", want: "2.35"},
+		{mode: "down", want: "2.34"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			rules, err := ParseRoundingRules(tt.mode, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			money, _ := ParseMoney("2.345", "USD")
+			if got := rules.Round(money).StringFixed(); got != tt.want {
+				t.Errorf("Round() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseRoundingRules("nearest", ""); !errors.Is(err, ErrInvalidRoundingRule) {
+		t.Errorf("ParseRoundingRules() error = %v, want %v", err, ErrInvalidRoundingRule)
+	}
+	if _, err := ParseRoundingRules("", "JPY"); !errors.Is(err, ErrInvalidRoundingRule) {
+		t.Errorf("ParseRoundingRules() error = %v, want %v", err, ErrInvalidRoundingRule)
+	}
+}
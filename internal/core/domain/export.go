@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportFormat is the file format of an export of products
+type ExportFormat string
+
+const (
+	ExportPDF  ExportFormat = "pdf"
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// ContentType returns the media type of the files of the format
+func (f ExportFormat) ContentType() string {
+	switch f {
+	case ExportCSV:
+		return "text/csv; charset=utf-8"
+	case ExportXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "application/pdf"
+	}
+}
+
+// ExportState is the state of an export job
+type ExportState string
+
+const (
+	ExportPending   ExportState = "pending"
+	ExportRunning   ExportState = "running"
+	ExportCompleted ExportState = "completed"
+	ExportFailed    ExportState = "failed"
+	ExportCanceled  ExportState = "canceled"
+	ExportExpired   ExportState = "expired"
+)
+
+// IsFinal reports whether a job in the state is done running
+func (s ExportState) IsFinal() bool {
+	return s != ExportPending && s != ExportRunning
+}
+
+// ExportOptions are the options of an export besides the filter of its products. Only some of them apply to a format
+type ExportOptions struct {
+	// Columns are the exported columns of a CSV or XLSX file, the default ones when empty
+	Columns []string `json:"columns,omitempty"`
+	// Delimiter separates the fields of a CSV file, a single character or "tab" or "semicolon"
+	Delimiter string `json:"delimiter,omitempty"`
+	// BOM starts a CSV file with a UTF-8 byte order mark
+	BOM bool `json:"bom,omitempty"`
+	// Sheets is "category" for a sheet of products per category in an XLSX file, or "single"
+	Sheets string `json:"sheets,omitempty"`
+	// Group is "category" to group the products of a PDF report by category, or "none"
+	Group string `json:"group,omitempty"`
+	// Currency converts the prices when given
+	Currency string `json:"currency,omitempty"`
+	// Skip and Limit select a page of the products of a PDF report, all of them when Limit is zero
+	Skip  uint64 `json:"skip,omitempty"`
+	Limit uint64 `json:"limit,omitempty"`
+}
+
+// ExportJob is an entity that represents an export of products generated in the background.
+// A job is pending until a worker runs it, its file can be downloaded once it is completed and until it expires
+type ExportJob struct {
+	ID      uuid.UUID
+	Format  ExportFormat
+	Filter  ProductFilter
+	Options ExportOptions
+	State   ExportState
+	// Rows is the number of products exported so far
+	Rows int64
+	// Size is the size of the file in bytes, once completed
+	Size int64
+	// Error is the reason a job failed
+	Error string
+	// Attempts counts the runs of the job, a run abandoned by a worker that stopped is started again
+	Attempts    int
+	CreatedAt   time.Time
+	StartedAt   *time.Time
+	HeartbeatAt *time.Time
+	FinishedAt  *time.Time
+	ExpiresAt   time.Time
+}
+
+// FileName returns the name of the file of an attempt of the job in the export storage
+func (j *ExportJob) FileName(attempt int) string {
+	return fmt.Sprintf("%s-%d.%s", j.ID, attempt, j.Format)
+}
+
+// DownloadName returns the name the file of the job is downloaded as
+func (j *ExportJob) DownloadName() string {
+	return fmt.Sprintf("products-%s.%s", j.CreatedAt.UTC().Format("20060102-150405"), j.Format)
+}
+
+// IsExpired reports whether the job expired at the given time, even when it is not marked as expired yet
+func (j *ExportJob) IsExpired(now time.Time) bool {
+	return j.State == ExportExpired || (j.State.IsFinal() && !j.ExpiresAt.After(now))
+}
+
+// LabelOptions are the options of a sheet of product labels besides the filter of its products
+type LabelOptions struct {
+	// Layout names the label sheet, such as avery-l7159 for 3x8 labels on A4
+	Layout string
+	// Symbology is "code128" for a barcode or "qr" for a QR code
+	Symbology string
+	// Encode is the product field the code holds, "reference" or "id"
+	Encode string
+	// Currency converts the prices when given
+	Currency string
+	// Start is the position of the first label on the first sheet, counted from 1, to print on a partly used sheet
+	Start int
+	// Skip and Limit select a page of the products, the exporter bounding and defaulting Limit
+	Skip  uint64
+	Limit uint64
+}
@@ -0,0 +1,63 @@
+package domain
+
+// ImportFormat is the file format of a product import
+type ImportFormat string
+
+const (
+	ImportCSV   ImportFormat = "csv"
+	ImportJSONL ImportFormat = "jsonl"
+)
+
+// ImportMode decides what happens to the valid rows of an import holding invalid ones
+type ImportMode string
+
+const (
+	// ImportAtomic imports nothing when a row is invalid
+	ImportAtomic ImportMode = "atomic"
+	// ImportPartial imports the valid rows and reports the invalid ones
+	ImportPartial ImportMode = "partial"
+)
+
+// ImportOptions are the options of a product import
+type ImportOptions struct {
+	// DryRun validates the rows and reports what would be imported without writing anything
+	DryRun bool
+	// Upsert updates the products whose reference already exists instead of rejecting their rows.
+	// As for an update, the empty values of a row keep the values of its product
+	Upsert bool
+	Mode   ImportMode
+}
+
+// ImportRow is a row of a product import. Errors holds the problems found while reading the row,
+// such as a malformed price or id, its product is only imported when it has none
+type ImportRow struct {
+	// Line is the line of the row in the file, counted from 1 including the header of a CSV file
+	Line    int
+	Product Product
+	// QuantitySet reports whether the row gives the quantity, an upsert keeping the quantity of the product otherwise
+	QuantitySet bool
+	Errors      []string
+}
+
+// ImportRowError is an invalid row of a product import with the reasons it was rejected
+type ImportRowError struct {
+	Line      int
+	Reference string
+	Errors    []string
+}
+
+// ImportReport is the result of a product import. Created, Updated and Unchanged count the valid rows
+// that were written, or would be for a dry run, they are zero for an atomic import holding invalid rows
+type ImportReport struct {
+	Rows    int
+	Created int
+	Updated int
+	// Unchanged counts the upserted rows matching their product, which are left as they are
+	Unchanged int
+	Failed    int
+	DryRun    bool
+	// Committed is set when the valid rows were written, it is unset for a dry run
+	// and for an atomic import holding invalid rows
+	Committed bool
+	Errors    []ImportRowError
+}
@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// DefaultCurrency is the currency of the amounts given without one
+	DefaultCurrency = "USD"
+	// MoneyPlaces is the number of decimal places amounts are stored with
+	MoneyPlaces = 2
+)
+
+// Money is a value object that represents an exact amount in a currency identified by its ISO 4217 code
+type Money struct {
+	Amount   decimal.Decimal
+	Currency string
+}
+
+// moneyJSON is the JSON representation of money, the amount is a string to keep it exact
+type moneyJSON struct {
+	Amount   string `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney creates money from an amount and a currency code, an empty currency is DefaultCurrency
+func NewMoney(amount decimal.Decimal, currency string) (Money, error) {
+	currency, err := ParseCurrency(currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return Money{amount, currency}, nil
+}
+
+// ParseMoney creates money from a decimal string such as "12.50" and a currency code
+func ParseMoney(amount, currency string) (Money, error) {
+	value, err := decimal.NewFromString(amount)
+	if err != nil {
+		return Money{}, ErrInvalidMoney
+	}
+
+	return NewMoney(value, currency)
+}
+
+// ParseCurrency normalizes a three letter currency code, an empty code is DefaultCurrency
+func ParseCurrency(currency string) (string, error) {
+	if currency == "" {
+		return DefaultCurrency, nil
+	}
+
+	currency = strings.ToUpper(currency)
+	if len(currency) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+
+	return currency, nil
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount.IsZero()
+}
+
+// IsPositive reports whether the amount is greater than zero
+func (m Money) IsPositive() bool {
+	return m.Amount.IsPositive()
+}
+
+// Equal reports whether both amounts are equal and in the same currency
+func (m Money) Equal(other Money) bool {
+	return m.Currency == other.Currency && m.Amount.Equal(other.Amount)
+}
+
+// Add returns the sum of both amounts, which must be in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{m.Amount.Add(other.Amount), m.Currency}, nil
+}
+
+// Sub returns the difference of both amounts, which must be in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return Money{m.Amount.Sub(other.Amount), m.Currency}, nil
+}
+
+// Mul returns the amount multiplied by a factor, such as a quantity or a rate
+func (m Money) Mul(factor decimal.Decimal) Money {
+	return Money{m.Amount.Mul(factor), m.Currency}
+}
+
+// Round returns the amount rounded half away from zero to the given number of decimal places
+func (m Money) Round(places int32) Money {
+	return Money{m.Amount.Round(places), m.Currency}
+}
+
+// StringFixed returns the amount with MoneyPlaces decimal places, without the currency
+func (m Money) StringFixed() string {
+	return m.Amount.StringFixed(MoneyPlaces)
+}
+
+// String returns the amount with MoneyPlaces decimal places followed by the currency, such as "12.50 USD"
+func (m Money) String() string {
+	return m.StringFixed() + " " + m.Currency
+}
+
+// MarshalJSON encodes money as an object holding the amount as a string and the currency
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(moneyJSON{m.StringFixed(), m.Currency})
+}
+
+// UnmarshalJSON decodes money from an object holding the amount and the currency, an empty currency being left empty,
+// or from a bare string or number amount whose currency is left empty
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '{' {
+		var v moneyJSON
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+
+		amount, err := decimal.NewFromString(v.Amount)
+		if err != nil {
+			return ErrInvalidMoney
+		}
+
+		currency := ""
+		if v.Currency != "" {
+			currency, err = ParseCurrency(v.Currency)
+			if err != nil {
+				return err
+			}
+		}
+
+		m.Amount = amount
+		m.Currency = currency
+		return nil
+	}
+
+	amount, err := decimal.NewFromString(strings.Trim(string(data), `"`))
+	if err != nil {
+		return ErrInvalidMoney
+	}
+
+	m.Amount = amount
+	return nil
+}
+
+// Scan implements sql.Scanner to read the amount from a numeric or decimal column, NULL is read as zero.
+// The currency is kept as it is stored in its own column
+func (m *Money) Scan(src any) error {
+	if src == nil {
+		m.Amount = decimal.Zero
+		return nil
+	}
+	if err := m.Amount.Scan(src); err != nil {
+		return fmt.Errorf("scan money: %w", err)
+	}
+	return nil
+}
+
+// Value implements driver.Valuer to write the amount to a numeric or decimal column
+func (m Money) Value() (driver.Value, error) {
+	return m.Amount.String(), nil
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		want     string
+		currency string
+		wantErr  bool
+	}{
+		{name: "object", data: `{"amount":"12.5","currency":"eur"}`, want: "12.50", currency: "EUR"},
+		{name: "string", data: `"0.10"`, want: "0.10"},
+		{name: "number", data: `19.99`, want: "19.99"},
+		{name: "object without currency", data: `{"amount":"3"}`, want: "3.00"},
+		{name: "object with invalid currency", data: `{"amount":"3","currency":"US"}`, wantErr: true},
+		{name: "object with non letter currency", data: `{"amount":"3","currency":"U$D"}`, wantErr: true},
+		{name: "not a number", data: `"abc"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			err := json.Unmarshal([]byte(tt.data), &m)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if m.StringFixed() != tt.want || m.Currency != tt.currency {
+				t.Errorf("UnmarshalJSON() = %v, want %v %v", m, tt.want, tt.currency)
+			}
+		})
+	}
+
+	m, _ := ParseMoney("0.1", "USD")
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"amount":"0.10","currency":"USD"}`; got != want {
+		t.Errorf("MarshalJSON() = %s, want %s", got, want)
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	a, _ := ParseMoney("0.1", "USD")
+	b, _ := ParseMoney("0.2", "USD")
+	c, _ := ParseMoney("0.3", "USD")
+
+	sum, err := a.Add(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sum.Equal(c) {
+		t.Errorf("Add() = %v, want %v", sum, c)
+	}
+
+	eur, _ := ParseMoney("0.1", "EUR")
+	if _, err := a.Add(eur); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want string
+	}{
+		{name: "pgx numeric", src: "1234.50", want: "1234.50"},
+		{name: "mysql decimal", src: []byte("99.99"), want: "99.99"},
+		{name: "null", src: nil, want: "0.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Money{Currency: "USD"}
+			if err := m.Scan(tt.src); err != nil {
+				t.Fatal(err)
+			}
+			if m.StringFixed() != tt.want || m.Currency != "USD" {
+				t.Errorf("Scan() = %v, want %v USD", m, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		currency string
+		want     string
+		wantErr  bool
+	}{
+		{currency: "", want: DefaultCurrency},
+		{currency: "vnd", want: "VND"},
+		{currency: "US", wantErr: true},
+		{currency: "U$D", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			got, err := ParseCurrency(tt.currency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCurrency() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCurrency() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
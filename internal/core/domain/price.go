@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChangeStatus is the state of a price change at a given time
+type PriceChangeStatus string
+
+const (
+	PriceChangeScheduled PriceChangeStatus = "scheduled"
+	PriceChangeActive    PriceChangeStatus = "active"
+	PriceChangeApplied   PriceChangeStatus = "applied"
+	PriceChangeExpired   PriceChangeStatus = "expired"
+	PriceChangeCanceled  PriceChangeStatus = "canceled"
+)
+
+// PriceChange is an entity that represents a price of a product on its price timeline.
+// A price change without an end replaces the base price of the product once it starts,
+// a price change with an end is a promotion that overrides the base price while it lasts
+type PriceChange struct {
+	ID         uuid.UUID
+	ProductID  uuid.UUID
+	Price      Money
+	StartsAt   time.Time
+	EndsAt     *time.Time
+	AppliedAt  *time.Time
+	CanceledAt *time.Time
+	// Attempts counts the failed attempts to apply the price change, which is not retried before RetryAt
+	Attempts  int
+	RetryAt   *time.Time
+	CreatedAt time.Time
+}
+
+// IsPromotion reports whether the price change only lasts until its end
+func (pc *PriceChange) IsPromotion() bool {
+	return pc.EndsAt != nil
+}
+
+// Validate checks that the price change can be scheduled at the given time
+func (pc *PriceChange) Validate(now time.Time) error {
+	if !pc.Price.IsPositive() {
+		return ErrInvalidPriceSchedule
+	}
+	if pc.EndsAt != nil && (!pc.EndsAt.After(pc.StartsAt) || !pc.EndsAt.After(now)) {
+		return ErrInvalidPriceSchedule
+	}
+	return nil
+}
+
+// Status returns the state of the price change at the given time
+func (pc *PriceChange) Status(now time.Time) PriceChangeStatus {
+	switch {
+	case pc.CanceledAt != nil:
+		return PriceChangeCanceled
+	case pc.AppliedAt != nil:
+		return PriceChangeApplied
+	case pc.StartsAt.After(now):
+		return PriceChangeScheduled
+	case pc.EndsAt != nil && !pc.EndsAt.After(now):
+		return PriceChangeExpired
+	default:
+		return PriceChangeActive
+	}
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriceChangeStatus(t *testing.T) {
+	now := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-24 * time.Hour)
+	future := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name   string
+		change PriceChange
+		want   PriceChangeStatus
+	}{
+		{name: "scheduled", change: PriceChange{StartsAt: future}, want: PriceChangeScheduled},
+		{name: "started without end", change: PriceChange{StartsAt: past}, want: PriceChangeActive},
+		{name: "running promotion", change: PriceChange{StartsAt: past, EndsAt: &future}, want: PriceChangeActive},
+		{name: "ended promotion", change: PriceChange{StartsAt: past.Add(-time.Hour), EndsAt: &past}, want: PriceChangeExpired},
+		{name: "applied", change: PriceChange{StartsAt: past, AppliedAt: &past}, want: PriceChangeApplied},
+		{name: "canceled", change: PriceChange{StartsAt: future, CanceledAt: &past}, want: PriceChangeCanceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.change.Status(now); got != tt.want {
+				t.Errorf("Status() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPriceChangeValidate(t *testing.T) {
+	now := time.Date(2030, 1, 15, 0, 0, 0, 0, time.UTC)
+	past := now.Add(-24 * time.Hour)
+	future := now.Add(24 * time.Hour)
+	price, _ := ParseMoney("10", "USD")
+
+	tests := []struct {
+		name    string
+		change  PriceChange
+		wantErr bool
+	}{
+		{name: "scheduled price", change: PriceChange{Price: price, StartsAt: future}},
+		{name: "promotion", change: PriceChange{Price: price, StartsAt: now, EndsAt: &future}},
+		{name: "non positive price", change: PriceChange{Price: Money{Currency: "USD"}, StartsAt: future}, wantErr: true},
+		{name: "ends before it starts", change: PriceChange{Price: price, StartsAt: future, EndsAt: &now}, wantErr: true},
+		{name: "already ended", change: PriceChange{Price: price, StartsAt: past.Add(-time.Hour), EndsAt: &past}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.change.Validate(now); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
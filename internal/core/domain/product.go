@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,17 +22,79 @@ func (s ProductStatus) String() string {
 	return string(s)
 }
 
-func ParseProductStatus(status string) ProductStatus {
-	switch status {
-	case "Available":
-		return StatusAvailable
-	case "On Order":
-		return StatusOnOrDer
-	case "Out of Stock":
-		return StatusOutOfStock
-	default:
-		return StatusUnknown
+// ParseProductStatus parses a status name, ignoring case and surrounding spaces.
+// An empty name is StatusUnknown and any other name returns ErrInvalidStatus
+func ParseProductStatus(status string) (ProductStatus, error) {
+	status = strings.TrimSpace(status)
+	if status == "" {
+		return StatusUnknown, nil
 	}
+
+	for _, s := range []ProductStatus{StatusAvailable, StatusOnOrDer, StatusOutOfStock} {
+		if strings.EqualFold(status, string(s)) {
+			return s, nil
+		}
+	}
+
+	return StatusUnknown, ErrInvalidStatus
+}
+
+// statusTransitions lists the statuses a product can move to from each status.
+// Stock is only available in StatusAvailable, a product out of stock can be ordered
+// and an order can be canceled back to StatusOutOfStock
+var statusTransitions = map[ProductStatus][]ProductStatus{
+	StatusAvailable:  {StatusOutOfStock},
+	StatusOutOfStock: {StatusAvailable, StatusOnOrDer},
+	StatusOnOrDer:    {StatusAvailable, StatusOutOfStock},
+}
+
+// CanTransitionTo reports whether a product can move from the status to the next one.
+// Any status can be set on a new product, whose status is StatusUnknown
+func (s ProductStatus) CanTransitionTo(next ProductStatus) bool {
+	if s == StatusUnknown || s == next {
+		return true
+	}
+
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextProductStatus resolves the status of a product moving from the current status to the requested one
+// with the given quantity. Without a requested status the quantity drives the status: running out of stock
+// sets StatusOutOfStock and restocking sets StatusAvailable. A requested status that is not allowed from
+// the current one, or that does not match the quantity, returns ErrInvalidStatus
+func NextProductStatus(current, requested ProductStatus, quantity int) (ProductStatus, error) {
+	if current == "" {
+		current = StatusUnknown
+	}
+	if requested == "" {
+		requested = StatusUnknown
+	}
+
+	next := requested
+	if next == StatusUnknown {
+		next = current
+		switch {
+		case quantity > 0:
+			next = StatusAvailable
+		case next == StatusAvailable || next == StatusUnknown:
+			next = StatusOutOfStock
+		}
+	}
+
+	if (next == StatusAvailable) != (quantity > 0) {
+		return current, ErrInvalidStatus
+	}
+	if !current.CanTransitionTo(next) {
+		return current, ErrInvalidStatus
+	}
+
+	return next, nil
 }
 
 // Product is an entity that represents a product
@@ -40,10 +105,123 @@ type Product struct {
 	AddedDate  time.Time
 	Status     ProductStatus
 	CategoryID *uuid.UUID
-	Price      float64
-	StockCity  string
-	SupplierID *uuid.UUID
-	Quantity   int
+	// Price is the base price, EffectivePrice also accounts for the running promotion
+	Price     Money
+	StockCity string
+	// StockCoordinates are the geocoded coordinates of the stock city, nil when it could not be located
+	StockCoordinates *Coordinates
+	SupplierID       *uuid.UUID
+	Quantity         int
+	DeletedAt        *time.Time
+
+	EffectivePrice Money
+	Category       *Category
+	Supplier       *Supplier
+	// Stocks is the stock of the product per warehouse, Quantity is the total stock
+	Stocks []WarehouseStock
+	// DistanceKM is the distance from the origin of a listing near a location to the closest of
+	// the warehouses holding the product and its stock city, nil when none of them is located
+	DistanceKM *float64
+}
+
+// ProductSort is the order of a list of products, made of comma separated fields among ProductSortFields, each
+// descending when prefixed with "-", such as "-added_date,name". Products are listed newest first by default
+type ProductSort string
+
+const (
+	// ProductSortDefault orders products newest first
+	ProductSortDefault ProductSort = ""
+	// ProductSortDistance orders products by distance from the origin of the listing, the products without a distance last
+	ProductSortDistance ProductSort = "distance"
+)
+
+// ProductSortFields are the fields products can be sorted by. Sorting by distance needs the origin of a listing
+// near a location, the products without a distance coming last in either direction
+var ProductSortFields = []string{
+	"id",
+	"reference",
+	"name",
+	"added_date",
+	"status",
+	"price",
+	"quantity",
+	"stock_city",
+	"distance",
+}
+
+// productSortDefault is the order of products when none is given, newest first
+var productSortDefault = []SortKey{{Field: "added_date", Desc: true}}
+
+// ParseProductSort parses a product sort, returning it normalized or ErrInvalidSort for a field that can not be sorted by
+func ParseProductSort(sort string) (ProductSort, error) {
+	keys, err := parseSort(sort, ProductSortFields)
+	if err != nil {
+		return ProductSortDefault, err
+	}
+	return ProductSort(formatSort(keys)), nil
+}
+
+// Keys returns the keys the products are sorted by, ending with the id so that the order is total
+func (s ProductSort) Keys() []SortKey {
+	return sortKeys(string(s), ProductSortFields, productSortDefault)
+}
+
+// SortsBy reports whether the products are sorted by the field
+func (s ProductSort) SortsBy(field string) bool {
+	return slices.ContainsFunc(s.Keys(), func(key SortKey) bool { return key.Field == field })
+}
+
+// NewProductCursor returns the cursor of a page of products sorted by the sort whose last product is the given one
+func NewProductCursor(sort ProductSort, product *Product) *Cursor {
+	keys := sort.Keys()
+	cursor := &Cursor{Sort: string(sort), Values: make([]*string, len(keys))}
+	for i, key := range keys {
+		cursor.Values[i] = productSortValue(product, key.Field)
+	}
+	return cursor
+}
+
+// productSortValue returns the value of a sort field of a product as text, nil for a product without a distance
+func productSortValue(product *Product, field string) *string {
+	var value string
+	switch field {
+	case "id":
+		value = product.ID.String()
+	case "reference":
+		value = product.Reference
+	case "name":
+		value = product.Name
+	case "added_date":
+		value = product.AddedDate.Format(time.RFC3339Nano)
+	case "status":
+		value = string(product.Status)
+	case "price":
+		value = product.Price.Amount.String()
+	case "quantity":
+		value = strconv.Itoa(product.Quantity)
+	case "stock_city":
+		value = product.StockCity
+	case "distance":
+		if product.DistanceKM == nil {
+			return nil
+		}
+		value = strconv.FormatFloat(*product.DistanceKM, 'g', -1, 64)
+	}
+	return &value
+}
 
-	Category *Category
+// ProductFilter holds the criteria to filter a list of products
+type ProductFilter struct {
+	Search         string
+	CategoryIDs    []uuid.UUID
+	WarehouseIDs   []uuid.UUID
+	IncludeDeleted bool
+	// Near is the origin the distance of the products is computed from, NearIP is located when Near is not given
+	Near   *Coordinates
+	NearIP string
+	// RadiusKM keeps the products within that distance of the origin, zero keeps them all
+	RadiusKM float64
+	Sort     ProductSort
+	// After lists the products after the cursor in the order of the sort, for a page of a keyset pagination
+	After *Cursor
 }
@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseProductStatus(t *testing.T) {
+	tests := []struct {
+		status  string
+		want    ProductStatus
+		wantErr error
+	}{
+		{status: "", want: StatusUnknown},
+		{status: "Available", want: StatusAvailable},
+		{status: " on order ", want: StatusOnOrDer},
+		{status: "OUT OF STOCK", want: StatusOutOfStock},
+		{status: "Availabel", want: StatusUnknown, wantErr: ErrInvalidStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			got, err := ParseProductStatus(tt.status)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseProductStatus() = %v, %v, want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNextProductStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   ProductStatus
+		requested ProductStatus
+		quantity  int
+		want      ProductStatus
+		wantErr   error
+	}{
+		{name: "new with stock", current: StatusUnknown, quantity: 5, want: StatusAvailable},
+		{name: "new without stock", current: StatusUnknown, quantity: 0, want: StatusOutOfStock},
+		{name: "new on order", current: StatusUnknown, requested: StatusOnOrDer, quantity: 0, want: StatusOnOrDer},
+		{name: "new available without stock", current: StatusUnknown, requested: StatusAvailable, quantity: 0, wantErr: ErrInvalidStatus},
+		{name: "running out of stock", current: StatusAvailable, quantity: 0, want: StatusOutOfStock},
+		{name: "restocking an order", current: StatusOnOrDer, quantity: 10, want: StatusAvailable},
+		{name: "restocking", current: StatusOutOfStock, quantity: 3, want: StatusAvailable},
+		{name: "waiting for an order", current: StatusOnOrDer, quantity: 0, want: StatusOnOrDer},
+		{name: "ordering", current: StatusOutOfStock, requested: StatusOnOrDer, quantity: 0, want: StatusOnOrDer},
+		{name: "canceling an order", current: StatusOnOrDer, requested: StatusOutOfStock, quantity: 0, want: StatusOutOfStock},
+		{name: "ordering with stock", current: StatusAvailable, requested: StatusOnOrDer, quantity: 5, wantErr: ErrInvalidStatus},
+		{name: "out of stock with stock", current: StatusAvailable, requested: StatusOutOfStock, quantity: 5, wantErr: ErrInvalidStatus},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NextProductStatus(tt.current, tt.requested, tt.quantity)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NextProductStatus() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("NextProductStatus() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
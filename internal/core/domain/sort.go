@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// SortKey is a field a list is sorted by, in ascending order unless Desc
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// parseSort parses a sort made of comma separated fields, each descending when prefixed with "-", such as
+// "-added_date,name". It returns ErrInvalidSort for a field that is not sortable or that is repeated
+func parseSort(sort string, sortable []string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, name := range strings.Split(sort, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		key := SortKey{
+			Field: strings.ToLower(strings.TrimPrefix(name, "-")),
+			Desc:  strings.HasPrefix(name, "-"),
+		}
+		if !slices.Contains(sortable, key.Field) {
+			return nil, fmt.Errorf("%w: %q is not one of %s", ErrInvalidSort, key.Field, strings.Join(sortable, ", "))
+		}
+		if slices.ContainsFunc(keys, func(k SortKey) bool { return k.Field == key.Field }) {
+			return nil, fmt.Errorf("%w: %q is sorted twice", ErrInvalidSort, key.Field)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// formatSort formats sort keys as parseSort reads them
+func formatSort(keys []SortKey) string {
+	fields := make([]string, len(keys))
+	for i, key := range keys {
+		fields[i] = key.Field
+		if key.Desc {
+			fields[i] = "-" + key.Field
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// sortKeys returns the keys of a valid sort, the default keys when it is empty. The id is added as the last key
+// when the sort does not hold it, in the direction of the key before it, so that the order is total
+func sortKeys(sort string, sortable []string, defaults []SortKey) []SortKey {
+	keys, err := parseSort(sort, sortable)
+	if err != nil || len(keys) == 0 {
+		keys = slices.Clone(defaults)
+	}
+
+	if !slices.ContainsFunc(keys, func(k SortKey) bool { return k.Field == "id" }) {
+		keys = append(keys, SortKey{Field: "id", Desc: keys[len(keys)-1].Desc})
+	}
+	return keys
+}
+
+// Cursor is the position of the last item of a page of a sorted list, the next page starting after it
+type Cursor struct {
+	// Sort is the sort of the list, a cursor only continues the list it was made for
+	Sort string `json:"sort"`
+	// Values are the values of the sort keys of the item as text, nil for a null value
+	Values []*string `json:"values"`
+}
+
+// Encode encodes the cursor as an opaque string safe in a url
+func (c *Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor decodes a cursor continuing a list with the given sort and number of sort keys,
+// returning ErrInvalidCursor for a malformed cursor or a cursor made for another sort
+func DecodeCursor(s string, sort string, keys int) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.Sort != sort || len(cursor.Values) != keys {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
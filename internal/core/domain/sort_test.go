@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseProductSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		sort    string
+		want    ProductSort
+		wantErr error
+	}{
+		{name: "empty"},
+		{name: "single field", sort: "distance", want: ProductSortDistance},
+		{name: "several fields", sort: "-added_date,name", want: "-added_date,name"},
+		{name: "blank and mixed case names", sort: " -Added_Date,, NAME ", want: "-added_date,name"},
+		{name: "unknown field", sort: "name,colour", wantErr: ErrInvalidSort},
+		{name: "not sortable field", sort: "category_id", wantErr: ErrInvalidSort},
+		{name: "repeated field", sort: "name,-name", wantErr: ErrInvalidSort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProductSort(tt.sort)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseProductSort() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseProductSort() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductSortKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		sort ProductSort
+		want []SortKey
+	}{
+		{
+			name: "newest first by default",
+			sort: ProductSortDefault,
+			want: []SortKey{{Field: "added_date", Desc: true}, {Field: "id", Desc: true}},
+		},
+		{
+			name: "id added in the direction of the last key",
+			sort: "-added_date,name",
+			want: []SortKey{{Field: "added_date", Desc: true}, {Field: "name"}, {Field: "id"}},
+		},
+		{
+			name: "id kept",
+			sort: "-id,name",
+			want: []SortKey{{Field: "id", Desc: true}, {Field: "name"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sort.Keys(); !slices.Equal(got, tt.want) {
+				t.Errorf("Keys() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductCursor(t *testing.T) {
+	distance := 1.5
+	product := &Product{
+		ID:         uuid.MustParse("6b7a4b0e-3f0a-4c8e-9a51-0e4b8b2f6d2a"),
+		Name:       "Rice",
+		AddedDate:  time.Date(2024, 5, 1, 8, 30, 0, 123, time.UTC),
+		DistanceKM: &distance,
+	}
+
+	sort := ProductSort("-added_date,name,distance")
+	cursor := NewProductCursor(sort, product)
+
+	got, err := DecodeCursor(cursor.Encode(), string(sort), len(sort.Keys()))
+	if err != nil {
+		t.Fatalf("DecodeCursor() error = %v", err)
+	}
+
+	want := []string{"2024-05-01T08:30:00.000000123Z", "Rice", "1.5", product.ID.String()}
+	if len(got.Values) != len(want) {
+		t.Fatalf("DecodeCursor() values = %d, want %d", len(got.Values), len(want))
+	}
+	for i := range want {
+		if got.Values[i] == nil || *got.Values[i] != want[i] {
+			t.Errorf("DecodeCursor() value %d = %v, want %q", i, got.Values[i], want[i])
+		}
+	}
+
+	product.DistanceKM = nil
+	if value := NewProductCursor(sort, product).Values[2]; value != nil {
+		t.Errorf("NewProductCursor() distance = %q, want nil", *value)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	cursor := NewCategoryCursor("name", &Category{ID: uuid.New(), Name: "Foods"}).Encode()
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   string
+		keys   int
+	}{
+		{name: "malformed", cursor: "not a cursor", sort: "name", keys: 2},
+		{name: "not json", cursor: "bm90IGpzb24", sort: "name", keys: 2},
+		{name: "other sort", cursor: cursor, sort: "-name", keys: 2},
+		{name: "other number of keys", cursor: cursor, sort: "name", keys: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.cursor, tt.sort, tt.keys); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor() error = %v, want %v", err, ErrInvalidCursor)
+			}
+		})
+	}
+}
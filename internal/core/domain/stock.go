@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StockMovementType is the kind of event that changed the stock of a product
+type StockMovementType string
+
+const (
+	// StockReceipt, StockSale and StockReturn always move a positive number of units, in or out of stock
+	StockReceipt StockMovementType = "receipt"
+	StockSale    StockMovementType = "sale"
+	StockReturn  StockMovementType = "return"
+	// StockAdjustment and StockTransfer move units in either direction, their quantity carries the sign
+	StockAdjustment StockMovementType = "adjustment"
+	StockTransfer   StockMovementType = "transfer"
+)
+
+// ParseStockMovementType parses a movement type name, ignoring case and surrounding spaces
+func ParseStockMovementType(movementType string) (StockMovementType, error) {
+	t := StockMovementType(strings.ToLower(strings.TrimSpace(movementType)))
+	switch t {
+	case StockReceipt, StockSale, StockReturn, StockAdjustment, StockTransfer:
+		return t, nil
+	default:
+		return "", ErrInvalidStockMovement
+	}
+}
+
+// StockMovement is an entity that represents an entry of the append-only stock ledger of a product.
+// Quantity is the signed change of stock and Balance the total stock of the product once the movement is applied.
+// A movement without a warehouse changes the stock not held by any warehouse, the two movements of a transfer
+// between warehouses share a transfer id
+type StockMovement struct {
+	ID          uuid.UUID
+	ProductID   uuid.UUID
+	WarehouseID *uuid.UUID
+	TransferID  *uuid.UUID
+	Type        StockMovementType
+	Quantity    int
+	Balance     int
+	Reason      string
+	Actor       string
+	RequestID   string
+	CreatedAt   time.Time
+}
+
+// NewStockMovement creates a movement of a product from the number of units moved.
+// Units of a sale leave the stock, units of a receipt or a return enter it and
+// the sign of the units of an adjustment or a transfer gives their direction
+func NewStockMovement(productID uuid.UUID, movementType StockMovementType, units int, reason string) (*StockMovement, error) {
+	movement := &StockMovement{
+		ProductID: productID,
+		Type:      movementType,
+		Quantity:  units,
+		Reason:    strings.TrimSpace(reason),
+	}
+
+	switch movementType {
+	case StockReceipt, StockReturn:
+		if units <= 0 {
+			return nil, ErrInvalidStockMovement
+		}
+	case StockSale:
+		if units <= 0 {
+			return nil, ErrInvalidStockMovement
+		}
+		movement.Quantity = -units
+	case StockAdjustment, StockTransfer:
+		if units == 0 || movement.Reason == "" {
+			return nil, ErrInvalidStockMovement
+		}
+	default:
+		return nil, ErrInvalidStockMovement
+	}
+
+	return movement, nil
+}
+
+// Apply returns the stock left once the movement is applied to the given stock and sets it as the balance.
+// A movement taking out more units than in stock returns ErrInsufficientStock
+func (sm *StockMovement) Apply(stock int) (int, error) {
+	balance := stock + sm.Quantity
+	if balance < 0 {
+		return stock, ErrInsufficientStock
+	}
+
+	sm.Balance = balance
+	return balance, nil
+}
+
+// StockMovementFilter holds the criteria to filter the movement history of a product
+type StockMovementFilter struct {
+	Types       []StockMovementType
+	WarehouseID *uuid.UUID
+	From        *time.Time
+	To          *time.Time
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestNewStockMovement(t *testing.T) {
+	tests := []struct {
+		name         string
+		movementType StockMovementType
+		units        int
+		reason       string
+		want         int
+		wantErr      error
+	}{
+		{name: "receipt", movementType: StockReceipt, units: 10, want: 10},
+		{name: "sale", movementType: StockSale, units: 3, want: -3},
+		{name: "return", movementType: StockReturn, units: 1, want: 1},
+		{name: "adjustment out", movementType: StockAdjustment, units: -2, reason: "damaged", want: -2},
+		{name: "transfer in", movementType: StockTransfer, units: 4, reason: "from Hanoi", want: 4},
+		{name: "negative receipt", movementType: StockReceipt, units: -1, wantErr: ErrInvalidStockMovement},
+		{name: "negative sale", movementType: StockSale, units: -1, wantErr: ErrInvalidStockMovement},
+		{name: "adjustment without reason", movementType: StockAdjustment, units: 2, reason: " ", wantErr: ErrInvalidStockMovement},
+		{name: "empty transfer", movementType: StockTransfer, units: 0, reason: "from Hanoi", wantErr: ErrInvalidStockMovement},
+		{name: "unknown type", movementType: "gift", units: 1, wantErr: ErrInvalidStockMovement},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewStockMovement(uuid.New(), tt.movementType, tt.units, tt.reason)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("NewStockMovement() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got.Quantity != tt.want {
+				t.Errorf("NewStockMovement().Quantity = %v, want %v", got.Quantity, tt.want)
+			}
+		})
+	}
+}
+
+func TestStockMovementApply(t *testing.T) {
+	tests := []struct {
+		name     string
+		stock    int
+		quantity int
+		want     int
+		wantErr  error
+	}{
+		{name: "receipt", stock: 0, quantity: 10, want: 10},
+		{name: "sale", stock: 5, quantity: -5, want: 0},
+		{name: "oversold", stock: 2, quantity: -3, want: 2, wantErr: ErrInsufficientStock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movement := StockMovement{Quantity: tt.quantity}
+			got, err := movement.Apply(tt.stock)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("Apply() = %v, %v, want %v, %v", got, err, tt.want, tt.wantErr)
+			}
+			if err == nil && movement.Balance != tt.want {
+				t.Errorf("Apply() balance = %v, want %v", movement.Balance, tt.want)
+			}
+		})
+	}
+}
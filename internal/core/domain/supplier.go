@@ -0,0 +1,11 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+// Supplier is an entity that represents a supplier of products
+type Supplier struct {
+	ID   uuid.UUID
+	Name string
+}
@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ProductField is a field of a product that can be selected
+type ProductField string
+
+const (
+	ProductFieldID        ProductField = "id"
+	ProductFieldReference ProductField = "reference"
+	ProductFieldName      ProductField = "name"
+	ProductFieldAddedDate ProductField = "added_date"
+	ProductFieldStatus    ProductField = "status"
+	// ProductFieldCategoryID is the id of the category, the category itself is a relation
+	ProductFieldCategoryID ProductField = "category_id"
+	ProductFieldPrice      ProductField = "price"
+	// ProductFieldEffectivePrice is the price the product sells at, read from its base price and price changes
+	ProductFieldEffectivePrice ProductField = "effective_price"
+	ProductFieldStockCity      ProductField = "stock_city"
+	ProductFieldSupplierID     ProductField = "supplier_id"
+	ProductFieldQuantity       ProductField = "quantity"
+	// ProductFieldDistanceKM is the distance from the origin of a listing near a location
+	ProductFieldDistanceKM ProductField = "distance_km"
+	ProductFieldDeletedAt  ProductField = "deleted_at"
+)
+
+// ProductFields are the fields of a product that can be selected, in the order they are represented
+var ProductFields = []ProductField{
+	ProductFieldID,
+	ProductFieldReference,
+	ProductFieldName,
+	ProductFieldAddedDate,
+	ProductFieldStatus,
+	ProductFieldCategoryID,
+	ProductFieldPrice,
+	ProductFieldEffectivePrice,
+	ProductFieldStockCity,
+	ProductFieldSupplierID,
+	ProductFieldQuantity,
+	ProductFieldDistanceKM,
+	ProductFieldDeletedAt,
+}
+
+// ProductRelation is an object related to a product that can be embedded with it
+type ProductRelation string
+
+const (
+	ProductRelationCategory ProductRelation = "category"
+	ProductRelationSupplier ProductRelation = "supplier"
+	// ProductRelationWarehouses is the stock of the product in each warehouse holding some
+	ProductRelationWarehouses ProductRelation = "warehouses"
+)
+
+// ProductRelations are the relations that can be embedded with a product
+var ProductRelations = []ProductRelation{
+	ProductRelationCategory,
+	ProductRelationSupplier,
+	ProductRelationWarehouses,
+}
+
+// ProductView selects the fields of the products represented and the related objects embedded with them.
+// A list of products only reads the columns of the fields, the products got by id are read whole as they are
+// cached whole. A nil view reads the whole products
+type ProductView struct {
+	// Fields are the selected fields, all of them when empty. The id is always selected
+	Fields  []ProductField
+	Include []ProductRelation
+}
+
+// ParseProductView parses comma separated lists of fields and relations, returning ErrInvalidProductView
+// for an unknown one. Blank and repeated names are skipped
+func ParseProductView(fields, include string) (ProductView, error) {
+	var view ProductView
+
+	for _, name := range strings.Split(fields, ",") {
+		field := ProductField(strings.ToLower(strings.TrimSpace(name)))
+		switch {
+		case field == "", slices.Contains(view.Fields, field):
+		case !slices.Contains(ProductFields, field):
+			return ProductView{}, fmt.Errorf("%w: unknown field %q", ErrInvalidProductView, name)
+		default:
+			view.Fields = append(view.Fields, field)
+		}
+	}
+
+	for _, name := range strings.Split(include, ",") {
+		relation := ProductRelation(strings.ToLower(strings.TrimSpace(name)))
+		switch {
+		case relation == "", slices.Contains(view.Include, relation):
+		case !slices.Contains(ProductRelations, relation):
+			return ProductView{}, fmt.Errorf("%w: unknown relation %q", ErrInvalidProductView, name)
+		default:
+			view.Include = append(view.Include, relation)
+		}
+	}
+
+	return view, nil
+}
+
+// Selects reports whether the field is selected. A nil view selects every field
+func (v *ProductView) Selects(field ProductField) bool {
+	if v == nil || len(v.Fields) == 0 || field == ProductFieldID {
+		return true
+	}
+	return slices.Contains(v.Fields, field)
+}
+
+// Includes reports whether the relation is embedded. A nil view embeds none of them,
+// the whole products being read with their category and warehouse stocks as before views
+func (v *ProductView) Includes(relation ProductRelation) bool {
+	return v != nil && slices.Contains(v.Include, relation)
+}
+
+// Reads reports whether the field is read, because it is selected or needed by a selected field or an embedded relation
+func (v *ProductView) Reads(field ProductField) bool {
+	if v.Selects(field) {
+		return true
+	}
+
+	switch field {
+	case ProductFieldPrice:
+		// The effective price falls back to the base price
+		return v.Selects(ProductFieldEffectivePrice)
+	case ProductFieldCategoryID:
+		return v.Includes(ProductRelationCategory)
+	case ProductFieldSupplierID:
+		return v.Includes(ProductRelationSupplier)
+	}
+	return false
+}
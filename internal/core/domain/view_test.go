@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParseProductView(t *testing.T) {
+	tests := []struct {
+		name    string
+		fields  string
+		include string
+		want    ProductView
+		wantErr error
+	}{
+		{name: "empty"},
+		{
+			name:    "fields and relations",
+			fields:  "id,name,price",
+			include: "category,warehouses",
+			want: ProductView{
+				Fields:  []ProductField{ProductFieldID, ProductFieldName, ProductFieldPrice},
+				Include: []ProductRelation{ProductRelationCategory, ProductRelationWarehouses},
+			},
+		},
+		{
+			name:    "blank, repeated and mixed case names",
+			fields:  " Name,,name , EFFECTIVE_PRICE",
+			include: "supplier,Supplier,",
+			want: ProductView{
+				Fields:  []ProductField{ProductFieldName, ProductFieldEffectivePrice},
+				Include: []ProductRelation{ProductRelationSupplier},
+			},
+		},
+		{name: "unknown field", fields: "id,colour", wantErr: ErrInvalidProductView},
+		{name: "relation as a field", fields: "category", wantErr: ErrInvalidProductView},
+		{name: "unknown relation", include: "orders", wantErr: ErrInvalidProductView},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProductView(tt.fields, tt.include)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseProductView() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(got.Fields, tt.want.Fields) || !slices.Equal(got.Include, tt.want.Include) {
+				t.Errorf("ParseProductView() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProductViewReads(t *testing.T) {
+	sparse := &ProductView{
+		Fields:  []ProductField{ProductFieldName, ProductFieldEffectivePrice},
+		Include: []ProductRelation{ProductRelationCategory},
+	}
+
+	tests := []struct {
+		name  string
+		view  *ProductView
+		field ProductField
+		want  bool
+	}{
+		{name: "nil view", view: nil, field: ProductFieldQuantity, want: true},
+		{name: "all fields", view: &ProductView{}, field: ProductFieldDeletedAt, want: true},
+		{name: "selected", view: sparse, field: ProductFieldName, want: true},
+		{name: "id always", view: sparse, field: ProductFieldID, want: true},
+		{name: "not selected", view: sparse, field: ProductFieldQuantity, want: false},
+		{name: "price for the effective price", view: sparse, field: ProductFieldPrice, want: true},
+		{name: "category id for the category", view: sparse, field: ProductFieldCategoryID, want: true},
+		{name: "supplier id without the supplier", view: sparse, field: ProductFieldSupplierID, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.view.Reads(tt.field); got != tt.want {
+				t.Errorf("Reads(%s) = %v, want %v", tt.field, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Coordinates is a position on Earth in decimal degrees
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Validate checks that the coordinates are within the range of latitudes and longitudes
+func (c Coordinates) Validate() error {
+	if c.Latitude < -90 || c.Latitude > 90 || c.Longitude < -180 || c.Longitude > 180 {
+		return ErrInvalidCoordinates
+	}
+	return nil
+}
+
+// earthRadiusKM is the mean radius of the Earth in kilometers
+const earthRadiusKM = 6371
+
+// DistanceKM returns the great-circle distance in kilometers to other coordinates with the haversine formula
+func (c Coordinates) DistanceKM(other Coordinates) float64 {
+	lat1 := c.Latitude * math.Pi / 180
+	lat2 := other.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (other.Longitude - c.Longitude) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKM * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// ParseCoordinates parses coordinates written as "latitude,longitude", such as "21.0285,105.8542"
+func ParseCoordinates(s string) (Coordinates, error) {
+	lat, lon, ok := strings.Cut(s, ",")
+	if !ok {
+		return Coordinates{}, ErrInvalidCoordinates
+	}
+
+	latitude, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return Coordinates{}, ErrInvalidCoordinates
+	}
+	longitude, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil {
+		return Coordinates{}, ErrInvalidCoordinates
+	}
+
+	c := Coordinates{Latitude: latitude, Longitude: longitude}
+	return c, c.Validate()
+}
+
+// BoundingBox is a range of latitudes and longitudes, used to prefilter positions before computing distances
+type BoundingBox struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+// BoundingBox returns the smallest box holding every position within the radius of the coordinates.
+// A box reaching a pole or crossing the antimeridian spans all the longitudes
+func (c Coordinates) BoundingBox(radiusKM float64) BoundingBox {
+	angle := radiusKM / earthRadiusKM
+	lat := c.Latitude * math.Pi / 180
+
+	box := BoundingBox{
+		MinLatitude:  math.Max(-90, c.Latitude-angle*180/math.Pi),
+		MaxLatitude:  math.Min(90, c.Latitude+angle*180/math.Pi),
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+	if box.MinLatitude == -90 || box.MaxLatitude == 90 {
+		return box
+	}
+
+	dLon := math.Asin(math.Min(1, math.Sin(angle)/math.Cos(lat))) * 180 / math.Pi
+	if c.Longitude-dLon >= -180 && c.Longitude+dLon <= 180 {
+		box.MinLongitude = c.Longitude - dLon
+		box.MaxLongitude = c.Longitude + dLon
+	}
+
+	return box
+}
+
+// Warehouse is an entity that represents a place products are stocked in.
+// Warehouses created from the former stock city of products have no coordinates until they are set
+type Warehouse struct {
+	ID          uuid.UUID
+	Name        string
+	City        string
+	Coordinates *Coordinates
+	CreatedAt   time.Time
+}
+
+// Validate checks that the warehouse has a name, a city and valid coordinates when they are set
+func (w *Warehouse) Validate() error {
+	w.Name = strings.TrimSpace(w.Name)
+	w.City = strings.TrimSpace(w.City)
+	if w.Name == "" || w.City == "" {
+		return ErrMissingRequiredData
+	}
+	if w.Coordinates != nil {
+		return w.Coordinates.Validate()
+	}
+	return nil
+}
+
+// WarehouseStock is the stock of a product in a warehouse
+type WarehouseStock struct {
+	ProductID uuid.UUID
+	Warehouse Warehouse
+	Quantity  int
+}
+
+// StockLevels is the stock of a product, in total and per warehouse id. The part of the total
+// stock that is not held by any warehouse, such as stock recorded before warehouses existed, is unassigned
+type StockLevels struct {
+	Total      int
+	Warehouses map[uuid.UUID]int
+}
+
+// NewStockLevels creates the stock levels of a product from its total stock and its warehouse stocks
+func NewStockLevels(total int, stocks []WarehouseStock) StockLevels {
+	levels := StockLevels{
+		Total:      total,
+		Warehouses: make(map[uuid.UUID]int, len(stocks)),
+	}
+	for _, stock := range stocks {
+		levels.Warehouses[stock.Warehouse.ID] = stock.Quantity
+	}
+	return levels
+}
+
+// Unassigned returns the part of the total stock that is not held by any warehouse
+func (sl *StockLevels) Unassigned() int {
+	unassigned := sl.Total
+	for _, quantity := range sl.Warehouses {
+		unassigned -= quantity
+	}
+	return unassigned
+}
+
+// Apply applies a movement to the stock of its warehouse, or to the unassigned stock when it has none,
+// and to the total stock. A movement taking out more units than its warehouse or the unassigned
+// stock holds returns ErrInsufficientStock and leaves the levels unchanged
+func (sl *StockLevels) Apply(movement *StockMovement) error {
+	if movement.WarehouseID == nil {
+		if sl.Unassigned()+movement.Quantity < 0 {
+			return ErrInsufficientStock
+		}
+	} else if sl.Warehouses[*movement.WarehouseID]+movement.Quantity < 0 {
+		return ErrInsufficientStock
+	}
+
+	total, err := movement.Apply(sl.Total)
+	if err != nil {
+		return err
+	}
+
+	sl.Total = total
+	if movement.WarehouseID != nil {
+		sl.Warehouses[*movement.WarehouseID] += movement.Quantity
+	}
+	return nil
+}
+
+// WarehouseTransfer moves units of a product from one warehouse to another
+type WarehouseTransfer struct {
+	ID              uuid.UUID
+	ProductID       uuid.UUID
+	FromWarehouseID uuid.UUID
+	ToWarehouseID   uuid.UUID
+	Quantity        int
+	Reason          string
+}
+
+// Movements returns the pair of transfer movements taking the units out of the source warehouse
+// and into the destination warehouse, both carrying the id of the transfer
+func (wt *WarehouseTransfer) Movements() ([]StockMovement, error) {
+	if wt.Quantity <= 0 || wt.FromWarehouseID == wt.ToWarehouseID {
+		return nil, ErrInvalidStockMovement
+	}
+
+	transferID := wt.ID
+	out := StockMovement{
+		ProductID:   wt.ProductID,
+		WarehouseID: &wt.FromWarehouseID,
+		TransferID:  &transferID,
+		Type:        StockTransfer,
+		Quantity:    -wt.Quantity,
+		Reason:      strings.TrimSpace(wt.Reason),
+	}
+	in := out
+	in.WarehouseID = &wt.ToWarehouseID
+	in.Quantity = wt.Quantity
+
+	return []StockMovement{out, in}, nil
+}
+
+// WarehouseAvailability is the stock of a product in a warehouse and the distance to the warehouse.
+// DistanceKM is nil for a warehouse without coordinates
+type WarehouseAvailability struct {
+	WarehouseStock
+	DistanceKM *float64
+}
+
+// ProductAvailability is the stock of a product in the warehouses holding some, nearest to the origin first
+type ProductAvailability struct {
+	ProductID  uuid.UUID
+	Origin     Coordinates
+	Total      int
+	Warehouses []WarehouseAvailability
+}
+
+// NewProductAvailability ranks the warehouses holding stock of a product by distance to the origin.
+// Warehouses without coordinates can not be ranked and come last, by name
+func NewProductAvailability(productID uuid.UUID, origin Coordinates, stocks []WarehouseStock) *ProductAvailability {
+	availability := &ProductAvailability{
+		ProductID:  productID,
+		Origin:     origin,
+		Warehouses: make([]WarehouseAvailability, 0, len(stocks)),
+	}
+
+	for _, stock := range stocks {
+		if stock.Quantity <= 0 {
+			continue
+		}
+
+		item := WarehouseAvailability{WarehouseStock: stock}
+		if stock.Warehouse.Coordinates != nil {
+			distance := origin.DistanceKM(*stock.Warehouse.Coordinates)
+			item.DistanceKM = &distance
+		}
+
+		availability.Total += stock.Quantity
+		availability.Warehouses = append(availability.Warehouses, item)
+	}
+
+	sort.SliceStable(availability.Warehouses, func(i, j int) bool {
+		a, b := availability.Warehouses[i], availability.Warehouses[j]
+		if a.DistanceKM == nil || b.DistanceKM == nil {
+			if a.DistanceKM != nil || b.DistanceKM != nil {
+				return a.DistanceKM != nil
+			}
+			return a.Warehouse.Name < b.Warehouse.Name
+		}
+		return *a.DistanceKM < *b.DistanceKM
+	})
+
+	return availability
+}
@@ -0,0 +1,173 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestStockLevelsApply(t *testing.T) {
+	hanoi, saigon := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name           string
+		warehouse      *uuid.UUID
+		quantity       int
+		wantTotal      int
+		wantWarehouse  int
+		wantUnassigned int
+		wantErr        error
+	}{
+		{name: "receipt in a warehouse", warehouse: &hanoi, quantity: 5, wantTotal: 17, wantWarehouse: 13, wantUnassigned: 4},
+		{name: "sale from a warehouse", warehouse: &hanoi, quantity: -8, wantTotal: 4, wantWarehouse: 0, wantUnassigned: 4},
+		{name: "oversold warehouse", warehouse: &hanoi, quantity: -9, wantTotal: 12, wantWarehouse: 8, wantUnassigned: 4, wantErr: ErrInsufficientStock},
+		{name: "receipt in a new warehouse", warehouse: &saigon, quantity: 3, wantTotal: 15, wantUnassigned: 4},
+		{name: "sale from unassigned stock", quantity: -4, wantTotal: 8, wantWarehouse: 8, wantUnassigned: 0},
+		{name: "oversold unassigned stock", quantity: -5, wantTotal: 12, wantWarehouse: 8, wantUnassigned: 4, wantErr: ErrInsufficientStock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels := NewStockLevels(12, []WarehouseStock{{Warehouse: Warehouse{ID: hanoi}, Quantity: 8}})
+			movement := StockMovement{WarehouseID: tt.warehouse, Quantity: tt.quantity}
+
+			err := levels.Apply(&movement)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Apply() error = %v, want %v", err, tt.wantErr)
+			}
+			if levels.Total != tt.wantTotal || levels.Unassigned() != tt.wantUnassigned {
+				t.Errorf("Apply() total, unassigned = %v, %v, want %v, %v", levels.Total, levels.Unassigned(), tt.wantTotal, tt.wantUnassigned)
+			}
+			if tt.warehouse == &hanoi && levels.Warehouses[hanoi] != tt.wantWarehouse {
+				t.Errorf("Apply() warehouse = %v, want %v", levels.Warehouses[hanoi], tt.wantWarehouse)
+			}
+		})
+	}
+}
+
+func TestWarehouseTransferMovements(t *testing.T) {
+	from, to := uuid.New(), uuid.New()
+
+	transfer := WarehouseTransfer{ID: uuid.New(), ProductID: uuid.New(), FromWarehouseID: from, ToWarehouseID: to, Quantity: 4}
+	movements, err := transfer.Movements()
+	if err != nil {
+		t.Fatalf("Movements() error = %v", err)
+	}
+	if len(movements) != 2 ||
+		*movements[0].WarehouseID != from || movements[0].Quantity != -4 ||
+		*movements[1].WarehouseID != to || movements[1].Quantity != 4 ||
+		*movements[0].TransferID != transfer.ID || *movements[1].TransferID != transfer.ID {
+		t.Errorf("Movements() = %+v", movements)
+	}
+
+	for _, invalid := range []WarehouseTransfer{
+		{FromWarehouseID: from, ToWarehouseID: to, Quantity: 0},
+		{FromWarehouseID: from, ToWarehouseID: from, Quantity: 1},
+	} {
+		if _, err := invalid.Movements(); !errors.Is(err, ErrInvalidStockMovement) {
+			t.Errorf("Movements() error = %v, want %v", err, ErrInvalidStockMovement)
+		}
+	}
+}
+
+func TestCoordinatesDistanceKM(t *testing.T) {
+	hanoi := Coordinates{Latitude: 21.0285, Longitude: 105.8542}
+	saigon := Coordinates{Latitude: 10.8231, Longitude: 106.6297}
+
+	if got := hanoi.DistanceKM(hanoi); got != 0 {
+		t.Errorf("DistanceKM() to itself = %v, want 0", got)
+	}
+	if got := hanoi.DistanceKM(saigon); got < 1130 || got > 1145 {
+		t.Errorf("DistanceKM() = %v, want about 1137", got)
+	}
+}
+
+func TestNewProductAvailability(t *testing.T) {
+	origin := Coordinates{Latitude: 16.0544, Longitude: 108.2022}
+	stocks := []WarehouseStock{
+		{Warehouse: Warehouse{Name: "Hanoi", Coordinates: &Coordinates{Latitude: 21.0285, Longitude: 105.8542}}, Quantity: 4},
+		{Warehouse: Warehouse{Name: "Unmapped"}, Quantity: 1},
+		{Warehouse: Warehouse{Name: "Empty", Coordinates: &origin}, Quantity: 0},
+		{Warehouse: Warehouse{Name: "Hue", Coordinates: &Coordinates{Latitude: 16.4637, Longitude: 107.5909}}, Quantity: 2},
+	}
+
+	availability := NewProductAvailability(uuid.New(), origin, stocks)
+
+	var names []string
+	for _, item := range availability.Warehouses {
+		names = append(names, item.Warehouse.Name)
+	}
+	if got := strings.Join(names, ","); got != "Hue,Hanoi,Unmapped" {
+		t.Errorf("NewProductAvailability() warehouses = %v, want Hue,Hanoi,Unmapped", got)
+	}
+	if availability.Total != 7 {
+		t.Errorf("NewProductAvailability() total = %v, want 7", availability.Total)
+	}
+	if availability.Warehouses[2].DistanceKM != nil {
+		t.Errorf("NewProductAvailability() distance without coordinates = %v, want nil", *availability.Warehouses[2].DistanceKM)
+	}
+}
+
+func TestParseCoordinates(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Coordinates
+		wantErr error
+	}{
+		{input: "21.0285,105.8542", want: Coordinates{Latitude: 21.0285, Longitude: 105.8542}},
+		{input: " -33.87 , 151.21 ", want: Coordinates{Latitude: -33.87, Longitude: 151.21}},
+		{input: "21.0285", wantErr: ErrInvalidCoordinates},
+		{input: "north,105", wantErr: ErrInvalidCoordinates},
+		{input: "91,105", wantErr: ErrInvalidCoordinates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseCoordinates(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseCoordinates() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && got != tt.want {
+				t.Errorf("ParseCoordinates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCoordinatesBoundingBox(t *testing.T) {
+	tests := []struct {
+		name          string
+		origin        Coordinates
+		radiusKM      float64
+		allLongitudes bool
+	}{
+		{name: "city", origin: Coordinates{Latitude: 21.0285, Longitude: 105.8542}, radiusKM: 50},
+		{name: "southern hemisphere", origin: Coordinates{Latitude: -33.87, Longitude: 151.21}, radiusKM: 500},
+		{name: "near a pole", origin: Coordinates{Latitude: 89.9, Longitude: 0}, radiusKM: 50, allLongitudes: true},
+		{name: "across the antimeridian", origin: Coordinates{Latitude: -17.7, Longitude: 179.9}, radiusKM: 50, allLongitudes: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box := tt.origin.BoundingBox(tt.radiusKM)
+
+			if got := box.MinLongitude == -180 && box.MaxLongitude == 180; got != tt.allLongitudes {
+				t.Fatalf("BoundingBox() = %+v, all longitudes = %v, want %v", box, got, tt.allLongitudes)
+			}
+
+			if tt.allLongitudes {
+				return
+			}
+
+			// The box reaches the radius due north and holds the point at the radius due east
+			north := Coordinates{Latitude: box.MaxLatitude, Longitude: tt.origin.Longitude}
+			if d := tt.origin.DistanceKM(north); math.Abs(d-tt.radiusKM) > 0.01 {
+				t.Errorf("BoundingBox() north edge is %v km away, want %v", d, tt.radiusKM)
+			}
+			east := Coordinates{Latitude: tt.origin.Latitude, Longitude: box.MaxLongitude}
+			if d := tt.origin.DistanceKM(east); d < tt.radiusKM-0.01 {
+				t.Errorf("BoundingBox() east edge is %v km away, want at least %v", d, tt.radiusKM)
+			}
+		})
+	}
+}
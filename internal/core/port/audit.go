@@ -0,0 +1,20 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+//go:generate mockgen -source=audit.go -destination=mock/audit.go -package=mock
+
+// AuditRepository is an interface for interacting with audit-related data
+type AuditRepository interface {
+	// CreateAuditEntry inserts a new audit entry into the database
+	CreateAuditEntry(ctx context.Context, entry *domain.AuditEntry) error
+	// CreateAuditEntries inserts audit entries into the database at once
+	CreateAuditEntries(ctx context.Context, entries []domain.AuditEntry) error
+	// ListAuditEntries selects the audit entries of an entity, newest first, with pagination
+	ListAuditEntries(ctx context.Context, entityType domain.AuditEntity, entityID uuid.UUID, skip, limit uint64) ([]domain.AuditEntry, error)
+}
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
@@ -13,14 +14,22 @@ import (
 type CategoryRepository interface {
 	// CreateCategory inserts a new category into the database
 	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
-	// GetCategoryByID selects a category by id
-	GetCategoryByID(ctx context.Context, id uuid.UUID) (*domain.Category, error)
-	// ListCategories selects a list of categories with pagination
-	ListCategories(ctx context.Context, skip, limit uint64) ([]domain.Category, error)
+	// GetCategoryByID selects a category by id, a soft deleted one only when includeDeleted
+	GetCategoryByID(ctx context.Context, id uuid.UUID, includeDeleted bool) (*domain.Category, error)
+	// GetCategoryForUpdate selects a category by id and locks it until the end of the transaction
+	GetCategoryForUpdate(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	// ListCategoryIDs selects the ids of the existing categories among the given ones, soft deleted ones excluded
+	ListCategoryIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	// ListCategories selects a list of categories in the order of the filter with pagination
+	ListCategories(ctx context.Context, filter domain.CategoryFilter, skip, limit uint64) ([]domain.Category, error)
 	// UpdateCategory updates a category
 	UpdateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
-	// DeleteCategory deletes a category
+	// DeleteCategory soft deletes a category that no product refers to
 	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	// RestoreCategory restores a soft deleted category
+	RestoreCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	// PurgeCategories permanently deletes the categories soft deleted before the given time
+	PurgeCategories(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 // CategoryService is an interface for interacting with category-related business logic
@@ -29,10 +38,14 @@ type CategoryService interface {
 	CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
 	// GetCategory returns a category by id
 	GetCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error)
-	// ListCategories returns a list of categories with pagination
-	ListCategories(ctx context.Context, skip, limit uint64) ([]domain.Category, error)
+	// ListCategories returns a list of categories in the order of the filter with pagination
+	ListCategories(ctx context.Context, filter domain.CategoryFilter, skip, limit uint64) ([]domain.Category, error)
 	// UpdateCategory updates a category
 	UpdateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error)
-	// DeleteCategory deletes a category
+	// DeleteCategory soft deletes a category
 	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	// RestoreCategory restores a soft deleted category
+	RestoreCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error)
+	// PurgeCategories permanently deletes the categories soft deleted longer ago than the retention window
+	PurgeCategories(ctx context.Context, retention time.Duration) (int64, error)
 }
@@ -0,0 +1,33 @@
+package port
+
+import (
+	"context"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+//go:generate mockgen -source=exchange_rate.go -destination=mock/exchange_rate.go -package=mock
+
+// ExchangeRateRepository is an interface for interacting with exchange rate-related data
+type ExchangeRateRepository interface {
+	// UpsertExchangeRate inserts or replaces the exchange rate of a currency
+	UpsertExchangeRate(ctx context.Context, rate *domain.ExchangeRate) (*domain.ExchangeRate, error)
+	// ListExchangeRates selects all exchange rates
+	ListExchangeRates(ctx context.Context) ([]domain.ExchangeRate, error)
+	// DeleteExchangeRate deletes the exchange rate of a currency
+	DeleteExchangeRate(ctx context.Context, currency string) error
+}
+
+// ExchangeRateService is an interface for interacting with exchange rate-related business logic
+type ExchangeRateService interface {
+	// ListExchangeRates returns all exchange rates
+	ListExchangeRates(ctx context.Context) ([]domain.ExchangeRate, error)
+	// SetExchangeRate creates or replaces the exchange rate of a currency
+	SetExchangeRate(ctx context.Context, rate *domain.ExchangeRate) (*domain.ExchangeRate, error)
+	// DeleteExchangeRate deletes the exchange rate of a currency
+	DeleteExchangeRate(ctx context.Context, currency string) error
+	// ImportExchangeRates creates or replaces a set of exchange rates at once
+	ImportExchangeRates(ctx context.Context, rates []domain.ExchangeRate) error
+	// NewConverter returns a converter to the currency using the current exchange rates and rounding rules
+	NewConverter(ctx context.Context, currency string) (*domain.CurrencyConverter, error)
+}
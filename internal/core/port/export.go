@@ -0,0 +1,78 @@
+package port
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+//go:generate mockgen -source=export.go -destination=mock/export.go -package=mock
+
+// ExportRepository is an interface for interacting with export job-related data
+type ExportRepository interface {
+	// CreateExportJob inserts a new export job into the database
+	CreateExportJob(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error)
+	// GetExportJobByID selects an export job by id
+	GetExportJobByID(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error)
+	// ClaimExportJob starts the oldest pending export job, or a running one whose heartbeat is older than staleBefore,
+	// and returns it with its attempt counted. It returns domain.ErrDataNotFound when there is none
+	ClaimExportJob(ctx context.Context, staleBefore time.Time, maxAttempts int) (*domain.ExportJob, error)
+	// UpdateExportProgress records the progress and the heartbeat of an attempt of a running export job.
+	// It returns domain.ErrDataNotFound when the attempt is no longer running
+	UpdateExportProgress(ctx context.Context, id uuid.UUID, attempt int, rows int64) error
+	// FinishExportJob records the end of an attempt of a running export job, completed or failed
+	FinishExportJob(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error)
+	// CancelExportJob cancels a pending or running export job
+	CancelExportJob(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error)
+	// ListExpiredExportJobs selects the export jobs that expired at the given time and are not marked as expired,
+	// leaving out the running ones whose heartbeat is not older than staleBefore
+	ListExpiredExportJobs(ctx context.Context, at, staleBefore time.Time, limit uint64) ([]domain.ExportJob, error)
+	// ExpireExportJob marks an export job as expired
+	ExpireExportJob(ctx context.Context, id uuid.UUID) error
+	// FailAbandonedExportJobs marks the running export jobs whose heartbeat is older than staleBefore and that
+	// can not be claimed again after maxAttempts runs as failed with the error message, and returns them
+	FailAbandonedExportJobs(ctx context.Context, staleBefore time.Time, maxAttempts int, message string) ([]domain.ExportJob, error)
+}
+
+// ExportStorage is an interface for storing the files of export jobs
+type ExportStorage interface {
+	// Create creates or truncates a file
+	Create(ctx context.Context, name string) (io.WriteCloser, error)
+	// Open opens a file for reading
+	Open(ctx context.Context, name string) (io.ReadSeekCloser, error)
+	// Remove removes a file, a missing file is not an error
+	Remove(ctx context.Context, name string) error
+}
+
+// ProductExporter is an interface for writing products in a file format
+type ProductExporter interface {
+	// ValidateExport checks the options of an export, returning domain.ErrInvalidExportOptions for unknown
+	// columns or an invalid delimiter and domain.ErrUnknownCurrency for a currency without exchange rate
+	ValidateExport(ctx context.Context, format domain.ExportFormat, options domain.ExportOptions) error
+	// ExportProducts writes the products matching the filter to w, calling progress with the number of products written so far
+	ExportProducts(ctx context.Context, w io.Writer, format domain.ExportFormat, filter domain.ProductFilter, options domain.ExportOptions, progress func(rows int64)) error
+	// ExportLabels writes a PDF sheet of labels of a page of the products matching the filter to w, returning
+	// domain.ErrInvalidExportOptions for an unknown layout, a limit above the largest page or a product whose
+	// code can not be encoded
+	ExportLabels(ctx context.Context, w io.Writer, filter domain.ProductFilter, options domain.LabelOptions) error
+}
+
+// ExportService is an interface for interacting with export job-related business logic
+type ExportService interface {
+	// CreateExport queues an export job
+	CreateExport(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error)
+	// GetExport returns an export job
+	GetExport(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error)
+	// CancelExport cancels a pending or running export job
+	CancelExport(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error)
+	// OpenExport opens the file of a completed export job
+	OpenExport(ctx context.Context, id uuid.UUID) (*domain.ExportJob, io.ReadSeekCloser, error)
+	// RunNextExport runs the next export job waiting for a worker and reports whether there was one
+	RunNextExport(ctx context.Context) (bool, error)
+	// ExpireExports fails the export jobs abandoned on their last attempt, removes the files of the export jobs
+	// past their retention and returns how many expired
+	ExpireExports(ctx context.Context) (int, error)
+}
@@ -1,8 +1,14 @@
 package port
 
+import "context"
+
 // GeoClient is an interface for interacting with a third-party geo service
 type GeoClient interface {
-	GetCityLocation(city string) (float64, float64, error)
-	GetIPLocation(ip string) (float64, float64, error)
+	// GetCityLocation returns the latitude and longitude of a city, domain.ErrUnknownCity when it is not known
+	GetCityLocation(ctx context.Context, city string) (float64, float64, error)
+	// GetIPLocation returns the latitude and longitude of an ip address, domain.ErrUnknownLocation when it is
+	// invalid, not public or not known
+	GetIPLocation(ctx context.Context, ip string) (float64, float64, error)
+	// GetDistance returns the distance in kilometers between two coordinates
 	GetDistance(lat1, lon1, lat2, lon2 float64) float64
 }
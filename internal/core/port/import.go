@@ -0,0 +1,16 @@
+package port
+
+import (
+	"io"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+//go:generate mockgen -source=import.go -destination=mock/import.go -package=mock
+
+// ProductImporter is an interface for reading products from a file format
+type ProductImporter interface {
+	// ParseProducts reads the rows of a product import from r, returning domain.ErrInvalidImportFile
+	// for a file that can not be read, such as a CSV file with an unknown column
+	ParseProducts(r io.Reader, format domain.ImportFormat) ([]domain.ImportRow, error)
+}
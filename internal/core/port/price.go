@@ -0,0 +1,48 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+//go:generate mockgen -source=price.go -destination=mock/price.go -package=mock
+
+// PriceRepository is an interface for interacting with price-related data
+type PriceRepository interface {
+	// CreatePriceChange inserts a new price change into the database
+	CreatePriceChange(ctx context.Context, change *domain.PriceChange) (*domain.PriceChange, error)
+	// CreatePriceChanges inserts price changes into the database at once
+	CreatePriceChanges(ctx context.Context, changes []domain.PriceChange) error
+	// GetPriceChangeByID selects a price change of a product by id
+	GetPriceChangeByID(ctx context.Context, productID, id uuid.UUID) (*domain.PriceChange, error)
+	// ListPriceChanges selects the price timeline of a product, latest start first, with pagination
+	ListPriceChanges(ctx context.Context, productID uuid.UUID, skip, limit uint64) ([]domain.PriceChange, error)
+	// CancelPriceChange cancels a price change that has not been applied
+	CancelPriceChange(ctx context.Context, id uuid.UUID) (*domain.PriceChange, error)
+	// GetEffectivePrices selects the price overriding the base price of the products at the given time, by product id
+	GetEffectivePrices(ctx context.Context, productIDs []uuid.UUID, at time.Time) (map[uuid.UUID]domain.Money, error)
+	// ClaimDuePriceChange selects and locks until the end of the transaction the oldest price change without an end
+	// that started at the given time, is not applied yet and is not delayed. It fails with domain.ErrDataNotFound when
+	// none is due, the price changes claimed by other transactions being skipped
+	ClaimDuePriceChange(ctx context.Context, at time.Time) (*domain.PriceChange, error)
+	// MarkPriceChangeApplied marks a price change as applied to the base price of its product,
+	// domain.ErrPriceChangeApplied when it already is
+	MarkPriceChangeApplied(ctx context.Context, id uuid.UUID, appliedAt time.Time) error
+	// DelayPriceChange counts a failed attempt to apply a price change and delays it until retryAt
+	DelayPriceChange(ctx context.Context, id uuid.UUID, retryAt time.Time) error
+}
+
+// PriceService is an interface for interacting with price-related business logic
+type PriceService interface {
+	// SchedulePriceChange schedules a new price for a product
+	SchedulePriceChange(ctx context.Context, change *domain.PriceChange) (*domain.PriceChange, error)
+	// ListPriceChanges returns the price timeline of a product with pagination
+	ListPriceChanges(ctx context.Context, productID uuid.UUID, skip, limit uint64) ([]domain.PriceChange, error)
+	// CancelPriceChange cancels a price change of a product that has not been applied
+	CancelPriceChange(ctx context.Context, productID, id uuid.UUID) (*domain.PriceChange, error)
+	// ApplyDuePriceChanges replaces the base price of the products whose scheduled price started and returns how many were applied
+	ApplyDuePriceChanges(ctx context.Context) (int, error)
+}
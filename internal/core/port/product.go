@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
@@ -13,30 +14,73 @@ import (
 type ProductRepository interface {
 	// CreateProduct inserts a new product into the database
 	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
-	// GetProductByID selects a product by id
-	GetProductByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
-	// ListProducts selects a list of products with pagination
-	ListProducts(ctx context.Context, search string, categoryIds []uuid.UUID, skip, limit uint64) ([]domain.Product, error)
+	// CreateProducts inserts products into the database at once, returning how many were inserted
+	CreateProducts(ctx context.Context, products []domain.Product) (int64, error)
+	// GetProductByID selects a product by id, a soft deleted one only when includeDeleted
+	GetProductByID(ctx context.Context, id uuid.UUID, includeDeleted bool) (*domain.Product, error)
+	// GetProductForUpdate selects a product by id and locks it until the end of the transaction
+	GetProductForUpdate(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+	// GetProductsForUpdate selects products by id and locks them until the end of the transaction
+	GetProductsForUpdate(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error)
+	// GetProductsByIDs selects the products with one of the ids, with their category, in no particular order
+	GetProductsByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error)
+	// GetProductsByReferences selects the products with one of the references, soft deleted ones included
+	GetProductsByReferences(ctx context.Context, references []string) ([]domain.Product, error)
+	// ListSupplierIDs selects the ids of the existing suppliers among the given ones
+	ListSupplierIDs(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	// ListSuppliers selects the suppliers with one of the ids
+	ListSuppliers(ctx context.Context, ids []uuid.UUID) ([]domain.Supplier, error)
+	// ListProducts selects a list of products with pagination, only reading the fields of the view when one is given
+	ListProducts(ctx context.Context, filter domain.ProductFilter, view *domain.ProductView, skip, limit uint64) ([]domain.Product, error)
+	// StreamProducts selects all the products matching the filter through a cursor, passing them to fn in batches
+	StreamProducts(ctx context.Context, filter domain.ProductFilter, batchSize int, fn func(products []domain.Product) error) error
 	// UpdateProduct updates a product
 	UpdateProduct(ctx context.Context, product *domain.Product, updatedFields ...string) (*domain.Product, error)
-	// DeleteProduct deletes a product
+	// DeleteProduct soft deletes a product, domain.ErrDataNotFound when it is missing or already deleted
 	DeleteProduct(ctx context.Context, id uuid.UUID) error
+	// WriteProducts creates, updates and soft deletes products in a single round trip,
+	// returning a domain.BatchError for the first write that fails
+	WriteProducts(ctx context.Context, writes []domain.ProductWrite) error
+	// RestoreProduct restores a soft deleted product
+	RestoreProduct(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+	// PurgeProducts permanently deletes the products soft deleted before the given time
+	PurgeProducts(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
 
 // ProductService is an interface for interacting with product-related business logic
 type ProductService interface {
 	// CreateProduct creates a new product
 	CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
-	// GetProduct returns a product by id
-	GetProduct(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+	// GetProduct returns a product by id, a soft deleted one only when includeDeleted
+	GetProduct(ctx context.Context, id uuid.UUID, includeDeleted bool) (*domain.Product, error)
+	// GetProducts returns products by id in the order of the ids, with the ids matching no product.
+	// A view selects the relations embedded, the products being read whole through the cache whatever its fields
+	GetProducts(ctx context.Context, ids []uuid.UUID, view *domain.ProductView) ([]domain.Product, []uuid.UUID, error)
 	// GetProductDistance returns the distance between products and ip
 	GetProductDistance(ctx context.Context, ip string, id uuid.UUID) (float64, error)
-	// ListProducts returns a list of products with pagination
-	ListProducts(ctx context.Context, search string, categoryIds []uuid.UUID, skip, limit uint64) ([]domain.Product, error)
+	// GetProductAvailability returns the stock of a product in the warehouses holding some, nearest to the origin first.
+	// Without an origin the location of the ip is used
+	GetProductAvailability(ctx context.Context, id uuid.UUID, origin *domain.Coordinates, ip string) (*domain.ProductAvailability, error)
+	// ListProducts returns a list of products with pagination. A view selects the fields read and the relations
+	// embedded, the whole products are returned with their category and warehouse stocks without one
+	ListProducts(ctx context.Context, filter domain.ProductFilter, view *domain.ProductView, skip, limit uint64) ([]domain.Product, error)
+	// ExportProducts passes all the products matching the filter to fn, one at a time, without holding them in memory
+	ExportProducts(ctx context.Context, filter domain.ProductFilter, fn func(product *domain.Product) error) error
 
 	ListProducts2(ctx context.Context, search string, categoryIDs []uuid.UUID, cursor *string, perPage uint64) ([]domain.Product, error)
-	// UpdateProduct updates a product
-	UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error)
-	// DeleteProduct deletes a product
+	// UpdateProduct updates the fields of a product that are not empty, and its quantity when quantitySet, zero included
+	UpdateProduct(ctx context.Context, product *domain.Product, quantitySet bool) (*domain.Product, error)
+	// DeleteProduct soft deletes a product, domain.ErrDataNotFound when it is missing or already deleted
 	DeleteProduct(ctx context.Context, id uuid.UUID) error
+	// RestoreProduct restores a soft deleted product
+	RestoreProduct(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+	// PurgeProducts permanently deletes the products soft deleted longer ago than the retention window
+	PurgeProducts(ctx context.Context, retention time.Duration) (int64, error)
+	// GetProductHistory returns the audit entries of a product with pagination, newest first
+	GetProductHistory(ctx context.Context, id uuid.UUID, skip, limit uint64) ([]domain.AuditEntry, error)
+	// BatchProducts applies a batch of creations, updates and deletions of products in a transaction,
+	// all of them or those that succeed depending on the mode, returning the result of each operation
+	BatchProducts(ctx context.Context, operations []domain.ProductOperation, mode domain.BatchMode) ([]domain.ProductOperationResult, error)
+	// ImportProducts validates the rows of an import and writes the valid ones, reporting the rejected rows
+	ImportProducts(ctx context.Context, rows []domain.ImportRow, options domain.ImportOptions) (*domain.ImportReport, error)
 }
@@ -0,0 +1,34 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+//go:generate mockgen -source=stock.go -destination=mock/stock.go -package=mock
+
+// StockRepository is an interface for interacting with stock-related data
+type StockRepository interface {
+	// CreateStockMovement inserts a new stock movement into the database
+	CreateStockMovement(ctx context.Context, movement *domain.StockMovement) (*domain.StockMovement, error)
+	// CreateStockMovements inserts stock movements into the database at once
+	CreateStockMovements(ctx context.Context, movements []domain.StockMovement) error
+	// ListStockMovements selects the stock movements of a product, newest first, with pagination
+	ListStockMovements(ctx context.Context, productID uuid.UUID, filter domain.StockMovementFilter, skip, limit uint64) ([]domain.StockMovement, error)
+	// GetWarehouseStocks selects the stock of the products in each warehouse, by product id
+	GetWarehouseStocks(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]domain.WarehouseStock, error)
+	// SetWarehouseStock sets the stock of a product in a warehouse
+	SetWarehouseStock(ctx context.Context, productID, warehouseID uuid.UUID, quantity int) error
+}
+
+// StockService is an interface for interacting with stock-related business logic
+type StockService interface {
+	// PostStockMovements applies stock movements to their products, either all of them or none
+	PostStockMovements(ctx context.Context, movements []domain.StockMovement) ([]domain.StockMovement, error)
+	// TransferStock moves stock of a product between two warehouses and returns the pair of transfer movements
+	TransferStock(ctx context.Context, transfer *domain.WarehouseTransfer) ([]domain.StockMovement, error)
+	// ListStockMovements returns the stock movement history of a product with pagination, newest first
+	ListStockMovements(ctx context.Context, productID uuid.UUID, filter domain.StockMovementFilter, skip, limit uint64) ([]domain.StockMovement, error)
+}
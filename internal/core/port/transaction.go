@@ -0,0 +1,11 @@
+package port
+
+import "context"
+
+// Transactor is an interface for running several repository calls atomically
+type Transactor interface {
+	// WithinTransaction runs fn in a transaction that is committed when fn returns nil
+	// and rolled back otherwise. Repositories called with the context given to fn take
+	// part in the transaction, nested calls join the outer transaction
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
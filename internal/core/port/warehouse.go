@@ -0,0 +1,38 @@
+package port
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+//go:generate mockgen -source=warehouse.go -destination=mock/warehouse.go -package=mock
+
+// WarehouseRepository is an interface for interacting with warehouse-related data
+type WarehouseRepository interface {
+	// CreateWarehouse inserts a new warehouse into the database
+	CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error)
+	// GetWarehouseByID selects a warehouse by id
+	GetWarehouseByID(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	// ListWarehouses selects a list of warehouses with pagination
+	ListWarehouses(ctx context.Context, skip, limit uint64) ([]domain.Warehouse, error)
+	// UpdateWarehouse updates a warehouse
+	UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error)
+	// DeleteWarehouse deletes a warehouse that holds no stock
+	DeleteWarehouse(ctx context.Context, id uuid.UUID) error
+}
+
+// WarehouseService is an interface for interacting with warehouse-related business logic
+type WarehouseService interface {
+	// CreateWarehouse creates a new warehouse
+	CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error)
+	// GetWarehouse returns a warehouse by id
+	GetWarehouse(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error)
+	// ListWarehouses returns a list of warehouses with pagination
+	ListWarehouses(ctx context.Context, skip, limit uint64) ([]domain.Warehouse, error)
+	// UpdateWarehouse updates a warehouse
+	UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error)
+	// DeleteWarehouse deletes a warehouse that holds no stock
+	DeleteWarehouse(ctx context.Context, id uuid.UUID) error
+}
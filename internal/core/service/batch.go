@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// batchRetries is the number of times a best effort batch is applied again without an operation that failed while
+// being written, the operations still pending past it are not applied
+const batchRetries = 8
+
+// BatchProducts applies a batch of operations on products, each validated as the single creation, update or
+// deletion it stands for. The operations are applied in a single transaction in which the writes of the products
+// are pipelined. An atomic batch applies all the operations or none of them, the operations it does not apply
+// failing with domain.ErrBatchAborted. A best effort batch applies the operations that succeed: the operations
+// taking a reference already taken fail before the transaction, and when one fails while being written, the
+// transaction is rolled back and applied again without it up to batchRetries times
+func (ps *ProductService) BatchProducts(ctx context.Context, operations []domain.ProductOperation, mode domain.BatchMode) ([]domain.ProductOperationResult, error) {
+	results := make([]domain.ProductOperationResult, len(operations))
+	fields := make([][]string, len(operations))
+	changed := make(map[uuid.UUID]bool)
+
+	var pending []int
+	for i := range operations {
+		product := &operations[i].Product
+
+		var err error
+		switch operations[i].Method {
+		case domain.OperationCreate:
+			err = ps.prepareCreateProduct(ctx, product)
+		case domain.OperationUpdate, domain.OperationDelete:
+			if changed[product.ID] {
+				err = domain.ErrDuplicateOperation
+				break
+			}
+			changed[product.ID] = true
+
+			if operations[i].Method == domain.OperationUpdate {
+				fields[i], err = ps.prepareUpdateProduct(ctx, product, operations[i].QuantitySet)
+			}
+		default:
+			err = domain.ErrInvalidOperation
+		}
+
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	pending, err := ps.rejectTakenReferences(ctx, operations, results, pending)
+	if err != nil {
+		return nil, err
+	}
+
+	if mode == domain.BatchAtomic && len(pending) < len(operations) {
+		abortOperations(results, pending)
+		return results, nil
+	}
+
+	// The updates are resolved against the locked products each time the batch is applied, from the requested products
+	requests := make([]domain.Product, len(operations))
+	for _, i := range pending {
+		requests[i] = operations[i].Product
+	}
+
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt > batchRetries {
+			abortOperations(results, pending)
+			return results, nil
+		}
+
+		failed, err := ps.applyProductOperations(ctx, operations, requests, fields, pending)
+		if err == nil {
+			break
+		}
+		if failed < 0 {
+			if isRepositoryError(err) || isStockError(err) {
+				return nil, err
+			}
+			return nil, domain.ErrInternal
+		}
+
+		if !isRepositoryError(err) && !isStockError(err) && !isUpdateError(err) {
+			err = domain.ErrInternal
+		}
+		results[pending[failed]].Err = err
+		pending = slices.Delete(pending, failed, failed+1)
+
+		if mode == domain.BatchAtomic {
+			abortOperations(results, pending)
+			return results, nil
+		}
+	}
+
+	var updated []*domain.Product
+	for _, i := range pending {
+		product := &operations[i].Product
+		switch operations[i].Method {
+		case domain.OperationCreate:
+			product.EffectivePrice = product.Price
+			results[i].Product = product
+		case domain.OperationUpdate:
+			updated = append(updated, product)
+			results[i].Product = product
+			ps.forgetProducts(ctx, product.ID)
+		case domain.OperationDelete:
+			ps.forgetProducts(ctx, product.ID)
+		}
+	}
+
+	if len(updated) > 0 {
+		err := ps.setEffectivePrices(ctx, updated...)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+
+		err = ps.setWarehouseStocks(ctx, updated...)
+		if err != nil {
+			return nil, domain.ErrInternal
+		}
+	}
+
+	return results, nil
+}
+
+// rejectTakenReferences fails with domain.ErrConflictingData the pending creations and updates setting a reference
+// that a product the batch does not change holds, or that an earlier operation of the batch sets, returning the
+// operations left pending. The references of the products the batch changes are left to the database
+func (ps *ProductService) rejectTakenReferences(ctx context.Context, operations []domain.ProductOperation, results []domain.ProductOperationResult, pending []int) ([]int, error) {
+	var references []string
+	changed := make(map[uuid.UUID]bool)
+	for _, i := range pending {
+		product := &operations[i].Product
+		if operations[i].Method != domain.OperationCreate {
+			changed[product.ID] = true
+		}
+		if operations[i].Method != domain.OperationDelete && product.Reference != "" {
+			references = append(references, product.Reference)
+		}
+	}
+	if len(references) == 0 {
+		return pending, nil
+	}
+
+	products, err := ps.productRepo.GetProductsByReferences(ctx, references)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	taken := make(map[string]bool, len(products))
+	for _, product := range products {
+		if !changed[product.ID] {
+			taken[product.Reference] = true
+		}
+	}
+
+	kept := pending[:0]
+	for _, i := range pending {
+		reference := operations[i].Product.Reference
+		if operations[i].Method != domain.OperationDelete && reference != "" {
+			if taken[reference] {
+				results[i].Err = domain.ErrConflictingData
+				continue
+			}
+			taken[reference] = true
+		}
+		kept = append(kept, i)
+	}
+
+	return kept, nil
+}
+
+// abortOperations fails the pending operations of an atomic batch that is not applied
+func abortOperations(results []domain.ProductOperationResult, pending []int) {
+	for _, i := range pending {
+		results[i].Err = domain.ErrBatchAborted
+	}
+}
+
+// applyProductOperations applies the pending operations of a batch in a transaction. The products updated or deleted
+// are locked first and the updates requested are resolved against them, then the products are written at once,
+// followed by the price changes, stock movements and audit entries of the operations. When an operation fails,
+// its position in the pending operations is returned with its error, a position of -1 means the whole transaction failed
+func (ps *ProductService) applyProductOperations(ctx context.Context, operations []domain.ProductOperation, requests []domain.Product, fields [][]string, pending []int) (int, error) {
+	err := ps.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var ids []uuid.UUID
+		for _, i := range pending {
+			if operations[i].Method != domain.OperationCreate {
+				ids = append(ids, operations[i].Product.ID)
+			}
+		}
+
+		// Lock the products so the prices, statuses and stock adjustments are resolved from the products they replace
+		locked := make(map[uuid.UUID]*domain.Product, len(ids))
+		if len(ids) > 0 {
+			products, err := ps.productRepo.GetProductsForUpdate(ctx, ids)
+			if err != nil {
+				return err
+			}
+			for j := range products {
+				locked[products[j].ID] = &products[j]
+			}
+		}
+
+		writes := make([]domain.ProductWrite, len(pending))
+		for k, i := range pending {
+			operation := &operations[i]
+			if operation.Method != domain.OperationCreate && locked[operation.Product.ID] == nil {
+				return &domain.BatchError{Index: k, Err: domain.ErrDataNotFound}
+			}
+
+			updatedFields := fields[i]
+			if operation.Method == domain.OperationUpdate {
+				operation.Product = requests[i]
+
+				var err error
+				updatedFields, err = resolveUpdateProduct(locked[operation.Product.ID], &operation.Product, operation.QuantitySet, fields[i])
+				if err != nil {
+					return &domain.BatchError{Index: k, Err: err}
+				}
+			}
+
+			writes[k] = domain.ProductWrite{
+				Method:  operation.Method,
+				Product: &operation.Product,
+				Fields:  updatedFields,
+			}
+		}
+
+		err := ps.productRepo.WriteProducts(ctx, writes)
+		if err != nil {
+			return err
+		}
+
+		var stocks map[uuid.UUID][]domain.WarehouseStock
+		if len(ids) > 0 {
+			stocks, err = ps.stockRepo.GetWarehouseStocks(ctx, ids)
+			if err != nil {
+				return err
+			}
+		}
+
+		var changes []domain.PriceChange
+		var movements []domain.StockMovement
+		entries := make([]domain.AuditEntry, len(pending))
+
+		for k, i := range pending {
+			product := &operations[i].Product
+			before := locked[product.ID]
+
+			var entry *domain.AuditEntry
+			switch operations[i].Method {
+			case domain.OperationCreate:
+				if product.Price.IsPositive() {
+					changes = append(changes, *newAppliedPriceChange(product.ID, product.Price))
+				}
+				before = &domain.Product{}
+				entry = newAuditEntry(ctx, domain.AuditEntityProduct, product.ID, domain.AuditActionCreate, domain.DiffProducts(nil, product))
+			case domain.OperationUpdate:
+				if !product.Price.Equal(before.Price) {
+					changes = append(changes, *newAppliedPriceChange(product.ID, product.Price))
+				}
+				entry = newAuditEntry(ctx, domain.AuditEntityProduct, product.ID, domain.AuditActionUpdate, domain.DiffProducts(before, product))
+			case domain.OperationDelete:
+				entry = newAuditEntry(ctx, domain.AuditEntityProduct, product.ID, domain.AuditActionDelete, domain.DiffProducts(before, nil))
+			}
+			entries[k] = *entry
+
+			if operations[i].Method == domain.OperationDelete || product.Quantity == before.Quantity {
+				continue
+			}
+
+			movementType, reason := domain.StockAdjustment, "quantity updated"
+			if operations[i].Method == domain.OperationCreate {
+				movementType, reason = domain.StockReceipt, "initial stock"
+			}
+
+			movement := newStockMovement(ctx, product.ID, movementType, product.Quantity-before.Quantity, reason)
+			levels := domain.NewStockLevels(before.Quantity, stocks[product.ID])
+			if err := levels.Apply(movement); err != nil {
+				return &domain.BatchError{Index: k, Err: err}
+			}
+			movements = append(movements, *movement)
+		}
+
+		if len(changes) > 0 {
+			err = ps.priceRepo.CreatePriceChanges(ctx, changes)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(movements) > 0 {
+			err = ps.stockRepo.CreateStockMovements(ctx, movements)
+			if err != nil {
+				return err
+			}
+		}
+
+		return ps.auditRepo.CreateAuditEntries(ctx, entries)
+	})
+
+	var batchErr *domain.BatchError
+	if errors.As(err, &batchErr) {
+		return batchErr.Index, batchErr.Err
+	}
+	return -1, err
+}
@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+func TestBatchProducts(t *testing.T) {
+	rice := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Status: domain.StatusAvailable, Quantity: 5}
+	tea := domain.Product{ID: uuid.New(), Reference: "R2", Name: "Tea", Status: domain.StatusAvailable, Quantity: 3}
+	errWrite := errors.New("connection reset by peer")
+
+	tests := []struct {
+		name       string
+		mode       domain.BatchMode
+		operations []domain.ProductOperation
+		failWrites map[string]error
+		wantErrs   []error
+		wantWrites int
+		// wantNames are the names of rice and tea after the batch, tea being deleted when its name is empty
+		wantNames [2]string
+	}{
+		{
+			name: "all applied",
+			mode: domain.BatchAtomic,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationCreate, Product: domain.Product{Reference: "R3", Name: "Salt"}},
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Name: "Brown rice"}},
+				{Method: domain.OperationDelete, Product: domain.Product{ID: tea.ID}},
+			},
+			wantErrs:   []error{nil, nil, nil},
+			wantWrites: 1,
+			wantNames:  [2]string{"Brown rice", ""},
+		},
+		{
+			name: "atomic write failed",
+			mode: domain.BatchAtomic,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationCreate, Product: domain.Product{Reference: "R1", Name: "Salt"}},
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Name: "Brown rice"}},
+				{Method: domain.OperationDelete, Product: domain.Product{ID: tea.ID}},
+			},
+			failWrites: map[string]error{"Salt": domain.ErrConflictingData},
+			wantErrs:   []error{domain.ErrConflictingData, domain.ErrBatchAborted, domain.ErrBatchAborted},
+			wantWrites: 1,
+			wantNames:  [2]string{"Rice", "Tea"},
+		},
+		{
+			name: "best effort write failed and retried",
+			mode: domain.BatchBestEffort,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationCreate, Product: domain.Product{Reference: "R1", Name: "Salt"}},
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Name: "Brown rice"}},
+				{Method: domain.OperationDelete, Product: domain.Product{ID: tea.ID}},
+			},
+			failWrites: map[string]error{"Salt": domain.ErrConflictingData},
+			wantErrs:   []error{domain.ErrConflictingData, nil, nil},
+			wantWrites: 2,
+			wantNames:  [2]string{"Brown rice", ""},
+		},
+		{
+			name: "best effort write failed with an internal error",
+			mode: domain.BatchBestEffort,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Name: "Brown rice"}},
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: tea.ID, Name: "Green tea"}},
+			},
+			failWrites: map[string]error{"Brown rice": errWrite},
+			wantErrs:   []error{domain.ErrInternal, nil},
+			wantWrites: 2,
+			wantNames:  [2]string{"Rice", "Green tea"},
+		},
+		{
+			name: "best effort with every write failed",
+			mode: domain.BatchBestEffort,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Name: "Brown rice"}},
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: tea.ID, Name: "Green tea"}},
+			},
+			failWrites: map[string]error{"Brown rice": domain.ErrConflictingData, "Green tea": domain.ErrConflictingData},
+			wantErrs:   []error{domain.ErrConflictingData, domain.ErrConflictingData},
+			wantWrites: 2,
+			wantNames:  [2]string{"Rice", "Tea"},
+		},
+		{
+			name: "atomic duplicate operation",
+			mode: domain.BatchAtomic,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Name: "Brown rice"}},
+				{Method: domain.OperationDelete, Product: domain.Product{ID: rice.ID}},
+			},
+			wantErrs:  []error{domain.ErrBatchAborted, domain.ErrDuplicateOperation},
+			wantNames: [2]string{"Rice", "Tea"},
+		},
+		{
+			name: "best effort duplicate operation",
+			mode: domain.BatchBestEffort,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Name: "Brown rice"}},
+				{Method: domain.OperationDelete, Product: domain.Product{ID: rice.ID}},
+			},
+			wantErrs:   []error{nil, domain.ErrDuplicateOperation},
+			wantWrites: 1,
+			wantNames:  [2]string{"Brown rice", "Tea"},
+		},
+		{
+			name: "unknown product",
+			mode: domain.BatchBestEffort,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: uuid.New(), Name: "Salt"}},
+				{Method: domain.OperationDelete, Product: domain.Product{ID: tea.ID}},
+			},
+			wantErrs:   []error{domain.ErrDataNotFound, nil},
+			wantWrites: 1,
+			wantNames:  [2]string{"Rice", ""},
+		},
+		{
+			name: "best effort reference taken",
+			mode: domain.BatchBestEffort,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationCreate, Product: domain.Product{Reference: "R2", Name: "Salt"}},
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Name: "Brown rice"}},
+			},
+			wantErrs:   []error{domain.ErrConflictingData, nil},
+			wantWrites: 1,
+			wantNames:  [2]string{"Brown rice", "Tea"},
+		},
+		{
+			name: "atomic reference taken",
+			mode: domain.BatchAtomic,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Reference: "R2"}},
+				{Method: domain.OperationCreate, Product: domain.Product{Reference: "R3", Name: "Salt"}},
+			},
+			wantErrs:  []error{domain.ErrConflictingData, domain.ErrBatchAborted},
+			wantNames: [2]string{"Rice", "Tea"},
+		},
+		{
+			name: "reference set twice",
+			mode: domain.BatchBestEffort,
+			operations: []domain.ProductOperation{
+				{Method: domain.OperationCreate, Product: domain.Product{Reference: "R3", Name: "Salt"}},
+				{Method: domain.OperationCreate, Product: domain.Product{Reference: "R3", Name: "Sugar"}},
+			},
+			wantErrs:   []error{nil, domain.ErrConflictingData},
+			wantWrites: 1,
+			wantNames:  [2]string{"Rice", "Tea"},
+		},
+		{
+			name: "unknown method",
+			mode: domain.BatchAtomic,
+			operations: []domain.ProductOperation{
+				{Method: "upsert", Product: domain.Product{Reference: "R3", Name: "Salt"}},
+				{Method: domain.OperationDelete, Product: domain.Product{ID: tea.ID}},
+			},
+			wantErrs:  []error{domain.ErrInvalidOperation, domain.ErrBatchAborted},
+			wantNames: [2]string{"Rice", "Tea"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := newFakeProductRepository(rice, tea)
+			productRepo.failWrites = tt.failWrites
+			ps := newTestProductService(productRepo, &fakeCategoryRepository{}, newFakeCache())
+
+			results, err := ps.BatchProducts(context.Background(), tt.operations, tt.mode)
+			if err != nil {
+				t.Fatalf("BatchProducts() error = %v", err)
+			}
+			if len(results) != len(tt.wantErrs) {
+				t.Fatalf("BatchProducts() = %d results, want %d", len(results), len(tt.wantErrs))
+			}
+			for i, want := range tt.wantErrs {
+				wantProduct := want == nil && tt.operations[i].Method != domain.OperationDelete
+				if !errors.Is(results[i].Err, want) || (results[i].Product != nil) != wantProduct {
+					t.Errorf("result %d = %v, %v, want %v", i, results[i].Product, results[i].Err, want)
+				}
+			}
+			if productRepo.writeCalls != tt.wantWrites {
+				t.Errorf("WriteProducts() calls = %d, want %d", productRepo.writeCalls, tt.wantWrites)
+			}
+
+			for i, product := range []domain.Product{rice, tea} {
+				got := productRepo.products[product.ID]
+				name := got.Name
+				if got.DeletedAt != nil {
+					name = ""
+				}
+				if name != tt.wantNames[i] {
+					t.Errorf("product %s name = %q, want %q", product.Reference, name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
+
+func TestBatchProductsRetriesBounded(t *testing.T) {
+	productRepo := newFakeProductRepository()
+	productRepo.failWrites = make(map[string]error)
+	operations := make([]domain.ProductOperation, batchRetries+2)
+	for i := range operations {
+		name := fmt.Sprintf("Product %d", i)
+		operations[i] = domain.ProductOperation{Method: domain.OperationCreate, Product: domain.Product{Reference: fmt.Sprintf("R%d", i), Name: name}}
+		productRepo.failWrites[name] = domain.ErrConflictingData
+	}
+	ps := newTestProductService(productRepo, &fakeCategoryRepository{}, newFakeCache())
+
+	results, err := ps.BatchProducts(context.Background(), operations, domain.BatchBestEffort)
+	if err != nil {
+		t.Fatalf("BatchProducts() error = %v", err)
+	}
+
+	if productRepo.writeCalls != batchRetries+1 {
+		t.Errorf("WriteProducts() calls = %d, want %d", productRepo.writeCalls, batchRetries+1)
+	}
+	for i, result := range results {
+		want := domain.ErrConflictingData
+		if i == len(results)-1 {
+			want = domain.ErrBatchAborted
+		}
+		if !errors.Is(result.Err, want) {
+			t.Errorf("result %d error = %v, want %v", i, result.Err, want)
+		}
+	}
+}
+
+func TestBatchProductsResolvesLockedProduct(t *testing.T) {
+	rice := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Status: domain.StatusAvailable, Price: usd(t, "10"), Quantity: 5}
+	productRepo := newFakeProductRepository(rice)
+	productRepo.concurrentWrite = func(product *domain.Product) {
+		product.Status, product.Quantity = domain.StatusOutOfStock, 0
+		product.Price = usd(t, "12")
+	}
+	priceRepo := &fakePriceRepository{}
+	ps := newTestProductService(productRepo, &fakeCategoryRepository{}, newFakeCache())
+	ps.priceRepo = priceRepo
+
+	operations := []domain.ProductOperation{
+		{Method: domain.OperationUpdate, Product: domain.Product{ID: rice.ID, Status: domain.StatusOnOrDer, Price: usd(t, "12")}},
+	}
+	results, err := ps.BatchProducts(context.Background(), operations, domain.BatchAtomic)
+	if err != nil || results[0].Err != nil {
+		t.Fatalf("BatchProducts() = %+v, %v, want the update applied", results, err)
+	}
+
+	if got := productRepo.products[rice.ID]; got.Status != domain.StatusOnOrDer || got.Quantity != 0 {
+		t.Errorf("rice = %v with %d in stock, want %v with 0", got.Status, got.Quantity, domain.StatusOnOrDer)
+	}
+	if len(priceRepo.created) != 0 {
+		t.Errorf("price changes = %+v, want none as the price was already set", priceRepo.created)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
@@ -15,14 +16,18 @@ import (
  * and cache service
  */
 type CategoryService struct {
-	repo  port.CategoryRepository
-	cache port.CacheRepository
+	repo       port.CategoryRepository
+	auditRepo  port.AuditRepository
+	transactor port.Transactor
+	cache      port.CacheRepository
 }
 
 // NewCategoryService creates a new category service instance
-func NewCategoryService(repo port.CategoryRepository, cache port.CacheRepository) *CategoryService {
+func NewCategoryService(repo port.CategoryRepository, auditRepo port.AuditRepository, transactor port.Transactor, cache port.CacheRepository) *CategoryService {
 	return &CategoryService{
 		repo,
+		auditRepo,
+		transactor,
 		cache,
 	}
 }
@@ -30,9 +35,17 @@ func NewCategoryService(repo port.CategoryRepository, cache port.CacheRepository
 // CreateCategory creates a new category
 func (cs *CategoryService) CreateCategory(ctx context.Context, category *domain.Category) (*domain.Category, error) {
 	category.ID = uuid.New()
-	category, err := cs.repo.CreateCategory(ctx, category)
+	err := cs.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := cs.repo.CreateCategory(ctx, category)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityCategory, category.ID, domain.AuditActionCreate, domain.DiffCategories(nil, category))
+		return cs.auditRepo.CreateAuditEntry(ctx, entry)
+	})
 	if err != nil {
-		if errors.Is(err, domain.ErrConflictingData) {
+		if isRepositoryError(err) {
 			return nil, err
 		}
 		return nil, domain.ErrInternal
@@ -42,7 +55,7 @@ func (cs *CategoryService) CreateCategory(ctx context.Context, category *domain.
 
 // GetCategory retrieves a category by id
 func (cs *CategoryService) GetCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
-	category, err := cs.repo.GetCategoryByID(ctx, id)
+	category, err := cs.repo.GetCategoryByID(ctx, id, false)
 	if err != nil {
 		if errors.Is(err, domain.ErrDataNotFound) {
 			return nil, err
@@ -54,9 +67,12 @@ func (cs *CategoryService) GetCategory(ctx context.Context, id uuid.UUID) (*doma
 }
 
 // ListCategories retrieves a list of categories
-func (cs *CategoryService) ListCategories(ctx context.Context, skip, limit uint64) ([]domain.Category, error) {
-	categories, err := cs.repo.ListCategories(ctx, skip, limit)
+func (cs *CategoryService) ListCategories(ctx context.Context, filter domain.CategoryFilter, skip, limit uint64) ([]domain.Category, error) {
+	categories, err := cs.repo.ListCategories(ctx, filter, skip, limit)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			return nil, err
+		}
 		return nil, domain.ErrInternal
 	}
 
@@ -68,17 +84,24 @@ func (cs *CategoryService) UpdateCategory(ctx context.Context, category *domain.
 	if category.Name == "" {
 		return nil, domain.ErrNoUpdatedData
 	}
-	_, err := cs.repo.GetCategoryByID(ctx, category.ID)
-	if err != nil {
-		if err == domain.ErrDataNotFound {
-			return nil, err
+
+	err := cs.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Lock the category so the audit entry diffs it against the category it replaces
+		before, err := cs.repo.GetCategoryForUpdate(ctx, category.ID)
+		if err != nil {
+			return err
 		}
-		return nil, domain.ErrInternal
-	}
 
-	_, err = cs.repo.UpdateCategory(ctx, category)
+		_, err = cs.repo.UpdateCategory(ctx, category)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityCategory, category.ID, domain.AuditActionUpdate, domain.DiffCategories(before, category))
+		return cs.auditRepo.CreateAuditEntry(ctx, entry)
+	})
 	if err != nil {
-		if err == domain.ErrConflictingData {
+		if isRepositoryError(err) {
 			return nil, err
 		}
 		return nil, domain.ErrInternal
@@ -87,14 +110,63 @@ func (cs *CategoryService) UpdateCategory(ctx context.Context, category *domain.
 	return category, nil
 }
 
-// DeleteCategory deletes a category
+// DeleteCategory soft deletes a category
 func (cs *CategoryService) DeleteCategory(ctx context.Context, id uuid.UUID) error {
-	_, err := cs.repo.GetCategoryByID(ctx, id)
+	err := cs.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Lock the category so that it is deleted and audited once when deleted concurrently
+		before, err := cs.repo.GetCategoryForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = cs.repo.DeleteCategory(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityCategory, id, domain.AuditActionDelete, domain.DiffCategories(before, nil))
+		return cs.auditRepo.CreateAuditEntry(ctx, entry)
+	})
 	if err != nil {
-		if err == domain.ErrDataNotFound {
+		if isRepositoryError(err) {
 			return err
 		}
 		return domain.ErrInternal
 	}
-	return cs.repo.DeleteCategory(ctx, id)
+
+	return nil
+}
+
+// RestoreCategory restores a soft deleted category
+func (cs *CategoryService) RestoreCategory(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	var category *domain.Category
+
+	err := cs.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		category, err = cs.repo.RestoreCategory(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityCategory, id, domain.AuditActionRestore, nil)
+		return cs.auditRepo.CreateAuditEntry(ctx, entry)
+	})
+	if err != nil {
+		if isRepositoryError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return category, nil
+}
+
+// PurgeCategories permanently deletes the categories soft deleted longer ago than the retention window
+func (cs *CategoryService) PurgeCategories(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := cs.repo.PurgeCategories(ctx, time.Now().Add(-retention))
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	return purged, nil
 }
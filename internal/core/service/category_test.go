@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+func TestUpdateCategoryAudit(t *testing.T) {
+	category := domain.Category{ID: uuid.New(), Name: "Foods"}
+	categoryRepo := &fakeCategoryRepository{categories: map[uuid.UUID]domain.Category{category.ID: category}}
+	auditRepo := &fakeAuditRepository{}
+	cs := NewCategoryService(categoryRepo, auditRepo, fakeTransactor{}, newFakeCache())
+
+	if _, err := cs.UpdateCategory(context.Background(), &domain.Category{ID: category.ID, Name: "Groceries"}); err != nil {
+		t.Fatalf("UpdateCategory() error = %v", err)
+	}
+	if _, err := cs.UpdateCategory(context.Background(), &domain.Category{ID: uuid.New(), Name: "Drinks"}); !errors.Is(err, domain.ErrDataNotFound) {
+		t.Errorf("UpdateCategory() of an unknown category error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+
+	want := domain.FieldChange{Field: "name", Before: "Foods", After: "Groceries"}
+	if len(auditRepo.entries) != 1 || len(auditRepo.entries[0].Changes) != 1 || auditRepo.entries[0].Changes[0] != want {
+		t.Errorf("audit entries = %+v, want one with the change %+v", auditRepo.entries, want)
+	}
+}
+
+func TestDeleteCategoryTwice(t *testing.T) {
+	category := domain.Category{ID: uuid.New(), Name: "Foods"}
+	categoryRepo := &fakeCategoryRepository{categories: map[uuid.UUID]domain.Category{category.ID: category}}
+	auditRepo := &fakeAuditRepository{}
+	cs := NewCategoryService(categoryRepo, auditRepo, fakeTransactor{}, newFakeCache())
+
+	if err := cs.DeleteCategory(context.Background(), category.ID); err != nil {
+		t.Fatalf("DeleteCategory() error = %v", err)
+	}
+	if err := cs.DeleteCategory(context.Background(), category.ID); !errors.Is(err, domain.ErrDataNotFound) {
+		t.Errorf("DeleteCategory() of a deleted category error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if len(auditRepo.entries) != 1 || auditRepo.entries[0].Action != domain.AuditActionDelete {
+		t.Errorf("audit entries = %+v, want a single deletion", auditRepo.entries)
+	}
+}
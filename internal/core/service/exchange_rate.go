@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+/**
+ * ExchangeRateService implements port.ExchangeRateService interface
+ * and provides an access to the exchange rate repository
+ */
+type ExchangeRateService struct {
+	repo       port.ExchangeRateRepository
+	transactor port.Transactor
+	rounding   domain.RoundingRules
+}
+
+// NewExchangeRateService creates a new exchange rate service instance
+func NewExchangeRateService(repo port.ExchangeRateRepository, transactor port.Transactor, rounding domain.RoundingRules) *ExchangeRateService {
+	return &ExchangeRateService{
+		repo,
+		transactor,
+		rounding,
+	}
+}
+
+// ListExchangeRates returns all exchange rates
+func (es *ExchangeRateService) ListExchangeRates(ctx context.Context) ([]domain.ExchangeRate, error) {
+	rates, err := es.repo.ListExchangeRates(ctx)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return rates, nil
+}
+
+// SetExchangeRate creates or replaces the exchange rate of a currency
+func (es *ExchangeRateService) SetExchangeRate(ctx context.Context, rate *domain.ExchangeRate) (*domain.ExchangeRate, error) {
+	if err := rate.Validate(); err != nil {
+		return nil, err
+	}
+
+	rate, err := es.repo.UpsertExchangeRate(ctx, rate)
+	if err != nil {
+		if isRepositoryError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return rate, nil
+}
+
+// DeleteExchangeRate deletes the exchange rate of a currency
+func (es *ExchangeRateService) DeleteExchangeRate(ctx context.Context, currency string) error {
+	currency, err := domain.ParseCurrency(currency)
+	if err != nil {
+		return err
+	}
+
+	err = es.repo.DeleteExchangeRate(ctx, currency)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// ImportExchangeRates creates or replaces a set of exchange rates in a single transaction
+func (es *ExchangeRateService) ImportExchangeRates(ctx context.Context, rates []domain.ExchangeRate) error {
+	for i := range rates {
+		if err := rates[i].Validate(); err != nil {
+			return err
+		}
+	}
+
+	err := es.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		for i := range rates {
+			if _, err := es.repo.UpsertExchangeRate(ctx, &rates[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if isRepositoryError(err) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
+// NewConverter returns a converter to the currency using the current exchange rates and rounding rules
+func (es *ExchangeRateService) NewConverter(ctx context.Context, currency string) (*domain.CurrencyConverter, error) {
+	rates, err := es.repo.ListExchangeRates(ctx)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return domain.NewCurrencyConverter(currency, rates, es.rounding)
+}
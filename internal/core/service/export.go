@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+const (
+	// defaultExportRetention is how long the file of an export job is kept when no retention is configured
+	defaultExportRetention = 24 * time.Hour
+	// exportHeartbeatInterval is how often a running export job records its progress
+	exportHeartbeatInterval = 5 * time.Second
+	// exportStaleAfter is how long after its last heartbeat a running export job is considered abandoned
+	// by its worker and started again
+	exportStaleAfter = time.Minute
+	// exportMaxAttempts is the number of runs of an export job before it is failed
+	exportMaxAttempts = 3
+	// exportAbandonedMessage is the reason an export job abandoned on its last attempt failed
+	exportAbandonedMessage = "export job was interrupted too many times"
+	// expiredBatchSize is the maximum number of export jobs expired at once
+	expiredBatchSize = 100
+)
+
+// errExportCanceled is the cause of the cancellation of a running export job canceled by a client
+var errExportCanceled = errors.New("export job canceled")
+
+// exportErrors are the domain errors an export can fail with that are meaningful to the client
+var exportErrors = []error{
+	domain.ErrInvalidExportOptions,
+	domain.ErrUnknownCurrency,
+	domain.ErrUnknownLocation,
+}
+
+/**
+ * ExportService implements port.ExportService interface
+ * and provides an access to the export repository, the export storage and the product exporter
+ */
+type ExportService struct {
+	repo      port.ExportRepository
+	storage   port.ExportStorage
+	exporter  port.ProductExporter
+	retention time.Duration
+	// heartbeatInterval is how often a running export job records its progress
+	heartbeatInterval time.Duration
+}
+
+// NewExportService creates a new export service instance
+func NewExportService(repo port.ExportRepository, storage port.ExportStorage, exporter port.ProductExporter, retention time.Duration) *ExportService {
+	if retention <= 0 {
+		retention = defaultExportRetention
+	}
+
+	return &ExportService{
+		repo,
+		storage,
+		exporter,
+		retention,
+		exportHeartbeatInterval,
+	}
+}
+
+// CreateExport checks the options of an export job and queues it
+func (es *ExportService) CreateExport(ctx context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	if err := es.exporter.ValidateExport(ctx, job.Format, job.Options); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job.ID = uuid.New()
+	job.State = domain.ExportPending
+	job.CreatedAt = now
+	job.ExpiresAt = now.Add(es.retention)
+
+	created, err := es.repo.CreateExportJob(ctx, job)
+	if err != nil {
+		if isRepositoryError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return created, nil
+}
+
+// GetExport returns an export job
+func (es *ExportService) GetExport(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error) {
+	job, err := es.repo.GetExportJobByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return job, nil
+}
+
+// CancelExport cancels a pending or running export job. The worker running it notices on its next heartbeat
+func (es *ExportService) CancelExport(ctx context.Context, id uuid.UUID) (*domain.ExportJob, error) {
+	job, err := es.repo.CancelExportJob(ctx, id)
+	if err == nil {
+		return job, nil
+	}
+	if !errors.Is(err, domain.ErrDataNotFound) {
+		return nil, domain.ErrInternal
+	}
+
+	// Either the job does not exist or it already finished
+	job, err = es.GetExport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.State == domain.ExportCanceled {
+		return job, nil
+	}
+	return nil, domain.ErrExportFinished
+}
+
+// OpenExport opens the file of a completed export job that has not expired
+func (es *ExportService) OpenExport(ctx context.Context, id uuid.UUID) (*domain.ExportJob, io.ReadSeekCloser, error) {
+	job, err := es.GetExport(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case !job.State.IsFinal():
+		return nil, nil, domain.ErrExportNotReady
+	case job.State != domain.ExportCompleted, job.IsExpired(time.Now()):
+		return nil, nil, domain.ErrExportUnavailable
+	}
+
+	file, err := es.storage.Open(ctx, job.FileName(job.Attempts))
+	if err != nil {
+		slog.Error("Error opening export file", "id", job.ID, "error", err)
+		return nil, nil, domain.ErrExportUnavailable
+	}
+
+	return job, file, nil
+}
+
+// RunNextExport claims the next export job waiting for a worker and writes its file. A job abandoned by a worker
+// that stopped without finishing it is claimed again once its heartbeat is stale, up to exportMaxAttempts runs
+// after which ExpireExports fails it.
+// When ctx is done while the job runs, it is left running for another worker to claim it again
+func (es *ExportService) RunNextExport(ctx context.Context) (bool, error) {
+	now := time.Now()
+	job, err := es.repo.ClaimExportJob(ctx, now.Add(-exportStaleAfter), exportMaxAttempts)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return false, nil
+		}
+		return false, domain.ErrInternal
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	var rows atomic.Int64
+	done := make(chan struct{})
+	defer close(done)
+	go es.heartbeat(runCtx, cancel, job, &rows, done)
+
+	name := job.FileName(job.Attempts)
+	size, err := es.writeExport(runCtx, name, job, &rows)
+
+	switch {
+	case errors.Is(context.Cause(runCtx), errExportCanceled):
+		es.removeFile(name)
+		return true, nil
+	case ctx.Err() != nil:
+		es.removeFile(name)
+		return true, ctx.Err()
+	}
+
+	finished := time.Now()
+	job.Rows = rows.Load()
+	job.FinishedAt = &finished
+	job.ExpiresAt = finished.Add(es.retention)
+	if err != nil {
+		slog.Error("Error running export job", "id", job.ID, "attempt", job.Attempts, "error", err)
+		es.removeFile(name)
+		job.State = domain.ExportFailed
+		job.Error = exportErrorMessage(err)
+	} else {
+		job.State = domain.ExportCompleted
+		job.Size = size
+	}
+
+	if _, err := es.repo.FinishExportJob(ctx, job); err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			// Canceled between the last heartbeat and the end of the export
+			es.removeFile(name)
+			return true, nil
+		}
+		return true, domain.ErrInternal
+	}
+
+	return true, nil
+}
+
+// writeExport writes the file of an export job to the storage and returns its size
+func (es *ExportService) writeExport(ctx context.Context, name string, job *domain.ExportJob, rows *atomic.Int64) (int64, error) {
+	file, err := es.storage.Create(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	w := &countingWriter{w: file}
+	err = es.exporter.ExportProducts(ctx, w, job.Format, job.Filter, job.Options, rows.Store)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return w.n, err
+}
+
+// heartbeat records the progress of a running export job until done is closed,
+// and cancels the run when the job was canceled or claimed by another worker
+func (es *ExportService) heartbeat(ctx context.Context, cancel context.CancelCauseFunc, job *domain.ExportJob, rows *atomic.Int64, done <-chan struct{}) {
+	ticker := time.NewTicker(es.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := es.repo.UpdateExportProgress(ctx, job.ID, job.Attempts, rows.Load())
+			if errors.Is(err, domain.ErrDataNotFound) {
+				cancel(errExportCanceled)
+				return
+			}
+			if err != nil {
+				slog.Warn("Error recording export progress", "id", job.ID, "error", err)
+			}
+		}
+	}
+}
+
+// ExpireExports fails the export jobs abandoned by their worker on their last attempt, which would otherwise
+// be left running as they are not claimed again, then removes the files of the export jobs past their retention
+// and marks them as expired
+func (es *ExportService) ExpireExports(ctx context.Context) (int, error) {
+	now := time.Now()
+	abandoned, err := es.repo.FailAbandonedExportJobs(ctx, now.Add(-exportStaleAfter), exportMaxAttempts, exportAbandonedMessage)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+	for _, job := range abandoned {
+		slog.Warn("Export job failed after being abandoned on its last attempt", "id", job.ID, "attempts", job.Attempts)
+		es.removeFiles(ctx, &job)
+	}
+
+	jobs, err := es.repo.ListExpiredExportJobs(ctx, now, now.Add(-exportStaleAfter), expiredBatchSize)
+	if err != nil {
+		return 0, domain.ErrInternal
+	}
+
+	expired := 0
+	for _, job := range jobs {
+		if !es.removeFiles(ctx, &job) {
+			continue
+		}
+
+		if err := es.repo.ExpireExportJob(ctx, job.ID); err != nil {
+			return expired, domain.ErrInternal
+		}
+		expired++
+	}
+
+	return expired, nil
+}
+
+// removeFiles removes the files of every attempt of an export job and reports whether they were all removed
+func (es *ExportService) removeFiles(ctx context.Context, job *domain.ExportJob) bool {
+	removed := true
+	for attempt := 1; attempt <= job.Attempts; attempt++ {
+		if err := es.storage.Remove(ctx, job.FileName(attempt)); err != nil {
+			slog.Error("Error removing export file", "id", job.ID, "attempt", attempt, "error", err)
+			removed = false
+		}
+	}
+	return removed
+}
+
+// removeFile removes the file of an attempt that did not complete
+func (es *ExportService) removeFile(name string) {
+	if err := es.storage.Remove(context.Background(), name); err != nil {
+		slog.Error("Error removing export file", "name", name, "error", err)
+	}
+}
+
+// exportErrorMessage returns the reason an export job failed shown to the client,
+// which does not disclose internal errors
+func exportErrorMessage(err error) string {
+	for _, target := range exportErrors {
+		if errors.Is(err, target) {
+			return err.Error()
+		}
+	}
+	return domain.ErrInternal.Error()
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write writes to w and counts the bytes written
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
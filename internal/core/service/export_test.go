@@ -0,0 +1,443 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// fakeExportRepository keeps the export jobs in memory with the state changes of the database
+type fakeExportRepository struct {
+	mu   sync.Mutex
+	jobs map[uuid.UUID]*domain.ExportJob
+}
+
+func newFakeExportRepository(jobs ...domain.ExportJob) *fakeExportRepository {
+	repo := &fakeExportRepository{jobs: make(map[uuid.UUID]*domain.ExportJob)}
+	for i := range jobs {
+		repo.jobs[jobs[i].ID] = &jobs[i]
+	}
+	return repo
+}
+
+// job returns a copy of the export job with the id
+func (r *fakeExportRepository) job(id uuid.UUID) domain.ExportJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return *r.jobs[id]
+}
+
+func (r *fakeExportRepository) CreateExportJob(_ context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	created := *job
+	r.jobs[job.ID] = &created
+	return job, nil
+}
+
+func (r *fakeExportRepository) GetExportJobByID(_ context.Context, id uuid.UUID) (*domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	found := *job
+	return &found, nil
+}
+
+func (r *fakeExportRepository) ClaimExportJob(_ context.Context, staleBefore time.Time, maxAttempts int) (*domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		stale := job.State == domain.ExportRunning && job.HeartbeatAt.Before(staleBefore)
+		if (job.State == domain.ExportPending || stale) && job.Attempts < maxAttempts {
+			now := time.Now()
+			job.State = domain.ExportRunning
+			job.Attempts++
+			job.Rows = 0
+			job.StartedAt = &now
+			job.HeartbeatAt = &now
+			claimed := *job
+			return &claimed, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (r *fakeExportRepository) UpdateExportProgress(_ context.Context, id uuid.UUID, attempt int, rows int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := r.jobs[id]
+	if job.State != domain.ExportRunning || job.Attempts != attempt {
+		return domain.ErrDataNotFound
+	}
+	now := time.Now()
+	job.Rows = rows
+	job.HeartbeatAt = &now
+	return nil
+}
+
+func (r *fakeExportRepository) FinishExportJob(_ context.Context, job *domain.ExportJob) (*domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := r.jobs[job.ID]
+	if stored.State != domain.ExportRunning || stored.Attempts != job.Attempts {
+		return nil, domain.ErrDataNotFound
+	}
+	*stored = *job
+	return job, nil
+}
+
+func (r *fakeExportRepository) CancelExportJob(_ context.Context, id uuid.UUID) (*domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok || job.State.IsFinal() {
+		return nil, domain.ErrDataNotFound
+	}
+	job.State = domain.ExportCanceled
+	canceled := *job
+	return &canceled, nil
+}
+
+func (r *fakeExportRepository) ListExpiredExportJobs(_ context.Context, at, staleBefore time.Time, limit uint64) ([]domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []domain.ExportJob
+	for _, job := range r.jobs {
+		running := job.State == domain.ExportRunning && !job.HeartbeatAt.Before(staleBefore)
+		if job.State != domain.ExportExpired && !job.ExpiresAt.After(at) && !running && uint64(len(jobs)) < limit {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *fakeExportRepository) ExpireExportJob(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[id].State = domain.ExportExpired
+	return nil
+}
+
+func (r *fakeExportRepository) FailAbandonedExportJobs(_ context.Context, staleBefore time.Time, maxAttempts int, message string) ([]domain.ExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var jobs []domain.ExportJob
+	for _, job := range r.jobs {
+		if job.State == domain.ExportRunning && job.HeartbeatAt.Before(staleBefore) && job.Attempts >= maxAttempts {
+			now := time.Now()
+			job.State = domain.ExportFailed
+			job.Error = message
+			job.FinishedAt = &now
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+// fakeExportStorage keeps the files in memory
+type fakeExportStorage struct {
+	mu    sync.Mutex
+	files map[string]*bytes.Buffer
+}
+
+func newFakeExportStorage(names ...string) *fakeExportStorage {
+	storage := &fakeExportStorage{files: make(map[string]*bytes.Buffer)}
+	for _, name := range names {
+		storage.files[name] = bytes.NewBufferString("content")
+	}
+	return storage
+}
+
+// has reports whether the file exists
+func (s *fakeExportStorage) has(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.files[name]
+	return ok
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+type nopReadSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopReadSeekCloser) Close() error {
+	return nil
+}
+
+func (s *fakeExportStorage) Create(_ context.Context, name string) (io.WriteCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[name] = &bytes.Buffer{}
+	return nopWriteCloser{s.files[name]}, nil
+}
+
+func (s *fakeExportStorage) Open(_ context.Context, name string) (io.ReadSeekCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	file, ok := s.files[name]
+	if !ok {
+		return nil, errors.New("no such file")
+	}
+	return nopReadSeekCloser{bytes.NewReader(file.Bytes())}, nil
+}
+
+func (s *fakeExportStorage) Remove(_ context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, name)
+	return nil
+}
+
+// fakeProductExporter runs export to write the products
+type fakeProductExporter struct {
+	export func(ctx context.Context, w io.Writer, progress func(rows int64)) error
+}
+
+func (e *fakeProductExporter) ValidateExport(_ context.Context, _ domain.ExportFormat, _ domain.ExportOptions) error {
+	return nil
+}
+
+func (e *fakeProductExporter) ExportProducts(ctx context.Context, w io.Writer, _ domain.ExportFormat, _ domain.ProductFilter, _ domain.ExportOptions, progress func(rows int64)) error {
+	return e.export(ctx, w, progress)
+}
+
+func (e *fakeProductExporter) ExportLabels(_ context.Context, _ io.Writer, _ domain.ProductFilter, _ domain.LabelOptions) error {
+	return errors.New("not implemented")
+}
+
+// createTestExport queues a CSV export job
+func createTestExport(t *testing.T, es *ExportService) *domain.ExportJob {
+	t.Helper()
+
+	job, err := es.CreateExport(context.Background(), &domain.ExportJob{Format: domain.ExportCSV})
+	if err != nil {
+		t.Fatalf("CreateExport() error = %v", err)
+	}
+	return job
+}
+
+func TestRunNextExportCompletes(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeExportRepository()
+	storage := newFakeExportStorage()
+	exporter := &fakeProductExporter{export: func(_ context.Context, w io.Writer, progress func(rows int64)) error {
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "product %d\n", i)
+			progress(int64(i))
+		}
+		return nil
+	}}
+	es := NewExportService(repo, storage, exporter, time.Hour)
+	created := createTestExport(t, es)
+
+	ran, err := es.RunNextExport(ctx)
+	if !ran || err != nil {
+		t.Fatalf("RunNextExport() = %v, %v, want the job run", ran, err)
+	}
+
+	job := repo.job(created.ID)
+	want := int64(len("product 1\nproduct 2\nproduct 3\n"))
+	if job.State != domain.ExportCompleted || job.Attempts != 1 || job.Rows != 3 || job.Size != want {
+		t.Errorf("job = %s, attempt %d, %d rows, %d bytes, want completed, attempt 1, 3 rows, %d bytes",
+			job.State, job.Attempts, job.Rows, job.Size, want)
+	}
+	if !job.ExpiresAt.After(*job.FinishedAt) {
+		t.Errorf("job expires at %v, want after it finished at %v", job.ExpiresAt, job.FinishedAt)
+	}
+
+	_, file, err := es.OpenExport(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("OpenExport() error = %v", err)
+	}
+	defer file.Close()
+	if content, _ := io.ReadAll(file); int64(len(content)) != want {
+		t.Errorf("OpenExport() file = %q, want %d bytes", content, want)
+	}
+
+	if ran, err := es.RunNextExport(ctx); ran || err != nil {
+		t.Errorf("RunNextExport() without pending job = %v, %v, want none run", ran, err)
+	}
+}
+
+func TestRunNextExportCanceledWhileRunning(t *testing.T) {
+	ctx := context.Background()
+	repo := newFakeExportRepository()
+	storage := newFakeExportStorage()
+	started := make(chan struct{})
+	exporter := &fakeProductExporter{export: func(ctx context.Context, w io.Writer, progress func(rows int64)) error {
+		fmt.Fprintln(w, "product 1")
+		progress(1)
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	es := NewExportService(repo, storage, exporter, time.Hour)
+	es.heartbeatInterval = 10 * time.Millisecond
+	created := createTestExport(t, es)
+
+	go func() {
+		<-started
+		if _, err := es.CancelExport(ctx, created.ID); err != nil {
+			t.Errorf("CancelExport() error = %v", err)
+		}
+	}()
+
+	ran, err := es.RunNextExport(ctx)
+	if !ran || err != nil {
+		t.Fatalf("RunNextExport() = %v, %v, want the job run", ran, err)
+	}
+
+	job := repo.job(created.ID)
+	if job.State != domain.ExportCanceled {
+		t.Errorf("job state = %s, want canceled", job.State)
+	}
+	if storage.has(job.FileName(1)) {
+		t.Errorf("file %s of the canceled job was not removed", job.FileName(1))
+	}
+}
+
+func TestRunNextExportFails(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		wantError string
+	}{
+		{
+			name:      "internal error hidden",
+			err:       errors.New("read tcp 10.0.0.5:5432: connection reset by peer"),
+			wantError: domain.ErrInternal.Error(),
+		},
+		{
+			name:      "client error shown",
+			err:       fmt.Errorf("%w: JPY", domain.ErrUnknownCurrency),
+			wantError: fmt.Errorf("%w: JPY", domain.ErrUnknownCurrency).Error(),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newFakeExportRepository()
+			storage := newFakeExportStorage()
+			exporter := &fakeProductExporter{export: func(_ context.Context, w io.Writer, _ func(rows int64)) error {
+				fmt.Fprintln(w, "product 1")
+				return tt.err
+			}}
+			es := NewExportService(repo, storage, exporter, time.Hour)
+			created := createTestExport(t, es)
+
+			if ran, err := es.RunNextExport(context.Background()); !ran || err != nil {
+				t.Fatalf("RunNextExport() = %v, %v, want the job run", ran, err)
+			}
+
+			job := repo.job(created.ID)
+			if job.State != domain.ExportFailed || job.Error != tt.wantError {
+				t.Errorf("job = %s, error %q, want failed, error %q", job.State, job.Error, tt.wantError)
+			}
+			if storage.has(job.FileName(1)) {
+				t.Errorf("file %s of the failed job was not removed", job.FileName(1))
+			}
+		})
+	}
+}
+
+func TestExpireExports(t *testing.T) {
+	now := time.Now()
+	stale := now.Add(-2 * exportStaleAfter)
+	fresh := now
+
+	completed := domain.ExportJob{ID: uuid.New(), Format: domain.ExportCSV, State: domain.ExportCompleted, Attempts: 3, ExpiresAt: now.Add(-time.Minute)}
+	retained := domain.ExportJob{ID: uuid.New(), Format: domain.ExportCSV, State: domain.ExportCompleted, Attempts: 1, ExpiresAt: now.Add(time.Hour)}
+	abandoned := domain.ExportJob{ID: uuid.New(), Format: domain.ExportCSV, State: domain.ExportRunning, Attempts: exportMaxAttempts, HeartbeatAt: &stale, ExpiresAt: now.Add(time.Hour)}
+	running := domain.ExportJob{ID: uuid.New(), Format: domain.ExportCSV, State: domain.ExportRunning, Attempts: exportMaxAttempts, HeartbeatAt: &fresh, ExpiresAt: now.Add(time.Hour)}
+
+	var names []string
+	for _, job := range []domain.ExportJob{completed, retained, abandoned, running} {
+		for attempt := 1; attempt <= job.Attempts; attempt++ {
+			names = append(names, job.FileName(attempt))
+		}
+	}
+	repo := newFakeExportRepository(completed, retained, abandoned, running)
+	storage := newFakeExportStorage(names...)
+	es := NewExportService(repo, storage, &fakeProductExporter{}, time.Hour)
+
+	expired, err := es.ExpireExports(context.Background())
+	if expired != 1 || err != nil {
+		t.Fatalf("ExpireExports() = %d, %v, want 1 expired", expired, err)
+	}
+
+	tests := []struct {
+		job       domain.ExportJob
+		wantState domain.ExportState
+		wantFiles bool
+	}{
+		{job: completed, wantState: domain.ExportExpired},
+		{job: retained, wantState: domain.ExportCompleted, wantFiles: true},
+		{job: abandoned, wantState: domain.ExportFailed},
+		{job: running, wantState: domain.ExportRunning, wantFiles: true},
+	}
+	for _, tt := range tests {
+		job := repo.job(tt.job.ID)
+		if job.State != tt.wantState {
+			t.Errorf("job %s state = %s, want %s", job.ID, job.State, tt.wantState)
+		}
+		for attempt := 1; attempt <= job.Attempts; attempt++ {
+			if storage.has(job.FileName(attempt)) != tt.wantFiles {
+				t.Errorf("file %s kept = %v, want %v", job.FileName(attempt), !tt.wantFiles, tt.wantFiles)
+			}
+		}
+	}
+
+	if job := repo.job(abandoned.ID); job.Error != exportAbandonedMessage {
+		t.Errorf("abandoned job error = %q, want %q", job.Error, exportAbandonedMessage)
+	}
+}
+
+func TestOpenExportUnavailable(t *testing.T) {
+	now := time.Now()
+	pending := domain.ExportJob{ID: uuid.New(), Format: domain.ExportCSV, State: domain.ExportPending, ExpiresAt: now.Add(time.Hour)}
+	running := domain.ExportJob{ID: uuid.New(), Format: domain.ExportCSV, State: domain.ExportRunning, Attempts: 1, HeartbeatAt: &now, ExpiresAt: now.Add(time.Hour)}
+	expired := domain.ExportJob{ID: uuid.New(), Format: domain.ExportCSV, State: domain.ExportCompleted, Attempts: 1, ExpiresAt: now.Add(-time.Minute)}
+	failed := domain.ExportJob{ID: uuid.New(), Format: domain.ExportCSV, State: domain.ExportFailed, Attempts: 1, ExpiresAt: now.Add(time.Hour)}
+
+	repo := newFakeExportRepository(pending, running, expired, failed)
+	storage := newFakeExportStorage(expired.FileName(1))
+	es := NewExportService(repo, storage, &fakeProductExporter{}, time.Hour)
+
+	tests := []struct {
+		name    string
+		id      uuid.UUID
+		wantErr error
+	}{
+		{name: "pending", id: pending.ID, wantErr: domain.ErrExportNotReady},
+		{name: "running", id: running.ID, wantErr: domain.ErrExportNotReady},
+		{name: "expired", id: expired.ID, wantErr: domain.ErrExportUnavailable},
+		{name: "failed", id: failed.ID, wantErr: domain.ErrExportUnavailable},
+		{name: "unknown", id: uuid.New(), wantErr: domain.ErrDataNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, file, err := es.OpenExport(context.Background(), tt.id)
+			if !errors.Is(err, tt.wantErr) || file != nil {
+				t.Errorf("OpenExport() = %v, %v, want %v", file, err, tt.wantErr)
+			}
+		})
+	}
+}
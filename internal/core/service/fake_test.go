@@ -0,0 +1,465 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+// The fakes embed the port they implement, a method they do not override panics when called
+
+// fakeTransactor runs fn without a transaction
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// fakeCache keeps the values in memory without expiring them
+type fakeCache struct {
+	mu     sync.Mutex
+	values map[string][]byte
+}
+
+func newFakeCache() *fakeCache {
+	return &fakeCache{values: make(map[string][]byte)}
+}
+
+func (c *fakeCache) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	return nil
+}
+
+func (c *fakeCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	value, ok := c.values[key]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return value, nil
+}
+
+func (c *fakeCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.values, key)
+	return nil
+}
+
+func (c *fakeCache) DeleteByPrefix(_ context.Context, prefix string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.values {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.values, key)
+		}
+	}
+	return nil
+}
+
+func (c *fakeCache) Close() error {
+	return nil
+}
+
+// fakeProductRepository keeps the products in memory
+type fakeProductRepository struct {
+	port.ProductRepository
+	products map[uuid.UUID]domain.Product
+	// byIDsReads counts the calls to GetProductsByIDs
+	byIDsReads int
+	// failWrites fails the writes of the products with the names, WriteProducts writing nothing when one fails
+	failWrites map[string]error
+	// writeCalls counts the calls to WriteProducts
+	writeCalls int
+	// concurrentWrite edits the products locked by the next lock, as a write committed while waiting for the lock
+	concurrentWrite func(product *domain.Product)
+}
+
+func newFakeProductRepository(products ...domain.Product) *fakeProductRepository {
+	repo := &fakeProductRepository{products: make(map[uuid.UUID]domain.Product)}
+	for _, product := range products {
+		repo.products[product.ID] = product
+	}
+	return repo
+}
+
+func (r *fakeProductRepository) GetProductByID(_ context.Context, id uuid.UUID, includeDeleted bool) (*domain.Product, error) {
+	product, ok := r.products[id]
+	if !ok || (product.DeletedAt != nil && !includeDeleted) {
+		return nil, domain.ErrDataNotFound
+	}
+	return &product, nil
+}
+
+func (r *fakeProductRepository) GetProductForUpdate(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	r.writeConcurrently(id)
+	return r.GetProductByID(ctx, id, false)
+}
+
+func (r *fakeProductRepository) GetProductsForUpdate(ctx context.Context, ids []uuid.UUID) ([]domain.Product, error) {
+	r.writeConcurrently(ids...)
+	return r.GetProductsByIDs(ctx, ids)
+}
+
+// writeConcurrently applies the concurrent write to the products once
+func (r *fakeProductRepository) writeConcurrently(ids ...uuid.UUID) {
+	if r.concurrentWrite == nil {
+		return
+	}
+	for _, id := range ids {
+		if product, ok := r.products[id]; ok {
+			r.concurrentWrite(&product)
+			r.products[id] = product
+		}
+	}
+	r.concurrentWrite = nil
+}
+
+func (r *fakeProductRepository) GetProductsByIDs(_ context.Context, ids []uuid.UUID) ([]domain.Product, error) {
+	r.byIDsReads++
+	var products []domain.Product
+	for _, id := range ids {
+		if product, ok := r.products[id]; ok && product.DeletedAt == nil {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (r *fakeProductRepository) UpdateProduct(_ context.Context, product *domain.Product, updatedFields ...string) (*domain.Product, error) {
+	stored, ok := r.products[product.ID]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	*product = updateFields(stored, product, updatedFields)
+	r.products[product.ID] = *product
+	return product, nil
+}
+
+// updateFields returns the stored product with the updated fields of the product, as updated in the database
+func updateFields(stored domain.Product, product *domain.Product, fields []string) domain.Product {
+	for _, field := range fields {
+		switch field {
+		case "reference":
+			stored.Reference = product.Reference
+		case "name":
+			stored.Name = product.Name
+		case "status":
+			stored.Status = product.Status
+		case "category_id":
+			stored.CategoryID = product.CategoryID
+		case "price":
+			stored.Price = product.Price
+		case "quantity":
+			stored.Quantity = product.Quantity
+		case "stock_city":
+			stored.StockCity = product.StockCity
+		case "stock_coordinates":
+			stored.StockCoordinates = product.StockCoordinates
+		}
+	}
+	return stored
+}
+
+func (r *fakeProductRepository) ListProducts(_ context.Context, _ domain.ProductFilter, _ *domain.ProductView, _, _ uint64) ([]domain.Product, error) {
+	var products []domain.Product
+	for _, product := range r.products {
+		if product.DeletedAt == nil {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (r *fakeProductRepository) DeleteProduct(_ context.Context, id uuid.UUID) error {
+	product, ok := r.products[id]
+	if !ok || product.DeletedAt != nil {
+		return domain.ErrDataNotFound
+	}
+	now := time.Now()
+	product.DeletedAt = &now
+	r.products[id] = product
+	return nil
+}
+
+func (r *fakeProductRepository) RestoreProduct(_ context.Context, id uuid.UUID) (*domain.Product, error) {
+	product, ok := r.products[id]
+	if !ok || product.DeletedAt == nil {
+		return nil, domain.ErrDataNotFound
+	}
+	product.DeletedAt = nil
+	r.products[id] = product
+	return &product, nil
+}
+
+func (r *fakeProductRepository) GetProductsByReferences(_ context.Context, references []string) ([]domain.Product, error) {
+	var products []domain.Product
+	for _, product := range r.products {
+		if slices.Contains(references, product.Reference) {
+			products = append(products, product)
+		}
+	}
+	return products, nil
+}
+
+func (r *fakeProductRepository) WriteProducts(_ context.Context, writes []domain.ProductWrite) error {
+	r.writeCalls++
+	for i, write := range writes {
+		if err, ok := r.failWrites[write.Product.Name]; ok {
+			return &domain.BatchError{Index: i, Err: err}
+		}
+	}
+
+	for _, write := range writes {
+		product := *write.Product
+		switch write.Method {
+		case domain.OperationUpdate:
+			product = updateFields(r.products[product.ID], write.Product, write.Fields)
+			*write.Product = product
+		case domain.OperationDelete:
+			product = r.products[product.ID]
+			now := time.Now()
+			product.DeletedAt = &now
+		}
+		r.products[product.ID] = product
+	}
+	return nil
+}
+
+// fakeCategoryRepository keeps the categories in memory
+type fakeCategoryRepository struct {
+	port.CategoryRepository
+	categories map[uuid.UUID]domain.Category
+}
+
+func (r *fakeCategoryRepository) GetCategoryByID(_ context.Context, id uuid.UUID, includeDeleted bool) (*domain.Category, error) {
+	category, ok := r.categories[id]
+	if !ok || (category.DeletedAt != nil && !includeDeleted) {
+		return nil, domain.ErrDataNotFound
+	}
+	return &category, nil
+}
+
+func (r *fakeCategoryRepository) GetCategoryForUpdate(ctx context.Context, id uuid.UUID) (*domain.Category, error) {
+	return r.GetCategoryByID(ctx, id, false)
+}
+
+func (r *fakeCategoryRepository) UpdateCategory(_ context.Context, category *domain.Category) (*domain.Category, error) {
+	stored, ok := r.categories[category.ID]
+	if !ok || stored.DeletedAt != nil {
+		return nil, domain.ErrDataNotFound
+	}
+	stored.Name = category.Name
+	r.categories[category.ID] = stored
+	*category = stored
+	return category, nil
+}
+
+func (r *fakeCategoryRepository) DeleteCategory(_ context.Context, id uuid.UUID) error {
+	category, ok := r.categories[id]
+	if !ok || category.DeletedAt != nil {
+		return domain.ErrForeignKeyViolation
+	}
+	now := time.Now()
+	category.DeletedAt = &now
+	r.categories[id] = category
+	return nil
+}
+
+func (r *fakeCategoryRepository) ListCategoryIDs(_ context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var existing []uuid.UUID
+	for _, id := range ids {
+		if category, ok := r.categories[id]; ok && category.DeletedAt == nil {
+			existing = append(existing, id)
+		}
+	}
+	return existing, nil
+}
+
+// fakeStockRepository records the stock movements and holds the stock of the products by warehouse id
+type fakeStockRepository struct {
+	port.StockRepository
+	movements []domain.StockMovement
+	stocks    map[uuid.UUID]map[uuid.UUID]int
+}
+
+func (r *fakeStockRepository) CreateStockMovement(_ context.Context, movement *domain.StockMovement) (*domain.StockMovement, error) {
+	r.movements = append(r.movements, *movement)
+	return movement, nil
+}
+
+func (r *fakeStockRepository) CreateStockMovements(_ context.Context, movements []domain.StockMovement) error {
+	r.movements = append(r.movements, movements...)
+	return nil
+}
+
+func (r *fakeStockRepository) GetWarehouseStocks(_ context.Context, productIDs []uuid.UUID) (map[uuid.UUID][]domain.WarehouseStock, error) {
+	stocks := make(map[uuid.UUID][]domain.WarehouseStock)
+	for _, productID := range productIDs {
+		for warehouseID, quantity := range r.stocks[productID] {
+			stocks[productID] = append(stocks[productID], domain.WarehouseStock{
+				ProductID: productID,
+				Warehouse: domain.Warehouse{ID: warehouseID},
+				Quantity:  quantity,
+			})
+		}
+	}
+	return stocks, nil
+}
+
+func (r *fakeStockRepository) SetWarehouseStock(_ context.Context, productID, warehouseID uuid.UUID, quantity int) error {
+	if r.stocks == nil {
+		r.stocks = make(map[uuid.UUID]map[uuid.UUID]int)
+	}
+	if r.stocks[productID] == nil {
+		r.stocks[productID] = make(map[uuid.UUID]int)
+	}
+	r.stocks[productID][warehouseID] = quantity
+	return nil
+}
+
+// fakeWarehouseRepository holds the warehouses, a warehouse holding stock in the stock repository can not be deleted
+type fakeWarehouseRepository struct {
+	port.WarehouseRepository
+	warehouses map[uuid.UUID]domain.Warehouse
+	stockRepo  *fakeStockRepository
+}
+
+func (r *fakeWarehouseRepository) CreateWarehouse(_ context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	r.warehouses[warehouse.ID] = *warehouse
+	return warehouse, nil
+}
+
+func (r *fakeWarehouseRepository) GetWarehouseByID(_ context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	warehouse, ok := r.warehouses[id]
+	if !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	return &warehouse, nil
+}
+
+func (r *fakeWarehouseRepository) UpdateWarehouse(_ context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	if _, ok := r.warehouses[warehouse.ID]; !ok {
+		return nil, domain.ErrDataNotFound
+	}
+	r.warehouses[warehouse.ID] = *warehouse
+	return warehouse, nil
+}
+
+func (r *fakeWarehouseRepository) DeleteWarehouse(_ context.Context, id uuid.UUID) error {
+	if _, ok := r.warehouses[id]; !ok {
+		return domain.ErrDataNotFound
+	}
+	for _, stocks := range r.stockRepo.stocks {
+		if stocks[id] > 0 {
+			return domain.ErrForeignKeyViolation
+		}
+	}
+	delete(r.warehouses, id)
+	return nil
+}
+
+// fakePriceRepository holds the due price changes, no scheduled price changing the effective prices
+type fakePriceRepository struct {
+	port.PriceRepository
+	due     []domain.PriceChange
+	applied []uuid.UUID
+	// created are the price changes created
+	created []domain.PriceChange
+	// failApplying fails marking the price change with the id applied
+	failApplying uuid.UUID
+	// delayed are the times the delayed price changes are retried at
+	delayed map[uuid.UUID]time.Time
+}
+
+func (r *fakePriceRepository) GetEffectivePrices(_ context.Context, _ []uuid.UUID, _ time.Time) (map[uuid.UUID]domain.Money, error) {
+	return map[uuid.UUID]domain.Money{}, nil
+}
+
+func (r *fakePriceRepository) CreatePriceChange(_ context.Context, change *domain.PriceChange) (*domain.PriceChange, error) {
+	r.created = append(r.created, *change)
+	return change, nil
+}
+
+func (r *fakePriceRepository) CreatePriceChanges(_ context.Context, changes []domain.PriceChange) error {
+	r.created = append(r.created, changes...)
+	return nil
+}
+
+func (r *fakePriceRepository) ClaimDuePriceChange(_ context.Context, at time.Time) (*domain.PriceChange, error) {
+	for _, change := range r.due {
+		retryAt, delayed := r.delayed[change.ID]
+		if !slices.Contains(r.applied, change.ID) && (!delayed || !retryAt.After(at)) {
+			return &change, nil
+		}
+	}
+	return nil, domain.ErrDataNotFound
+}
+
+func (r *fakePriceRepository) MarkPriceChangeApplied(_ context.Context, id uuid.UUID, _ time.Time) error {
+	if id == r.failApplying {
+		return errors.New("price change can not be applied")
+	}
+	if slices.Contains(r.applied, id) {
+		return domain.ErrPriceChangeApplied
+	}
+	r.applied = append(r.applied, id)
+	return nil
+}
+
+func (r *fakePriceRepository) DelayPriceChange(_ context.Context, id uuid.UUID, retryAt time.Time) error {
+	if r.delayed == nil {
+		r.delayed = make(map[uuid.UUID]time.Time)
+	}
+	r.delayed[id] = retryAt
+	return nil
+}
+
+func (r *fakePriceRepository) CancelPriceChange(_ context.Context, id uuid.UUID) (*domain.PriceChange, error) {
+	r.applied = append(r.applied, id)
+	return &domain.PriceChange{ID: id}, nil
+}
+
+// fakeAuditRepository records the audit entries
+type fakeAuditRepository struct {
+	port.AuditRepository
+	entries []domain.AuditEntry
+}
+
+func (r *fakeAuditRepository) CreateAuditEntry(_ context.Context, entry *domain.AuditEntry) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *fakeAuditRepository) CreateAuditEntries(_ context.Context, entries []domain.AuditEntry) error {
+	r.entries = append(r.entries, entries...)
+	return nil
+}
+
+// fakeGeoClient locates every city and ip address at the origin, or fails with err
+type fakeGeoClient struct {
+	err error
+}
+
+func (c *fakeGeoClient) GetCityLocation(context.Context, string) (float64, float64, error) {
+	return 0, 0, c.err
+}
+
+func (c *fakeGeoClient) GetIPLocation(context.Context, string) (float64, float64, error) {
+	return 0, 0, c.err
+}
+
+func (c *fakeGeoClient) GetDistance(lat1, lon1, lat2, lon2 float64) float64 {
+	return math.Hypot(lat2-lat1, lon2-lon1)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/util"
+)
+
+// repositoryErrors are the domain errors a repository can return that are
+// meaningful to the client and must not be masked as internal errors
+var repositoryErrors = []error{
+	domain.ErrDataNotFound,
+	domain.ErrConflictingData,
+	domain.ErrForeignKeyViolation,
+	domain.ErrInvalidReference,
+	domain.ErrMissingRequiredData,
+	domain.ErrConstraintViolation,
+	domain.ErrConcurrentUpdate,
+}
+
+// isRepositoryError reports whether the error wraps one of the repository domain errors
+func isRepositoryError(err error) bool {
+	for _, target := range repositoryErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// newAuditEntry creates an audit entry for a change made by the actor of the request carried by the context
+func newAuditEntry(ctx context.Context, entityType domain.AuditEntity, entityID uuid.UUID, action domain.AuditAction, changes []domain.FieldChange) *domain.AuditEntry {
+	return &domain.AuditEntry{
+		ID:           uuid.New(),
+		EntityType:   entityType,
+		EntityID:     entityID,
+		Action:       action,
+		Actor:        util.ActorFromContext(ctx),
+		ClaimedActor: util.ClaimedActorFromContext(ctx),
+		RequestID:    util.RequestIDFromContext(ctx),
+		Changes:      changes,
+		CreatedAt:    time.Now(),
+	}
+}
+
+// forgetCachedProducts removes products from the cache GetProducts reads them from, a failure only being logged.
+// Every service writing a product field must forget the product once the write is committed
+func forgetCachedProducts(ctx context.Context, cache port.CacheRepository, ids ...uuid.UUID) {
+	if cache == nil {
+		return
+	}
+
+	for _, id := range ids {
+		key := util.GenerateCacheKey("product", id)
+		if err := cache.Delete(ctx, key); err != nil {
+			slog.Warn("Error removing cached product", "key", key, "error", err)
+		}
+	}
+}
@@ -0,0 +1,368 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// importUpdate is an upserted row of an import with the product it updates
+type importUpdate struct {
+	before  *domain.Product
+	product *domain.Product
+	fields  []string
+}
+
+// ImportProducts validates the rows of a product import and writes the valid ones in a single transaction.
+// A row is rejected when it could not be read, its reference is taken by a deleted product, by another row or,
+// without upsert, by an existing product, its category or supplier does not exist, or its price or status is invalid.
+// An atomic import writes nothing when a row is rejected, a partial one writes the valid rows and a dry run writes nothing
+func (ps *ProductService) ImportProducts(ctx context.Context, rows []domain.ImportRow, options domain.ImportOptions) (*domain.ImportReport, error) {
+	if options.Mode == "" {
+		options.Mode = domain.ImportAtomic
+	}
+
+	report := &domain.ImportReport{
+		Rows:   len(rows),
+		DryRun: options.DryRun,
+	}
+
+	existing, categories, suppliers, err := ps.lookupImportReferences(ctx, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	var creates []*domain.Product
+	var updates []importUpdate
+	firstLines := make(map[string]int, len(rows))
+
+	for i := range rows {
+		row := &rows[i]
+		product := row.Product
+		errs := slices.Clone(row.Errors)
+		rejectf := func(format string, args ...any) {
+			errs = append(errs, fmt.Sprintf(format, args...))
+		}
+
+		if product.Reference != "" {
+			if line, ok := firstLines[product.Reference]; ok {
+				rejectf("reference: duplicates line %d", line)
+			} else {
+				firstLines[product.Reference] = row.Line
+			}
+		}
+		if product.CategoryID != nil && !categories[*product.CategoryID] {
+			rejectf("category_id: %v", domain.ErrDataNotFound)
+		}
+		if product.SupplierID != nil && !suppliers[*product.SupplierID] {
+			rejectf("supplier_id: %v", domain.ErrDataNotFound)
+		}
+
+		before, exists := existing[product.Reference]
+		switch {
+		case exists && before.DeletedAt != nil:
+			rejectf("reference: taken by a deleted product")
+		case exists && !options.Upsert:
+			rejectf("reference: already exists")
+		}
+
+		var update importUpdate
+		if exists {
+			update = mergeImportedProduct(before, &product, row.QuantitySet)
+		}
+
+		current, currency := domain.StatusUnknown, domain.DefaultCurrency
+		if exists {
+			current, currency = before.Status, before.Price.Currency
+		}
+
+		if product.Price.Amount.IsNegative() {
+			rejectf("price: must not be negative")
+		} else if price, err := normalizePrice(product.Price, currency); err != nil {
+			rejectf("price: %v", err)
+		} else {
+			product.Price = price
+		}
+
+		status, err := domain.NextProductStatus(current, product.Status, product.Quantity)
+		if err != nil {
+			rejectf("status: %s can not be set from %s with a quantity of %d", product.Status, current, product.Quantity)
+		}
+		product.Status = status
+
+		if len(errs) > 0 {
+			report.Errors = append(report.Errors, domain.ImportRowError{
+				Line:      row.Line,
+				Reference: product.Reference,
+				Errors:    errs,
+			})
+			continue
+		}
+
+		if !exists {
+			creates = append(creates, &product)
+			continue
+		}
+
+		update.product = &product
+		if len(domain.DiffProducts(before, &product)) == 0 {
+			report.Unchanged++
+			continue
+		}
+		updates = append(updates, update)
+	}
+
+	report.Failed = len(report.Errors)
+	if report.Failed > 0 && options.Mode == domain.ImportAtomic {
+		report.Unchanged = 0
+		return report, nil
+	}
+
+	report.Created = len(creates)
+	report.Updated = len(updates)
+	if options.DryRun || len(creates)+len(updates) == 0 {
+		return report, nil
+	}
+
+	ps.locateImportedProducts(ctx, creates, updates)
+
+	err = ps.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := ps.createImportedProducts(ctx, creates)
+		if err != nil {
+			return err
+		}
+
+		return ps.updateImportedProducts(ctx, updates)
+	})
+	if err != nil {
+		if isRepositoryError(err) || isStockError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	for _, update := range updates {
+		ps.forgetProducts(ctx, update.product.ID)
+	}
+
+	report.Committed = true
+	return report, nil
+}
+
+// lookupImportReferences selects the products with the references of the rows of an import
+// and reports which of their categories and suppliers exist
+func (ps *ProductService) lookupImportReferences(ctx context.Context, rows []domain.ImportRow) (map[string]*domain.Product, map[uuid.UUID]bool, map[uuid.UUID]bool, error) {
+	var references []string
+	var categoryIDs, supplierIDs []uuid.UUID
+
+	for _, row := range rows {
+		if row.Product.Reference != "" {
+			references = append(references, row.Product.Reference)
+		}
+		if row.Product.CategoryID != nil {
+			categoryIDs = append(categoryIDs, *row.Product.CategoryID)
+		}
+		if row.Product.SupplierID != nil {
+			supplierIDs = append(supplierIDs, *row.Product.SupplierID)
+		}
+	}
+
+	existing := make(map[string]*domain.Product)
+	if len(references) > 0 {
+		products, err := ps.productRepo.GetProductsByReferences(ctx, references)
+		if err != nil {
+			return nil, nil, nil, domain.ErrInternal
+		}
+		for i := range products {
+			existing[products[i].Reference] = &products[i]
+		}
+	}
+
+	categories := make(map[uuid.UUID]bool)
+	if len(categoryIDs) > 0 {
+		ids, err := ps.categoryRepo.ListCategoryIDs(ctx, categoryIDs)
+		if err != nil {
+			return nil, nil, nil, domain.ErrInternal
+		}
+		for _, id := range ids {
+			categories[id] = true
+		}
+	}
+
+	suppliers := make(map[uuid.UUID]bool)
+	if len(supplierIDs) > 0 {
+		ids, err := ps.productRepo.ListSupplierIDs(ctx, supplierIDs)
+		if err != nil {
+			return nil, nil, nil, domain.ErrInternal
+		}
+		for _, id := range ids {
+			suppliers[id] = true
+		}
+	}
+
+	return existing, categories, suppliers, nil
+}
+
+// mergeImportedProduct sets the empty values of an upserted row to the values of the product it updates,
+// returning the fields it sets as for an update. The quantity is kept unless the row sets it, to zero included
+func mergeImportedProduct(before, product *domain.Product, quantitySet bool) importUpdate {
+	fields := []string{"name"}
+
+	product.ID = before.ID
+	product.AddedDate = before.AddedDate
+	product.StockCoordinates = before.StockCoordinates
+
+	if product.CategoryID == nil {
+		product.CategoryID = before.CategoryID
+	} else {
+		fields = append(fields, "category_id")
+	}
+	if product.SupplierID == nil {
+		product.SupplierID = before.SupplierID
+	} else {
+		fields = append(fields, "supplier_id")
+	}
+	if product.Price.IsZero() {
+		product.Price = before.Price
+	} else {
+		fields = append(fields, "price")
+	}
+	if !quantitySet {
+		product.Quantity = before.Quantity
+	} else {
+		fields = append(fields, "quantity")
+	}
+	if product.StockCity == "" {
+		product.StockCity = before.StockCity
+	} else if product.StockCity != before.StockCity {
+		fields = append(fields, "stock_city", "stock_coordinates")
+	}
+
+	return importUpdate{before: before, fields: append(fields, "status")}
+}
+
+// locateImportedProducts geocodes the stock cities of the imported products, each city once
+func (ps *ProductService) locateImportedProducts(ctx context.Context, creates []*domain.Product, updates []importUpdate) {
+	located := make(map[string]*domain.Coordinates)
+	locate := func(product *domain.Product) {
+		if product.StockCity == "" {
+			return
+		}
+
+		coordinates, ok := located[product.StockCity]
+		if !ok {
+			coordinates = ps.locateStockCity(ctx, product.StockCity)
+			located[product.StockCity] = coordinates
+		}
+		product.StockCoordinates = coordinates
+	}
+
+	for _, product := range creates {
+		locate(product)
+	}
+	for _, update := range updates {
+		if slices.Contains(update.fields, "stock_coordinates") {
+			locate(update.product)
+		}
+	}
+}
+
+// createImportedProducts creates the new products of an import at once, with the price change,
+// the stock receipt and the audit entry of each as for a single creation
+func (ps *ProductService) createImportedProducts(ctx context.Context, creates []*domain.Product) error {
+	if len(creates) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	products := make([]domain.Product, len(creates))
+	var changes []domain.PriceChange
+	var movements []domain.StockMovement
+	entries := make([]domain.AuditEntry, len(creates))
+
+	for i, product := range creates {
+		product.ID = uuid.New()
+		product.AddedDate = now
+		products[i] = *product
+
+		if product.Price.IsPositive() {
+			changes = append(changes, *newAppliedPriceChange(product.ID, product.Price))
+		}
+
+		if product.Quantity != 0 {
+			movement := newStockMovement(ctx, product.ID, domain.StockReceipt, product.Quantity, "initial stock")
+			levels := domain.NewStockLevels(0, nil)
+			if err := levels.Apply(movement); err != nil {
+				return err
+			}
+			movements = append(movements, *movement)
+		}
+
+		entries[i] = *newAuditEntry(ctx, domain.AuditEntityProduct, product.ID, domain.AuditActionCreate, domain.DiffProducts(nil, product))
+	}
+
+	_, err := ps.productRepo.CreateProducts(ctx, products)
+	if err != nil {
+		return err
+	}
+
+	if len(changes) > 0 {
+		err = ps.priceRepo.CreatePriceChanges(ctx, changes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(movements) > 0 {
+		err = ps.stockRepo.CreateStockMovements(ctx, movements)
+		if err != nil {
+			return err
+		}
+	}
+
+	return ps.auditRepo.CreateAuditEntries(ctx, entries)
+}
+
+// updateImportedProducts updates the upserted products of an import one at a time, each as a single update
+// recording its price change and stock adjustment, and creates their audit entries at once
+func (ps *ProductService) updateImportedProducts(ctx context.Context, updates []importUpdate) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	entries := make([]domain.AuditEntry, len(updates))
+	for i, update := range updates {
+		product := update.product
+
+		// Lock the product so the stock adjustment is computed from the quantity it replaces
+		locked, err := ps.productRepo.GetProductForUpdate(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = ps.productRepo.UpdateProduct(ctx, product, update.fields...)
+		if err != nil {
+			return err
+		}
+
+		if !product.Price.Equal(update.before.Price) {
+			_, err = ps.priceRepo.CreatePriceChange(ctx, newAppliedPriceChange(product.ID, product.Price))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = recordStockMovement(ctx, ps.stockRepo, product.ID, domain.StockAdjustment, locked.Quantity, product.Quantity, "quantity updated")
+		if err != nil {
+			return err
+		}
+
+		entries[i] = *newAuditEntry(ctx, domain.AuditEntityProduct, product.ID, domain.AuditActionUpdate, domain.DiffProducts(update.before, product))
+	}
+
+	return ps.auditRepo.CreateAuditEntries(ctx, entries)
+}
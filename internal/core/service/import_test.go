@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+func TestImportProductsUpsertQuantity(t *testing.T) {
+	tests := []struct {
+		name         string
+		quantity     int
+		quantitySet  bool
+		wantQuantity int
+		wantStatus   domain.ProductStatus
+		wantUpdated  int
+	}{
+		{name: "quantity kept", wantQuantity: 5, wantStatus: domain.StatusAvailable},
+		{name: "quantity set", quantity: 8, quantitySet: true, wantQuantity: 8, wantStatus: domain.StatusAvailable, wantUpdated: 1},
+		{name: "quantity set to zero", quantitySet: true, wantQuantity: 0, wantStatus: domain.StatusOutOfStock, wantUpdated: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Status: domain.StatusAvailable, Price: usd(t, "10"), Quantity: 5}
+			productRepo := newFakeProductRepository(product)
+			ps := newTestProductService(productRepo, &fakeCategoryRepository{}, newFakeCache())
+
+			rows := []domain.ImportRow{{
+				Line:        2,
+				Product:     domain.Product{Reference: "R1", Name: "Rice", Quantity: tt.quantity},
+				QuantitySet: tt.quantitySet,
+			}}
+			report, err := ps.ImportProducts(context.Background(), rows, domain.ImportOptions{Upsert: true})
+			if err != nil {
+				t.Fatalf("ImportProducts() error = %v", err)
+			}
+			if report.Failed != 0 || report.Updated != tt.wantUpdated {
+				t.Fatalf("ImportProducts() = %+v, want %d updated", report, tt.wantUpdated)
+			}
+
+			got := productRepo.products[product.ID]
+			if got.Quantity != tt.wantQuantity || got.Status != tt.wantStatus {
+				t.Errorf("product = quantity %d, status %q, want %d, %q", got.Quantity, got.Status, tt.wantQuantity, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestImportProductsUnknownCategory(t *testing.T) {
+	category := domain.Category{ID: uuid.New(), Name: "Foods"}
+	categoryRepo := &fakeCategoryRepository{categories: map[uuid.UUID]domain.Category{category.ID: category}}
+	ps := newTestProductService(newFakeProductRepository(), categoryRepo, newFakeCache())
+
+	unknown := uuid.New()
+	rows := []domain.ImportRow{
+		{Line: 2, Product: domain.Product{Reference: "R1", Name: "Rice", CategoryID: &category.ID}},
+		{Line: 3, Product: domain.Product{Reference: "R2", Name: "Tea", CategoryID: &unknown}},
+	}
+	report, err := ps.ImportProducts(context.Background(), rows, domain.ImportOptions{DryRun: true, Mode: domain.ImportPartial})
+	if err != nil {
+		t.Fatalf("ImportProducts() error = %v", err)
+	}
+
+	want := []domain.ImportRowError{{Line: 3, Reference: "R2", Errors: []string{"category_id: " + domain.ErrDataNotFound.Error()}}}
+	if report.Created != 1 || !slices.EqualFunc(report.Errors, want, func(a, b domain.ImportRowError) bool {
+		return a.Line == b.Line && a.Reference == b.Reference && slices.Equal(a.Errors, b.Errors)
+	}) {
+		t.Errorf("ImportProducts() = %d created, errors %+v, want 1 created, errors %+v", report.Created, report.Errors, want)
+	}
+}
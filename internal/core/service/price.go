@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+const (
+	// dueBatchSize is the maximum number of due price changes applied in a single run
+	dueBatchSize = 100
+	// minPriceRetryDelay and maxPriceRetryDelay bound the delay before a price change that failed is applied again
+	minPriceRetryDelay = time.Minute
+	maxPriceRetryDelay = 6 * time.Hour
+)
+
+/**
+ * PriceService implements port.PriceService interface
+ * and provides an access to the price and product repositories
+ */
+type PriceService struct {
+	priceRepo   port.PriceRepository
+	productRepo port.ProductRepository
+	auditRepo   port.AuditRepository
+	transactor  port.Transactor
+	cache       port.CacheRepository
+}
+
+// NewPriceService creates a new price service instance. The cache is the one products are read from by id, nil when disabled
+func NewPriceService(priceRepo port.PriceRepository, productRepo port.ProductRepository, auditRepo port.AuditRepository, transactor port.Transactor, cache port.CacheRepository) *PriceService {
+	return &PriceService{
+		priceRepo,
+		productRepo,
+		auditRepo,
+		transactor,
+		cache,
+	}
+}
+
+// SchedulePriceChange schedules a new price for a product, starting now when no start is given
+func (ps *PriceService) SchedulePriceChange(ctx context.Context, change *domain.PriceChange) (*domain.PriceChange, error) {
+	product, err := ps.productRepo.GetProductByID(ctx, change.ProductID, false)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	now := time.Now()
+	price, err := normalizePrice(change.Price, product.Price.Currency)
+	if err != nil {
+		return nil, err
+	}
+	if price.Currency != product.Price.Currency {
+		return nil, domain.ErrCurrencyMismatch
+	}
+	change.Price = price
+
+	if change.StartsAt.IsZero() {
+		change.StartsAt = now
+	}
+
+	if err := change.Validate(now); err != nil {
+		return nil, err
+	}
+
+	change.ID = uuid.New()
+
+	err = ps.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := ps.priceRepo.CreatePriceChange(ctx, change)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityProduct, change.ProductID, domain.AuditActionSchedulePrice, priceChangeFields(change))
+		return ps.auditRepo.CreateAuditEntry(ctx, entry)
+	})
+	if err != nil {
+		if isRepositoryError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return change, nil
+}
+
+// ListPriceChanges returns the price timeline of a product, latest start first
+func (ps *PriceService) ListPriceChanges(ctx context.Context, productID uuid.UUID, skip, limit uint64) ([]domain.PriceChange, error) {
+	changes, err := ps.priceRepo.ListPriceChanges(ctx, productID, skip, limit)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return changes, nil
+}
+
+// CancelPriceChange cancels a price change of a product. A running promotion ends immediately,
+// a price change that already replaced the base price can not be canceled
+func (ps *PriceService) CancelPriceChange(ctx context.Context, productID, id uuid.UUID) (*domain.PriceChange, error) {
+	change, err := ps.priceRepo.GetPriceChangeByID(ctx, productID, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	switch change.Status(time.Now()) {
+	case domain.PriceChangeApplied:
+		return nil, domain.ErrPriceChangeApplied
+	case domain.PriceChangeCanceled:
+		return change, nil
+	}
+
+	err = ps.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		change, err = ps.priceRepo.CancelPriceChange(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityProduct, productID, domain.AuditActionCancelPrice, priceChangeFields(change))
+		return ps.auditRepo.CreateAuditEntry(ctx, entry)
+	})
+	if err != nil {
+		if isRepositoryError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return change, nil
+}
+
+// ApplyDuePriceChanges replaces the base price of the products whose scheduled price started.
+// Price changes of deleted products are canceled instead. Each change is claimed and applied in its own transaction,
+// a change that can not be applied is logged and delayed so that it does not hold back the others
+func (ps *PriceService) ApplyDuePriceChanges(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	applied := 0
+	var repriced []uuid.UUID
+	defer func() { forgetCachedProducts(ctx, ps.cache, repriced...) }()
+
+	for i := 0; i < dueBatchSize; i++ {
+		change, ok, err := ps.applyDuePriceChange(ctx, now)
+		if change == nil {
+			if err != nil {
+				return applied, domain.ErrInternal
+			}
+			break
+		}
+		if err != nil {
+			slog.Error("Error applying scheduled price", "id", change.ID, "product_id", change.ProductID, "attempts", change.Attempts+1, "error", err)
+
+			retryAt := now.Add(priceRetryDelay(change.Attempts))
+			if err := ps.priceRepo.DelayPriceChange(ctx, change.ID, retryAt); err != nil {
+				return applied, domain.ErrInternal
+			}
+			continue
+		}
+		if ok {
+			applied++
+			repriced = append(repriced, change.ProductID)
+		}
+	}
+
+	return applied, nil
+}
+
+// applyDuePriceChange claims the oldest due price change and replaces the base price of its locked product
+// within a transaction, reporting whether it was applied rather than canceled because the product is deleted.
+// The change is nil when none is due
+func (ps *PriceService) applyDuePriceChange(ctx context.Context, now time.Time) (*domain.PriceChange, bool, error) {
+	var change *domain.PriceChange
+	applied := false
+
+	err := ps.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		change, err = ps.priceRepo.ClaimDuePriceChange(ctx, now)
+		if errors.Is(err, domain.ErrDataNotFound) {
+			change = nil
+			return nil
+		}
+		if err != nil {
+			change = nil
+			return err
+		}
+
+		product, err := ps.productRepo.GetProductForUpdate(ctx, change.ProductID)
+		if errors.Is(err, domain.ErrDataNotFound) {
+			_, err := ps.priceRepo.CancelPriceChange(ctx, change.ID)
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		before := *product
+		product.Price = change.Price
+		_, err = ps.productRepo.UpdateProduct(ctx, product, "price")
+		if err != nil {
+			return err
+		}
+
+		if err := ps.priceRepo.MarkPriceChangeApplied(ctx, change.ID, now); err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityProduct, product.ID, domain.AuditActionUpdate, domain.DiffProducts(&before, product))
+		if err := ps.auditRepo.CreateAuditEntry(ctx, entry); err != nil {
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+
+	return change, applied, err
+}
+
+// priceRetryDelay returns how long a price change that failed the given number of times before is delayed,
+// doubling from minPriceRetryDelay with each attempt up to maxPriceRetryDelay
+func priceRetryDelay(attempts int) time.Duration {
+	delay := minPriceRetryDelay
+	for i := 0; i < attempts && delay < maxPriceRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxPriceRetryDelay)
+}
+
+// newAppliedPriceChange creates the price change recording a base price set directly on a product
+func newAppliedPriceChange(productID uuid.UUID, price domain.Money) *domain.PriceChange {
+	now := time.Now()
+	return &domain.PriceChange{
+		ID:        uuid.New(),
+		ProductID: productID,
+		Price:     price,
+		StartsAt:  now,
+		AppliedAt: &now,
+	}
+}
+
+// normalizePrice rounds a price to domain.MoneyPlaces and normalizes its currency,
+// falling back to the given currency when the price has none. Negative prices are rejected
+func normalizePrice(price domain.Money, fallbackCurrency string) (domain.Money, error) {
+	if price.Amount.IsNegative() {
+		return domain.Money{}, domain.ErrInvalidMoney
+	}
+
+	currency := price.Currency
+	if currency == "" {
+		currency = fallbackCurrency
+	}
+
+	return domain.NewMoney(price.Amount.Round(domain.MoneyPlaces), currency)
+}
+
+// priceChangeFields describes a price change as audit field changes
+func priceChangeFields(change *domain.PriceChange) []domain.FieldChange {
+	fields := []domain.FieldChange{
+		{Field: "price_change_id", After: change.ID},
+		{Field: "price", After: change.Price},
+		{Field: "starts_at", After: change.StartsAt},
+	}
+	if change.EndsAt != nil {
+		fields = append(fields, domain.FieldChange{Field: "ends_at", After: *change.EndsAt})
+	}
+	return fields
+}
@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+func TestApplyDuePriceChangesSkipsFailures(t *testing.T) {
+	rice := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Status: domain.StatusAvailable, Price: usd(t, "10")}
+	tea := domain.Product{ID: uuid.New(), Reference: "R2", Name: "Tea", Status: domain.StatusAvailable, Price: usd(t, "4")}
+	failing := domain.PriceChange{ID: uuid.New(), ProductID: rice.ID, Price: usd(t, "12")}
+	applied := domain.PriceChange{ID: uuid.New(), ProductID: tea.ID, Price: usd(t, "5")}
+	canceled := domain.PriceChange{ID: uuid.New(), ProductID: uuid.New(), Price: usd(t, "1")}
+
+	productRepo := newFakeProductRepository(rice, tea)
+	priceRepo := &fakePriceRepository{due: []domain.PriceChange{failing, applied, canceled}, failApplying: failing.ID}
+	prices := NewPriceService(priceRepo, productRepo, &fakeAuditRepository{}, fakeTransactor{}, newFakeCache())
+
+	count, err := prices.ApplyDuePriceChanges(context.Background())
+	if err != nil || count != 1 {
+		t.Fatalf("ApplyDuePriceChanges() = %d, %v, want 1 applied", count, err)
+	}
+	if want := []uuid.UUID{applied.ID, canceled.ID}; !slices.Equal(priceRepo.applied, want) {
+		t.Errorf("applied or canceled price changes = %v, want %v", priceRepo.applied, want)
+	}
+	if got := productRepo.products[tea.ID].Price; !got.Equal(applied.Price) {
+		t.Errorf("tea price = %v, want %v", got, applied.Price)
+	}
+	if _, ok := priceRepo.delayed[failing.ID]; !ok || len(priceRepo.delayed) != 1 {
+		t.Errorf("delayed price changes = %v, want the failing one", priceRepo.delayed)
+	}
+}
+
+func TestApplyDuePriceChangesFailuresDoNotBlock(t *testing.T) {
+	rice := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Status: domain.StatusAvailable, Price: usd(t, "10")}
+	failing := domain.PriceChange{ID: uuid.New(), ProductID: rice.ID, Price: usd(t, "12")}
+	due := []domain.PriceChange{failing}
+	for i := 0; i < dueBatchSize; i++ {
+		due = append(due, domain.PriceChange{ID: uuid.New(), ProductID: uuid.New(), Price: usd(t, "1")})
+	}
+
+	priceRepo := &fakePriceRepository{due: due, failApplying: failing.ID}
+	prices := NewPriceService(priceRepo, newFakeProductRepository(rice), &fakeAuditRepository{}, fakeTransactor{}, newFakeCache())
+
+	if _, err := prices.ApplyDuePriceChanges(context.Background()); err != nil {
+		t.Fatalf("ApplyDuePriceChanges() error = %v", err)
+	}
+	if len(priceRepo.applied) != dueBatchSize-1 {
+		t.Errorf("canceled price changes = %d, want %d past the failing one", len(priceRepo.applied), dueBatchSize-1)
+	}
+}
+
+func TestPriceRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 0, want: minPriceRetryDelay},
+		{attempts: 1, want: 2 * minPriceRetryDelay},
+		{attempts: 3, want: 8 * minPriceRetryDelay},
+		{attempts: 100, want: maxPriceRetryDelay},
+	}
+	for _, tt := range tests {
+		if got := priceRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("priceRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
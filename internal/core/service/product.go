@@ -4,11 +4,13 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
 	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/util"
 )
 
 /**
@@ -19,6 +21,10 @@ import (
 type ProductService struct {
 	productRepo  port.ProductRepository
 	categoryRepo port.CategoryRepository
+	priceRepo    port.PriceRepository
+	stockRepo    port.StockRepository
+	auditRepo    port.AuditRepository
+	transactor   port.Transactor
 	cache        port.CacheRepository
 	geoClient    port.GeoClient
 }
@@ -29,10 +35,23 @@ func (ps *ProductService) ListProducts2(ctx context.Context, search string, cate
 }
 
 // NewProductService creates a new product service instance
-func NewProductService(productRepo port.ProductRepository, categoryRepo port.CategoryRepository, cache port.CacheRepository, geoClient port.GeoClient) *ProductService {
+func NewProductService(
+	productRepo port.ProductRepository,
+	categoryRepo port.CategoryRepository,
+	priceRepo port.PriceRepository,
+	stockRepo port.StockRepository,
+	auditRepo port.AuditRepository,
+	transactor port.Transactor,
+	cache port.CacheRepository,
+	geoClient port.GeoClient,
+) *ProductService {
 	return &ProductService{
 		productRepo,
 		categoryRepo,
+		priceRepo,
+		stockRepo,
+		auditRepo,
+		transactor,
 		cache,
 		geoClient,
 	}
@@ -40,29 +59,41 @@ func NewProductService(productRepo port.ProductRepository, categoryRepo port.Cat
 
 // CreateProduct creates a new product
 func (ps *ProductService) CreateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	if product.CategoryID != nil {
-		_, err := ps.categoryRepo.GetCategoryByID(ctx, *product.CategoryID)
+	err := ps.prepareCreateProduct(ctx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ps.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := ps.productRepo.CreateProduct(ctx, product)
 		if err != nil {
-			if errors.Is(err, domain.ErrDataNotFound) {
-				return nil, err
-			}
-			return nil, domain.ErrInternal
+			return err
 		}
-	}
 
-	id := uuid.New()
-	product.ID = id
+		if product.Price.IsPositive() {
+			_, err = ps.priceRepo.CreatePriceChange(ctx, newAppliedPriceChange(product.ID, product.Price))
+			if err != nil {
+				return err
+			}
+		}
 
-	product.AddedDate = time.Now()
+		err = recordStockMovement(ctx, ps.stockRepo, product.ID, domain.StockReceipt, 0, product.Quantity, "initial stock")
+		if err != nil {
+			return err
+		}
 
-	product, err := ps.productRepo.CreateProduct(ctx, product)
+		entry := newAuditEntry(ctx, domain.AuditEntityProduct, product.ID, domain.AuditActionCreate, domain.DiffProducts(nil, product))
+		return ps.auditRepo.CreateAuditEntry(ctx, entry)
+	})
 	if err != nil {
-		if errors.Is(err, domain.ErrConflictingData) {
+		if isRepositoryError(err) {
 			return nil, err
 		}
 		return nil, domain.ErrInternal
 	}
 
+	product.EffectivePrice = product.Price
+
 	//cacheKey := util.GenerateCacheKey("product", product.ID)
 	//productSerialized, err := util.Serialize(product)
 	//if err != nil {
@@ -82,11 +113,43 @@ func (ps *ProductService) CreateProduct(ctx context.Context, product *domain.Pro
 	return product, nil
 }
 
-// GetProduct retrieves a product by id
-func (ps *ProductService) GetProduct(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+// prepareCreateProduct validates a new product, checking its category and resolving its price and status,
+// and sets its id, added date and stock coordinates
+func (ps *ProductService) prepareCreateProduct(ctx context.Context, product *domain.Product) error {
+	if product.CategoryID != nil {
+		_, err := ps.categoryRepo.GetCategoryByID(ctx, *product.CategoryID, false)
+		if err != nil {
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return err
+			}
+			return domain.ErrInternal
+		}
+	}
+
+	price, err := normalizePrice(product.Price, domain.DefaultCurrency)
+	if err != nil {
+		return err
+	}
+	product.Price = price
+
+	status, err := domain.NextProductStatus(domain.StatusUnknown, product.Status, product.Quantity)
+	if err != nil {
+		return err
+	}
+	product.Status = status
+
+	product.ID = uuid.New()
+	product.AddedDate = time.Now()
+	product.StockCoordinates = ps.locateStockCity(ctx, product.StockCity)
+
+	return nil
+}
+
+// GetProduct retrieves a product by id, a soft deleted one only when includeDeleted
+func (ps *ProductService) GetProduct(ctx context.Context, id uuid.UUID, includeDeleted bool) (*domain.Product, error) {
 	var product *domain.Product
 
-	product, err := ps.productRepo.GetProductByID(ctx, id)
+	product, err := ps.productRepo.GetProductByID(ctx, id, includeDeleted)
 	if err != nil {
 		if err == domain.ErrDataNotFound {
 			return nil, err
@@ -94,37 +157,174 @@ func (ps *ProductService) GetProduct(ctx context.Context, id uuid.UUID) (*domain
 		return nil, domain.ErrInternal
 	}
 
-	category, err := ps.categoryRepo.GetCategoryByID(ctx, *product.CategoryID)
-	if err != nil {
-		if err == domain.ErrDataNotFound {
-			return nil, err
+	if product.CategoryID != nil {
+		category, err := ps.categoryRepo.GetCategoryByID(ctx, *product.CategoryID, includeDeleted)
+		if err != nil {
+			if err == domain.ErrDataNotFound {
+				return nil, err
+			}
+			return nil, domain.ErrInternal
 		}
+
+		product.Category = category
+	}
+
+	err = ps.setEffectivePrices(ctx, product)
+	if err != nil {
 		return nil, domain.ErrInternal
 	}
 
-	product.Category = category
+	err = ps.setWarehouseStocks(ctx, product)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
 
 	return product, nil
 }
 
+// productCacheTTL is how long a product read by id stays cached. The products are forgotten by every service
+// writing them, the ttl bounds how stale a write made outside of the services can be read
+const productCacheTTL = 5 * time.Minute
+
+// GetProducts retrieves products by id in the order of the ids, each id once, with the ids matching no product.
+// When caching is enabled, the cached products are read from the cache and the others in a single query
+// before being cached, which is why the whole products are read whatever the view. The categories, effective
+// prices, warehouse stocks and suppliers are always read afresh
+func (ps *ProductService) GetProducts(ctx context.Context, ids []uuid.UUID, view *domain.ProductView) ([]domain.Product, []uuid.UUID, error) {
+	found := make(map[uuid.UUID]*domain.Product, len(ids))
+	var uncached []uuid.UUID
+	var cached []*domain.Product
+
+	for _, id := range ids {
+		if _, ok := found[id]; ok {
+			continue
+		}
+		found[id] = ps.getCachedProduct(ctx, id)
+		if found[id] == nil {
+			uncached = append(uncached, id)
+		} else {
+			cached = append(cached, found[id])
+		}
+	}
+
+	err := ps.setCategories(ctx, make(map[uuid.UUID]*domain.Category), cached...)
+	if err != nil {
+		return nil, nil, domain.ErrInternal
+	}
+
+	if len(uncached) > 0 {
+		products, err := ps.productRepo.GetProductsByIDs(ctx, uncached)
+		if err != nil {
+			return nil, nil, domain.ErrInternal
+		}
+
+		for i := range products {
+			found[products[i].ID] = &products[i]
+			ps.cacheProduct(ctx, &products[i])
+		}
+	}
+
+	var products []domain.Product
+	var missing []uuid.UUID
+	for _, id := range ids {
+		product, ok := found[id]
+		if !ok {
+			continue
+		}
+		delete(found, id)
+
+		if product == nil {
+			missing = append(missing, id)
+			continue
+		}
+		products = append(products, *product)
+	}
+
+	productPtrs := make([]*domain.Product, len(products))
+	for i := range products {
+		productPtrs[i] = &products[i]
+	}
+	err = ps.setProductView(ctx, view, productPtrs...)
+	if err != nil {
+		return nil, nil, domain.ErrInternal
+	}
+
+	return products, missing, nil
+}
+
+// getCachedProduct returns the cached product with the id, or nil when caching is disabled or it is not cached
+func (ps *ProductService) getCachedProduct(ctx context.Context, id uuid.UUID) *domain.Product {
+	if ps.cache == nil {
+		return nil
+	}
+
+	value, err := ps.cache.Get(ctx, util.GenerateCacheKey("product", id))
+	if err != nil {
+		return nil
+	}
+
+	var product domain.Product
+	if err := util.Deserialize(value, &product); err != nil {
+		return nil
+	}
+
+	return &product
+}
+
+// cacheProduct caches a product read from the database without its category, which changes apart from the product
+// and is read afresh, a failure only being logged
+func (ps *ProductService) cacheProduct(ctx context.Context, product *domain.Product) {
+	if ps.cache == nil {
+		return
+	}
+
+	cached := *product
+	cached.Category = nil
+
+	key := util.GenerateCacheKey("product", product.ID)
+	value, err := util.Serialize(&cached)
+	if err == nil {
+		err = ps.cache.Set(ctx, key, value, productCacheTTL)
+	}
+	if err != nil {
+		slog.Warn("Error caching product", "key", key, "error", err)
+	}
+}
+
+// forgetProducts removes the products from the cache once they are updated or deleted, a failure only being logged
+func (ps *ProductService) forgetProducts(ctx context.Context, ids ...uuid.UUID) {
+	forgetCachedProducts(ctx, ps.cache, ids...)
+}
+
 // ListProducts retrieves a list of products
-func (ps *ProductService) ListProducts(ctx context.Context, search string, categoryIds []uuid.UUID, skip, limit uint64) ([]domain.Product, error) {
-	products, err := ps.productRepo.ListProducts(ctx, search, categoryIds, skip, limit)
+func (ps *ProductService) ListProducts(ctx context.Context, filter domain.ProductFilter, view *domain.ProductView, skip, limit uint64) ([]domain.Product, error) {
+	err := ps.locateNearIP(ctx, &filter)
 	if err != nil {
-		return nil, domain.ErrInternal
+		return nil, err
 	}
 
-	categories := make(map[uuid.UUID]domain.Category)
-	for i, product := range products {
-		category := &domain.Category{}
-		if _, ok := categories[*product.CategoryID]; !ok && product.CategoryID != nil {
-			category, err = ps.categoryRepo.GetCategoryByID(ctx, *product.CategoryID)
-			if err != nil {
-				slog.Error("Error getting category by id", "error", err)
-			}
+	products, err := ps.productRepo.ListProducts(ctx, filter, view, skip, limit)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			return nil, err
 		}
+		return nil, domain.ErrInternal
+	}
 
-		products[i].Category = category
+	productPtrs := make([]*domain.Product, len(products))
+	for i := range products {
+		productPtrs[i] = &products[i]
+	}
+	if view == nil {
+		err = ps.setCategories(ctx, make(map[uuid.UUID]*domain.Category), productPtrs...)
+		if err != nil {
+			slog.Error("Error getting categories", "error", err)
+			return nil, domain.ErrInternal
+		}
+	}
+	err = ps.setProductView(ctx, view, productPtrs...)
+	if err != nil {
+		return nil, domain.ErrInternal
 	}
 
 	//productsSerialized, err := util.Serialize(products)
@@ -140,8 +340,60 @@ func (ps *ProductService) ListProducts(ctx context.Context, search string, categ
 	return products, nil
 }
 
+// exportBatchSize is the number of products read from the database at once during an export
+const exportBatchSize = 1000
+
+// ExportProducts passes all the products matching the filter to fn with their category and effective price.
+// The products are read in batches through a cursor, fn must not keep the product it is passed
+func (ps *ProductService) ExportProducts(ctx context.Context, filter domain.ProductFilter, fn func(product *domain.Product) error) error {
+	err := ps.locateNearIP(ctx, &filter)
+	if err != nil {
+		return err
+	}
+
+	categories := make(map[uuid.UUID]*domain.Category)
+	var fnErr error
+
+	err = ps.productRepo.StreamProducts(ctx, filter, exportBatchSize, func(products []domain.Product) error {
+		productPtrs := make([]*domain.Product, len(products))
+		for i := range products {
+			productPtrs[i] = &products[i]
+		}
+
+		err := ps.setCategories(ctx, categories, productPtrs...)
+		if err != nil {
+			return err
+		}
+
+		err = ps.setEffectivePrices(ctx, productPtrs...)
+		if err != nil {
+			return err
+		}
+
+		for _, product := range productPtrs {
+			fnErr = fn(product)
+			if fnErr != nil {
+				return fnErr
+			}
+		}
+
+		return nil
+	})
+	if fnErr != nil {
+		return fnErr
+	}
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
+
 func (ps *ProductService) GetProductDistance(ctx context.Context, ip string, id uuid.UUID) (float64, error) {
-	product, err := ps.productRepo.GetProductByID(ctx, id)
+	product, err := ps.productRepo.GetProductByID(ctx, id, false)
 	if err != nil {
 		if errors.Is(err, domain.ErrDataNotFound) {
 			return 0, err
@@ -153,24 +405,33 @@ func (ps *ProductService) GetProductDistance(ctx context.Context, ip string, id
 		return 0, domain.ErrDataNotFound
 	}
 
-	dstLat, dstLon, err := ps.geoClient.GetCityLocation(product.StockCity)
-	if err != nil {
-		return 0, domain.ErrInternal
+	stock := product.StockCoordinates
+	if stock == nil {
+		lat, lon, err := ps.geoClient.GetCityLocation(ctx, product.StockCity)
+		if err != nil {
+			slog.Warn("Error locating stock city", "city", product.StockCity, "error", err)
+			if errors.Is(err, domain.ErrUnknownCity) {
+				return 0, domain.ErrUnknownCity
+			}
+			return 0, domain.ErrInternal
+		}
+		stock = &domain.Coordinates{Latitude: lat, Longitude: lon}
 	}
 
-	srcLat, srcLon, err := ps.geoClient.GetIPLocation(ip)
+	src, err := ps.locateIP(ctx, ip)
 	if err != nil {
-		return 0, domain.ErrInternal
+		return 0, err
 	}
 
-	distance := ps.geoClient.GetDistance(srcLat, srcLon, dstLat, dstLon)
+	distance := ps.geoClient.GetDistance(src.Latitude, src.Longitude, stock.Latitude, stock.Longitude)
 
 	return distance, nil
 }
 
-// UpdateProduct updates a product
-func (ps *ProductService) UpdateProduct(ctx context.Context, product *domain.Product) (*domain.Product, error) {
-	_, err := ps.productRepo.GetProductByID(ctx, product.ID)
+// GetProductAvailability returns the stock of a product in the warehouses holding some, ranked by distance
+// from the origin with the stored warehouse coordinates. Without an origin the location of the ip is used
+func (ps *ProductService) GetProductAvailability(ctx context.Context, id uuid.UUID, origin *domain.Coordinates, ip string) (*domain.ProductAvailability, error) {
+	product, err := ps.productRepo.GetProductByID(ctx, id, false)
 	if err != nil {
 		if errors.Is(err, domain.ErrDataNotFound) {
 			return nil, err
@@ -178,6 +439,130 @@ func (ps *ProductService) UpdateProduct(ctx context.Context, product *domain.Pro
 		return nil, domain.ErrInternal
 	}
 
+	if origin == nil {
+		origin, err = ps.locateIP(ctx, ip)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	stocks, err := ps.stockRepo.GetWarehouseStocks(ctx, []uuid.UUID{product.ID})
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return domain.NewProductAvailability(product.ID, *origin, stocks[product.ID]), nil
+}
+
+// locateNearIP sets the origin of the filter to the location of its ip address when it has no origin
+func (ps *ProductService) locateNearIP(ctx context.Context, filter *domain.ProductFilter) error {
+	if filter.Near != nil || filter.NearIP == "" {
+		return nil
+	}
+
+	near, err := ps.locateIP(ctx, filter.NearIP)
+	if err != nil {
+		return err
+	}
+
+	filter.Near = near
+	return nil
+}
+
+// locateIP returns the location of an ip address. An address that can not be located is an ErrUnknownLocation,
+// a failing geo service an ErrInternal
+func (ps *ProductService) locateIP(ctx context.Context, ip string) (*domain.Coordinates, error) {
+	lat, lon, err := ps.geoClient.GetIPLocation(ctx, ip)
+	if err != nil {
+		slog.Warn("Error locating client ip", "ip", ip, "error", err)
+		if errors.Is(err, domain.ErrUnknownLocation) {
+			return nil, domain.ErrUnknownLocation
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return &domain.Coordinates{Latitude: lat, Longitude: lon}, nil
+}
+
+// locateStockCity geocodes a stock city, a city that can not be located is stored without coordinates
+func (ps *ProductService) locateStockCity(ctx context.Context, city string) *domain.Coordinates {
+	if city == "" {
+		return nil
+	}
+
+	lat, lon, err := ps.geoClient.GetCityLocation(ctx, city)
+	if err != nil {
+		slog.Warn("Error locating stock city", "city", city, "error", err)
+		return nil
+	}
+
+	return &domain.Coordinates{Latitude: lat, Longitude: lon}
+}
+
+// UpdateProduct updates a product, its quantity only when quantitySet so that it can be set to zero
+func (ps *ProductService) UpdateProduct(ctx context.Context, product *domain.Product, quantitySet bool) (*domain.Product, error) {
+	updatedFields, err := ps.prepareUpdateProduct(ctx, product, quantitySet)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ps.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Lock the product so the price, status, stock adjustment and audit entry are resolved from the product it replaces
+		locked, err := ps.productRepo.GetProductForUpdate(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+
+		fields, err := resolveUpdateProduct(locked, product, quantitySet, updatedFields)
+		if err != nil {
+			return err
+		}
+
+		_, err = ps.productRepo.UpdateProduct(ctx, product, fields...)
+		if err != nil {
+			return err
+		}
+
+		if !product.Price.Equal(locked.Price) {
+			_, err = ps.priceRepo.CreatePriceChange(ctx, newAppliedPriceChange(product.ID, product.Price))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = recordStockMovement(ctx, ps.stockRepo, product.ID, domain.StockAdjustment, locked.Quantity, product.Quantity, "quantity updated")
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityProduct, product.ID, domain.AuditActionUpdate, domain.DiffProducts(locked, product))
+		return ps.auditRepo.CreateAuditEntry(ctx, entry)
+	})
+	if err != nil {
+		if isRepositoryError(err) || isStockError(err) || isUpdateError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+	ps.forgetProducts(ctx, product.ID)
+
+	err = ps.setEffectivePrices(ctx, product)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	err = ps.setWarehouseStocks(ctx, product)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return product, nil
+}
+
+// prepareUpdateProduct validates the parts of an update of a product that do not depend on the product it replaces.
+// The fields that are not empty are updated along with its quantity when quantitySet. It checks its category and
+// locates its stock city, returning the updated fields
+func (ps *ProductService) prepareUpdateProduct(ctx context.Context, product *domain.Product, quantitySet bool) ([]string, error) {
 	var updatedFields []string
 	if product.Reference != "" {
 		updatedFields = append(updatedFields, "reference")
@@ -185,17 +570,18 @@ func (ps *ProductService) UpdateProduct(ctx context.Context, product *domain.Pro
 	if product.Name != "" {
 		updatedFields = append(updatedFields, "name")
 	}
-	if product.Price != 0 {
+	if !product.Price.IsZero() {
 		updatedFields = append(updatedFields, "price")
 	}
-	if product.Quantity != 0 {
+	if quantitySet {
 		updatedFields = append(updatedFields, "quantity")
 	}
 	if product.StockCity != "" {
-		updatedFields = append(updatedFields, "stock_city")
+		product.StockCoordinates = ps.locateStockCity(ctx, product.StockCity)
+		updatedFields = append(updatedFields, "stock_city", "stock_coordinates")
 	}
 	if product.CategoryID != nil {
-		_, err := ps.categoryRepo.GetCategoryByID(ctx, *product.CategoryID)
+		_, err := ps.categoryRepo.GetCategoryByID(ctx, *product.CategoryID, false)
 		if err != nil {
 			if errors.Is(err, domain.ErrDataNotFound) {
 				return nil, err
@@ -205,34 +591,282 @@ func (ps *ProductService) UpdateProduct(ctx context.Context, product *domain.Pro
 		updatedFields = append(updatedFields, "category_id")
 	}
 
-	if product.Status != domain.StatusUnknown {
-		updatedFields = append(updatedFields, "status")
+	return updatedFields, nil
+}
+
+// resolveUpdateProduct resolves an update of a product prepared by prepareUpdateProduct against the product it
+// replaces, which is locked. It normalizes the price in the currency of the product, keeps its quantity when it is
+// not set and resolves its status, returning the updated fields
+func resolveUpdateProduct(before, product *domain.Product, quantitySet bool, updatedFields []string) ([]string, error) {
+	fields := slices.Clone(updatedFields)
+
+	if !product.Price.IsZero() {
+		price, err := normalizePrice(product.Price, before.Price.Currency)
+		if err != nil {
+			return nil, err
+		}
+		product.Price = price
+	} else {
+		product.Price = before.Price
+	}
+	if !quantitySet {
+		product.Quantity = before.Quantity
+	}
+
+	status, err := domain.NextProductStatus(before.Status, product.Status, product.Quantity)
+	if err != nil {
+		return nil, err
+	}
+	if product.Status != domain.StatusUnknown || status != before.Status {
+		product.Status = status
+		fields = append(fields, "status")
 	}
 
-	if len(updatedFields) == 0 {
+	if len(fields) == 0 {
 		return nil, domain.ErrNoUpdatedData
 	}
 
-	_, err = ps.productRepo.UpdateProduct(ctx, product, updatedFields...)
+	return fields, nil
+}
+
+// isUpdateError reports whether the error is an update of a product that is not valid for the product it replaces
+func isUpdateError(err error) bool {
+	return errors.Is(err, domain.ErrInvalidStatus) ||
+		errors.Is(err, domain.ErrInvalidMoney) ||
+		errors.Is(err, domain.ErrInvalidCurrency) ||
+		errors.Is(err, domain.ErrNoUpdatedData)
+}
+
+// DeleteProduct soft deletes a product
+func (ps *ProductService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	err := ps.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Lock the product so that it is deleted and audited once when deleted concurrently
+		before, err := ps.productRepo.GetProductForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = ps.productRepo.DeleteProduct(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityProduct, id, domain.AuditActionDelete, domain.DiffProducts(before, nil))
+		return ps.auditRepo.CreateAuditEntry(ctx, entry)
+	})
 	if err != nil {
-		if errors.Is(err, domain.ErrConflictingData) {
+		if isRepositoryError(err) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+	ps.forgetProducts(ctx, id)
+
+	return nil
+}
+
+// RestoreProduct restores a soft deleted product
+func (ps *ProductService) RestoreProduct(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	var product *domain.Product
+
+	err := ps.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		product, err = ps.productRepo.RestoreProduct(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		// A product can not be restored into a deleted category, its category must be restored first
+		if product.CategoryID != nil {
+			_, err = ps.categoryRepo.GetCategoryByID(ctx, *product.CategoryID, false)
+			if errors.Is(err, domain.ErrDataNotFound) {
+				return domain.ErrInvalidReference
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityProduct, id, domain.AuditActionRestore, nil)
+		return ps.auditRepo.CreateAuditEntry(ctx, entry)
+	})
+	if err != nil {
+		if isRepositoryError(err) {
 			return nil, err
 		}
 		return nil, domain.ErrInternal
 	}
 
+	err = ps.setEffectivePrices(ctx, product)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	err = ps.setWarehouseStocks(ctx, product)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
 	return product, nil
 }
 
-// DeleteProduct deletes a product
-func (ps *ProductService) DeleteProduct(ctx context.Context, id uuid.UUID) error {
-	_, err := ps.productRepo.GetProductByID(ctx, id)
+// PurgeProducts permanently deletes the products soft deleted longer ago than the retention window
+func (ps *ProductService) PurgeProducts(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := ps.productRepo.PurgeProducts(ctx, time.Now().Add(-retention))
 	if err != nil {
-		if err == domain.ErrDataNotFound {
+		return 0, domain.ErrInternal
+	}
+
+	return purged, nil
+}
+
+// GetProductHistory returns the audit entries of a product, newest first
+func (ps *ProductService) GetProductHistory(ctx context.Context, id uuid.UUID, skip, limit uint64) ([]domain.AuditEntry, error) {
+	entries, err := ps.auditRepo.ListAuditEntries(ctx, domain.AuditEntityProduct, id, skip, limit)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return entries, nil
+}
+
+// setEffectivePrices sets the price the products currently sell at, which is the base price
+// unless a scheduled price that has not replaced it yet or a promotion is running
+func (ps *ProductService) setEffectivePrices(ctx context.Context, products ...*domain.Product) error {
+	ids := make([]uuid.UUID, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	prices, err := ps.priceRepo.GetEffectivePrices(ctx, ids, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		product.EffectivePrice = product.Price
+		if price, ok := prices[product.ID]; ok {
+			product.EffectivePrice = price
+		}
+	}
+
+	return nil
+}
+
+// setProductView sets the effective prices and the related objects of the products the view asks for. Without a view,
+// the effective prices and warehouse stocks are set, the category being set by the caller as it always was
+func (ps *ProductService) setProductView(ctx context.Context, view *domain.ProductView, products ...*domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+
+	if view.Selects(domain.ProductFieldEffectivePrice) {
+		err := ps.setEffectivePrices(ctx, products...)
+		if err != nil {
 			return err
 		}
-		return domain.ErrInternal
 	}
 
-	return ps.productRepo.DeleteProduct(ctx, id)
+	if view == nil || view.Includes(domain.ProductRelationWarehouses) {
+		err := ps.setWarehouseStocks(ctx, products...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if view.Includes(domain.ProductRelationCategory) {
+		var uncategorized []*domain.Product
+		for _, product := range products {
+			if product.Category == nil {
+				uncategorized = append(uncategorized, product)
+			}
+		}
+
+		err := ps.setCategories(ctx, make(map[uuid.UUID]*domain.Category), uncategorized...)
+		if err != nil {
+			return err
+		}
+	}
+
+	if view.Includes(domain.ProductRelationSupplier) {
+		err := ps.setSuppliers(ctx, products...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setSuppliers sets the supplier of the products, reading all their suppliers at once
+func (ps *ProductService) setSuppliers(ctx context.Context, products ...*domain.Product) error {
+	var ids []uuid.UUID
+	for _, product := range products {
+		if product.SupplierID != nil {
+			ids = append(ids, *product.SupplierID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	suppliers, err := ps.productRepo.ListSuppliers(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uuid.UUID]*domain.Supplier, len(suppliers))
+	for i := range suppliers {
+		byID[suppliers[i].ID] = &suppliers[i]
+	}
+	for _, product := range products {
+		if product.SupplierID != nil {
+			product.Supplier = byID[*product.SupplierID]
+		}
+	}
+
+	return nil
+}
+
+// setCategories sets the category of the products, reading each category once. The categories
+// already read are kept in the given map, a category that no longer exists is left unset
+func (ps *ProductService) setCategories(ctx context.Context, categories map[uuid.UUID]*domain.Category, products ...*domain.Product) error {
+	for _, product := range products {
+		if product.CategoryID == nil {
+			continue
+		}
+
+		category, ok := categories[*product.CategoryID]
+		if !ok {
+			var err error
+			category, err = ps.categoryRepo.GetCategoryByID(ctx, *product.CategoryID, false)
+			if err != nil && !errors.Is(err, domain.ErrDataNotFound) {
+				return err
+			}
+			categories[*product.CategoryID] = category
+		}
+
+		product.Category = category
+	}
+
+	return nil
+}
+
+// setWarehouseStocks sets the stock of the products in each warehouse
+func (ps *ProductService) setWarehouseStocks(ctx context.Context, products ...*domain.Product) error {
+	ids := make([]uuid.UUID, len(products))
+	for i, product := range products {
+		ids[i] = product.ID
+	}
+
+	stocks, err := ps.stockRepo.GetWarehouseStocks(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, product := range products {
+		product.Stocks = stocks[product.ID]
+	}
+
+	return nil
 }
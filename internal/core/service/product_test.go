@@ -0,0 +1,389 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// newTestProductService creates a product service over the fake repositories with caching enabled
+func newTestProductService(productRepo *fakeProductRepository, categoryRepo *fakeCategoryRepository, cache *fakeCache) *ProductService {
+	return NewProductService(
+		productRepo,
+		categoryRepo,
+		&fakePriceRepository{},
+		&fakeStockRepository{},
+		&fakeAuditRepository{},
+		fakeTransactor{},
+		cache,
+		nil,
+	)
+}
+
+// usd returns an amount of US dollars, failing the test when it is not one
+func usd(t *testing.T, amount string) domain.Money {
+	t.Helper()
+
+	money, err := domain.ParseMoney(amount, "USD")
+	if err != nil {
+		t.Fatalf("ParseMoney(%q) error = %v", amount, err)
+	}
+	return money
+}
+
+// getProduct gets a product through GetProducts, failing the test when it is missing
+func getProduct(t *testing.T, ps *ProductService, id uuid.UUID) domain.Product {
+	t.Helper()
+
+	products, missing, err := ps.GetProducts(context.Background(), []uuid.UUID{id}, nil)
+	if err != nil {
+		t.Fatalf("GetProducts() error = %v", err)
+	}
+	if len(products) != 1 || len(missing) != 0 {
+		t.Fatalf("GetProducts() = %d products, %v missing, want the product", len(products), missing)
+	}
+	return products[0]
+}
+
+func TestGetProductsSeesStockMovements(t *testing.T) {
+	ctx := context.Background()
+	product := domain.Product{ID: uuid.New(), Name: "Rice", Status: domain.StatusAvailable, Quantity: 5}
+	productRepo := newFakeProductRepository(product)
+	cache := newFakeCache()
+
+	ps := newTestProductService(productRepo, &fakeCategoryRepository{}, cache)
+	ss := NewStockService(&fakeStockRepository{}, productRepo, &fakeAuditRepository{}, fakeTransactor{}, cache)
+
+	if got := getProduct(t, ps, product.ID); got.Quantity != 5 {
+		t.Fatalf("GetProducts() quantity = %d, want 5", got.Quantity)
+	}
+	getProduct(t, ps, product.ID)
+	if productRepo.byIDsReads != 1 {
+		t.Fatalf("GetProductsByIDs() calls = %d, want the second read from the cache", productRepo.byIDsReads)
+	}
+
+	sale, err := domain.NewStockMovement(product.ID, domain.StockSale, 5, "")
+	if err != nil {
+		t.Fatalf("NewStockMovement() error = %v", err)
+	}
+	if _, err := ss.PostStockMovements(ctx, []domain.StockMovement{*sale}); err != nil {
+		t.Fatalf("PostStockMovements() error = %v", err)
+	}
+
+	got := getProduct(t, ps, product.ID)
+	if got.Quantity != 0 || got.Status != domain.StatusOutOfStock {
+		t.Errorf("GetProducts() after the sale = quantity %d, status %q, want 0, %q", got.Quantity, got.Status, domain.StatusOutOfStock)
+	}
+}
+
+func TestGetProductsSeesPriceChanges(t *testing.T) {
+	ctx := context.Background()
+	product := domain.Product{ID: uuid.New(), Name: "Rice", Status: domain.StatusAvailable, Price: usd(t, "10")}
+	productRepo := newFakeProductRepository(product)
+	cache := newFakeCache()
+
+	ps := newTestProductService(productRepo, &fakeCategoryRepository{}, cache)
+	change := domain.PriceChange{ID: uuid.New(), ProductID: product.ID, Price: usd(t, "12")}
+	prices := NewPriceService(&fakePriceRepository{due: []domain.PriceChange{change}}, productRepo, &fakeAuditRepository{}, fakeTransactor{}, cache)
+
+	getProduct(t, ps, product.ID)
+
+	if applied, err := prices.ApplyDuePriceChanges(ctx); err != nil || applied != 1 {
+		t.Fatalf("ApplyDuePriceChanges() = %d, %v, want 1 applied", applied, err)
+	}
+
+	if got := getProduct(t, ps, product.ID); !got.Price.Equal(change.Price) {
+		t.Errorf("GetProducts() price after the price change = %v, want %v", got.Price, change.Price)
+	}
+}
+
+func TestGetProductsReadsCategoriesAfresh(t *testing.T) {
+	category := domain.Category{ID: uuid.New(), Name: "Foods"}
+	categoryRepo := &fakeCategoryRepository{categories: map[uuid.UUID]domain.Category{category.ID: category}}
+
+	product := domain.Product{ID: uuid.New(), Name: "Rice", CategoryID: &category.ID, Category: &category}
+	ps := newTestProductService(newFakeProductRepository(product), categoryRepo, newFakeCache())
+
+	getProduct(t, ps, product.ID)
+
+	category.Name = "Groceries"
+	categoryRepo.categories[category.ID] = category
+
+	got := getProduct(t, ps, product.ID)
+	if got.Category == nil || got.Category.Name != "Groceries" {
+		t.Errorf("GetProducts() category after its update = %+v, want Groceries", got.Category)
+	}
+}
+
+func TestGetProductIncludeDeleted(t *testing.T) {
+	deletedAt := time.Now()
+	foods := domain.Category{ID: uuid.New(), Name: "Foods"}
+	drinks := domain.Category{ID: uuid.New(), Name: "Drinks", DeletedAt: &deletedAt}
+	categoryRepo := &fakeCategoryRepository{categories: map[uuid.UUID]domain.Category{foods.ID: foods, drinks.ID: drinks}}
+
+	tests := []struct {
+		name           string
+		category       *domain.Category
+		deleted        bool
+		includeDeleted bool
+		wantErr        error
+	}{
+		{name: "product", category: &foods},
+		{name: "product without category"},
+		{name: "deleted product", category: &foods, deleted: true, wantErr: domain.ErrDataNotFound},
+		{name: "deleted product included", category: &foods, deleted: true, includeDeleted: true},
+		{name: "deleted product in a deleted category included", category: &drinks, deleted: true, includeDeleted: true},
+		{name: "product in a deleted category", category: &drinks, wantErr: domain.ErrDataNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Price: usd(t, "10")}
+			if tt.category != nil {
+				product.CategoryID = &tt.category.ID
+			}
+			if tt.deleted {
+				product.DeletedAt = &deletedAt
+			}
+			ps := newTestProductService(newFakeProductRepository(product), categoryRepo, newFakeCache())
+
+			got, err := ps.GetProduct(context.Background(), product.ID, tt.includeDeleted)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetProduct() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.ID != product.ID || (got.DeletedAt != nil) != tt.deleted {
+				t.Errorf("GetProduct() = %+v, want the product", got)
+			}
+			if (got.Category != nil) != (tt.category != nil) || (got.Category != nil && got.Category.ID != tt.category.ID) {
+				t.Errorf("GetProduct() category = %+v, want %+v", got.Category, tt.category)
+			}
+		})
+	}
+}
+
+func TestRestoreProductDeletedCategory(t *testing.T) {
+	deletedAt := time.Now()
+	foods := domain.Category{ID: uuid.New(), Name: "Foods"}
+	drinks := domain.Category{ID: uuid.New(), Name: "Drinks", DeletedAt: &deletedAt}
+	categoryRepo := &fakeCategoryRepository{categories: map[uuid.UUID]domain.Category{foods.ID: foods, drinks.ID: drinks}}
+
+	tests := []struct {
+		name     string
+		category *domain.Category
+		wantErr  error
+	}{
+		{name: "category", category: &foods},
+		{name: "no category"},
+		{name: "deleted category", category: &drinks, wantErr: domain.ErrInvalidReference},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Price: usd(t, "10"), DeletedAt: &deletedAt}
+			if tt.category != nil {
+				product.CategoryID = &tt.category.ID
+			}
+			auditRepo := &fakeAuditRepository{}
+			ps := newTestProductService(newFakeProductRepository(product), categoryRepo, newFakeCache())
+			ps.auditRepo = auditRepo
+
+			_, err := ps.RestoreProduct(context.Background(), product.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RestoreProduct() error = %v, want %v", err, tt.wantErr)
+			}
+			wantEntries := 1
+			if tt.wantErr != nil {
+				wantEntries = 0
+			}
+			if len(auditRepo.entries) != wantEntries {
+				t.Errorf("audit entries = %d, want %d", len(auditRepo.entries), wantEntries)
+			}
+		})
+	}
+}
+
+func TestListProductsCategories(t *testing.T) {
+	foods := domain.Category{ID: uuid.New(), Name: "Foods"}
+	rice := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", CategoryID: &foods.ID, Price: usd(t, "10")}
+	salt := domain.Product{ID: uuid.New(), Reference: "R2", Name: "Salt", Price: usd(t, "2")}
+	categoryRepo := &fakeCategoryRepository{categories: map[uuid.UUID]domain.Category{foods.ID: foods}}
+	ps := newTestProductService(newFakeProductRepository(rice, salt), categoryRepo, newFakeCache())
+
+	products, err := ps.ListProducts(context.Background(), domain.ProductFilter{}, nil, 0, 10)
+	if err != nil {
+		t.Fatalf("ListProducts() error = %v", err)
+	}
+	if len(products) != 2 {
+		t.Fatalf("ListProducts() = %d products, want 2", len(products))
+	}
+	for _, product := range products {
+		wantCategory := product.ID == rice.ID
+		if (product.Category != nil) != wantCategory || (wantCategory && product.Category.ID != foods.ID) {
+			t.Errorf("product %s category = %+v, want one only for %s", product.Reference, product.Category, rice.Reference)
+		}
+	}
+}
+
+func TestUpdateProductAuditsLockedProduct(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Status: domain.StatusAvailable, Price: usd(t, "10"), Quantity: 5}
+	productRepo := newFakeProductRepository(product)
+	productRepo.concurrentWrite = func(product *domain.Product) {
+		product.Name = "Brown rice"
+		product.Price = usd(t, "12")
+	}
+	auditRepo := &fakeAuditRepository{}
+	priceRepo := &fakePriceRepository{}
+	ps := newTestProductService(productRepo, &fakeCategoryRepository{}, newFakeCache())
+	ps.auditRepo, ps.priceRepo = auditRepo, priceRepo
+
+	_, err := ps.UpdateProduct(context.Background(), &domain.Product{ID: product.ID, Name: "Jasmine rice", Status: domain.StatusUnknown, Price: usd(t, "12")}, false)
+	if err != nil {
+		t.Fatalf("UpdateProduct() error = %v", err)
+	}
+
+	want := []domain.FieldChange{{Field: "name", Before: "Brown rice", After: "Jasmine rice"}}
+	if len(auditRepo.entries) != 1 || !slices.Equal(auditRepo.entries[0].Changes, want) {
+		t.Errorf("audit entries = %+v, want one with the changes %+v", auditRepo.entries, want)
+	}
+	if len(priceRepo.created) != 0 {
+		t.Errorf("price changes = %+v, want none as the price was already set", priceRepo.created)
+	}
+}
+
+func TestUpdateProductStatusFromLockedProduct(t *testing.T) {
+	tests := []struct {
+		name string
+		// stored is the product read before the update, concurrent the product locked by the update
+		stored, concurrent domain.Product
+		wantErr            error
+	}{
+		{
+			name:       "ordered once sold out",
+			stored:     domain.Product{Status: domain.StatusAvailable, Quantity: 5},
+			concurrent: domain.Product{Status: domain.StatusOutOfStock, Quantity: 0},
+		},
+		{
+			name:       "not ordered once restocked",
+			stored:     domain.Product{Status: domain.StatusOutOfStock, Quantity: 0},
+			concurrent: domain.Product{Status: domain.StatusAvailable, Quantity: 5},
+			wantErr:    domain.ErrInvalidStatus,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Status: tt.stored.Status, Price: usd(t, "10"), Quantity: tt.stored.Quantity}
+			productRepo := newFakeProductRepository(product)
+			productRepo.concurrentWrite = func(product *domain.Product) {
+				product.Status, product.Quantity = tt.concurrent.Status, tt.concurrent.Quantity
+			}
+			ps := newTestProductService(productRepo, &fakeCategoryRepository{}, newFakeCache())
+
+			_, err := ps.UpdateProduct(context.Background(), &domain.Product{ID: product.ID, Status: domain.StatusOnOrDer}, false)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateProduct() error = %v, want %v", err, tt.wantErr)
+			}
+
+			want := domain.StatusOnOrDer
+			if tt.wantErr != nil {
+				want = tt.concurrent.Status
+			}
+			if got := productRepo.products[product.ID]; got.Status != want || got.Quantity != tt.concurrent.Quantity {
+				t.Errorf("product = %v with %d in stock, want %v with %d", got.Status, got.Quantity, want, tt.concurrent.Quantity)
+			}
+		})
+	}
+}
+
+func TestDeleteProductTwice(t *testing.T) {
+	product := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", Price: usd(t, "10")}
+	auditRepo := &fakeAuditRepository{}
+	ps := newTestProductService(newFakeProductRepository(product), &fakeCategoryRepository{}, newFakeCache())
+	ps.auditRepo = auditRepo
+
+	if err := ps.DeleteProduct(context.Background(), product.ID); err != nil {
+		t.Fatalf("DeleteProduct() error = %v", err)
+	}
+	if err := ps.DeleteProduct(context.Background(), product.ID); !errors.Is(err, domain.ErrDataNotFound) {
+		t.Errorf("DeleteProduct() of a deleted product error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+	if len(auditRepo.entries) != 1 || auditRepo.entries[0].Action != domain.AuditActionDelete {
+		t.Errorf("audit entries = %+v, want a single deletion", auditRepo.entries)
+	}
+}
+
+func TestUpdateProductQuantity(t *testing.T) {
+	tests := []struct {
+		name         string
+		update       domain.Product
+		quantitySet  bool
+		wantQuantity int
+		wantStatus   domain.ProductStatus
+		wantMoved    int
+	}{
+		{name: "quantity kept", update: domain.Product{Name: "Brown rice"}, wantQuantity: 5, wantStatus: domain.StatusAvailable},
+		{name: "quantity set", update: domain.Product{Quantity: 8}, quantitySet: true, wantQuantity: 8, wantStatus: domain.StatusAvailable, wantMoved: 3},
+		{name: "quantity set to zero", quantitySet: true, wantQuantity: 0, wantStatus: domain.StatusOutOfStock, wantMoved: -5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := domain.Product{ID: uuid.New(), Name: "Rice", Status: domain.StatusAvailable, Price: usd(t, "10"), Quantity: 5}
+			productRepo := newFakeProductRepository(product)
+			stockRepo := &fakeStockRepository{}
+			ps := newTestProductService(productRepo, &fakeCategoryRepository{}, newFakeCache())
+			ps.stockRepo = stockRepo
+
+			update := tt.update
+			update.ID = product.ID
+			if _, err := ps.UpdateProduct(context.Background(), &update, tt.quantitySet); err != nil {
+				t.Fatalf("UpdateProduct() error = %v", err)
+			}
+
+			got := productRepo.products[product.ID]
+			if got.Quantity != tt.wantQuantity || got.Status != tt.wantStatus {
+				t.Errorf("product = quantity %d, status %q, want %d, %q", got.Quantity, got.Status, tt.wantQuantity, tt.wantStatus)
+			}
+
+			moved := 0
+			for _, movement := range stockRepo.movements {
+				moved += movement.Quantity
+			}
+			if moved != tt.wantMoved {
+				t.Errorf("stock moved = %d, want %d", moved, tt.wantMoved)
+			}
+		})
+	}
+}
+
+func TestGetProductAvailabilityLocationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		wantErr error
+	}{
+		{name: "located", wantErr: nil},
+		{name: "unknown ip", err: fmt.Errorf("%w: ip address is not public", domain.ErrUnknownLocation), wantErr: domain.ErrUnknownLocation},
+		{name: "geo service failed", err: errors.New("connection refused"), wantErr: domain.ErrInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := domain.Product{ID: uuid.New(), Reference: "R1", Name: "Rice", StockCity: "Hanoi"}
+			ps := newTestProductService(newFakeProductRepository(product), &fakeCategoryRepository{}, newFakeCache())
+			ps.geoClient = &fakeGeoClient{err: tt.err}
+
+			availability, err := ps.GetProductAvailability(context.Background(), product.ID, nil, "8.8.8.8")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetProductAvailability() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && availability.ProductID != product.ID {
+				t.Errorf("GetProductAvailability() = %+v, want the availability of %s", availability, product.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/util"
+)
+
+/**
+ * StockService implements port.StockService interface
+ * and provides an access to the stock and product repositories
+ */
+type StockService struct {
+	stockRepo   port.StockRepository
+	productRepo port.ProductRepository
+	auditRepo   port.AuditRepository
+	transactor  port.Transactor
+	cache       port.CacheRepository
+}
+
+// NewStockService creates a new stock service instance. The cache is the one products are read from by id, nil when disabled
+func NewStockService(stockRepo port.StockRepository, productRepo port.ProductRepository, auditRepo port.AuditRepository, transactor port.Transactor, cache port.CacheRepository) *StockService {
+	return &StockService{
+		stockRepo,
+		productRepo,
+		auditRepo,
+		transactor,
+		cache,
+	}
+}
+
+// PostStockMovements applies stock movements in the given order within a single transaction.
+// The quantity, the warehouse stocks and the status of each product are kept in sync with its ledger,
+// and a movement of a product that does not exist or taking out more than in stock rejects all the movements
+func (ss *StockService) PostStockMovements(ctx context.Context, movements []domain.StockMovement) ([]domain.StockMovement, error) {
+	// Lock the products in a stable order so concurrent postings can not deadlock
+	var productIDs []uuid.UUID
+	for _, movement := range movements {
+		if !slices.Contains(productIDs, movement.ProductID) {
+			productIDs = append(productIDs, movement.ProductID)
+		}
+	}
+	slices.SortFunc(productIDs, func(a, b uuid.UUID) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	err := ss.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before := make(map[uuid.UUID]domain.Product, len(productIDs))
+		products := make(map[uuid.UUID]*domain.Product, len(productIDs))
+		for _, id := range productIDs {
+			product, err := ss.productRepo.GetProductForUpdate(ctx, id)
+			if err != nil {
+				return fmt.Errorf("product %s: %w", id, err)
+			}
+
+			before[id] = *product
+			products[id] = product
+		}
+
+		stocks, err := ss.stockRepo.GetWarehouseStocks(ctx, productIDs)
+		if err != nil {
+			return err
+		}
+
+		levels := make(map[uuid.UUID]*domain.StockLevels, len(productIDs))
+		for _, id := range productIDs {
+			productLevels := domain.NewStockLevels(products[id].Quantity, stocks[id])
+			levels[id] = &productLevels
+		}
+
+		for i := range movements {
+			movement := &movements[i]
+			productLevels := levels[movement.ProductID]
+
+			if err := productLevels.Apply(movement); err != nil {
+				return fmt.Errorf("movement %d: %w", i, err)
+			}
+
+			movement.ID = uuid.New()
+			movement.Actor = util.ActorFromContext(ctx)
+			movement.RequestID = util.RequestIDFromContext(ctx)
+			_, err = ss.stockRepo.CreateStockMovement(ctx, movement)
+			if err != nil {
+				return err
+			}
+
+			if movement.WarehouseID != nil {
+				err = ss.stockRepo.SetWarehouseStock(ctx, movement.ProductID, *movement.WarehouseID, productLevels.Warehouses[*movement.WarehouseID])
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, id := range productIDs {
+			product, b := products[id], before[id]
+			product.Quantity = levels[id].Total
+			if product.Quantity == b.Quantity {
+				continue
+			}
+
+			// Without a requested status it only follows the quantity, which can not fail
+			product.Status, _ = domain.NextProductStatus(b.Status, domain.StatusUnknown, product.Quantity)
+			_, err := ss.productRepo.UpdateProduct(ctx, product, "quantity", "status")
+			if err != nil {
+				return err
+			}
+
+			entry := newAuditEntry(ctx, domain.AuditEntityProduct, id, domain.AuditActionUpdate, domain.DiffProducts(&b, product))
+			if err := ss.auditRepo.CreateAuditEntry(ctx, entry); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		if isRepositoryError(err) || isStockError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	forgetCachedProducts(ctx, ss.cache, productIDs...)
+
+	return movements, nil
+}
+
+// TransferStock moves stock of a product from one warehouse to another, recording
+// the units leaving the source and entering the destination as a pair of transfer movements
+func (ss *StockService) TransferStock(ctx context.Context, transfer *domain.WarehouseTransfer) ([]domain.StockMovement, error) {
+	transfer.ID = uuid.New()
+
+	movements, err := transfer.Movements()
+	if err != nil {
+		return nil, err
+	}
+
+	return ss.PostStockMovements(ctx, movements)
+}
+
+// ListStockMovements returns the stock movement history of a product, newest first
+func (ss *StockService) ListStockMovements(ctx context.Context, productID uuid.UUID, filter domain.StockMovementFilter, skip, limit uint64) ([]domain.StockMovement, error) {
+	movements, err := ss.stockRepo.ListStockMovements(ctx, productID, filter, skip, limit)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return movements, nil
+}
+
+// isStockError reports whether the error is a stock movement that can not be applied
+func isStockError(err error) bool {
+	return errors.Is(err, domain.ErrInsufficientStock) || errors.Is(err, domain.ErrInvalidStockMovement)
+}
+
+// recordStockMovement appends a movement bringing the total stock of a product from one quantity to another
+// to its ledger, it is used when the quantity is set directly on the product. The difference is taken from or
+// added to the stock not held by any warehouse, which can not become negative
+func recordStockMovement(ctx context.Context, stockRepo port.StockRepository, productID uuid.UUID, movementType domain.StockMovementType, from, to int, reason string) error {
+	if from == to {
+		return nil
+	}
+
+	movement := newStockMovement(ctx, productID, movementType, to-from, reason)
+
+	stocks, err := stockRepo.GetWarehouseStocks(ctx, []uuid.UUID{productID})
+	if err != nil {
+		return err
+	}
+
+	levels := domain.NewStockLevels(from, stocks[productID])
+	if err := levels.Apply(movement); err != nil {
+		return err
+	}
+
+	_, err = stockRepo.CreateStockMovement(ctx, movement)
+	return err
+}
+
+// newStockMovement creates a stock movement of a product made by the actor of the request carried by the context
+func newStockMovement(ctx context.Context, productID uuid.UUID, movementType domain.StockMovementType, quantity int, reason string) *domain.StockMovement {
+	return &domain.StockMovement{
+		ID:        uuid.New(),
+		ProductID: productID,
+		Type:      movementType,
+		Quantity:  quantity,
+		Reason:    reason,
+		Actor:     util.ActorFromContext(ctx),
+		RequestID: util.RequestIDFromContext(ctx),
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+func TestPostStockMovements(t *testing.T) {
+	missing := uuid.New()
+
+	tests := []struct {
+		name      string
+		stored    domain.Product
+		movements []domain.StockMovement
+		// wantQuantity and wantStatus are the product once posted, unchanged when the movements are rejected
+		wantQuantity int
+		wantStatus   domain.ProductStatus
+		wantErr      error
+	}{
+		{
+			name:         "sold out",
+			stored:       domain.Product{Status: domain.StatusAvailable, Quantity: 5},
+			movements:    []domain.StockMovement{{Type: domain.StockSale, Quantity: -5}},
+			wantQuantity: 0,
+			wantStatus:   domain.StatusOutOfStock,
+		},
+		{
+			name:         "restocked",
+			stored:       domain.Product{Status: domain.StatusOutOfStock, Quantity: 0},
+			movements:    []domain.StockMovement{{Type: domain.StockReceipt, Quantity: 3}},
+			wantQuantity: 3,
+			wantStatus:   domain.StatusAvailable,
+		},
+		{
+			name:         "applied in order",
+			stored:       domain.Product{Status: domain.StatusAvailable, Quantity: 5},
+			movements:    []domain.StockMovement{{Type: domain.StockReceipt, Quantity: 2}, {Type: domain.StockSale, Quantity: -7}},
+			wantQuantity: 0,
+			wantStatus:   domain.StatusOutOfStock,
+		},
+		{
+			name:         "negative stock",
+			stored:       domain.Product{Status: domain.StatusAvailable, Quantity: 5},
+			movements:    []domain.StockMovement{{Type: domain.StockSale, Quantity: -7}, {Type: domain.StockReceipt, Quantity: 2}},
+			wantQuantity: 5,
+			wantStatus:   domain.StatusAvailable,
+			wantErr:      domain.ErrInsufficientStock,
+		},
+		{
+			name:         "unknown product",
+			stored:       domain.Product{Status: domain.StatusAvailable, Quantity: 5},
+			movements:    []domain.StockMovement{{Type: domain.StockSale, Quantity: -1}, {ProductID: missing, Type: domain.StockReceipt, Quantity: 1}},
+			wantQuantity: 5,
+			wantStatus:   domain.StatusAvailable,
+			wantErr:      domain.ErrDataNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			product := tt.stored
+			product.ID, product.Name = uuid.New(), "Rice"
+			for i := range tt.movements {
+				if tt.movements[i].ProductID == uuid.Nil {
+					tt.movements[i].ProductID = product.ID
+				}
+			}
+
+			productRepo := newFakeProductRepository(product)
+			stockRepo := &fakeStockRepository{}
+			auditRepo := &fakeAuditRepository{}
+			ss := NewStockService(stockRepo, productRepo, auditRepo, fakeTransactor{}, newFakeCache())
+
+			_, err := ss.PostStockMovements(context.Background(), tt.movements)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PostStockMovements() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got := productRepo.products[product.ID]
+			if got.Quantity != tt.wantQuantity || got.Status != tt.wantStatus {
+				t.Errorf("product = %d in stock %v, want %d %v", got.Quantity, got.Status, tt.wantQuantity, tt.wantStatus)
+			}
+			if tt.wantErr != nil {
+				if len(stockRepo.movements) != 0 || len(auditRepo.entries) != 0 {
+					t.Errorf("posted %d movements and %d audit entries, want none", len(stockRepo.movements), len(auditRepo.entries))
+				}
+				return
+			}
+			if len(stockRepo.movements) != len(tt.movements) || len(auditRepo.entries) != 1 {
+				t.Errorf("posted %d movements and %d audit entries, want %d and 1", len(stockRepo.movements), len(auditRepo.entries), len(tt.movements))
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/port"
+)
+
+/**
+ * WarehouseService implements port.WarehouseService interface
+ * and provides an access to the warehouse repository
+ */
+type WarehouseService struct {
+	repo       port.WarehouseRepository
+	auditRepo  port.AuditRepository
+	transactor port.Transactor
+}
+
+// NewWarehouseService creates a new warehouse service instance
+func NewWarehouseService(repo port.WarehouseRepository, auditRepo port.AuditRepository, transactor port.Transactor) *WarehouseService {
+	return &WarehouseService{
+		repo,
+		auditRepo,
+		transactor,
+	}
+}
+
+// CreateWarehouse creates a new warehouse
+func (ws *WarehouseService) CreateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	if err := warehouse.Validate(); err != nil {
+		return nil, err
+	}
+
+	warehouse.ID = uuid.New()
+
+	err := ws.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := ws.repo.CreateWarehouse(ctx, warehouse)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityWarehouse, warehouse.ID, domain.AuditActionCreate, domain.DiffWarehouses(nil, warehouse))
+		return ws.auditRepo.CreateAuditEntry(ctx, entry)
+	})
+	if err != nil {
+		if isRepositoryError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return warehouse, nil
+}
+
+// GetWarehouse retrieves a warehouse by id
+func (ws *WarehouseService) GetWarehouse(ctx context.Context, id uuid.UUID) (*domain.Warehouse, error) {
+	warehouse, err := ws.repo.GetWarehouseByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return warehouse, nil
+}
+
+// ListWarehouses retrieves a list of warehouses
+func (ws *WarehouseService) ListWarehouses(ctx context.Context, skip, limit uint64) ([]domain.Warehouse, error) {
+	warehouses, err := ws.repo.ListWarehouses(ctx, skip, limit)
+	if err != nil {
+		return nil, domain.ErrInternal
+	}
+
+	return warehouses, nil
+}
+
+// UpdateWarehouse updates the name, city and coordinates of a warehouse, the fields left empty are kept
+func (ws *WarehouseService) UpdateWarehouse(ctx context.Context, warehouse *domain.Warehouse) (*domain.Warehouse, error) {
+	if warehouse.Name == "" && warehouse.City == "" && warehouse.Coordinates == nil {
+		return nil, domain.ErrNoUpdatedData
+	}
+
+	before, err := ws.repo.GetWarehouseByID(ctx, warehouse.ID)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	if warehouse.Name == "" {
+		warehouse.Name = before.Name
+	}
+	if warehouse.City == "" {
+		warehouse.City = before.City
+	}
+	if warehouse.Coordinates == nil {
+		warehouse.Coordinates = before.Coordinates
+	}
+
+	if err := warehouse.Validate(); err != nil {
+		return nil, err
+	}
+
+	err = ws.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		_, err := ws.repo.UpdateWarehouse(ctx, warehouse)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityWarehouse, warehouse.ID, domain.AuditActionUpdate, domain.DiffWarehouses(before, warehouse))
+		return ws.auditRepo.CreateAuditEntry(ctx, entry)
+	})
+	if err != nil {
+		if isRepositoryError(err) {
+			return nil, err
+		}
+		return nil, domain.ErrInternal
+	}
+
+	return warehouse, nil
+}
+
+// DeleteWarehouse deletes a warehouse that holds no stock
+func (ws *WarehouseService) DeleteWarehouse(ctx context.Context, id uuid.UUID) error {
+	before, err := ws.repo.GetWarehouseByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrDataNotFound) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	err = ws.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		err := ws.repo.DeleteWarehouse(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		entry := newAuditEntry(ctx, domain.AuditEntityWarehouse, id, domain.AuditActionDelete, domain.DiffWarehouses(before, nil))
+		return ws.auditRepo.CreateAuditEntry(ctx, entry)
+	})
+	if err != nil {
+		if isRepositoryError(err) {
+			return err
+		}
+		return domain.ErrInternal
+	}
+
+	return nil
+}
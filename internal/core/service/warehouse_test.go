@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/tuan1kdt/soa-ba-test/internal/core/domain"
+)
+
+// stockFixture is a product of 6 units, 4 held by the north warehouse, 1 by the south one and 1 unassigned
+type stockFixture struct {
+	product      domain.Product
+	north, south uuid.UUID
+	productRepo  *fakeProductRepository
+	stockRepo    *fakeStockRepository
+	auditRepo    *fakeAuditRepository
+	stocks       *StockService
+}
+
+func newStockFixture() *stockFixture {
+	f := &stockFixture{
+		product:   domain.Product{ID: uuid.New(), Name: "Rice", Status: domain.StatusAvailable, Quantity: 6},
+		north:     uuid.New(),
+		south:     uuid.New(),
+		auditRepo: &fakeAuditRepository{},
+	}
+	f.stockRepo = &fakeStockRepository{stocks: map[uuid.UUID]map[uuid.UUID]int{
+		f.product.ID: {f.north: 4, f.south: 1},
+	}}
+	f.productRepo = newFakeProductRepository(f.product)
+	f.stocks = NewStockService(f.stockRepo, f.productRepo, f.auditRepo, fakeTransactor{}, newFakeCache())
+	return f
+}
+
+// checkStockLevels checks the quantity of the product and its stock in the warehouses,
+// and that the warehouses hold no more than the quantity of the product
+func (f *stockFixture) checkStockLevels(t *testing.T, quantity, north, south int) {
+	t.Helper()
+
+	product := f.productRepo.products[f.product.ID]
+	stocks := f.stockRepo.stocks[f.product.ID]
+	if product.Quantity != quantity || stocks[f.north] != north || stocks[f.south] != south {
+		t.Errorf("stock = %d, %d north, %d south, want %d, %d, %d", product.Quantity, stocks[f.north], stocks[f.south], quantity, north, south)
+	}
+	if held := stocks[f.north] + stocks[f.south]; held > product.Quantity {
+		t.Errorf("warehouses hold %d units, more than the %d of the product", held, product.Quantity)
+	}
+}
+
+func TestTransferStock(t *testing.T) {
+	tests := []struct {
+		name     string
+		quantity int
+		// sameWarehouse transfers from the north warehouse to itself
+		sameWarehouse bool
+		wantNorth     int
+		wantSouth     int
+		wantErr       error
+	}{
+		{name: "moved", quantity: 3, wantNorth: 1, wantSouth: 4},
+		{name: "whole warehouse", quantity: 4, wantNorth: 0, wantSouth: 5},
+		{name: "more than the source holds", quantity: 5, wantNorth: 4, wantSouth: 1, wantErr: domain.ErrInsufficientStock},
+		{name: "within the same warehouse", quantity: 1, sameWarehouse: true, wantNorth: 4, wantSouth: 1, wantErr: domain.ErrInvalidStockMovement},
+		{name: "nothing", quantity: 0, wantNorth: 4, wantSouth: 1, wantErr: domain.ErrInvalidStockMovement},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newStockFixture()
+			transfer := &domain.WarehouseTransfer{ProductID: f.product.ID, FromWarehouseID: f.north, ToWarehouseID: f.south, Quantity: tt.quantity}
+			if tt.sameWarehouse {
+				transfer.ToWarehouseID = f.north
+			}
+
+			movements, err := f.stocks.TransferStock(context.Background(), transfer)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TransferStock() error = %v, want %v", err, tt.wantErr)
+			}
+
+			f.checkStockLevels(t, 6, tt.wantNorth, tt.wantSouth)
+			if tt.wantErr != nil {
+				if len(f.stockRepo.movements) != 0 {
+					t.Errorf("stock movements = %+v, want none", f.stockRepo.movements)
+				}
+				return
+			}
+			if len(movements) != 2 || *movements[0].TransferID != transfer.ID || *movements[1].TransferID != transfer.ID {
+				t.Errorf("TransferStock() = %+v, want a pair of movements of the transfer", movements)
+			}
+			if len(f.auditRepo.entries) != 0 {
+				t.Errorf("audit entries = %+v, want none as the quantity of the product is kept", f.auditRepo.entries)
+			}
+		})
+	}
+}
+
+func TestPostStockMovementsWarehouseStocks(t *testing.T) {
+	tests := []struct {
+		name string
+		// warehouse moves the stock of the north or the south warehouse, or the unassigned stock when empty
+		warehouse    string
+		quantity     int
+		wantQuantity int
+		wantNorth    int
+		wantSouth    int
+		wantErr      error
+	}{
+		{name: "received by a warehouse", warehouse: "north", quantity: 2, wantQuantity: 8, wantNorth: 6, wantSouth: 1},
+		{name: "sold from a warehouse", warehouse: "south", quantity: -1, wantQuantity: 5, wantNorth: 4, wantSouth: 0},
+		{name: "sold from the unassigned stock", quantity: -1, wantQuantity: 5, wantNorth: 4, wantSouth: 1},
+		{name: "more than a warehouse holds", warehouse: "south", quantity: -2, wantQuantity: 6, wantNorth: 4, wantSouth: 1, wantErr: domain.ErrInsufficientStock},
+		{name: "more than the unassigned stock", quantity: -2, wantQuantity: 6, wantNorth: 4, wantSouth: 1, wantErr: domain.ErrInsufficientStock},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newStockFixture()
+			movement := domain.StockMovement{ProductID: f.product.ID, Type: domain.StockAdjustment, Quantity: tt.quantity}
+			switch tt.warehouse {
+			case "north":
+				movement.WarehouseID = &f.north
+			case "south":
+				movement.WarehouseID = &f.south
+			}
+
+			_, err := f.stocks.PostStockMovements(context.Background(), []domain.StockMovement{movement})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PostStockMovements() error = %v, want %v", err, tt.wantErr)
+			}
+
+			f.checkStockLevels(t, tt.wantQuantity, tt.wantNorth, tt.wantSouth)
+		})
+	}
+}
+
+// newTestWarehouseService creates a warehouse service over the fake repositories holding a warehouse
+func newTestWarehouseService(warehouse domain.Warehouse, stockRepo *fakeStockRepository) (*WarehouseService, *fakeWarehouseRepository, *fakeAuditRepository) {
+	repo := &fakeWarehouseRepository{warehouses: map[uuid.UUID]domain.Warehouse{warehouse.ID: warehouse}, stockRepo: stockRepo}
+	auditRepo := &fakeAuditRepository{}
+	return NewWarehouseService(repo, auditRepo, fakeTransactor{}), repo, auditRepo
+}
+
+func TestUpdateWarehouse(t *testing.T) {
+	coordinates := &domain.Coordinates{Latitude: 21.03, Longitude: 105.85}
+	warehouse := domain.Warehouse{ID: uuid.New(), Name: "Hanoi North", City: "Hanoi", Coordinates: coordinates}
+
+	tests := []struct {
+		name        string
+		update      domain.Warehouse
+		want        domain.Warehouse
+		wantChanges []string
+		wantErr     error
+	}{
+		{
+			name:        "fields left empty kept",
+			update:      domain.Warehouse{Name: " Hanoi West "},
+			want:        domain.Warehouse{ID: warehouse.ID, Name: "Hanoi West", City: "Hanoi", Coordinates: coordinates},
+			wantChanges: []string{"name"},
+		},
+		{
+			name:    "nothing updated",
+			want:    warehouse,
+			wantErr: domain.ErrNoUpdatedData,
+		},
+		{
+			name:    "invalid coordinates",
+			update:  domain.Warehouse{Coordinates: &domain.Coordinates{Latitude: 91}},
+			want:    warehouse,
+			wantErr: domain.ErrInvalidCoordinates,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws, repo, auditRepo := newTestWarehouseService(warehouse, &fakeStockRepository{})
+
+			update := tt.update
+			update.ID = warehouse.ID
+			_, err := ws.UpdateWarehouse(context.Background(), &update)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateWarehouse() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := repo.warehouses[warehouse.ID]; got.Name != tt.want.Name || got.City != tt.want.City || got.Coordinates != tt.want.Coordinates {
+				t.Errorf("warehouse = %+v, want %+v", got, tt.want)
+			}
+
+			var changes []string
+			for _, entry := range auditRepo.entries {
+				for _, change := range entry.Changes {
+					changes = append(changes, change.Field)
+				}
+			}
+			if !slices.Equal(changes, tt.wantChanges) {
+				t.Errorf("audited changes = %v, want %v", changes, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestDeleteWarehouse(t *testing.T) {
+	tests := []struct {
+		name    string
+		held    int
+		wantErr error
+	}{
+		{name: "empty", held: 0},
+		{name: "holding stock", held: 2, wantErr: domain.ErrForeignKeyViolation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warehouse := domain.Warehouse{ID: uuid.New(), Name: "Hanoi North", City: "Hanoi"}
+			stockRepo := &fakeStockRepository{stocks: map[uuid.UUID]map[uuid.UUID]int{
+				uuid.New(): {warehouse.ID: tt.held},
+			}}
+			ws, repo, auditRepo := newTestWarehouseService(warehouse, stockRepo)
+
+			err := ws.DeleteWarehouse(context.Background(), warehouse.ID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeleteWarehouse() error = %v, want %v", err, tt.wantErr)
+			}
+
+			wantKept := tt.wantErr != nil
+			if _, kept := repo.warehouses[warehouse.ID]; kept != wantKept {
+				t.Errorf("warehouse kept = %v, want %v", kept, wantKept)
+			}
+			if audited := len(auditRepo.entries) == 1; audited == wantKept {
+				t.Errorf("deletion audited = %v, want %v", audited, !wantKept)
+			}
+		})
+	}
+
+	ws, _, _ := newTestWarehouseService(domain.Warehouse{ID: uuid.New()}, &fakeStockRepository{})
+	if err := ws.DeleteWarehouse(context.Background(), uuid.New()); !errors.Is(err, domain.ErrDataNotFound) {
+		t.Errorf("DeleteWarehouse() of a missing warehouse error = %v, want %v", err, domain.ErrDataNotFound)
+	}
+}
@@ -0,0 +1,51 @@
+package util
+
+import "context"
+
+type (
+	// actorContextKey is the context key of the actor performing the request
+	actorContextKey struct{}
+	// claimedActorContextKey is the context key of the actor the client claims to be
+	claimedActorContextKey struct{}
+	// requestIDContextKey is the context key of the request id
+	requestIDContextKey struct{}
+)
+
+// DefaultActor is the actor recorded when the request does not identify one
+const DefaultActor = "anonymous"
+
+// WithActor returns a copy of the context that carries the actor performing the request
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor carried by the context, or DefaultActor
+func ActorFromContext(ctx context.Context) string {
+	actor, ok := ctx.Value(actorContextKey{}).(string)
+	if !ok || actor == "" {
+		return DefaultActor
+	}
+	return actor
+}
+
+// WithClaimedActor returns a copy of the context that carries the actor the client claims to be, which is not verified
+func WithClaimedActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, claimedActorContextKey{}, actor)
+}
+
+// ClaimedActorFromContext returns the actor the client claims to be carried by the context, or an empty string
+func ClaimedActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(claimedActorContextKey{}).(string)
+	return actor
+}
+
+// WithRequestID returns a copy of the context that carries the request id
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request id carried by the context, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}